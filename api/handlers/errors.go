@@ -47,6 +47,24 @@ func ErrFinishDB(err error) render.Renderer {
 	}
 }
 
+func ErrPauseDB(err error) render.Renderer {
+	return &ErrResponse{
+		Err:            err,
+		HTTPStatusCode: 500,
+		StatusText:     "Error while pausing ride.",
+		ErrorText:      err.Error(),
+	}
+}
+
+func ErrResumeDB(err error) render.Renderer {
+	return &ErrResponse{
+		Err:            err,
+		HTTPStatusCode: 500,
+		StatusText:     "Error while resuming ride.",
+		ErrorText:      err.Error(),
+	}
+}
+
 func ErrFindDB(err error) render.Renderer {
 	return &ErrResponse{
 		Err:            err,
@@ -55,3 +73,219 @@ func ErrFindDB(err error) render.Renderer {
 		ErrorText:      err.Error(),
 	}
 }
+
+func ErrNotFound(err error) render.Renderer {
+	return &ErrResponse{
+		Err:            err,
+		HTTPStatusCode: 404,
+		StatusText:     "Resource not found.",
+		ErrorText:      err.Error(),
+	}
+}
+
+func ErrListDB(err error) render.Renderer {
+	return &ErrResponse{
+		Err:            err,
+		HTTPStatusCode: 500,
+		StatusText:     "Error while listing rides.",
+		ErrorText:      err.Error(),
+	}
+}
+
+func ErrConflict(err error) render.Renderer {
+	return &ErrResponse{
+		Err:            err,
+		HTTPStatusCode: 409,
+		StatusText:     "Request conflicts with the current state of the resource.",
+		ErrorText:      err.Error(),
+	}
+}
+
+func ErrTariffDB(err error) render.Renderer {
+	return &ErrResponse{
+		Err:            err,
+		HTTPStatusCode: 500,
+		StatusText:     "Error while managing tariffs.",
+		ErrorText:      err.Error(),
+	}
+}
+
+func ErrPromoDB(err error) render.Renderer {
+	return &ErrResponse{
+		Err:            err,
+		HTTPStatusCode: 500,
+		StatusText:     "Error while managing promos.",
+		ErrorText:      err.Error(),
+	}
+}
+
+func ErrPromoCodeInvalid(err error) render.Renderer {
+	return &ErrResponse{
+		Err:            err,
+		HTTPStatusCode: 422,
+		StatusText:     "Promo code can't be redeemed.",
+		ErrorText:      err.Error(),
+	}
+}
+
+func ErrPassDB(err error) render.Renderer {
+	return &ErrResponse{
+		Err:            err,
+		HTTPStatusCode: 500,
+		StatusText:     "Error while managing passes.",
+		ErrorText:      err.Error(),
+	}
+}
+
+func ErrQuoteDB(err error) render.Renderer {
+	return &ErrResponse{
+		Err:            err,
+		HTTPStatusCode: 500,
+		StatusText:     "Error while quoting ride.",
+		ErrorText:      err.Error(),
+	}
+}
+
+func ErrTaxDB(err error) render.Renderer {
+	return &ErrResponse{
+		Err:            err,
+		HTTPStatusCode: 500,
+		StatusText:     "Error while managing tax rates.",
+		ErrorText:      err.Error(),
+	}
+}
+
+func ErrCancelDB(err error) render.Renderer {
+	return &ErrResponse{
+		Err:            err,
+		HTTPStatusCode: 500,
+		StatusText:     "Error while cancelling ride.",
+		ErrorText:      err.Error(),
+	}
+}
+
+func ErrPaymentDeclined(err error) render.Renderer {
+	return &ErrResponse{
+		Err:            err,
+		HTTPStatusCode: 402,
+		StatusText:     "Payment declined.",
+		ErrorText:      err.Error(),
+	}
+}
+
+func ErrPaymentFailed(err error) render.Renderer {
+	return &ErrResponse{
+		Err:            err,
+		HTTPStatusCode: 502,
+		StatusText:     "Error while capturing payment.",
+		ErrorText:      err.Error(),
+	}
+}
+
+func ErrVehicleUnreachable(err error) render.Renderer {
+	return &ErrResponse{
+		Err:            err,
+		HTTPStatusCode: 502,
+		StatusText:     "Vehicle didn't respond.",
+		ErrorText:      err.Error(),
+	}
+}
+
+func ErrInsufficientBalance(err error) render.Renderer {
+	return &ErrResponse{
+		Err:            err,
+		HTTPStatusCode: 402,
+		StatusText:     "Insufficient balance.",
+		ErrorText:      err.Error(),
+	}
+}
+
+func ErrWalletDB(err error) render.Renderer {
+	return &ErrResponse{
+		Err:            err,
+		HTTPStatusCode: 500,
+		StatusText:     "Error while managing wallets.",
+		ErrorText:      err.Error(),
+	}
+}
+
+func ErrAdjustmentDB(err error) render.Renderer {
+	return &ErrResponse{
+		Err:            err,
+		HTTPStatusCode: 500,
+		StatusText:     "Error while adjusting ride.",
+		ErrorText:      err.Error(),
+	}
+}
+
+func ErrSettlementFailed(err error) render.Renderer {
+	return &ErrResponse{
+		Err:            err,
+		HTTPStatusCode: 502,
+		StatusText:     "Error while settling adjustment.",
+		ErrorText:      err.Error(),
+	}
+}
+
+func ErrAdjustmentRejected(err error) render.Renderer {
+	return &ErrResponse{
+		Err:            err,
+		HTTPStatusCode: 422,
+		StatusText:     "Adjustment rejected.",
+		ErrorText:      err.Error(),
+	}
+}
+
+func ErrInvoiceDB(err error) render.Renderer {
+	return &ErrResponse{
+		Err:            err,
+		HTTPStatusCode: 500,
+		StatusText:     "Error while managing invoices.",
+		ErrorText:      err.Error(),
+	}
+}
+
+func ErrUserDB(err error) render.Renderer {
+	return &ErrResponse{
+		Err:            err,
+		HTTPStatusCode: 500,
+		StatusText:     "Error while managing users.",
+		ErrorText:      err.Error(),
+	}
+}
+
+func ErrRiderNotAllowed(err error) render.Renderer {
+	return &ErrResponse{
+		Err:            err,
+		HTTPStatusCode: 403,
+		StatusText:     "User can't ride.",
+		ErrorText:      err.Error(),
+	}
+}
+
+func ErrVehicleDB(err error) render.Renderer {
+	return &ErrResponse{
+		Err:            err,
+		HTTPStatusCode: 500,
+		StatusText:     "Error while managing vehicles.",
+		ErrorText:      err.Error(),
+	}
+}
+
+func ErrVehicleUnavailable(err error) render.Renderer {
+	return &ErrResponse{
+		Err:            err,
+		HTTPStatusCode: 409,
+		StatusText:     "Vehicle can't be ridden.",
+		ErrorText:      err.Error(),
+	}
+}
+
+func ErrBatteryTooLow(err error) render.Renderer {
+	return &ErrResponse{
+		Err:            err,
+		HTTPStatusCode: 422,
+		StatusText:     "Battery too low.",
+		ErrorText:      err.Error(),
+	}
+}
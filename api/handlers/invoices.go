@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+
+	"backend/invoices"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// Invoices serves the invoices of the user in the path, so it is mounted
+// under /users/{id}/invoices.
+type Invoices struct {
+	*chi.Mux
+	invoices invoices.Service
+}
+
+// InvoiceResponse is the response payload for the Invoice data model.
+type InvoiceResponse struct {
+	*invoices.Invoice
+}
+
+func (ir *InvoiceResponse) Render(w http.ResponseWriter, r *http.Request) error {
+	return nil
+}
+
+// InvoiceListResponse is the response payload for a list of invoices.
+type InvoiceListResponse struct {
+	Invoices []invoices.Invoice `json:"invoices"`
+}
+
+func (il *InvoiceListResponse) Render(w http.ResponseWriter, r *http.Request) error {
+	return nil
+}
+
+var ErrInvoiceFormatInvalid = errors.New("format must be one of: json, html.")
+
+// Invoices godoc
+// @Summary lists the invoices of a user.
+// @Description list monthly invoices, the latest first
+// @Tags invoices
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {object} InvoiceListResponse
+// @Router /users/{id}/invoices [get]
+func (i Invoices) InvoiceListHandler(w http.ResponseWriter, req *http.Request) {
+	if err, list := i.invoices.ListInvoices(req.Context(), chi.URLParam(req, "id")); err != nil {
+		if err := render.Render(w, req, ErrInvoiceDB(err)); err != nil {
+			return
+		}
+		return
+	} else {
+		resp := &InvoiceListResponse{Invoices: list}
+
+		render.Status(req, http.StatusOK)
+		if err := render.Render(w, req, resp); err != nil {
+			return
+		}
+	}
+}
+
+// Invoices godoc
+// @Summary returns the invoice of a user that matches the given number.
+// @Description get invoice as JSON, or as a printable HTML document with format=html
+// @Tags invoices
+// @Produce json,html
+// @Param id path string true "User ID"
+// @Param number path string true "Invoice number"
+// @Param format query string false "Document format (default json)" Enums(json, html)
+// @Success 200 {object} invoices.Invoice
+// @Failure 400 {object} ErrResponse
+// @Failure 404 {object} ErrResponse
+// @Router /users/{id}/invoices/{number} [get]
+func (i Invoices) InvoiceGetHandler(w http.ResponseWriter, req *http.Request) {
+	format := req.URL.Query().Get("format")
+	if format != "" && format != "json" && format != "html" {
+		if err := render.Render(w, req, ErrInvalidRequest(ErrInvoiceFormatInvalid)); err != nil {
+			return
+		}
+		return
+	}
+
+	if err, invoice := i.invoices.GetInvoice(req.Context(), chi.URLParam(req, "id"), chi.URLParam(req, "number")); err != nil {
+		renderer := ErrInvoiceDB(err)
+		if errors.Is(err, invoices.ErrInvoiceNotFound) {
+			renderer = ErrNotFound(err)
+		}
+		if err := render.Render(w, req, renderer); err != nil {
+			return
+		}
+		return
+	} else if format == "html" {
+		// The document is rendered before anything is written, so a failure
+		// still gets an error response instead of a truncated 200.
+		var document bytes.Buffer
+		if err := invoice.WriteHTML(&document); err != nil {
+			if err := render.Render(w, req, ErrInvoiceDB(err)); err != nil {
+				return
+			}
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		if _, err := document.WriteTo(w); err != nil {
+			return
+		}
+	} else {
+		resp := &InvoiceResponse{Invoice: invoice}
+
+		render.Status(req, http.StatusOK)
+		if err := render.Render(w, req, resp); err != nil {
+			return
+		}
+	}
+}
+
+func NewInvoicesHandler(invoices invoices.Service) Invoices {
+	i := Invoices{
+		Mux:      chi.NewRouter(),
+		invoices: invoices,
+	}
+
+	i.Get("/", i.InvoiceListHandler)
+	i.Get("/{number}", i.InvoiceGetHandler)
+
+	return i
+}
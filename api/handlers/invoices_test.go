@@ -0,0 +1,98 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"backend/api/handlers"
+	"backend/invoices"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-rel/rel"
+	"github.com/go-rel/rel/where"
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+)
+
+var invoice = invoices.Invoice{ID: 1, Number: "2026-000001", UserID: "1", Currency: "EUR", NetAmount: 100, TaxAmount: 21, TotalAmount: 121}
+
+// usersRouter mounts the invoices the way the API does, so the user ID is
+// read from the path.
+func usersRouter(repository *reltest.Repository) http.Handler {
+	router := chi.NewRouter()
+	router.Mount("/users/{id}/invoices", handlers.NewInvoicesHandler(invoices.New(repository)))
+	return router
+}
+
+func TestListInvoices(t *testing.T) {
+	var (
+		req, _     = http.NewRequest("GET", "/users/1/invoices", nil)
+		rr         = httptest.NewRecorder()
+		repository = reltest.New()
+	)
+
+	repository.ExpectFindAll(rel.Where(where.Eq("user_id", "1")).SortDesc("id")).Result([]invoices.Invoice{invoice})
+
+	usersRouter(repository).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var resp handlers.InvoiceListResponse
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Errorf("Error decoding response body: %v", err)
+	}
+	assert.Len(t, resp.Invoices, 1)
+	assert.Equal(t, "2026-000001", resp.Invoices[0].Number)
+
+	repository.AssertExpectations(t)
+}
+
+func TestGetInvoiceHTML(t *testing.T) {
+	var (
+		req, _     = http.NewRequest("GET", "/users/1/invoices/2026-000001?format=html", nil)
+		rr         = httptest.NewRecorder()
+		repository = reltest.New()
+	)
+
+	repository.ExpectFind(where.Eq("user_id", "1").AndEq("number", "2026-000001")).Result(invoice)
+
+	usersRouter(repository).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "text/html; charset=utf-8", rr.Header().Get("Content-Type"))
+	assert.Contains(t, rr.Body.String(), "<h1>Invoice 2026-000001</h1>")
+
+	repository.AssertExpectations(t)
+}
+
+func TestGetInvoiceNotFound(t *testing.T) {
+	var (
+		req, _     = http.NewRequest("GET", "/users/2/invoices/2026-000001", nil)
+		rr         = httptest.NewRecorder()
+		repository = reltest.New()
+	)
+
+	repository.ExpectFind(where.Eq("user_id", "2").AndEq("number", "2026-000001")).NotFound()
+
+	usersRouter(repository).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+
+	repository.AssertExpectations(t)
+}
+
+func TestGetInvoiceFormatInvalid(t *testing.T) {
+	var (
+		req, _     = http.NewRequest("GET", "/users/1/invoices/2026-000001?format=pdf", nil)
+		rr         = httptest.NewRecorder()
+		repository = reltest.New()
+	)
+
+	usersRouter(repository).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	repository.AssertExpectations(t)
+}
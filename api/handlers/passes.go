@@ -0,0 +1,305 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"backend/money"
+	"backend/passes"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+type Passes struct {
+	*chi.Mux
+	passes passes.Service
+}
+
+// ProductRequest puts a pass on sale. It covers every unlock fee when
+// free_unlocks is set, and minutes minutes of riding time, for
+// validity_days days from its purchase.
+type ProductRequest struct {
+	Name         string         `json:"name"`
+	FreeUnlocks  bool           `json:"free_unlocks"`
+	Minutes      int            `json:"minutes"`
+	ValidityDays int            `json:"validity_days"`
+	Price        int            `json:"price"`
+	Currency     money.Currency `json:"currency"`
+}
+
+var ErrProductNameBlank = errors.New("missing required Name field.")
+
+func (product *ProductRequest) Bind(r *http.Request) error {
+	if product.Name == "" {
+		return ErrProductNameBlank
+	}
+	return nil
+}
+
+// PurchaseRequest buys a pass of the given product for the user.
+type PurchaseRequest struct {
+	UserID    string `json:"user_id"`
+	ProductID uint   `json:"product_id"`
+}
+
+var (
+	ErrPurchaseUserIDBlank    = errors.New("missing required UserID field.")
+	ErrPurchaseProductIDBlank = errors.New("missing required ProductID field.")
+)
+
+func (purchase *PurchaseRequest) Bind(r *http.Request) error {
+	if purchase.UserID == "" {
+		return ErrPurchaseUserIDBlank
+	}
+	if purchase.ProductID == 0 {
+		return ErrPurchaseProductIDBlank
+	}
+	return nil
+}
+
+// ProductResponse is the response payload for the Product data model.
+type ProductResponse struct {
+	*passes.Product
+}
+
+func (pr *ProductResponse) Render(w http.ResponseWriter, r *http.Request) error {
+	return nil
+}
+
+// ProductListResponse is the response payload for a list of products.
+type ProductListResponse struct {
+	Products []passes.Product `json:"products"`
+}
+
+func (pl *ProductListResponse) Render(w http.ResponseWriter, r *http.Request) error {
+	return nil
+}
+
+// PassResponse is the response payload for the Pass data model.
+type PassResponse struct {
+	*passes.Pass
+}
+
+func (pr *PassResponse) Render(w http.ResponseWriter, r *http.Request) error {
+	return nil
+}
+
+// PassListResponse is the response payload for a list of passes.
+type PassListResponse struct {
+	Passes []passes.Pass `json:"passes"`
+}
+
+func (pl *PassListResponse) Render(w http.ResponseWriter, r *http.Request) error {
+	return nil
+}
+
+// ConsumptionListResponse is the response payload for what a ride consumed
+// of the passes.
+type ConsumptionListResponse struct {
+	Consumptions []passes.Consumption `json:"consumptions"`
+}
+
+func (cl *ConsumptionListResponse) Render(w http.ResponseWriter, r *http.Request) error {
+	return nil
+}
+
+var ErrPassUserIDBlank = errors.New("missing required user_id query parameter.")
+
+// Passes godoc
+// @Summary lists the passes of a user.
+// @Description list passes with what they have left, the latest to expire first
+// @Tags passes
+// @Produce json
+// @Param user_id query string true "User ID"
+// @Success 200 {object} PassListResponse
+// @Failure 400 {object} ErrResponse
+// @Router /passes [get]
+func (p Passes) PassListHandler(w http.ResponseWriter, req *http.Request) {
+	userID := req.URL.Query().Get("user_id")
+	if userID == "" {
+		if err := render.Render(w, req, ErrInvalidRequest(ErrPassUserIDBlank)); err != nil {
+			return
+		}
+		return
+	}
+
+	if err, list := p.passes.ListPasses(req.Context(), userID); err != nil {
+		if err := render.Render(w, req, ErrPassDB(err)); err != nil {
+			return
+		}
+		return
+	} else {
+		resp := &PassListResponse{Passes: list}
+
+		render.Status(req, http.StatusOK)
+		if err := render.Render(w, req, resp); err != nil {
+			return
+		}
+	}
+}
+
+// Passes godoc
+// @Summary purchases a pass.
+// @Description purchase pass, valid from now for the validity days of its product
+// @Tags passes
+// @Accept json
+// @Produce json
+// @Param params body PurchaseRequest true "Purchase request parameters"
+// @Success 201 {object} passes.Pass
+// @Failure 400 {object} ErrResponse
+// @Failure 404 {object} ErrResponse
+// @Router /passes [post]
+func (p Passes) PassPurchaseHandler(w http.ResponseWriter, req *http.Request) {
+	data := &PurchaseRequest{}
+	if err := render.Bind(req, data); err != nil {
+		if err := render.Render(w, req, ErrInvalidRequest(err)); err != nil {
+			return
+		}
+		return
+	}
+
+	if err, pass := p.passes.PurchasePass(req.Context(), data.UserID, data.ProductID, time.Now()); err != nil {
+		if err := render.Render(w, req, passErrRenderer(err)); err != nil {
+			return
+		}
+		return
+	} else {
+		resp := &PassResponse{Pass: pass}
+
+		render.Status(req, http.StatusCreated)
+		if err := render.Render(w, req, resp); err != nil {
+			return
+		}
+	}
+}
+
+// Passes godoc
+// @Summary lists every pass product.
+// @Description list pass products
+// @Tags passes
+// @Produce json
+// @Success 200 {object} ProductListResponse
+// @Router /passes/products [get]
+func (p Passes) ProductListHandler(w http.ResponseWriter, req *http.Request) {
+	if err, list := p.passes.ListProducts(req.Context()); err != nil {
+		if err := render.Render(w, req, ErrPassDB(err)); err != nil {
+			return
+		}
+		return
+	} else {
+		resp := &ProductListResponse{Products: list}
+
+		render.Status(req, http.StatusOK)
+		if err := render.Render(w, req, resp); err != nil {
+			return
+		}
+	}
+}
+
+// Passes godoc
+// @Summary puts a pass on sale.
+// @Description create pass product: free unlocks, minutes or both
+// @Tags passes
+// @Accept json
+// @Produce json
+// @Param params body ProductRequest true "Product request parameters"
+// @Success 201 {object} passes.Product
+// @Failure 400 {object} ErrResponse
+// @Router /passes/products [post]
+func (p Passes) ProductCreateHandler(w http.ResponseWriter, req *http.Request) {
+	data := &ProductRequest{}
+	if err := render.Bind(req, data); err != nil {
+		if err := render.Render(w, req, ErrInvalidRequest(err)); err != nil {
+			return
+		}
+		return
+	}
+
+	product := passes.Product{
+		Name:         data.Name,
+		FreeUnlocks:  data.FreeUnlocks,
+		Minutes:      data.Minutes,
+		ValidityDays: data.ValidityDays,
+		Price:        data.Price,
+		Currency:     data.Currency,
+	}
+	if err, savedProduct := p.passes.CreateProduct(req.Context(), &product); err != nil {
+		if err := render.Render(w, req, passErrRenderer(err)); err != nil {
+			return
+		}
+		return
+	} else {
+		resp := &ProductResponse{Product: savedProduct}
+
+		render.Status(req, http.StatusCreated)
+		if err := render.Render(w, req, resp); err != nil {
+			return
+		}
+	}
+}
+
+// Passes godoc
+// @Summary gives back what a ride consumed of the passes.
+// @Description reverse the pass consumption of a ride whose charge was refunded, reversing twice gives nothing back
+// @Tags passes
+// @Produce json
+// @Param id path int true "Ride ID"
+// @Success 200 {object} ConsumptionListResponse
+// @Failure 400 {object} ErrResponse
+// @Router /passes/rides/{id}/reverse [post]
+func (p Passes) RideReverseHandler(w http.ResponseWriter, req *http.Request) {
+	rideID, err := strconv.ParseUint(chi.URLParam(req, "id"), 10, 0)
+	if err != nil {
+		if err := render.Render(w, req, ErrInvalidRequest(ErrRideIDInvalid)); err != nil {
+			return
+		}
+		return
+	}
+
+	if err, reversed := p.passes.ReverseRide(req.Context(), uint(rideID), time.Now()); err != nil {
+		if err := render.Render(w, req, ErrPassDB(err)); err != nil {
+			return
+		}
+		return
+	} else {
+		resp := &ConsumptionListResponse{Consumptions: reversed}
+
+		render.Status(req, http.StatusOK)
+		if err := render.Render(w, req, resp); err != nil {
+			return
+		}
+	}
+}
+
+func passErrRenderer(err error) render.Renderer {
+	switch {
+	case errors.Is(err, passes.ErrProductNotFound):
+		return ErrNotFound(err)
+	case errors.Is(err, passes.ErrProductNameBlank),
+		errors.Is(err, passes.ErrProductEmpty),
+		errors.Is(err, passes.ErrProductMinutesNegative),
+		errors.Is(err, passes.ErrProductValidityInvalid),
+		errors.Is(err, passes.ErrProductPriceInvalid),
+		errors.Is(err, passes.ErrPassUserIDBlank):
+		return ErrInvalidRequest(err)
+	}
+	return ErrPassDB(err)
+}
+
+func NewPassesHandler(passes passes.Service) Passes {
+	p := Passes{
+		Mux:    chi.NewRouter(),
+		passes: passes,
+	}
+
+	p.Get("/", p.PassListHandler)
+	p.Post("/", p.PassPurchaseHandler)
+	p.Get("/products", p.ProductListHandler)
+	p.Post("/products", p.ProductCreateHandler)
+	p.Post("/rides/{id}/reverse", p.RideReverseHandler)
+
+	return p
+}
@@ -0,0 +1,123 @@
+package handlers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"backend/api/handlers"
+	"backend/passes"
+
+	"github.com/go-rel/rel/where"
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateProduct(t *testing.T) {
+	var (
+		request    = handlers.ProductRequest{Name: "100 minutes", Minutes: 100, ValidityDays: 30, Price: 1500, Currency: "EUR"}
+		body, _    = json.Marshal(request)
+		req, _     = http.NewRequest("POST", "/products", bytes.NewBuffer(body))
+		rr         = httptest.NewRecorder()
+		repository = reltest.New()
+		handler    = handlers.NewPassesHandler(passes.New(repository))
+	)
+	req.Header.Add("Content-Type", "application/json")
+
+	repository.ExpectInsert().ForType("*passes.Product")
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+
+	var product passes.Product
+	if err := json.NewDecoder(rr.Body).Decode(&product); err != nil {
+		t.Errorf("Error decoding response body: %v", err)
+	}
+	assert.NotEmpty(t, product.ID)
+	assert.Equal(t, 100, product.Minutes)
+
+	repository.AssertExpectations(t)
+}
+
+func TestCreateProductInvalid(t *testing.T) {
+	var (
+		request    = handlers.ProductRequest{Name: "Nothing", ValidityDays: 30, Currency: "EUR"}
+		body, _    = json.Marshal(request)
+		req, _     = http.NewRequest("POST", "/products", bytes.NewBuffer(body))
+		rr         = httptest.NewRecorder()
+		repository = reltest.New()
+		handler    = handlers.NewPassesHandler(passes.New(repository))
+	)
+	req.Header.Add("Content-Type", "application/json")
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	repository.AssertExpectations(t)
+}
+
+func TestPurchasePass(t *testing.T) {
+	var (
+		request    = handlers.PurchaseRequest{UserID: "1", ProductID: 1}
+		body, _    = json.Marshal(request)
+		req, _     = http.NewRequest("POST", "/", bytes.NewBuffer(body))
+		rr         = httptest.NewRecorder()
+		repository = reltest.New()
+		handler    = handlers.NewPassesHandler(passes.New(repository))
+		product    = passes.Product{ID: 1, Name: "100 minutes", Minutes: 100, ValidityDays: 30, Price: 1500, Currency: "EUR"}
+	)
+	req.Header.Add("Content-Type", "application/json")
+
+	repository.ExpectFind(where.Eq("id", uint(1))).Result(product)
+	repository.ExpectInsert().ForType("*passes.Pass")
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+
+	var pass passes.Pass
+	if err := json.NewDecoder(rr.Body).Decode(&pass); err != nil {
+		t.Errorf("Error decoding response body: %v", err)
+	}
+	assert.Equal(t, "1", pass.UserID)
+	assert.Equal(t, 100, pass.MinutesLeft)
+
+	repository.AssertExpectations(t)
+}
+
+func TestPurchasePassProductNotFound(t *testing.T) {
+	var (
+		request    = handlers.PurchaseRequest{UserID: "1", ProductID: 2}
+		body, _    = json.Marshal(request)
+		req, _     = http.NewRequest("POST", "/", bytes.NewBuffer(body))
+		rr         = httptest.NewRecorder()
+		repository = reltest.New()
+		handler    = handlers.NewPassesHandler(passes.New(repository))
+	)
+	req.Header.Add("Content-Type", "application/json")
+
+	repository.ExpectFind(where.Eq("id", uint(2))).NotFound()
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+
+	repository.AssertExpectations(t)
+}
+
+func TestListPassesBadRequest(t *testing.T) {
+	var (
+		req, _     = http.NewRequest("GET", "/", nil)
+		rr         = httptest.NewRecorder()
+		repository = reltest.New()
+		handler    = handlers.NewPassesHandler(passes.New(repository))
+	)
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
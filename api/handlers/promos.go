@@ -0,0 +1,199 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"backend/money"
+	"backend/pricing"
+	"backend/promos"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+type Promos struct {
+	*chi.Mux
+	promos promos.Service
+}
+
+// PromoRequest creates a promo code. Omitting starts_at makes it redeemable
+// right away, omitting ends_at keeps it redeemable indefinitely, and zero
+// limits mean unlimited redemptions.
+type PromoRequest struct {
+	Code                  string               `json:"code"`
+	Kind                  pricing.DiscountKind `json:"kind"`
+	Percent               int                  `json:"percent"`
+	Minutes               int                  `json:"minutes"`
+	Amount                int                  `json:"amount"`
+	Currency              money.Currency       `json:"currency"`
+	StartsAt              *time.Time           `json:"starts_at"`
+	EndsAt                *time.Time           `json:"ends_at"`
+	MaxRedemptions        int                  `json:"max_redemptions"`
+	MaxRedemptionsPerUser int                  `json:"max_redemptions_per_user"`
+}
+
+var (
+	ErrPromoCodeBlank = errors.New("missing required Code field.")
+	ErrPromoKindBlank = errors.New("missing required Kind field.")
+)
+
+func (promo *PromoRequest) Bind(r *http.Request) error {
+	if promo.Code == "" {
+		return ErrPromoCodeBlank
+	}
+	if promo.Kind == "" {
+		return ErrPromoKindBlank
+	}
+	return nil
+}
+
+func (promo *PromoRequest) promo() promos.Promo {
+	p := promos.Promo{
+		Code:                  promo.Code,
+		Kind:                  promo.Kind,
+		Percent:               promo.Percent,
+		Minutes:               promo.Minutes,
+		Amount:                promo.Amount,
+		Currency:              promo.Currency,
+		EndsAt:                promo.EndsAt,
+		MaxRedemptions:        promo.MaxRedemptions,
+		MaxRedemptionsPerUser: promo.MaxRedemptionsPerUser,
+	}
+	if promo.StartsAt != nil {
+		p.StartsAt = *promo.StartsAt
+	}
+	return p
+}
+
+// PromoResponse is the response payload for the Promo data model.
+type PromoResponse struct {
+	*promos.Promo
+}
+
+func (pr *PromoResponse) Render(w http.ResponseWriter, r *http.Request) error {
+	return nil
+}
+
+// PromoListResponse is the response payload for a list of promos.
+type PromoListResponse struct {
+	Promos []promos.Promo `json:"promos"`
+}
+
+func (pl *PromoListResponse) Render(w http.ResponseWriter, r *http.Request) error {
+	return nil
+}
+
+// Promos godoc
+// @Summary lists every promo code.
+// @Description list promos, the latest to start first
+// @Tags promos
+// @Produce json
+// @Success 200 {object} PromoListResponse
+// @Router /promos [get]
+func (p Promos) PromoListHandler(w http.ResponseWriter, req *http.Request) {
+	if err, list := p.promos.ListPromos(req.Context()); err != nil {
+		if err := render.Render(w, req, ErrPromoDB(err)); err != nil {
+			return
+		}
+		return
+	} else {
+		resp := &PromoListResponse{Promos: list}
+
+		render.Status(req, http.StatusOK)
+		if err := render.Render(w, req, resp); err != nil {
+			return
+		}
+	}
+}
+
+// Promos godoc
+// @Summary creates a promo code.
+// @Description create promo: free_unlock, percent_off, free_minutes or amount_off
+// @Tags promos
+// @Accept json
+// @Produce json
+// @Param params body PromoRequest true "Promo request parameters"
+// @Success 201 {object} promos.Promo
+// @Failure 400 {object} ErrResponse
+// @Failure 409 {object} ErrResponse
+// @Router /promos [post]
+func (p Promos) PromoCreateHandler(w http.ResponseWriter, req *http.Request) {
+	data := &PromoRequest{}
+	if err := render.Bind(req, data); err != nil {
+		if err := render.Render(w, req, ErrInvalidRequest(err)); err != nil {
+			return
+		}
+		return
+	}
+
+	promo := data.promo()
+	if err, savedPromo := p.promos.CreatePromo(req.Context(), &promo, time.Now()); err != nil {
+		if err := render.Render(w, req, promoErrRenderer(err)); err != nil {
+			return
+		}
+		return
+	} else {
+		resp := &PromoResponse{Promo: savedPromo}
+
+		render.Status(req, http.StatusCreated)
+		if err := render.Render(w, req, resp); err != nil {
+			return
+		}
+	}
+}
+
+// Promos godoc
+// @Summary returns the promo that matches the given code.
+// @Description get promo, codes are case-insensitive
+// @Tags promos
+// @Produce json
+// @Param code path string true "Promo code"
+// @Success 200 {object} promos.Promo
+// @Failure 404 {object} ErrResponse
+// @Router /promos/{code} [get]
+func (p Promos) PromoGetHandler(w http.ResponseWriter, req *http.Request) {
+	if err, promo := p.promos.GetPromo(req.Context(), chi.URLParam(req, "code")); err != nil {
+		if err := render.Render(w, req, promoErrRenderer(err)); err != nil {
+			return
+		}
+		return
+	} else {
+		resp := &PromoResponse{Promo: promo}
+
+		render.Status(req, http.StatusOK)
+		if err := render.Render(w, req, resp); err != nil {
+			return
+		}
+	}
+}
+
+func promoErrRenderer(err error) render.Renderer {
+	switch {
+	case errors.Is(err, promos.ErrPromoNotFound):
+		return ErrNotFound(err)
+	case errors.Is(err, promos.ErrPromoCodeTaken):
+		return ErrConflict(err)
+	case errors.Is(err, promos.ErrPromoCodeBlank),
+		errors.Is(err, promos.ErrPromoLimitNegative),
+		errors.Is(err, promos.ErrPromoEndsAtInvalid),
+		errors.Is(err, pricing.ErrDiscountKindInvalid),
+		errors.Is(err, pricing.ErrDiscountInvalid):
+		return ErrInvalidRequest(err)
+	}
+	return ErrPromoDB(err)
+}
+
+func NewPromosHandler(promos promos.Service) Promos {
+	p := Promos{
+		Mux:    chi.NewRouter(),
+		promos: promos,
+	}
+
+	p.Get("/", p.PromoListHandler)
+	p.Post("/", p.PromoCreateHandler)
+	p.Get("/{code}", p.PromoGetHandler)
+
+	return p
+}
@@ -0,0 +1,126 @@
+package handlers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"backend/api/handlers"
+	"backend/pricing"
+	"backend/promos"
+
+	"github.com/go-rel/rel"
+	"github.com/go-rel/rel/where"
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreatePromo(t *testing.T) {
+	var (
+		request    = handlers.PromoRequest{Code: "welcome", Kind: pricing.DiscountFreeMinutes, Minutes: 10, MaxRedemptionsPerUser: 1}
+		body, _    = json.Marshal(request)
+		req, _     = http.NewRequest("POST", "/", bytes.NewBuffer(body))
+		rr         = httptest.NewRecorder()
+		repository = reltest.New()
+		handler    = handlers.NewPromosHandler(promos.New(repository))
+	)
+	req.Header.Add("Content-Type", "application/json")
+
+	repository.ExpectInsert().ForType("*promos.Promo")
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+
+	var promo promos.Promo
+	if err := json.NewDecoder(rr.Body).Decode(&promo); err != nil {
+		t.Errorf("Error decoding response body: %v", err)
+	}
+	assert.NotEmpty(t, promo.ID)
+	assert.Equal(t, "WELCOME", promo.Code)
+	assert.Equal(t, 10, promo.Minutes)
+	assert.NotEmpty(t, promo.StartsAt)
+
+	repository.AssertExpectations(t)
+}
+
+func TestCreatePromoBadRequest(t *testing.T) {
+	var (
+		request    = handlers.PromoRequest{Code: "WELCOME"}
+		body, _    = json.Marshal(request)
+		req, _     = http.NewRequest("POST", "/", bytes.NewBuffer(body))
+		rr         = httptest.NewRecorder()
+		repository = reltest.New()
+		handler    = handlers.NewPromosHandler(promos.New(repository))
+	)
+	req.Header.Add("Content-Type", "application/json")
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	var resp handlers.ErrResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err == nil {
+		assert.Equal(t, "missing required Kind field.", resp.ErrorText)
+	}
+}
+
+func TestCreatePromoInvalid(t *testing.T) {
+	var (
+		request    = handlers.PromoRequest{Code: "HALF", Kind: pricing.DiscountPercentOff, Percent: 150}
+		body, _    = json.Marshal(request)
+		req, _     = http.NewRequest("POST", "/", bytes.NewBuffer(body))
+		rr         = httptest.NewRecorder()
+		repository = reltest.New()
+		handler    = handlers.NewPromosHandler(promos.New(repository))
+	)
+	req.Header.Add("Content-Type", "application/json")
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	var resp handlers.ErrResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err == nil {
+		assert.Equal(t, pricing.ErrDiscountInvalid.Error(), resp.ErrorText)
+	}
+}
+
+func TestCreatePromoCodeTaken(t *testing.T) {
+	var (
+		request    = handlers.PromoRequest{Code: "SPRING", Kind: pricing.DiscountFreeUnlock}
+		body, _    = json.Marshal(request)
+		req, _     = http.NewRequest("POST", "/", bytes.NewBuffer(body))
+		rr         = httptest.NewRecorder()
+		repository = reltest.New()
+		handler    = handlers.NewPromosHandler(promos.New(repository))
+	)
+	req.Header.Add("Content-Type", "application/json")
+
+	repository.ExpectInsert().ForType("*promos.Promo").Error(rel.ErrUniqueConstraint)
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusConflict, rr.Code)
+
+	repository.AssertExpectations(t)
+}
+
+func TestGetPromoNotFound(t *testing.T) {
+	var (
+		req, _     = http.NewRequest("GET", "/winter", nil)
+		rr         = httptest.NewRecorder()
+		repository = reltest.New()
+		handler    = handlers.NewPromosHandler(promos.New(repository))
+	)
+
+	repository.ExpectFind(where.Eq("code", "WINTER")).NotFound()
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+
+	repository.AssertExpectations(t)
+}
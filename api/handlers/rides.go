@@ -3,30 +3,43 @@ package handlers
 import (
 	"errors"
 	"net/http"
+	"strconv"
 	"time"
 
+	"backend/iot"
+	"backend/money"
+	"backend/payments"
+	"backend/pricing"
+	"backend/promos"
 	"backend/rides"
+	"backend/users"
+	"backend/vehicles"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/render"
-	"github.com/go-rel/rel"
-	"github.com/go-rel/rel/where"
 )
 
 type Rides struct {
 	*chi.Mux
-	repository rel.Repository
-	rides      rides.Service
+	rides rides.Service
 }
 
+// RideRequest starts a ride, optionally with a promo code. The city and the
+// operator of its vehicle pick its tax rate.
 type RideRequest struct {
 	UserID    string `json:"user_id"`
 	VehicleID string `json:"vehicle_id"`
+	PromoCode string `json:"promo_code,omitempty"`
 }
 
 var (
 	ErrRideUserIDBlank    = errors.New("missing required UserID field.")
 	ErrRideVehicleIDBlank = errors.New("missing required VehicleID field.")
+	ErrRideIDInvalid      = errors.New("ride ID must be a positive integer.")
+	ErrRideStatusInvalid  = errors.New("status must be one of: reserved, active, paused, finished, cancelled, force_closed.")
+	ErrCreatedFromInvalid = errors.New("created_from must be an RFC 3339 timestamp.")
+	ErrCreatedToInvalid   = errors.New("created_to must be an RFC 3339 timestamp.")
+	ErrLimitInvalid       = errors.New("limit must be a positive integer.")
 )
 
 func (ride *RideRequest) Bind(r *http.Request) error {
@@ -39,6 +52,37 @@ func (ride *RideRequest) Bind(r *http.Request) error {
 	return nil
 }
 
+// AdjustmentRequest corrects the price of a finished ride: negative amounts
+// give money back, positive ones charge more. The currency defaults to the
+// currency of the ride, and agent identifies the support agent.
+type AdjustmentRequest struct {
+	Amount   int64          `json:"amount"`
+	Currency money.Currency `json:"currency,omitempty"`
+	Reason   rides.Reason   `json:"reason" enums:"overcharge,undercharge,vehicle_issue,goodwill,other"`
+	Note     string         `json:"note,omitempty"`
+	Agent    string         `json:"agent"`
+}
+
+var (
+	ErrAdjustmentAmountBlank = errors.New("missing required Amount field.")
+	ErrAdjustmentReasonBlank = errors.New("missing required Reason field.")
+	ErrAdjustmentAgentBlank  = errors.New("missing required Agent field.")
+	ErrAdjustmentIDInvalid   = errors.New("adjustment ID must be a positive integer.")
+)
+
+func (adjustment *AdjustmentRequest) Bind(r *http.Request) error {
+	if adjustment.Amount == 0 {
+		return ErrAdjustmentAmountBlank
+	}
+	if adjustment.Reason == "" {
+		return ErrAdjustmentReasonBlank
+	}
+	if adjustment.Agent == "" {
+		return ErrAdjustmentAgentBlank
+	}
+	return nil
+}
+
 // RideResponse is the response payload for the Ride data model.
 type RideResponse struct {
 	*rides.Ride
@@ -48,6 +92,56 @@ func (rd *RideResponse) Render(w http.ResponseWriter, r *http.Request) error {
 	return nil
 }
 
+// QuoteResponse is the response payload for the Quote data model.
+type QuoteResponse struct {
+	*rides.Quote
+}
+
+func (qr *QuoteResponse) Render(w http.ResponseWriter, r *http.Request) error {
+	return nil
+}
+
+// AdjustmentResponse is the response payload for the Adjustment data model,
+// with the effective price of the ride once adjusted.
+type AdjustmentResponse struct {
+	*rides.Adjustment
+	EffectivePrice money.Money `json:"effective_price"`
+}
+
+// newAdjustmentResponse returns the response of the given adjustment of
+// the ride.
+func newAdjustmentResponse(ride *rides.Ride, adjustment *rides.Adjustment) (*AdjustmentResponse, error) {
+	effectivePrice, err := ride.EffectivePrice()
+	if err != nil {
+		return nil, err
+	}
+
+	return &AdjustmentResponse{Adjustment: adjustment, EffectivePrice: effectivePrice}, nil
+}
+
+func (ar *AdjustmentResponse) Render(w http.ResponseWriter, r *http.Request) error {
+	return nil
+}
+
+// AdjustmentListResponse is the response payload for the adjustments of a
+// ride.
+type AdjustmentListResponse struct {
+	Adjustments []rides.Adjustment `json:"adjustments"`
+}
+
+func (al *AdjustmentListResponse) Render(w http.ResponseWriter, r *http.Request) error {
+	return nil
+}
+
+// RidePageResponse is the response payload for a page of rides.
+type RidePageResponse struct {
+	*rides.Page
+}
+
+func (rp *RidePageResponse) Render(w http.ResponseWriter, r *http.Request) error {
+	return nil
+}
+
 // Rides godoc
 // @Summary starts a ride.
 // @Description create ride
@@ -55,7 +149,12 @@ func (rd *RideResponse) Render(w http.ResponseWriter, r *http.Request) error {
 // @Accept json
 // @Produce json
 // @Param params body RideRequest true "Ride request parameters"
-// @Success 201 {object} rides.Ride
+// @Success 201 {object} rides.Ride{price=money.Money,net=money.Money,tax=money.Money,hold=money.Money,adjustments=money.Money,effective_price=money.Money}
+// @Failure 402 {object} ErrResponse
+// @Failure 403 {object} ErrResponse
+// @Failure 409 {object} ErrResponse
+// @Failure 422 {object} ErrResponse
+// @Failure 502 {object} ErrResponse
 // @Router /rides [post]
 func (r Rides) RideStartHandler(w http.ResponseWriter, req *http.Request) {
 	data := &RideRequest{}
@@ -69,10 +168,33 @@ func (r Rides) RideStartHandler(w http.ResponseWriter, req *http.Request) {
 	ride := rides.Ride{
 		UserID:    data.UserID,
 		VehicleID: data.VehicleID,
+		PromoCode: data.PromoCode,
 	}
 
 	if err, savedRide := r.rides.StartRide(req.Context(), &ride); err != nil {
-		if err := render.Render(w, req, ErrStartDB(err)); err != nil {
+		renderer := ErrStartDB(err)
+		switch {
+		case errors.Is(err, rides.ErrRideAlreadyStarted):
+			renderer = ErrConflict(err)
+		case isPromoCodeErr(err):
+			renderer = ErrPromoCodeInvalid(err)
+		case errors.Is(err, payments.ErrDeclined):
+			renderer = ErrPaymentDeclined(err)
+		case errors.Is(err, payments.ErrInsufficientBalance):
+			renderer = ErrInsufficientBalance(err)
+		case errors.Is(err, users.ErrUserNotFound),
+			errors.Is(err, users.ErrUserSuspended),
+			errors.Is(err, users.ErrUserDeleted):
+			renderer = ErrRiderNotAllowed(err)
+		case errors.Is(err, vehicles.ErrVehicleNotFound),
+			errors.Is(err, vehicles.ErrVehicleNotAvailable):
+			renderer = ErrVehicleUnavailable(err)
+		case errors.Is(err, vehicles.ErrBatteryTooLow):
+			renderer = ErrBatteryTooLow(err)
+		case isGatewayErr(err):
+			renderer = ErrVehicleUnreachable(err)
+		}
+		if err := render.Render(w, req, renderer); err != nil {
 			return
 		}
 		return
@@ -88,26 +210,71 @@ func (r Rides) RideStartHandler(w http.ResponseWriter, req *http.Request) {
 
 // Rides godoc
 // @Summary finishes the ride that matches the given ID.
-// @Description finish ride
+// @Description finish ride, locking its vehicle. The ride stays open when the vehicle can't be locked
 // @Tags rides
 // @Accept json
 // @Produce json
 // @Param id path string true "Ride ID"
-// @Success 200 {object} rides.Ride
-// @Router /rides/:id/finish [post]
+// @Success 200 {object} rides.Ride{price=money.Money,net=money.Money,tax=money.Money,hold=money.Money,adjustments=money.Money,effective_price=money.Money}
+// @Failure 404 {object} ErrResponse
+// @Failure 409 {object} ErrResponse
+// @Failure 502 {object} ErrResponse
+// @Router /rides/{id}/finish [post]
 func (r Rides) RideFinishHandler(w http.ResponseWriter, req *http.Request) {
-	rideID := chi.URLParam(req, "id")
+	ride, ok := r.findRide(w, req)
+	if !ok {
+		return
+	}
 
-	var ride rides.Ride
-	if err := r.repository.Find(req.Context(), &ride, where.Eq("id", rideID)); err != nil {
-		if err := render.Render(w, req, ErrFindDB(err)); err != nil {
+	if err, savedRide := r.rides.FinishRide(req.Context(), ride, time.Now()); err != nil {
+		renderer := ErrFinishDB(err)
+		var transitionErr rides.TransitionError
+		switch {
+		case errors.As(err, &transitionErr),
+			errors.Is(err, rides.ErrRideStatusChanged),
+			errors.Is(err, rides.ErrRideAlreadyFinished):
+			renderer = ErrConflict(err)
+		case isGatewayErr(err):
+			renderer = ErrVehicleUnreachable(err)
+		}
+		if err := render.Render(w, req, renderer); err != nil {
+			return
+		}
+		return
+	} else {
+		resp := &RideResponse{Ride: savedRide}
+
+		render.Status(req, http.StatusOK)
+		if err := render.Render(w, req, resp); err != nil {
 			return
 		}
+	}
+}
+
+// Rides godoc
+// @Summary pauses the ride that matches the given ID.
+// @Description pause ride, paused minutes are billed at a lower rate
+// @Tags rides
+// @Accept json
+// @Produce json
+// @Param id path string true "Ride ID"
+// @Success 200 {object} rides.Ride{price=money.Money,net=money.Money,tax=money.Money,hold=money.Money,adjustments=money.Money,effective_price=money.Money}
+// @Failure 404 {object} ErrResponse
+// @Failure 409 {object} ErrResponse
+// @Router /rides/{id}/pause [post]
+func (r Rides) RidePauseHandler(w http.ResponseWriter, req *http.Request) {
+	ride, ok := r.findRide(w, req)
+	if !ok {
 		return
 	}
 
-	if err, savedRide := r.rides.FinishRide(req.Context(), &ride, time.Now()); err != nil {
-		if err := render.Render(w, req, ErrFinishDB(err)); err != nil {
+	if err, savedRide := r.rides.PauseRide(req.Context(), ride, time.Now()); err != nil {
+		renderer := ErrPauseDB(err)
+		var transitionErr rides.TransitionError
+		if errors.As(err, &transitionErr) || errors.Is(err, rides.ErrRideStatusChanged) {
+			renderer = ErrConflict(err)
+		}
+		if err := render.Render(w, req, renderer); err != nil {
 			return
 		}
 		return
@@ -121,15 +288,516 @@ func (r Rides) RideFinishHandler(w http.ResponseWriter, req *http.Request) {
 	}
 }
 
-func NewRidesHandler(repository rel.Repository, rides rides.Service) Rides {
+// Rides godoc
+// @Summary resumes the paused ride that matches the given ID.
+// @Description resume ride
+// @Tags rides
+// @Accept json
+// @Produce json
+// @Param id path string true "Ride ID"
+// @Success 200 {object} rides.Ride{price=money.Money,net=money.Money,tax=money.Money,hold=money.Money,adjustments=money.Money,effective_price=money.Money}
+// @Failure 404 {object} ErrResponse
+// @Failure 409 {object} ErrResponse
+// @Router /rides/{id}/resume [post]
+func (r Rides) RideResumeHandler(w http.ResponseWriter, req *http.Request) {
+	ride, ok := r.findRide(w, req)
+	if !ok {
+		return
+	}
+
+	if err, savedRide := r.rides.ResumeRide(req.Context(), ride, time.Now()); err != nil {
+		renderer := ErrResumeDB(err)
+		var transitionErr rides.TransitionError
+		if errors.As(err, &transitionErr) || errors.Is(err, rides.ErrRideStatusChanged) {
+			renderer = ErrConflict(err)
+		}
+		if err := render.Render(w, req, renderer); err != nil {
+			return
+		}
+		return
+	} else {
+		resp := &RideResponse{Ride: savedRide}
+
+		render.Status(req, http.StatusOK)
+		if err := render.Render(w, req, resp); err != nil {
+			return
+		}
+	}
+}
+
+// Rides godoc
+// @Summary cancels the ride that matches the given ID.
+// @Description cancel a reserved ride, or an active one within 2 minutes of its start, without charging it, locking its vehicle, giving back its promo code and releasing its payment hold
+// @Tags rides
+// @Produce json
+// @Param id path string true "Ride ID"
+// @Success 200 {object} rides.Ride{price=money.Money,net=money.Money,tax=money.Money,hold=money.Money,adjustments=money.Money,effective_price=money.Money}
+// @Failure 404 {object} ErrResponse
+// @Failure 409 {object} ErrResponse
+// @Failure 502 {object} ErrResponse
+// @Router /rides/{id}/cancel [post]
+func (r Rides) RideCancelHandler(w http.ResponseWriter, req *http.Request) {
+	ride, ok := r.findRide(w, req)
+	if !ok {
+		return
+	}
+
+	if err, savedRide := r.rides.CancelRide(req.Context(), ride, time.Now()); err != nil {
+		renderer := ErrCancelDB(err)
+		var transitionErr rides.TransitionError
+		switch {
+		case errors.As(err, &transitionErr),
+			errors.Is(err, rides.ErrRideStatusChanged),
+			errors.Is(err, rides.ErrCancelTooLate):
+			renderer = ErrConflict(err)
+		case isGatewayErr(err):
+			renderer = ErrVehicleUnreachable(err)
+		}
+		if err := render.Render(w, req, renderer); err != nil {
+			return
+		}
+		return
+	} else {
+		resp := &RideResponse{Ride: savedRide}
+
+		render.Status(req, http.StatusOK)
+		if err := render.Render(w, req, resp); err != nil {
+			return
+		}
+	}
+}
+
+// Rides godoc
+// @Summary retries capturing the price of the ride that matches the given ID.
+// @Description capture the payment of a finished ride whose payment is pending
+// @Tags rides
+// @Produce json
+// @Param id path string true "Ride ID"
+// @Success 200 {object} rides.Ride{price=money.Money,net=money.Money,tax=money.Money,hold=money.Money,adjustments=money.Money,effective_price=money.Money}
+// @Failure 404 {object} ErrResponse
+// @Failure 409 {object} ErrResponse
+// @Failure 502 {object} ErrResponse
+// @Router /rides/{id}/capture [post]
+func (r Rides) RideCaptureHandler(w http.ResponseWriter, req *http.Request) {
+	ride, ok := r.findRide(w, req)
+	if !ok {
+		return
+	}
+
+	if err, savedRide := r.rides.CapturePayment(req.Context(), ride, time.Now()); err != nil {
+		renderer := ErrPaymentFailed(err)
+		if errors.Is(err, rides.ErrPaymentNotPending) {
+			renderer = ErrConflict(err)
+		}
+		if err := render.Render(w, req, renderer); err != nil {
+			return
+		}
+		return
+	} else {
+		resp := &RideResponse{Ride: savedRide}
+
+		render.Status(req, http.StatusOK)
+		if err := render.Render(w, req, resp); err != nil {
+			return
+		}
+	}
+}
+
+// Rides godoc
+// @Summary adjusts the price of the ride that matches the given ID.
+// @Description adjust the price of a finished ride, which is kept as charged, and record who did it and why. The adjustment is refunded or charged on the card of the user, and answered with 202 and left pending when the provider fails
+// @Tags rides
+// @Accept json
+// @Produce json
+// @Param id path int true "Ride ID"
+// @Param params body AdjustmentRequest true "Adjustment request parameters"
+// @Success 201 {object} AdjustmentResponse
+// @Success 202 {object} AdjustmentResponse
+// @Failure 400 {object} ErrResponse
+// @Failure 404 {object} ErrResponse
+// @Failure 409 {object} ErrResponse
+// @Failure 422 {object} ErrResponse
+// @Router /rides/{id}/adjustments [post]
+func (r Rides) RideAdjustHandler(w http.ResponseWriter, req *http.Request) {
+	data := &AdjustmentRequest{}
+	if err := render.Bind(req, data); err != nil {
+		if err := render.Render(w, req, ErrInvalidRequest(err)); err != nil {
+			return
+		}
+		return
+	}
+
+	ride, ok := r.findRide(w, req)
+	if !ok {
+		return
+	}
+
+	adjustment := rides.Adjustment{
+		Amount:   data.Amount,
+		Currency: data.Currency,
+		Reason:   data.Reason,
+		Note:     data.Note,
+		Agent:    data.Agent,
+	}
+	if err, savedAdjustment := r.rides.AdjustRide(req.Context(), ride, &adjustment, time.Now()); err != nil {
+		renderer := ErrAdjustmentDB(err)
+		switch {
+		case errors.Is(err, rides.ErrRideNotFinished):
+			renderer = ErrConflict(err)
+		case errors.Is(err, rides.ErrAdjustmentExceedsPrice):
+			renderer = ErrAdjustmentRejected(err)
+		case errors.Is(err, rides.ErrAdjustmentAmountZero),
+			errors.Is(err, rides.ErrAdjustmentCurrencyMismatch),
+			errors.Is(err, rides.ErrAdjustmentReasonInvalid),
+			errors.Is(err, rides.ErrAdjustmentAgentBlank):
+			renderer = ErrInvalidRequest(err)
+		}
+		if err := render.Render(w, req, renderer); err != nil {
+			return
+		}
+		return
+	} else {
+		resp, err := newAdjustmentResponse(ride, savedAdjustment)
+		if err != nil {
+			if err := render.Render(w, req, ErrAdjustmentDB(err)); err != nil {
+				return
+			}
+			return
+		}
+
+		status := http.StatusCreated
+		if savedAdjustment.Status == rides.AdjustmentPending {
+			status = http.StatusAccepted
+		}
+		render.Status(req, status)
+		if err := render.Render(w, req, resp); err != nil {
+			return
+		}
+	}
+}
+
+// Rides godoc
+// @Summary retries settling an adjustment of the ride that matches the given ID.
+// @Description refund or charge a pending adjustment on the card of the user
+// @Tags rides
+// @Produce json
+// @Param id path int true "Ride ID"
+// @Param adjustmentID path int true "Adjustment ID"
+// @Success 200 {object} AdjustmentResponse
+// @Failure 400 {object} ErrResponse
+// @Failure 404 {object} ErrResponse
+// @Failure 409 {object} ErrResponse
+// @Failure 502 {object} ErrResponse
+// @Router /rides/{id}/adjustments/{adjustmentID}/settle [post]
+func (r Rides) RideAdjustmentSettleHandler(w http.ResponseWriter, req *http.Request) {
+	adjustmentID, err := strconv.ParseUint(chi.URLParam(req, "adjustmentID"), 10, 0)
+	if err != nil {
+		if err := render.Render(w, req, ErrInvalidRequest(ErrAdjustmentIDInvalid)); err != nil {
+			return
+		}
+		return
+	}
+
+	ride, ok := r.findRide(w, req)
+	if !ok {
+		return
+	}
+
+	if err, settledAdjustment := r.rides.SettleAdjustment(req.Context(), ride, uint(adjustmentID), time.Now()); err != nil {
+		renderer := ErrSettlementFailed(err)
+		switch {
+		case errors.Is(err, rides.ErrAdjustmentNotFound):
+			renderer = ErrNotFound(err)
+		case errors.Is(err, rides.ErrAdjustmentNotPending):
+			renderer = ErrConflict(err)
+		}
+		if err := render.Render(w, req, renderer); err != nil {
+			return
+		}
+		return
+	} else {
+		resp, err := newAdjustmentResponse(ride, settledAdjustment)
+		if err != nil {
+			if err := render.Render(w, req, ErrAdjustmentDB(err)); err != nil {
+				return
+			}
+			return
+		}
+
+		render.Status(req, http.StatusOK)
+		if err := render.Render(w, req, resp); err != nil {
+			return
+		}
+	}
+}
+
+// Rides godoc
+// @Summary lists the adjustments of the ride that matches the given ID.
+// @Description list adjustments, the oldest first
+// @Tags rides
+// @Produce json
+// @Param id path int true "Ride ID"
+// @Success 200 {object} AdjustmentListResponse
+// @Failure 400 {object} ErrResponse
+// @Failure 404 {object} ErrResponse
+// @Router /rides/{id}/adjustments [get]
+func (r Rides) RideAdjustmentListHandler(w http.ResponseWriter, req *http.Request) {
+	ride, ok := r.findRide(w, req)
+	if !ok {
+		return
+	}
+
+	if err, list := r.rides.ListAdjustments(req.Context(), ride.ID); err != nil {
+		if err := render.Render(w, req, ErrAdjustmentDB(err)); err != nil {
+			return
+		}
+		return
+	} else {
+		resp := &AdjustmentListResponse{Adjustments: list}
+
+		render.Status(req, http.StatusOK)
+		if err := render.Render(w, req, resp); err != nil {
+			return
+		}
+	}
+}
+
+// Rides godoc
+// @Summary returns the running price of the ride that matches the given ID.
+// @Description quote ride, priced as if it finished at the given instant without storing anything
+// @Tags rides
+// @Produce json
+// @Param id path int true "Ride ID"
+// @Param at query string false "RFC 3339 timestamp, defaults to now"
+// @Success 200 {object} rides.Quote
+// @Failure 400 {object} ErrResponse
+// @Failure 404 {object} ErrResponse
+// @Failure 409 {object} ErrResponse
+// @Router /rides/{id}/quote [get]
+func (r Rides) RideQuoteHandler(w http.ResponseWriter, req *http.Request) {
+	at := time.Now()
+	if value := req.URL.Query().Get("at"); value != "" {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			if err := render.Render(w, req, ErrInvalidRequest(ErrAtInvalid)); err != nil {
+				return
+			}
+			return
+		}
+		at = parsed
+	}
+
+	ride, ok := r.findRide(w, req)
+	if !ok {
+		return
+	}
+
+	if err, quote := r.rides.QuoteRide(req.Context(), ride, at); err != nil {
+		renderer := ErrQuoteDB(err)
+		switch {
+		case errors.Is(err, rides.ErrRideNotInProgress):
+			renderer = ErrConflict(err)
+		case errors.Is(err, rides.ErrQuoteAtInvalid):
+			renderer = ErrInvalidRequest(err)
+		}
+		if err := render.Render(w, req, renderer); err != nil {
+			return
+		}
+		return
+	} else {
+		resp := &QuoteResponse{Quote: quote}
+
+		render.Status(req, http.StatusOK)
+		if err := render.Render(w, req, resp); err != nil {
+			return
+		}
+	}
+}
+
+// Rides godoc
+// @Summary returns the ride that matches the given ID.
+// @Description get ride
+// @Tags rides
+// @Produce json
+// @Param id path int true "Ride ID"
+// @Success 200 {object} rides.Ride{price=money.Money,net=money.Money,tax=money.Money,hold=money.Money,adjustments=money.Money,effective_price=money.Money}
+// @Failure 400 {object} ErrResponse
+// @Failure 404 {object} ErrResponse
+// @Router /rides/{id} [get]
+func (r Rides) RideGetHandler(w http.ResponseWriter, req *http.Request) {
+	rideID, err := strconv.ParseUint(chi.URLParam(req, "id"), 10, 0)
+	if err != nil {
+		if err := render.Render(w, req, ErrInvalidRequest(ErrRideIDInvalid)); err != nil {
+			return
+		}
+		return
+	}
+
+	if err, ride := r.rides.GetRide(req.Context(), uint(rideID)); err != nil {
+		renderer := ErrFindDB(err)
+		if errors.Is(err, rides.ErrRideNotFound) {
+			renderer = ErrNotFound(err)
+		}
+		if err := render.Render(w, req, renderer); err != nil {
+			return
+		}
+		return
+	} else {
+		resp := &RideResponse{Ride: ride}
+
+		render.Status(req, http.StatusOK)
+		if err := render.Render(w, req, resp); err != nil {
+			return
+		}
+	}
+}
+
+// Rides godoc
+// @Summary lists rides from newest to oldest.
+// @Description list rides using cursor-based pagination
+// @Tags rides
+// @Produce json
+// @Param user_id query string false "Filter by user ID"
+// @Param vehicle_id query string false "Filter by vehicle ID"
+// @Param status query string false "Filter by status" Enums(reserved, active, paused, finished, cancelled, force_closed)
+// @Param created_from query string false "Only rides created at or after this RFC 3339 timestamp"
+// @Param created_to query string false "Only rides created before this RFC 3339 timestamp"
+// @Param cursor query string false "Cursor returned as next_cursor by the previous page"
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Success 200 {object} rides.Page{rides=[]rides.Ride{price=money.Money,net=money.Money,tax=money.Money,hold=money.Money,adjustments=money.Money,effective_price=money.Money}}
+// @Failure 400 {object} ErrResponse
+// @Router /rides [get]
+func (r Rides) RideListHandler(w http.ResponseWriter, req *http.Request) {
+	filter, err := parseFilter(req)
+	if err != nil {
+		if err := render.Render(w, req, ErrInvalidRequest(err)); err != nil {
+			return
+		}
+		return
+	}
+
+	if err, page := r.rides.ListRides(req.Context(), filter); err != nil {
+		renderer := ErrListDB(err)
+		if errors.Is(err, rides.ErrInvalidCursor) {
+			renderer = ErrInvalidRequest(err)
+		}
+		if err := render.Render(w, req, renderer); err != nil {
+			return
+		}
+		return
+	} else {
+		resp := &RidePageResponse{Page: page}
+
+		render.Status(req, http.StatusOK)
+		if err := render.Render(w, req, resp); err != nil {
+			return
+		}
+	}
+}
+
+// findRide loads the ride that matches the ID in the path, rendering the
+// error when it can't.
+func (r Rides) findRide(w http.ResponseWriter, req *http.Request) (*rides.Ride, bool) {
+	rideID, err := strconv.ParseUint(chi.URLParam(req, "id"), 10, 0)
+	if err != nil {
+		if err := render.Render(w, req, ErrInvalidRequest(ErrRideIDInvalid)); err != nil {
+			return nil, false
+		}
+		return nil, false
+	}
+
+	err, ride := r.rides.GetRide(req.Context(), uint(rideID))
+	if err != nil {
+		renderer := ErrFindDB(err)
+		if errors.Is(err, rides.ErrRideNotFound) {
+			renderer = ErrNotFound(err)
+		}
+		if err := render.Render(w, req, renderer); err != nil {
+			return nil, false
+		}
+		return nil, false
+	}
+
+	return ride, true
+}
+
+// isPromoCodeErr reports whether a ride couldn't start because of its promo
+// code.
+func isPromoCodeErr(err error) bool {
+	return errors.Is(err, promos.ErrPromoNotFound) ||
+		errors.Is(err, promos.ErrPromoNotActive) ||
+		errors.Is(err, promos.ErrPromoExhausted) ||
+		errors.Is(err, promos.ErrPromoUserLimitReached) ||
+		errors.Is(err, pricing.ErrDiscountNotApplicable)
+}
+
+func isGatewayErr(err error) bool {
+	return errors.Is(err, iot.ErrUnreachable) || errors.Is(err, iot.ErrTimeout)
+}
+
+func parseFilter(req *http.Request) (rides.Filter, error) {
+	var (
+		query  = req.URL.Query()
+		filter = rides.Filter{
+			UserID:    query.Get("user_id"),
+			VehicleID: query.Get("vehicle_id"),
+			Cursor:    query.Get("cursor"),
+		}
+	)
+
+	if value := query.Get("status"); value != "" {
+		status := rides.Status(value)
+		if !status.Valid() {
+			return filter, ErrRideStatusInvalid
+		}
+		filter.Status = status
+	}
+
+	if value := query.Get("created_from"); value != "" {
+		createdAfter, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return filter, ErrCreatedFromInvalid
+		}
+		filter.CreatedAfter = createdAfter
+	}
+
+	if value := query.Get("created_to"); value != "" {
+		createdBefore, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return filter, ErrCreatedToInvalid
+		}
+		filter.CreatedBefore = createdBefore
+	}
+
+	if value := query.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit <= 0 {
+			return filter, ErrLimitInvalid
+		}
+		filter.Limit = limit
+	}
+
+	return filter, nil
+}
+
+func NewRidesHandler(rides rides.Service) Rides {
 	r := Rides{
-		Mux:        chi.NewRouter(),
-		repository: repository,
-		rides:      rides,
+		Mux:   chi.NewRouter(),
+		rides: rides,
 	}
 
+	r.Get("/", r.RideListHandler)
 	r.Post("/", r.RideStartHandler)
+	r.Get("/{id}", r.RideGetHandler)
+	r.Get("/{id}/quote", r.RideQuoteHandler)
 	r.Post("/{id}/finish", r.RideFinishHandler)
+	r.Post("/{id}/pause", r.RidePauseHandler)
+	r.Post("/{id}/resume", r.RideResumeHandler)
+	r.Post("/{id}/cancel", r.RideCancelHandler)
+	r.Post("/{id}/capture", r.RideCaptureHandler)
+	r.Get("/{id}/adjustments", r.RideAdjustmentListHandler)
+	r.Post("/{id}/adjustments", r.RideAdjustHandler)
+	r.Post("/{id}/adjustments/{adjustmentID}/settle", r.RideAdjustmentSettleHandler)
 
 	return r
 }
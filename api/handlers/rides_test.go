@@ -2,7 +2,9 @@ package handlers_test
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -10,8 +12,16 @@ import (
 	"time"
 
 	"backend/api/handlers"
+	"backend/batteries"
+	"backend/iot"
+	"backend/money"
+	"backend/payments"
+	"backend/pricing"
+	"backend/promos"
 	"backend/rides"
-	"backend/utils"
+	"backend/users"
+	"backend/vehicles"
+	"backend/wallets"
 
 	"github.com/go-rel/rel"
 	"github.com/go-rel/rel/where"
@@ -19,6 +29,36 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
+var rider = users.User{ID: 1, Name: "Alice", Email: "alice@example.com", Status: users.StatusActive}
+
+var tariff = pricing.Tariff{Version: "1", Currency: "EUR", UnlockPrice: 18, MinutePrice: 100, PausedMinutePrice: 25, Billing: pricing.Billing{Unit: pricing.UnitMinute, Rounding: pricing.RoundingCeil}}
+
+// newRides builds the rides service over the repository with in-memory
+// providers, after letting the overrides swap the ones a test exercises.
+func newRides(repository rel.Repository, overrides ...func(*rides.Deps)) rides.Service {
+	deps := rides.Deps{
+		Riders:   users.NewDirectory(repository),
+		Vehicles: vehicles.NewFleet(repository, batteries.DefaultLevels.Start),
+		Gateway:  iot.NewSimulator(),
+		Tariffs:  pricing.Fixed{Tariff: tariff},
+		Promos:   promos.NewRedeemer(repository),
+		Passes:   pricing.NoPasses{},
+		Taxes:    pricing.NoTaxes{},
+		Payments: payments.NewFake(),
+		Wallets:  wallets.NewLedger(repository),
+		Pricer:   pricing.Flat{},
+		Hold:     rides.DefaultHold,
+	}
+	for _, override := range overrides {
+		override(&deps)
+	}
+	return rides.New(repository, deps)
+}
+
+func eur(amount int64) money.Money {
+	return money.New(amount, "EUR")
+}
+
 func TestStartRide(t *testing.T) {
 	var (
 		request    = handlers.RideRequest{UserID: "1", VehicleID: "1"}
@@ -26,17 +66,27 @@ func TestStartRide(t *testing.T) {
 		req, _     = http.NewRequest("POST", "/", bytes.NewBuffer(body))
 		rr         = httptest.NewRecorder()
 		repository = reltest.New()
-		service    = rides.New(repository)
-		handler    = handlers.NewRidesHandler(repository, service)
+		service    = newRides(repository)
+		handler    = handlers.NewRidesHandler(service)
 	)
 	req.Header.Add("Content-Type", "application/json")
+	repository.ExpectFind(where.Eq("id", uint(1))).Result(rider)
+	repository.ExpectFind(where.Eq("id", uint(1))).Result(scooter)
 
-	repository.ExpectCount("rides",
-		rel.Eq("finished", false).And(
-			rel.Eq("user_id", request.UserID).OrEq("vehicle_id", request.VehicleID),
-		),
-	).Result(0)
-	repository.ExpectInsert().ForType("*rides.Ride")
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectCount("rides",
+			rel.In("status", rides.StatusReserved, rides.StatusActive, rides.StatusPaused).And(
+				rel.Eq("user_id", request.UserID).OrEq("vehicle_id", request.VehicleID),
+			),
+		).Result(0)
+		repository.ExpectUpdateAny(
+			rel.From("vehicles").Where(where.Eq("id", uint(1)).AndEq("status", vehicles.StatusAvailable).AndGte("battery", batteries.DefaultLevels.Start)),
+			rel.Set("status", vehicles.StatusInRide),
+			rel.Set("updated_at", reltest.Any),
+		).UpdatedCount(1)
+		repository.ExpectInsert().ForType("*rides.Ride")
+		repository.ExpectInsert().ForType("*rides.Transition")
+	})
 
 	handler.ServeHTTP(rr, req)
 
@@ -46,10 +96,276 @@ func TestStartRide(t *testing.T) {
 	if err := json.NewDecoder(rr.Body).Decode(&ride); err != nil {
 		t.Errorf("Error decoding response body: %v", err)
 	}
-	assert.Equal(t, ride.Price, utils.GetEnvAsInt("RIDE_INITIAL_PRICE", 18))
+	assert.Equal(t, eur(18), ride.Price)
+	assert.Equal(t, tariff, ride.Tariff)
+	assert.Equal(t, "Valencia", ride.City)
+	assert.Equal(t, "acme", ride.Operator)
 	assert.NotNil(t, ride.CreatedAt)
 	assert.NotNil(t, ride.UpdatedAt)
-	assert.False(t, ride.Finished)
+	assert.Equal(t, rides.StatusActive, ride.Status)
+}
+
+func TestStartRideDeclined(t *testing.T) {
+	var (
+		request    = handlers.RideRequest{UserID: "1", VehicleID: "1"}
+		body, _    = json.Marshal(request)
+		req, _     = http.NewRequest("POST", "/", bytes.NewBuffer(body))
+		rr         = httptest.NewRecorder()
+		repository = reltest.New()
+		provider   = payments.NewFake()
+		service    = newRides(repository, func(d *rides.Deps) { d.Payments = provider })
+		handler    = handlers.NewRidesHandler(service)
+	)
+	req.Header.Add("Content-Type", "application/json")
+	repository.ExpectFind(where.Eq("id", uint(1))).Result(rider)
+	repository.ExpectFind(where.Eq("id", uint(1))).Result(scooter)
+	provider.Decline("1")
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusPaymentRequired, rr.Code)
+
+	repository.AssertExpectations(t)
+}
+
+func TestStartRideUserSuspended(t *testing.T) {
+	var (
+		request    = handlers.RideRequest{UserID: "1", VehicleID: "1"}
+		body, _    = json.Marshal(request)
+		req, _     = http.NewRequest("POST", "/", bytes.NewBuffer(body))
+		rr         = httptest.NewRecorder()
+		repository = reltest.New()
+		service    = newRides(repository)
+		handler    = handlers.NewRidesHandler(service)
+		suspended  = rider
+	)
+	req.Header.Add("Content-Type", "application/json")
+	suspended.Status = users.StatusSuspended
+	repository.ExpectFind(where.Eq("id", uint(1))).Result(suspended)
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+
+	var resp handlers.ErrResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Errorf("Error decoding response body: %v", err)
+	}
+	assert.Equal(t, users.ErrUserSuspended.Error(), resp.ErrorText)
+
+	repository.AssertExpectations(t)
+}
+
+func TestStartRideVehicleNotAvailable(t *testing.T) {
+	var (
+		request    = handlers.RideRequest{UserID: "1", VehicleID: "1"}
+		body, _    = json.Marshal(request)
+		req, _     = http.NewRequest("POST", "/", bytes.NewBuffer(body))
+		rr         = httptest.NewRecorder()
+		repository = reltest.New()
+		service    = newRides(repository)
+		handler    = handlers.NewRidesHandler(service)
+	)
+	req.Header.Add("Content-Type", "application/json")
+	repository.ExpectFind(where.Eq("id", uint(1))).Result(rider)
+	repository.ExpectFind(where.Eq("id", uint(1))).Result(scooter)
+
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectCount("rides",
+			rel.In("status", rides.StatusReserved, rides.StatusActive, rides.StatusPaused).And(
+				rel.Eq("user_id", request.UserID).OrEq("vehicle_id", request.VehicleID),
+			),
+		).Result(0)
+		repository.ExpectUpdateAny(
+			rel.From("vehicles").Where(where.Eq("id", uint(1)).AndEq("status", vehicles.StatusAvailable).AndGte("battery", batteries.DefaultLevels.Start)),
+			rel.Set("status", vehicles.StatusInRide),
+			rel.Set("updated_at", reltest.Any),
+		).UpdatedCount(0)
+		repository.ExpectFind(where.Eq("id", uint(1))).Result(vehicles.Vehicle{ID: 1, Type: vehicles.TypeScooter, Model: "Ninebot Max", Status: vehicles.StatusMaintenance})
+	})
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusConflict, rr.Code)
+
+	var resp handlers.ErrResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Errorf("Error decoding response body: %v", err)
+	}
+	assert.Equal(t, vehicles.ErrVehicleNotAvailable.Error(), resp.ErrorText)
+
+	repository.AssertExpectations(t)
+}
+
+func TestStartRideBatteryTooLow(t *testing.T) {
+	var (
+		request    = handlers.RideRequest{UserID: "1", VehicleID: "1"}
+		body, _    = json.Marshal(request)
+		req, _     = http.NewRequest("POST", "/", bytes.NewBuffer(body))
+		rr         = httptest.NewRecorder()
+		repository = reltest.New()
+		service    = newRides(repository)
+		handler    = handlers.NewRidesHandler(service)
+	)
+	req.Header.Add("Content-Type", "application/json")
+	repository.ExpectFind(where.Eq("id", uint(1))).Result(rider)
+	repository.ExpectFind(where.Eq("id", uint(1))).Result(scooter)
+
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectCount("rides",
+			rel.In("status", rides.StatusReserved, rides.StatusActive, rides.StatusPaused).And(
+				rel.Eq("user_id", request.UserID).OrEq("vehicle_id", request.VehicleID),
+			),
+		).Result(0)
+		repository.ExpectUpdateAny(
+			rel.From("vehicles").Where(where.Eq("id", uint(1)).AndEq("status", vehicles.StatusAvailable).AndGte("battery", batteries.DefaultLevels.Start)),
+			rel.Set("status", vehicles.StatusInRide),
+			rel.Set("updated_at", reltest.Any),
+		).UpdatedCount(0)
+		repository.ExpectFind(where.Eq("id", uint(1))).Result(vehicles.Vehicle{ID: 1, Type: vehicles.TypeScooter, Model: "Ninebot Max", Status: vehicles.StatusAvailable, Battery: 12})
+	})
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+
+	var resp handlers.ErrResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Errorf("Error decoding response body: %v", err)
+	}
+	assert.Equal(t, vehicles.ErrBatteryTooLow.Error(), resp.ErrorText)
+
+	repository.AssertExpectations(t)
+}
+
+func TestStartRideVehicleUnreachable(t *testing.T) {
+	var (
+		request    = handlers.RideRequest{UserID: "1", VehicleID: "1"}
+		body, _    = json.Marshal(request)
+		req, _     = http.NewRequest("POST", "/", bytes.NewBuffer(body))
+		rr         = httptest.NewRecorder()
+		repository = reltest.New()
+		simulator  = iot.NewSimulator()
+		gateway    = iot.NewRetrying(simulator, iot.Policy{Timeout: 10 * time.Millisecond, Attempts: 2})
+		service    = newRides(repository, func(d *rides.Deps) { d.Gateway = gateway })
+		handler    = handlers.NewRidesHandler(service)
+	)
+	simulator.Delay(time.Second)
+	req.Header.Add("Content-Type", "application/json")
+	repository.ExpectFind(where.Eq("id", uint(1))).Result(rider)
+	repository.ExpectFind(where.Eq("id", uint(1))).Result(scooter)
+
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectCount("rides",
+			rel.In("status", rides.StatusReserved, rides.StatusActive, rides.StatusPaused).And(
+				rel.Eq("user_id", request.UserID).OrEq("vehicle_id", request.VehicleID),
+			),
+		).Result(0)
+		repository.ExpectUpdateAny(
+			rel.From("vehicles").Where(where.Eq("id", uint(1)).AndEq("status", vehicles.StatusAvailable).AndGte("battery", batteries.DefaultLevels.Start)),
+			rel.Set("status", vehicles.StatusInRide),
+			rel.Set("updated_at", reltest.Any),
+		).UpdatedCount(1)
+		repository.ExpectInsert().ForType("*rides.Ride")
+		repository.ExpectInsert().ForType("*rides.Transition")
+	})
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadGateway, rr.Code)
+
+	var resp handlers.ErrResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Errorf("Error decoding response body: %v", err)
+	}
+	assert.Equal(t, iot.ErrTimeout.Error(), resp.ErrorText)
+	assert.False(t, simulator.Unlocked("1"))
+
+	repository.AssertExpectations(t)
+}
+
+func TestStartRideWithPromoCode(t *testing.T) {
+	var (
+		request    = handlers.RideRequest{UserID: "1", VehicleID: "1", PromoCode: "spring"}
+		body, _    = json.Marshal(request)
+		req, _     = http.NewRequest("POST", "/", bytes.NewBuffer(body))
+		rr         = httptest.NewRecorder()
+		repository = reltest.New()
+		service    = newRides(repository)
+		handler    = handlers.NewRidesHandler(service)
+		promo      = promos.Promo{ID: 3, Code: "SPRING", Kind: pricing.DiscountFreeUnlock, MaxRedemptions: 10}
+	)
+	req.Header.Add("Content-Type", "application/json")
+	repository.ExpectFind(where.Eq("id", uint(1))).Result(rider)
+	repository.ExpectFind(where.Eq("id", uint(1))).Result(scooter)
+
+	repository.ExpectFind(where.Eq("code", "SPRING")).Result(promo)
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectCount("rides",
+			rel.In("status", rides.StatusReserved, rides.StatusActive, rides.StatusPaused).And(
+				rel.Eq("user_id", request.UserID).OrEq("vehicle_id", request.VehicleID),
+			),
+		).Result(0)
+		repository.ExpectUpdateAny(
+			rel.From("vehicles").Where(where.Eq("id", uint(1)).AndEq("status", vehicles.StatusAvailable).AndGte("battery", batteries.DefaultLevels.Start)),
+			rel.Set("status", vehicles.StatusInRide),
+			rel.Set("updated_at", reltest.Any),
+		).UpdatedCount(1)
+		repository.ExpectInsert().ForType("*rides.Ride")
+		repository.ExpectFind(where.Eq("code", "SPRING")).Result(promo)
+		repository.ExpectUpdateAny(
+			rel.From("promos").Where(
+				where.Eq("id", promo.ID).And(
+					where.Eq("max_redemptions", 0).Or(where.Fragment("redemptions < max_redemptions")),
+				),
+			),
+			rel.Inc("redemptions"),
+		).UpdatedCount(1)
+		repository.ExpectInsert().ForType("*promos.Redemption")
+		repository.ExpectInsert().ForType("*rides.Transition")
+	})
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+
+	var ride rides.Ride
+	if err := json.NewDecoder(rr.Body).Decode(&ride); err != nil {
+		t.Errorf("Error decoding response body: %v", err)
+	}
+	assert.Equal(t, "SPRING", ride.PromoCode)
+	assert.Equal(t, &pricing.Discount{Code: "SPRING", Kind: pricing.DiscountFreeUnlock}, ride.Discount)
+
+	repository.AssertExpectations(t)
+}
+
+func TestStartRideWithExhaustedPromoCode(t *testing.T) {
+	var (
+		request    = handlers.RideRequest{UserID: "1", VehicleID: "1", PromoCode: "SPRING"}
+		body, _    = json.Marshal(request)
+		req, _     = http.NewRequest("POST", "/", bytes.NewBuffer(body))
+		rr         = httptest.NewRecorder()
+		repository = reltest.New()
+		service    = newRides(repository)
+		handler    = handlers.NewRidesHandler(service)
+		promo      = promos.Promo{ID: 3, Code: "SPRING", Kind: pricing.DiscountFreeUnlock, MaxRedemptions: 10, Redemptions: 10}
+	)
+	req.Header.Add("Content-Type", "application/json")
+	repository.ExpectFind(where.Eq("id", uint(1))).Result(rider)
+	repository.ExpectFind(where.Eq("id", uint(1))).Result(scooter)
+
+	repository.ExpectFind(where.Eq("code", "SPRING")).Result(promo)
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+
+	var resp handlers.ErrResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err == nil {
+		assert.Equal(t, promos.ErrPromoExhausted.Error(), resp.ErrorText)
+	}
+
+	repository.AssertExpectations(t)
 }
 
 func TestStartRideBadRequestVehicle(t *testing.T) {
@@ -59,8 +375,8 @@ func TestStartRideBadRequestVehicle(t *testing.T) {
 		req, _     = http.NewRequest("POST", "/", bytes.NewBuffer(body))
 		rr         = httptest.NewRecorder()
 		repository = reltest.New()
-		service    = rides.New(repository)
-		handler    = handlers.NewRidesHandler(repository, service)
+		service    = newRides(repository)
+		handler    = handlers.NewRidesHandler(service)
 	)
 	req.Header.Add("Content-Type", "application/json")
 
@@ -82,8 +398,8 @@ func TestStartRideBadRequestUser(t *testing.T) {
 		req, _     = http.NewRequest("POST", "/", bytes.NewBuffer(body))
 		rr         = httptest.NewRecorder()
 		repository = reltest.New()
-		service    = rides.New(repository)
-		handler    = handlers.NewRidesHandler(repository, service)
+		service    = newRides(repository)
+		handler    = handlers.NewRidesHandler(service)
 	)
 	req.Header.Add("Content-Type", "application/json")
 
@@ -105,45 +421,66 @@ func TestStartRideAlreadyStarted(t *testing.T) {
 		req, _     = http.NewRequest("POST", "/", bytes.NewBuffer(body))
 		rr         = httptest.NewRecorder()
 		repository = reltest.New()
-		service    = rides.New(repository)
-		handler    = handlers.NewRidesHandler(repository, service)
+		service    = newRides(repository)
+		handler    = handlers.NewRidesHandler(service)
 	)
 	req.Header.Add("Content-Type", "application/json")
+	repository.ExpectFind(where.Eq("id", uint(1))).Result(rider)
+	repository.ExpectFind(where.Eq("id", uint(1))).Result(scooter)
 
-	repository.ExpectCount("rides",
-		rel.Eq("finished", false).And(
-			rel.Eq("user_id", request.UserID).OrEq("vehicle_id", request.VehicleID),
-		),
-	).Result(1)
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectCount("rides",
+			rel.In("status", rides.StatusReserved, rides.StatusActive, rides.StatusPaused).And(
+				rel.Eq("user_id", request.UserID).OrEq("vehicle_id", request.VehicleID),
+			),
+		).Result(1)
+	})
 
 	handler.ServeHTTP(rr, req)
 
-	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	assert.Equal(t, http.StatusConflict, rr.Code)
 
 	var resp handlers.ErrResponse
 	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
-		assert.Equal(t, resp.StatusText, "Error while starting ride.")
-		assert.Equal(t, resp.ErrorText, "A ride is already started for this vehicle or user")
+		t.Errorf("Error decoding response body: %v", err)
 	}
+	assert.Equal(t, "A ride is already started for this vehicle or user", resp.ErrorText)
 }
 
 func TestFinishRide(t *testing.T) {
 	var (
 		rideID      = uint(1)
 		now         = time.Now()
-		startedRide = rides.Ride{ID: rideID, VehicleID: "1", UserID: "1", Price: 18, Finished: false, CreatedAt: now, UpdatedAt: now}
+		startedRide = rides.Ride{ID: rideID, VehicleID: "1", UserID: "1", Price: eur(18), Status: rides.StatusActive, CreatedAt: now, UpdatedAt: now, Tariff: tariff}
 		path        = fmt.Sprintf("/%d/finish", rideID)
 		req, _      = http.NewRequest("POST", path, nil)
 		rr          = httptest.NewRecorder()
 		repository  = reltest.New()
-		service     = rides.New(repository)
-		handler     = handlers.NewRidesHandler(repository, service)
+		service     = newRides(repository)
+		handler     = handlers.NewRidesHandler(service)
 	)
 	req.Header.Add("Content-Type", "application/json")
 
-	repository.ExpectFind(where.Eq("id", "1")).Result(startedRide)
-	repository.ExpectCount("rides", rel.And(rel.Eq("id", rideID), rel.Eq("finished", true))).Result(0)
-	repository.ExpectUpdate().ForType("*rides.Ride")
+	repository.ExpectFind(where.Eq("id", rideID)).Result(startedRide)
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectFindAll(where.Eq("ride_id", rideID)).Result([]rides.Pause{})
+		repository.ExpectUpdateAny(
+			rel.From("rides").Where(where.Eq("id", rideID).AndEq("status", rides.StatusActive)),
+			rel.Set("status", rides.StatusFinished),
+			rel.Set("updated_at", reltest.Any),
+			rel.Set("price_amount", reltest.Any),
+			rel.Set("price_currency", reltest.Any),
+			rel.Set("net_amount", reltest.Any),
+			rel.Set("tax_amount", reltest.Any),
+			rel.Set("items", reltest.Any),
+		).UpdatedCount(1)
+		repository.ExpectInsert().ForType("*rides.Transition")
+		repository.ExpectUpdateAny(
+			rel.From("vehicles").Where(where.Eq("id", uint(1)).AndEq("status", vehicles.StatusInRide)),
+			rel.Set("status", vehicles.StatusAvailable),
+			rel.Set("updated_at", reltest.Any),
+		).UpdatedCount(1)
+	})
 
 	handler.ServeHTTP(rr, req)
 
@@ -153,9 +490,10 @@ func TestFinishRide(t *testing.T) {
 	if err := json.NewDecoder(rr.Body).Decode(&ride); err != nil {
 		t.Errorf("Error decoding response body: %v", err)
 	}
-	assert.Greater(t, ride.Price, utils.GetEnvAsInt("RIDE_INITIAL_PRICE", 18))
-	assert.Less(t, ride.Price, 218)
-	assert.True(t, ride.Finished)
+	assert.Equal(t, tariff.Currency, ride.Price.Currency)
+	assert.Greater(t, ride.Price.Amount, int64(tariff.UnlockPrice))
+	assert.Less(t, ride.Price.Amount, int64(218))
+	assert.Equal(t, rides.StatusFinished, ride.Status)
 }
 
 func TestFinishRideAlreadyFinished(t *testing.T) {
@@ -165,23 +503,660 @@ func TestFinishRideAlreadyFinished(t *testing.T) {
 		req, _     = http.NewRequest("POST", path, nil)
 		rr         = httptest.NewRecorder()
 		repository = reltest.New()
-		service    = rides.New(repository)
-		handler    = handlers.NewRidesHandler(repository, service)
+		service    = newRides(repository)
+		handler    = handlers.NewRidesHandler(service)
 	)
 	req.Header.Add("Content-Type", "application/json")
 
-	startedRide := rides.Ride{ID: rideID, VehicleID: "1", UserID: "1", Price: 18, Finished: false}
-	repository.ExpectFind(where.Eq("id", "1")).Result(startedRide)
-	repository.ExpectCount("rides", rel.And(rel.Eq("id", rideID), rel.Eq("finished", true))).Result(1)
-	repository.ExpectUpdate().ForType("*rides.Ride")
+	startedRide := rides.Ride{ID: rideID, VehicleID: "1", UserID: "1", Price: eur(18), Status: rides.StatusActive}
+	repository.ExpectFind(where.Eq("id", rideID)).Result(startedRide)
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectFindAll(where.Eq("ride_id", rideID)).Result([]rides.Pause{})
+		repository.ExpectUpdateAny(
+			rel.From("rides").Where(where.Eq("id", rideID).AndEq("status", rides.StatusActive)),
+			rel.Set("status", rides.StatusFinished),
+			rel.Set("updated_at", reltest.Any),
+			rel.Set("price_amount", reltest.Any),
+			rel.Set("price_currency", reltest.Any),
+			rel.Set("net_amount", reltest.Any),
+			rel.Set("tax_amount", reltest.Any),
+			rel.Set("items", reltest.Any),
+		).UpdatedCount(0)
+	})
+	repository.ExpectFind(where.Eq("id", rideID)).Result(rides.Ride{ID: rideID, Status: rides.StatusFinished})
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusConflict, rr.Code)
+
+	var resp handlers.ErrResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Errorf("Error decoding response body: %v", err)
+	}
+	assert.Equal(t, "This ride is already finished", resp.ErrorText)
+}
+
+func TestFinishRideNotFound(t *testing.T) {
+	var (
+		req, _     = http.NewRequest("POST", "/2/finish", nil)
+		rr         = httptest.NewRecorder()
+		repository = reltest.New()
+		service    = newRides(repository)
+		handler    = handlers.NewRidesHandler(service)
+	)
+
+	repository.ExpectFind(where.Eq("id", uint(2))).NotFound()
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+
+	var resp handlers.ErrResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Errorf("Error decoding response body: %v", err)
+	}
+	assert.Equal(t, "No ride matches the given ID", resp.ErrorText)
+
+	repository.AssertExpectations(t)
+}
+
+func TestQuoteRide(t *testing.T) {
+	var (
+		rideID     = uint(1)
+		createdAt  = time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+		ride       = rides.Ride{ID: rideID, VehicleID: "1", UserID: "1", Price: eur(18), Status: rides.StatusActive, CreatedAt: createdAt, UpdatedAt: createdAt, Tariff: tariff}
+		path       = fmt.Sprintf("/%d/quote?at=%s", rideID, createdAt.Add(10*time.Minute+30*time.Second).Format(time.RFC3339))
+		req, _     = http.NewRequest("GET", path, nil)
+		rr         = httptest.NewRecorder()
+		repository = reltest.New()
+		service    = newRides(repository)
+		handler    = handlers.NewRidesHandler(service)
+	)
+
+	repository.ExpectFind(where.Eq("id", rideID)).Result(ride)
+	repository.ExpectFindAll(where.Eq("ride_id", rideID)).Result([]rides.Pause{})
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var quote rides.Quote
+	if err := json.NewDecoder(rr.Body).Decode(&quote); err != nil {
+		t.Errorf("Error decoding response body: %v", err)
+	}
+	assert.Equal(t, pricing.UnitMinute, quote.Unit)
+	assert.Equal(t, 11, quote.RidingUnits)
+	assert.Equal(t, eur(1118), quote.Price)
+	assert.Equal(t, eur(1118), quote.Net)
+	assert.Equal(t, eur(0), quote.Tax)
+	assert.Len(t, quote.Items, 2)
+	assert.Equal(t, eur(18), quote.Items[0].Amount)
+	assert.Equal(t, eur(1100), quote.Items[1].Amount)
+
+	repository.AssertExpectations(t)
+}
+
+func TestQuoteRideFinished(t *testing.T) {
+	var (
+		rideID     = uint(1)
+		ride       = rides.Ride{ID: rideID, VehicleID: "1", UserID: "1", Price: eur(118), Status: rides.StatusFinished, Tariff: tariff}
+		req, _     = http.NewRequest("GET", "/1/quote", nil)
+		rr         = httptest.NewRecorder()
+		repository = reltest.New()
+		service    = newRides(repository)
+		handler    = handlers.NewRidesHandler(service)
+	)
+
+	repository.ExpectFind(where.Eq("id", rideID)).Result(ride)
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusConflict, rr.Code)
+
+	repository.AssertExpectations(t)
+}
+
+func TestQuoteRideInvalidAt(t *testing.T) {
+	var (
+		req, _     = http.NewRequest("GET", "/1/quote?at=tomorrow", nil)
+		rr         = httptest.NewRecorder()
+		repository = reltest.New()
+		service    = newRides(repository)
+		handler    = handlers.NewRidesHandler(service)
+	)
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestCancelRide(t *testing.T) {
+	var (
+		rideID     = uint(1)
+		ride       = rides.Ride{ID: rideID, VehicleID: "1", UserID: "1", Price: eur(18), Status: rides.StatusActive, Tariff: tariff, CreatedAt: time.Now()}
+		req, _     = http.NewRequest("POST", "/1/cancel", nil)
+		rr         = httptest.NewRecorder()
+		repository = reltest.New()
+		service    = newRides(repository)
+		handler    = handlers.NewRidesHandler(service)
+	)
+
+	repository.ExpectFind(where.Eq("id", rideID)).Result(ride)
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectUpdateAny(
+			rel.From("rides").Where(where.Eq("id", rideID).AndEq("status", rides.StatusActive)),
+			rel.Set("status", rides.StatusCancelled),
+			rel.Set("updated_at", reltest.Any),
+			rel.Set("price_amount", int64(0)),
+			rel.Set("net_amount", int64(0)),
+			rel.Set("tax_amount", int64(0)),
+			rel.Set("items", reltest.Any),
+		).UpdatedCount(1)
+		repository.ExpectInsert().ForType("*rides.Transition")
+		repository.ExpectUpdateAny(
+			rel.From("vehicles").Where(where.Eq("id", uint(1)).AndEq("status", vehicles.StatusInRide)),
+			rel.Set("status", vehicles.StatusAvailable),
+			rel.Set("updated_at", reltest.Any),
+		).UpdatedCount(1)
+	})
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var cancelled rides.Ride
+	if err := json.NewDecoder(rr.Body).Decode(&cancelled); err != nil {
+		t.Errorf("Error decoding response body: %v", err)
+	}
+	assert.Equal(t, rides.StatusCancelled, cancelled.Status)
+	assert.Equal(t, eur(0), cancelled.Price)
+
+	repository.AssertExpectations(t)
+}
+
+func TestCancelRideTooLate(t *testing.T) {
+	var (
+		rideID     = uint(1)
+		ride       = rides.Ride{ID: rideID, VehicleID: "1", UserID: "1", Price: eur(18), Status: rides.StatusActive, Tariff: tariff, CreatedAt: time.Now().Add(-time.Hour)}
+		req, _     = http.NewRequest("POST", "/1/cancel", nil)
+		rr         = httptest.NewRecorder()
+		repository = reltest.New()
+		service    = newRides(repository)
+		handler    = handlers.NewRidesHandler(service)
+	)
+
+	repository.ExpectFind(where.Eq("id", rideID)).Result(ride)
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusConflict, rr.Code)
+
+	var resp handlers.ErrResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Errorf("Error decoding response body: %v", err)
+	}
+	assert.Equal(t, rides.ErrCancelTooLate.Error(), resp.ErrorText)
+
+	repository.AssertExpectations(t)
+}
+
+func TestCaptureRideNotPending(t *testing.T) {
+	var (
+		rideID     = uint(1)
+		ride       = rides.Ride{ID: rideID, VehicleID: "1", UserID: "1", Price: eur(118), Status: rides.StatusFinished, Tariff: tariff, PaymentID: "fake_1", PaymentStatus: payments.StatusCaptured}
+		req, _     = http.NewRequest("POST", "/1/capture", nil)
+		rr         = httptest.NewRecorder()
+		repository = reltest.New()
+		service    = newRides(repository)
+		handler    = handlers.NewRidesHandler(service)
+	)
+
+	repository.ExpectFind(where.Eq("id", rideID)).Result(ride)
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusConflict, rr.Code)
+
+	repository.AssertExpectations(t)
+}
+
+func TestGetRide(t *testing.T) {
+	var (
+		rideID     = uint(1)
+		ride       = rides.Ride{ID: rideID, VehicleID: "1", UserID: "1", Price: eur(18)}
+		path       = fmt.Sprintf("/%d", rideID)
+		req, _     = http.NewRequest("GET", path, nil)
+		rr         = httptest.NewRecorder()
+		repository = reltest.New()
+		service    = newRides(repository)
+		handler    = handlers.NewRidesHandler(service)
+	)
+
+	repository.ExpectFind(where.Eq("id", rideID)).Result(ride)
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var foundRide rides.Ride
+	if err := json.NewDecoder(rr.Body).Decode(&foundRide); err != nil {
+		t.Errorf("Error decoding response body: %v", err)
+	}
+	assert.Equal(t, rideID, foundRide.ID)
+	assert.Equal(t, eur(18), foundRide.Price)
+}
+
+func TestGetRideNotFound(t *testing.T) {
+	var (
+		req, _     = http.NewRequest("GET", "/2", nil)
+		rr         = httptest.NewRecorder()
+		repository = reltest.New()
+		service    = newRides(repository)
+		handler    = handlers.NewRidesHandler(service)
+	)
+
+	repository.ExpectFind(where.Eq("id", uint(2))).NotFound()
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+
+	var resp handlers.ErrResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Errorf("Error decoding response body: %v", err)
+	}
+	assert.Equal(t, "No ride matches the given ID", resp.ErrorText)
+}
+
+func TestGetRideInvalidID(t *testing.T) {
+	var (
+		req, _     = http.NewRequest("GET", "/abc", nil)
+		rr         = httptest.NewRecorder()
+		repository = reltest.New()
+		service    = newRides(repository)
+		handler    = handlers.NewRidesHandler(service)
+	)
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestListRides(t *testing.T) {
+	var (
+		req, _     = http.NewRequest("GET", "/?user_id=1&status=active&limit=1", nil)
+		rr         = httptest.NewRecorder()
+		repository = reltest.New()
+		service    = newRides(repository)
+		handler    = handlers.NewRidesHandler(service)
+		now        = time.Now().UTC()
+	)
+
+	repository.ExpectFindAll(
+		rel.From("rides").Where(
+			where.Eq("user_id", "1"),
+			where.Eq("status", rides.StatusActive),
+		).SortDesc("created_at", "id").Limit(2),
+	).Result([]rides.Ride{
+		{ID: 2, UserID: "1", VehicleID: "2", CreatedAt: now},
+		{ID: 1, UserID: "1", VehicleID: "1", CreatedAt: now.Add(-time.Hour)},
+	})
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var page rides.Page
+	if err := json.NewDecoder(rr.Body).Decode(&page); err != nil {
+		t.Errorf("Error decoding response body: %v", err)
+	}
+	assert.Len(t, page.Rides, 1)
+	assert.Equal(t, uint(2), page.Rides[0].ID)
+	assert.NotEmpty(t, page.NextCursor)
+
+	repository.AssertExpectations(t)
+}
+
+func TestListRidesBadRequest(t *testing.T) {
+	for _, query := range []string{
+		"status=unknown",
+		"created_from=yesterday",
+		"created_to=2022-06-20",
+		"limit=0",
+		"cursor=bad",
+	} {
+		var (
+			req, _     = http.NewRequest("GET", "/?"+query, nil)
+			rr         = httptest.NewRecorder()
+			repository = reltest.New()
+			service    = newRides(repository)
+			handler    = handlers.NewRidesHandler(service)
+		)
+
+		handler.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code, query)
+	}
+}
+
+func TestPauseRide(t *testing.T) {
+	var (
+		rideID      = uint(1)
+		now         = time.Now()
+		startedRide = rides.Ride{ID: rideID, VehicleID: "1", UserID: "1", Price: eur(18), Status: rides.StatusActive, CreatedAt: now, UpdatedAt: now, Tariff: tariff}
+		path        = fmt.Sprintf("/%d/pause", rideID)
+		req, _      = http.NewRequest("POST", path, nil)
+		rr          = httptest.NewRecorder()
+		repository  = reltest.New()
+		service     = newRides(repository)
+		handler     = handlers.NewRidesHandler(service)
+	)
+
+	repository.ExpectFind(where.Eq("id", rideID)).Result(startedRide)
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectUpdateAny(
+			rel.From("rides").Where(where.Eq("id", rideID).AndEq("status", rides.StatusActive)),
+			rel.Set("status", rides.StatusPaused),
+			rel.Set("updated_at", reltest.Any),
+		).UpdatedCount(1)
+		repository.ExpectInsert().ForType("*rides.Transition")
+		repository.ExpectInsert().ForType("*rides.Pause")
+	})
 
 	handler.ServeHTTP(rr, req)
 
-	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var ride rides.Ride
+	if err := json.NewDecoder(rr.Body).Decode(&ride); err != nil {
+		t.Errorf("Error decoding response body: %v", err)
+	}
+	assert.Equal(t, rides.StatusPaused, ride.Status)
+	assert.Equal(t, eur(18), ride.Price)
+}
+
+func TestPauseRideNotActive(t *testing.T) {
+	var (
+		rideID     = uint(1)
+		path       = fmt.Sprintf("/%d/pause", rideID)
+		req, _     = http.NewRequest("POST", path, nil)
+		rr         = httptest.NewRecorder()
+		repository = reltest.New()
+		service    = newRides(repository)
+		handler    = handlers.NewRidesHandler(service)
+	)
+
+	finishedRide := rides.Ride{ID: rideID, VehicleID: "1", UserID: "1", Price: eur(118), Status: rides.StatusFinished}
+	repository.ExpectFind(where.Eq("id", rideID)).Result(finishedRide)
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusConflict, rr.Code)
 
 	var resp handlers.ErrResponse
 	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
-		assert.Equal(t, resp.StatusText, "Error while finishing ride.")
-		assert.Equal(t, resp.ErrorText, "This ride is already finished")
+		t.Errorf("Error decoding response body: %v", err)
+	}
+	assert.Equal(t, `A ride can't go from "finished" to "paused"`, resp.ErrorText)
+}
+
+func TestResumeRide(t *testing.T) {
+	var (
+		rideID     = uint(1)
+		now        = time.Now()
+		pausedRide = rides.Ride{ID: rideID, VehicleID: "1", UserID: "1", Price: eur(18), Status: rides.StatusPaused, CreatedAt: now, UpdatedAt: now}
+		path       = fmt.Sprintf("/%d/resume", rideID)
+		req, _     = http.NewRequest("POST", path, nil)
+		rr         = httptest.NewRecorder()
+		repository = reltest.New()
+		service    = newRides(repository)
+		handler    = handlers.NewRidesHandler(service)
+	)
+
+	repository.ExpectFind(where.Eq("id", rideID)).Result(pausedRide)
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectUpdateAny(
+			rel.From("rides").Where(where.Eq("id", rideID).AndEq("status", rides.StatusPaused)),
+			rel.Set("status", rides.StatusActive),
+			rel.Set("updated_at", reltest.Any),
+		).UpdatedCount(1)
+		repository.ExpectInsert().ForType("*rides.Transition")
+		repository.ExpectUpdateAny(
+			rel.From("ride_pauses").Where(where.Eq("ride_id", rideID).AndNil("ended_at")),
+			rel.Set("ended_at", reltest.Any),
+		).UpdatedCount(1)
+	})
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var ride rides.Ride
+	if err := json.NewDecoder(rr.Body).Decode(&ride); err != nil {
+		t.Errorf("Error decoding response body: %v", err)
 	}
+	assert.Equal(t, rides.StatusActive, ride.Status)
+}
+
+func TestResumeRideNotPaused(t *testing.T) {
+	var (
+		rideID     = uint(1)
+		path       = fmt.Sprintf("/%d/resume", rideID)
+		req, _     = http.NewRequest("POST", path, nil)
+		rr         = httptest.NewRecorder()
+		repository = reltest.New()
+		service    = newRides(repository)
+		handler    = handlers.NewRidesHandler(service)
+	)
+
+	activeRide := rides.Ride{ID: rideID, VehicleID: "1", UserID: "1", Price: eur(18), Status: rides.StatusActive}
+	repository.ExpectFind(where.Eq("id", rideID)).Result(activeRide)
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusConflict, rr.Code)
+
+	var resp handlers.ErrResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Errorf("Error decoding response body: %v", err)
+	}
+	assert.Equal(t, `A ride can't go from "active" to "active"`, resp.ErrorText)
+}
+
+// capturedRide is a finished ride charged 4.18 € with a card on the
+// provider.
+func capturedRide(provider *payments.Fake) rides.Ride {
+	id, _ := provider.Authorize(context.TODO(), "1", eur(3000))
+	_ = provider.Capture(context.TODO(), id, eur(418), "ride-1-capture")
+	return rides.Ride{ID: 1, VehicleID: "1", UserID: "1", Price: eur(418), Status: rides.StatusFinished, Tariff: tariff, PaymentID: id, HoldAmount: 3000, PaymentStatus: payments.StatusCaptured}
+}
+
+func expectRefundAdjustment(repository *reltest.Repository) {
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectUpdateAny(
+			rel.From("rides").Where(where.Eq("id", uint(1)).AndIn("status", rides.StatusFinished, rides.StatusForceClosed).AndGte("adjustments_amount", int64(-218))),
+			rel.IncBy("adjustments_amount", -200),
+			rel.Set("updated_at", reltest.Any),
+		).UpdatedCount(1)
+		repository.ExpectInsert().ForType("*rides.Adjustment")
+		repository.ExpectInsert().ForType("*audit.Record")
+	})
+}
+
+func expectAdjustmentSettled(repository *reltest.Repository, adjustmentID uint) {
+	repository.ExpectUpdateAny(
+		rel.From("ride_adjustments").Where(where.Eq("id", adjustmentID).AndEq("status", rides.AdjustmentPending)),
+		rel.Set("status", rides.AdjustmentSettled),
+		rel.Set("settled_at", reltest.Any),
+	).UpdatedCount(1)
+}
+
+func TestAdjustRide(t *testing.T) {
+	var (
+		rideID     = uint(1)
+		provider   = payments.NewFake()
+		ride       = capturedRide(provider)
+		request    = handlers.AdjustmentRequest{Amount: -200, Reason: rides.ReasonVehicleIssue, Agent: "42"}
+		body, _    = json.Marshal(request)
+		req, _     = http.NewRequest("POST", "/1/adjustments", bytes.NewBuffer(body))
+		rr         = httptest.NewRecorder()
+		repository = reltest.New()
+		service    = newRides(repository, func(d *rides.Deps) { d.Payments = provider })
+		handler    = handlers.NewRidesHandler(service)
+	)
+	req.Header.Add("Content-Type", "application/json")
+
+	repository.ExpectFind(where.Eq("id", rideID)).Result(ride)
+	expectRefundAdjustment(repository)
+	expectAdjustmentSettled(repository, 1)
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+
+	var resp handlers.AdjustmentResponse
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Errorf("Error decoding response body: %v", err)
+	}
+	assert.NotEmpty(t, resp.ID)
+	assert.Equal(t, int64(-200), resp.Amount)
+	assert.Equal(t, rides.AdjustmentSettled, resp.Status)
+	assert.Equal(t, eur(218), resp.EffectivePrice)
+
+	repository.AssertExpectations(t)
+}
+
+func TestAdjustRideRefundFails(t *testing.T) {
+	var (
+		rideID     = uint(1)
+		provider   = payments.NewFake()
+		ride       = capturedRide(provider)
+		request    = handlers.AdjustmentRequest{Amount: -200, Reason: rides.ReasonVehicleIssue, Agent: "42"}
+		body, _    = json.Marshal(request)
+		req, _     = http.NewRequest("POST", "/1/adjustments", bytes.NewBuffer(body))
+		rr         = httptest.NewRecorder()
+		repository = reltest.New()
+		service    = newRides(repository, func(d *rides.Deps) { d.Payments = provider })
+		handler    = handlers.NewRidesHandler(service)
+	)
+	req.Header.Add("Content-Type", "application/json")
+	provider.FailRefunds(errors.New("payment provider unavailable"))
+
+	repository.ExpectFind(where.Eq("id", rideID)).Result(ride)
+	expectRefundAdjustment(repository)
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusAccepted, rr.Code)
+
+	var resp handlers.AdjustmentResponse
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Errorf("Error decoding response body: %v", err)
+	}
+	assert.Equal(t, rides.AdjustmentPending, resp.Status)
+
+	repository.AssertExpectations(t)
+}
+
+func TestSettleAdjustment(t *testing.T) {
+	var (
+		rideID     = uint(1)
+		provider   = payments.NewFake()
+		ride       = capturedRide(provider)
+		adjustment = rides.Adjustment{ID: 3, RideID: rideID, Amount: -200, Currency: "EUR", Reason: rides.ReasonOvercharge, Agent: "42", Status: rides.AdjustmentPending}
+		req, _     = http.NewRequest("POST", "/1/adjustments/3/settle", nil)
+		rr         = httptest.NewRecorder()
+		repository = reltest.New()
+		service    = newRides(repository, func(d *rides.Deps) { d.Payments = provider })
+		handler    = handlers.NewRidesHandler(service)
+	)
+
+	repository.ExpectFind(where.Eq("id", rideID)).Result(ride)
+	repository.ExpectFind(where.Eq("id", uint(3)).AndEq("ride_id", rideID)).Result(adjustment)
+	expectAdjustmentSettled(repository, 3)
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var resp handlers.AdjustmentResponse
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Errorf("Error decoding response body: %v", err)
+	}
+	assert.Equal(t, rides.AdjustmentSettled, resp.Status)
+
+	hold, _ := provider.Hold(ride.PaymentID)
+	assert.Equal(t, eur(200), hold.Refunded)
+
+	repository.AssertExpectations(t)
+}
+
+func TestSettleAdjustmentNotPending(t *testing.T) {
+	var (
+		rideID     = uint(1)
+		provider   = payments.NewFake()
+		ride       = capturedRide(provider)
+		adjustment = rides.Adjustment{ID: 3, RideID: rideID, Amount: -200, Currency: "EUR", Reason: rides.ReasonOvercharge, Agent: "42", Status: rides.AdjustmentSettled}
+		req, _     = http.NewRequest("POST", "/1/adjustments/3/settle", nil)
+		rr         = httptest.NewRecorder()
+		repository = reltest.New()
+		service    = newRides(repository, func(d *rides.Deps) { d.Payments = provider })
+		handler    = handlers.NewRidesHandler(service)
+	)
+
+	repository.ExpectFind(where.Eq("id", rideID)).Result(ride)
+	repository.ExpectFind(where.Eq("id", uint(3)).AndEq("ride_id", rideID)).Result(adjustment)
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusConflict, rr.Code)
+
+	repository.AssertExpectations(t)
+}
+
+func TestAdjustRideNotFinished(t *testing.T) {
+	var (
+		rideID     = uint(1)
+		ride       = rides.Ride{ID: rideID, VehicleID: "1", UserID: "1", Price: eur(18), Status: rides.StatusActive, Tariff: tariff}
+		request    = handlers.AdjustmentRequest{Amount: -18, Reason: rides.ReasonGoodwill, Agent: "42"}
+		body, _    = json.Marshal(request)
+		req, _     = http.NewRequest("POST", "/1/adjustments", bytes.NewBuffer(body))
+		rr         = httptest.NewRecorder()
+		repository = reltest.New()
+		service    = newRides(repository)
+		handler    = handlers.NewRidesHandler(service)
+	)
+	req.Header.Add("Content-Type", "application/json")
+
+	repository.ExpectFind(where.Eq("id", rideID)).Result(ride)
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusConflict, rr.Code)
+
+	repository.AssertExpectations(t)
+}
+
+func TestAdjustRideWithoutAgent(t *testing.T) {
+	var (
+		request    = handlers.AdjustmentRequest{Amount: -18, Reason: rides.ReasonGoodwill}
+		body, _    = json.Marshal(request)
+		req, _     = http.NewRequest("POST", "/1/adjustments", bytes.NewBuffer(body))
+		rr         = httptest.NewRecorder()
+		repository = reltest.New()
+		service    = newRides(repository)
+		handler    = handlers.NewRidesHandler(service)
+	)
+	req.Header.Add("Content-Type", "application/json")
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	var resp handlers.ErrResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err == nil {
+		assert.Equal(t, handlers.ErrAdjustmentAgentBlank.Error(), resp.ErrorText)
+	}
+
+	repository.AssertExpectations(t)
 }
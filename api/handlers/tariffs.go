@@ -0,0 +1,377 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"backend/money"
+	"backend/pricing"
+	"backend/tariffs"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+type Tariffs struct {
+	*chi.Mux
+	tariffs tariffs.Service
+}
+
+// TariffRequest schedules or replaces a tariff version. Blank vehicle_type
+// and city make it apply to every vehicle type and city. Omitting
+// effective_from makes it effective right away, omitting effective_to keeps
+// it effective until a newer version supersedes it. Rule windows are in
+// time_zone, UTC by default, and time is billed in started minutes unless
+// billing says otherwise. Caps limit what a user is charged per day and per
+// rolling week. Prices, minimum and maximum charges and caps are all net of
+// tax, which is added on top of them. Prepaid tariffs charge rides to the
+// wallet of the user.
+type TariffRequest struct {
+	Name              string          `json:"name"`
+	VehicleType       string          `json:"vehicle_type"`
+	City              string          `json:"city"`
+	Currency          money.Currency  `json:"currency"`
+	UnlockPrice       int             `json:"unlock_price"`
+	MinutePrice       int             `json:"minute_price"`
+	PausedMinutePrice int             `json:"paused_minute_price"`
+	Billing           pricing.Billing `json:"billing"`
+	Caps              pricing.Caps    `json:"caps"`
+	Prepaid           pricing.Prepaid `json:"prepaid"`
+	TimeZone          string          `json:"time_zone"`
+	Rules             pricing.Rules   `json:"rules"`
+	EffectiveFrom     *time.Time      `json:"effective_from"`
+	EffectiveTo       *time.Time      `json:"effective_to"`
+}
+
+var (
+	ErrTariffNameBlank     = errors.New("missing required Name field.")
+	ErrTariffCurrencyBlank = errors.New("missing required Currency field.")
+	ErrTariffIDInvalid     = errors.New("tariff ID must be a positive integer.")
+	ErrAtInvalid           = errors.New("at must be an RFC 3339 timestamp.")
+)
+
+func (tariff *TariffRequest) Bind(r *http.Request) error {
+	if tariff.Name == "" {
+		return ErrTariffNameBlank
+	}
+	if tariff.Currency == "" {
+		return ErrTariffCurrencyBlank
+	}
+	return nil
+}
+
+func (tariff *TariffRequest) tariff(id uint) tariffs.Tariff {
+	t := tariffs.Tariff{
+		ID:                id,
+		Name:              tariff.Name,
+		VehicleType:       tariff.VehicleType,
+		City:              tariff.City,
+		Currency:          tariff.Currency,
+		UnlockPrice:       tariff.UnlockPrice,
+		MinutePrice:       tariff.MinutePrice,
+		PausedMinutePrice: tariff.PausedMinutePrice,
+		Billing:           tariff.Billing,
+		Caps:              tariff.Caps,
+		Prepaid:           tariff.Prepaid,
+		TimeZone:          tariff.TimeZone,
+		Rules:             tariff.Rules,
+		EffectiveTo:       tariff.EffectiveTo,
+	}
+	if tariff.EffectiveFrom != nil {
+		t.EffectiveFrom = *tariff.EffectiveFrom
+	}
+	return t
+}
+
+// TariffResponse is the response payload for the Tariff data model.
+type TariffResponse struct {
+	*tariffs.Tariff
+}
+
+func (tr *TariffResponse) Render(w http.ResponseWriter, r *http.Request) error {
+	return nil
+}
+
+// TariffListResponse is the response payload for a list of tariffs.
+type TariffListResponse struct {
+	Tariffs []tariffs.Tariff `json:"tariffs"`
+}
+
+func (tl *TariffListResponse) Render(w http.ResponseWriter, r *http.Request) error {
+	return nil
+}
+
+// Tariffs godoc
+// @Summary lists the tariff versions, optionally those for a vehicle type or city.
+// @Description list tariffs, the latest to become effective first. Given a vehicle_type or city, only the versions for it and the generic ones are listed, so the app can show prices before unlocking
+// @Tags tariffs
+// @Produce json
+// @Param vehicle_type query string false "Vehicle type" Enums(scooter, bike, moped)
+// @Param city query string false "City"
+// @Success 200 {object} TariffListResponse
+// @Failure 400 {object} ErrResponse
+// @Router /tariffs [get]
+func (t Tariffs) TariffListHandler(w http.ResponseWriter, req *http.Request) {
+	filter := tariffs.Filter{
+		VehicleType: req.URL.Query().Get("vehicle_type"),
+		City:        req.URL.Query().Get("city"),
+	}
+	if err, list := t.tariffs.ListTariffs(req.Context(), filter); err != nil {
+		if err := render.Render(w, req, tariffErrRenderer(err)); err != nil {
+			return
+		}
+		return
+	} else {
+		resp := &TariffListResponse{Tariffs: list}
+
+		render.Status(req, http.StatusOK)
+		if err := render.Render(w, req, resp); err != nil {
+			return
+		}
+	}
+}
+
+// Tariffs godoc
+// @Summary schedules a tariff version.
+// @Description create tariff, effective right away unless effective_from is given
+// @Tags tariffs
+// @Accept json
+// @Produce json
+// @Param params body TariffRequest true "Tariff request parameters"
+// @Success 201 {object} tariffs.Tariff
+// @Failure 400 {object} ErrResponse
+// @Router /tariffs [post]
+func (t Tariffs) TariffCreateHandler(w http.ResponseWriter, req *http.Request) {
+	data := &TariffRequest{}
+	if err := render.Bind(req, data); err != nil {
+		if err := render.Render(w, req, ErrInvalidRequest(err)); err != nil {
+			return
+		}
+		return
+	}
+
+	tariff := data.tariff(0)
+	if err, savedTariff := t.tariffs.CreateTariff(req.Context(), &tariff, time.Now()); err != nil {
+		if err := render.Render(w, req, tariffErrRenderer(err)); err != nil {
+			return
+		}
+		return
+	} else {
+		resp := &TariffResponse{Tariff: savedTariff}
+
+		render.Status(req, http.StatusCreated)
+		if err := render.Render(w, req, resp); err != nil {
+			return
+		}
+	}
+}
+
+// Tariffs godoc
+// @Summary returns the tariff active at the given instant.
+// @Description preview the tariff rides on the vehicle type in the city starting at the given instant are billed with, falling back to the generic versions
+// @Tags tariffs
+// @Produce json
+// @Param at query string false "RFC 3339 timestamp, defaults to now"
+// @Param vehicle_type query string false "Vehicle type" Enums(scooter, bike, moped)
+// @Param city query string false "City"
+// @Success 200 {object} tariffs.Tariff
+// @Failure 400 {object} ErrResponse
+// @Failure 404 {object} ErrResponse
+// @Router /tariffs/active [get]
+func (t Tariffs) TariffActiveHandler(w http.ResponseWriter, req *http.Request) {
+	at := time.Now()
+	if value := req.URL.Query().Get("at"); value != "" {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			if err := render.Render(w, req, ErrInvalidRequest(ErrAtInvalid)); err != nil {
+				return
+			}
+			return
+		}
+		at = parsed
+	}
+
+	scope := pricing.Scope{
+		VehicleType: req.URL.Query().Get("vehicle_type"),
+		City:        req.URL.Query().Get("city"),
+	}
+	if err, tariff := t.tariffs.ActiveTariff(req.Context(), scope, at); err != nil {
+		if err := render.Render(w, req, tariffErrRenderer(err)); err != nil {
+			return
+		}
+		return
+	} else {
+		resp := &TariffResponse{Tariff: tariff}
+
+		render.Status(req, http.StatusOK)
+		if err := render.Render(w, req, resp); err != nil {
+			return
+		}
+	}
+}
+
+// Tariffs godoc
+// @Summary returns the tariff that matches the given ID.
+// @Description get tariff
+// @Tags tariffs
+// @Produce json
+// @Param id path int true "Tariff ID"
+// @Success 200 {object} tariffs.Tariff
+// @Failure 400 {object} ErrResponse
+// @Failure 404 {object} ErrResponse
+// @Router /tariffs/{id} [get]
+func (t Tariffs) TariffGetHandler(w http.ResponseWriter, req *http.Request) {
+	tariffID, err := parseTariffID(req)
+	if err != nil {
+		if err := render.Render(w, req, ErrInvalidRequest(err)); err != nil {
+			return
+		}
+		return
+	}
+
+	if err, tariff := t.tariffs.GetTariff(req.Context(), tariffID); err != nil {
+		if err := render.Render(w, req, tariffErrRenderer(err)); err != nil {
+			return
+		}
+		return
+	} else {
+		resp := &TariffResponse{Tariff: tariff}
+
+		render.Status(req, http.StatusOK)
+		if err := render.Render(w, req, resp); err != nil {
+			return
+		}
+	}
+}
+
+// Tariffs godoc
+// @Summary replaces the tariff that matches the given ID.
+// @Description update tariff, only the effective_to of a tariff that is already effective can change
+// @Tags tariffs
+// @Accept json
+// @Produce json
+// @Param id path int true "Tariff ID"
+// @Param params body TariffRequest true "Tariff request parameters"
+// @Success 200 {object} tariffs.Tariff
+// @Failure 400 {object} ErrResponse
+// @Failure 404 {object} ErrResponse
+// @Failure 409 {object} ErrResponse
+// @Router /tariffs/{id} [put]
+func (t Tariffs) TariffUpdateHandler(w http.ResponseWriter, req *http.Request) {
+	tariffID, err := parseTariffID(req)
+	if err != nil {
+		if err := render.Render(w, req, ErrInvalidRequest(err)); err != nil {
+			return
+		}
+		return
+	}
+
+	data := &TariffRequest{}
+	if err := render.Bind(req, data); err != nil {
+		if err := render.Render(w, req, ErrInvalidRequest(err)); err != nil {
+			return
+		}
+		return
+	}
+
+	tariff := data.tariff(tariffID)
+	if err, savedTariff := t.tariffs.UpdateTariff(req.Context(), &tariff, time.Now()); err != nil {
+		if err := render.Render(w, req, tariffErrRenderer(err)); err != nil {
+			return
+		}
+		return
+	} else {
+		resp := &TariffResponse{Tariff: savedTariff}
+
+		render.Status(req, http.StatusOK)
+		if err := render.Render(w, req, resp); err != nil {
+			return
+		}
+	}
+}
+
+// Tariffs godoc
+// @Summary deletes the tariff that matches the given ID.
+// @Description delete tariff, only tariffs that are not effective yet can be deleted
+// @Tags tariffs
+// @Param id path int true "Tariff ID"
+// @Success 204
+// @Failure 400 {object} ErrResponse
+// @Failure 404 {object} ErrResponse
+// @Failure 409 {object} ErrResponse
+// @Router /tariffs/{id} [delete]
+func (t Tariffs) TariffDeleteHandler(w http.ResponseWriter, req *http.Request) {
+	tariffID, err := parseTariffID(req)
+	if err != nil {
+		if err := render.Render(w, req, ErrInvalidRequest(err)); err != nil {
+			return
+		}
+		return
+	}
+
+	if err := t.tariffs.DeleteTariff(req.Context(), tariffID, time.Now()); err != nil {
+		if err := render.Render(w, req, tariffErrRenderer(err)); err != nil {
+			return
+		}
+		return
+	}
+
+	render.NoContent(w, req)
+}
+
+func parseTariffID(req *http.Request) (uint, error) {
+	tariffID, err := strconv.ParseUint(chi.URLParam(req, "id"), 10, 0)
+	if err != nil {
+		return 0, ErrTariffIDInvalid
+	}
+	return uint(tariffID), nil
+}
+
+func tariffErrRenderer(err error) render.Renderer {
+	switch {
+	case errors.Is(err, tariffs.ErrTariffNotFound), errors.Is(err, tariffs.ErrNoActiveTariff):
+		return ErrNotFound(err)
+	case errors.Is(err, tariffs.ErrTariffAlreadyEffective), errors.Is(err, tariffs.ErrTariffExpired):
+		return ErrConflict(err)
+	case errors.Is(err, tariffs.ErrTariffNameBlank),
+		errors.Is(err, tariffs.ErrTariffVehicleTypeInvalid),
+		errors.Is(err, tariffs.ErrTariffCurrencyInvalid),
+		errors.Is(err, tariffs.ErrTariffPriceNegative),
+		errors.Is(err, pricing.ErrBillingUnitInvalid),
+		errors.Is(err, pricing.ErrBillingBlockInvalid),
+		errors.Is(err, pricing.ErrBillingRoundingInvalid),
+		errors.Is(err, pricing.ErrBillingGraceNegative),
+		errors.Is(err, pricing.ErrBillingChargeInvalid),
+		errors.Is(err, pricing.ErrCapNegative),
+		errors.Is(err, pricing.ErrPrepaidMinimumNegative),
+		errors.Is(err, tariffs.ErrTariffTimeZoneInvalid),
+		errors.Is(err, pricing.ErrRuleNameBlank),
+		errors.Is(err, pricing.ErrRuleClockInvalid),
+		errors.Is(err, pricing.ErrRuleDayInvalid),
+		errors.Is(err, pricing.ErrRuleMultiplierNegative),
+		errors.Is(err, pricing.ErrRulePriceNegative),
+		errors.Is(err, tariffs.ErrTariffEffectiveFromPast),
+		errors.Is(err, tariffs.ErrTariffEffectiveToInvalid),
+		errors.Is(err, tariffs.ErrTariffEffectiveToPast):
+		return ErrInvalidRequest(err)
+	}
+	return ErrTariffDB(err)
+}
+
+func NewTariffsHandler(tariffs tariffs.Service) Tariffs {
+	t := Tariffs{
+		Mux:     chi.NewRouter(),
+		tariffs: tariffs,
+	}
+
+	t.Get("/", t.TariffListHandler)
+	t.Post("/", t.TariffCreateHandler)
+	t.Get("/active", t.TariffActiveHandler)
+	t.Get("/{id}", t.TariffGetHandler)
+	t.Put("/{id}", t.TariffUpdateHandler)
+	t.Delete("/{id}", t.TariffDeleteHandler)
+
+	return t
+}
@@ -0,0 +1,255 @@
+package handlers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"backend/api/handlers"
+	"backend/pricing"
+	"backend/tariffs"
+
+	"github.com/go-rel/rel"
+	"github.com/go-rel/rel/where"
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateTariff(t *testing.T) {
+	var (
+		effectiveFrom = time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+		request       = handlers.TariffRequest{Name: "Winter", Currency: "EUR", UnlockPrice: 20, MinutePrice: 110, PausedMinutePrice: 30, EffectiveFrom: &effectiveFrom}
+		body, _       = json.Marshal(request)
+		req, _        = http.NewRequest("POST", "/", bytes.NewBuffer(body))
+		rr            = httptest.NewRecorder()
+		repository    = reltest.New()
+		handler       = handlers.NewTariffsHandler(tariffs.New(repository))
+	)
+	req.Header.Add("Content-Type", "application/json")
+
+	repository.ExpectInsert().ForType("*tariffs.Tariff")
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+
+	var tariff tariffs.Tariff
+	if err := json.NewDecoder(rr.Body).Decode(&tariff); err != nil {
+		t.Errorf("Error decoding response body: %v", err)
+	}
+	assert.NotEmpty(t, tariff.ID)
+	assert.Equal(t, 110, tariff.MinutePrice)
+	assert.Equal(t, pricing.Billing{Unit: pricing.UnitMinute, Rounding: pricing.RoundingCeil}, tariff.Billing)
+	assert.True(t, effectiveFrom.Equal(tariff.EffectiveFrom))
+	assert.Nil(t, tariff.EffectiveTo)
+
+	repository.AssertExpectations(t)
+}
+
+func TestCreateTariffBadRequest(t *testing.T) {
+	var (
+		request    = handlers.TariffRequest{Name: "Winter"}
+		body, _    = json.Marshal(request)
+		req, _     = http.NewRequest("POST", "/", bytes.NewBuffer(body))
+		rr         = httptest.NewRecorder()
+		repository = reltest.New()
+		handler    = handlers.NewTariffsHandler(tariffs.New(repository))
+	)
+	req.Header.Add("Content-Type", "application/json")
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	var resp handlers.ErrResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err == nil {
+		assert.Equal(t, "Invalid request.", resp.StatusText)
+		assert.Equal(t, "missing required Currency field.", resp.ErrorText)
+	}
+}
+
+func TestCreateTariffInvalid(t *testing.T) {
+	var (
+		request    = handlers.TariffRequest{Name: "Winter", Currency: "EUR", MinutePrice: -1}
+		body, _    = json.Marshal(request)
+		req, _     = http.NewRequest("POST", "/", bytes.NewBuffer(body))
+		rr         = httptest.NewRecorder()
+		repository = reltest.New()
+		handler    = handlers.NewTariffsHandler(tariffs.New(repository))
+	)
+	req.Header.Add("Content-Type", "application/json")
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	var resp handlers.ErrResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err == nil {
+		assert.Equal(t, tariffs.ErrTariffPriceNegative.Error(), resp.ErrorText)
+	}
+}
+
+func TestGetTariffNotFound(t *testing.T) {
+	var (
+		req, _     = http.NewRequest("GET", "/2", nil)
+		rr         = httptest.NewRecorder()
+		repository = reltest.New()
+		handler    = handlers.NewTariffsHandler(tariffs.New(repository))
+	)
+
+	repository.ExpectFind(where.Eq("id", uint(2))).NotFound()
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+
+	repository.AssertExpectations(t)
+}
+
+func TestListTariffsForVehicleType(t *testing.T) {
+	var (
+		req, _     = http.NewRequest("GET", "/?vehicle_type=moped", nil)
+		rr         = httptest.NewRecorder()
+		repository = reltest.New()
+		handler    = handlers.NewTariffsHandler(tariffs.New(repository))
+		mopeds     = tariffs.Tariff{ID: 2, Name: "Mopeds", VehicleType: "moped", Currency: "EUR", UnlockPrice: 50, MinutePrice: 200, Billing: pricing.Billing{Unit: pricing.UnitMinute, Rounding: pricing.RoundingCeil}}
+	)
+
+	repository.ExpectFindAll(rel.From("tariffs").Where(
+		where.In("vehicle_type", "", "moped"),
+	).SortDesc("effective_from", "id")).Result([]tariffs.Tariff{mopeds})
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var resp handlers.TariffListResponse
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Errorf("Error decoding response body: %v", err)
+	}
+	assert.Len(t, resp.Tariffs, 1)
+	assert.Equal(t, "moped", resp.Tariffs[0].VehicleType)
+
+	repository.AssertExpectations(t)
+}
+
+func TestListTariffsVehicleTypeInvalid(t *testing.T) {
+	var (
+		req, _     = http.NewRequest("GET", "/?vehicle_type=car", nil)
+		rr         = httptest.NewRecorder()
+		repository = reltest.New()
+		handler    = handlers.NewTariffsHandler(tariffs.New(repository))
+	)
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestActiveTariff(t *testing.T) {
+	var (
+		at         = time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+		req, _     = http.NewRequest("GET", fmt.Sprintf("/active?at=%s&vehicle_type=moped", at.Format(time.RFC3339)), nil)
+		rr         = httptest.NewRecorder()
+		repository = reltest.New()
+		handler    = handlers.NewTariffsHandler(tariffs.New(repository))
+		active     = tariffs.Tariff{ID: 1, Name: "Default", Currency: "EUR", UnlockPrice: 18, MinutePrice: 100, PausedMinutePrice: 25, Billing: pricing.Billing{Unit: pricing.UnitMinute, Rounding: pricing.RoundingCeil}}
+	)
+
+	repository.ExpectFindAll(rel.Where(
+		where.Lte("effective_from", at),
+		where.Nil("effective_to").OrGt("effective_to", at),
+		where.In("vehicle_type", "", "moped"),
+		where.In("city", "", ""),
+	).SortDesc("effective_from", "id")).Result([]tariffs.Tariff{active})
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var tariff tariffs.Tariff
+	if err := json.NewDecoder(rr.Body).Decode(&tariff); err != nil {
+		t.Errorf("Error decoding response body: %v", err)
+	}
+	assert.Equal(t, uint(1), tariff.ID)
+
+	repository.AssertExpectations(t)
+}
+
+func TestActiveTariffInvalidAt(t *testing.T) {
+	var (
+		req, _     = http.NewRequest("GET", "/active?at=yesterday", nil)
+		rr         = httptest.NewRecorder()
+		repository = reltest.New()
+		handler    = handlers.NewTariffsHandler(tariffs.New(repository))
+	)
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestDeleteEffectiveTariff(t *testing.T) {
+	var (
+		req, _     = http.NewRequest("DELETE", "/1", nil)
+		rr         = httptest.NewRecorder()
+		repository = reltest.New()
+		handler    = handlers.NewTariffsHandler(tariffs.New(repository))
+		stored     = tariffs.Tariff{ID: 1, Name: "Default", Currency: "EUR", Billing: pricing.Billing{Unit: pricing.UnitMinute, Rounding: pricing.RoundingCeil}, EffectiveFrom: time.Now().Add(-time.Hour)}
+	)
+
+	repository.ExpectFind(where.Eq("id", uint(1))).Result(stored)
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusConflict, rr.Code)
+
+	repository.AssertExpectations(t)
+}
+
+func TestCreateTariffInvalidRule(t *testing.T) {
+	var (
+		request    = handlers.TariffRequest{Name: "Winter", Currency: "EUR", TimeZone: "Europe/Madrid", Rules: pricing.Rules{{Name: "Night", From: "22:00", To: "6am"}}}
+		body, _    = json.Marshal(request)
+		req, _     = http.NewRequest("POST", "/", bytes.NewBuffer(body))
+		rr         = httptest.NewRecorder()
+		repository = reltest.New()
+		handler    = handlers.NewTariffsHandler(tariffs.New(repository))
+	)
+	req.Header.Add("Content-Type", "application/json")
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	var resp handlers.ErrResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err == nil {
+		assert.Equal(t, pricing.ErrRuleClockInvalid.Error(), resp.ErrorText)
+	}
+}
+
+func TestCreateTariffPrepaidMinimumNegative(t *testing.T) {
+	var (
+		request    = handlers.TariffRequest{Name: "Wallet", Currency: "EUR", Prepaid: pricing.Prepaid{Enabled: true, MinimumBalance: -1}}
+		body, _    = json.Marshal(request)
+		req, _     = http.NewRequest("POST", "/", bytes.NewBuffer(body))
+		rr         = httptest.NewRecorder()
+		repository = reltest.New()
+		handler    = handlers.NewTariffsHandler(tariffs.New(repository))
+	)
+	req.Header.Add("Content-Type", "application/json")
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	var resp handlers.ErrResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Errorf("Error decoding response body: %v", err)
+	}
+	assert.Equal(t, pricing.ErrPrepaidMinimumNegative.Error(), resp.ErrorText)
+}
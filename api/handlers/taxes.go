@@ -0,0 +1,279 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"backend/pricing"
+	"backend/taxes"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+type Taxes struct {
+	*chi.Mux
+	taxes taxes.Service
+}
+
+// TaxRateRequest sets the tax rate of either a city or an operator, in
+// basis points, rounded per line item or per total. The rate of a city wins
+// over the rate of the operator.
+type TaxRateRequest struct {
+	City     string              `json:"city"`
+	Operator string              `json:"operator"`
+	Name     string              `json:"name"`
+	Rate     int                 `json:"rate"`
+	Rounding pricing.TaxRounding `json:"rounding"`
+}
+
+var (
+	ErrTaxNameBlank     = errors.New("missing required Name field.")
+	ErrTaxRoundingBlank = errors.New("missing required Rounding field.")
+	ErrTaxIDInvalid     = errors.New("tax rate ID must be a positive integer.")
+)
+
+func (rate *TaxRateRequest) Bind(r *http.Request) error {
+	if rate.Name == "" {
+		return ErrTaxNameBlank
+	}
+	if rate.Rounding == "" {
+		return ErrTaxRoundingBlank
+	}
+	return nil
+}
+
+func (rate *TaxRateRequest) rate(id uint) taxes.Rate {
+	return taxes.Rate{
+		ID:       id,
+		City:     rate.City,
+		Operator: rate.Operator,
+		Name:     rate.Name,
+		Rate:     rate.Rate,
+		Rounding: rate.Rounding,
+	}
+}
+
+// TaxRateResponse is the response payload for the Rate data model.
+type TaxRateResponse struct {
+	*taxes.Rate
+}
+
+func (tr *TaxRateResponse) Render(w http.ResponseWriter, r *http.Request) error {
+	return nil
+}
+
+// TaxRateListResponse is the response payload for a list of tax rates.
+type TaxRateListResponse struct {
+	Rates []taxes.Rate `json:"rates"`
+}
+
+func (tl *TaxRateListResponse) Render(w http.ResponseWriter, r *http.Request) error {
+	return nil
+}
+
+// Taxes godoc
+// @Summary lists every tax rate.
+// @Description list tax rates, cities first
+// @Tags taxes
+// @Produce json
+// @Success 200 {object} TaxRateListResponse
+// @Router /taxes [get]
+func (t Taxes) TaxRateListHandler(w http.ResponseWriter, req *http.Request) {
+	if err, list := t.taxes.ListRates(req.Context()); err != nil {
+		if err := render.Render(w, req, ErrTaxDB(err)); err != nil {
+			return
+		}
+		return
+	} else {
+		resp := &TaxRateListResponse{Rates: list}
+
+		render.Status(req, http.StatusOK)
+		if err := render.Render(w, req, resp); err != nil {
+			return
+		}
+	}
+}
+
+// Taxes godoc
+// @Summary sets the tax rate of a city or an operator.
+// @Description create tax rate, applied to the rides started from now on
+// @Tags taxes
+// @Accept json
+// @Produce json
+// @Param params body TaxRateRequest true "Tax rate request parameters"
+// @Success 201 {object} taxes.Rate
+// @Failure 400 {object} ErrResponse
+// @Failure 409 {object} ErrResponse
+// @Router /taxes [post]
+func (t Taxes) TaxRateCreateHandler(w http.ResponseWriter, req *http.Request) {
+	data := &TaxRateRequest{}
+	if err := render.Bind(req, data); err != nil {
+		if err := render.Render(w, req, ErrInvalidRequest(err)); err != nil {
+			return
+		}
+		return
+	}
+
+	rate := data.rate(0)
+	if err, savedRate := t.taxes.CreateRate(req.Context(), &rate); err != nil {
+		if err := render.Render(w, req, taxErrRenderer(err)); err != nil {
+			return
+		}
+		return
+	} else {
+		resp := &TaxRateResponse{Rate: savedRate}
+
+		render.Status(req, http.StatusCreated)
+		if err := render.Render(w, req, resp); err != nil {
+			return
+		}
+	}
+}
+
+// Taxes godoc
+// @Summary returns the tax rate that matches the given ID.
+// @Description get tax rate
+// @Tags taxes
+// @Produce json
+// @Param id path int true "Tax rate ID"
+// @Success 200 {object} taxes.Rate
+// @Failure 400 {object} ErrResponse
+// @Failure 404 {object} ErrResponse
+// @Router /taxes/{id} [get]
+func (t Taxes) TaxRateGetHandler(w http.ResponseWriter, req *http.Request) {
+	rateID, err := parseTaxRateID(req)
+	if err != nil {
+		if err := render.Render(w, req, ErrInvalidRequest(err)); err != nil {
+			return
+		}
+		return
+	}
+
+	if err, rate := t.taxes.GetRate(req.Context(), rateID); err != nil {
+		if err := render.Render(w, req, taxErrRenderer(err)); err != nil {
+			return
+		}
+		return
+	} else {
+		resp := &TaxRateResponse{Rate: rate}
+
+		render.Status(req, http.StatusOK)
+		if err := render.Render(w, req, resp); err != nil {
+			return
+		}
+	}
+}
+
+// Taxes godoc
+// @Summary replaces the tax rate that matches the given ID.
+// @Description update tax rate, rides already started keep the rate they started with
+// @Tags taxes
+// @Accept json
+// @Produce json
+// @Param id path int true "Tax rate ID"
+// @Param params body TaxRateRequest true "Tax rate request parameters"
+// @Success 200 {object} taxes.Rate
+// @Failure 400 {object} ErrResponse
+// @Failure 404 {object} ErrResponse
+// @Failure 409 {object} ErrResponse
+// @Router /taxes/{id} [put]
+func (t Taxes) TaxRateUpdateHandler(w http.ResponseWriter, req *http.Request) {
+	rateID, err := parseTaxRateID(req)
+	if err != nil {
+		if err := render.Render(w, req, ErrInvalidRequest(err)); err != nil {
+			return
+		}
+		return
+	}
+
+	data := &TaxRateRequest{}
+	if err := render.Bind(req, data); err != nil {
+		if err := render.Render(w, req, ErrInvalidRequest(err)); err != nil {
+			return
+		}
+		return
+	}
+
+	rate := data.rate(rateID)
+	if err, savedRate := t.taxes.UpdateRate(req.Context(), &rate); err != nil {
+		if err := render.Render(w, req, taxErrRenderer(err)); err != nil {
+			return
+		}
+		return
+	} else {
+		resp := &TaxRateResponse{Rate: savedRate}
+
+		render.Status(req, http.StatusOK)
+		if err := render.Render(w, req, resp); err != nil {
+			return
+		}
+	}
+}
+
+// Taxes godoc
+// @Summary deletes the tax rate that matches the given ID.
+// @Description delete tax rate, rides already started keep the rate they started with
+// @Tags taxes
+// @Param id path int true "Tax rate ID"
+// @Success 204
+// @Failure 400 {object} ErrResponse
+// @Failure 404 {object} ErrResponse
+// @Router /taxes/{id} [delete]
+func (t Taxes) TaxRateDeleteHandler(w http.ResponseWriter, req *http.Request) {
+	rateID, err := parseTaxRateID(req)
+	if err != nil {
+		if err := render.Render(w, req, ErrInvalidRequest(err)); err != nil {
+			return
+		}
+		return
+	}
+
+	if err := t.taxes.DeleteRate(req.Context(), rateID); err != nil {
+		if err := render.Render(w, req, taxErrRenderer(err)); err != nil {
+			return
+		}
+		return
+	}
+
+	render.NoContent(w, req)
+}
+
+func parseTaxRateID(req *http.Request) (uint, error) {
+	rateID, err := strconv.ParseUint(chi.URLParam(req, "id"), 10, 0)
+	if err != nil {
+		return 0, ErrTaxIDInvalid
+	}
+	return uint(rateID), nil
+}
+
+func taxErrRenderer(err error) render.Renderer {
+	switch {
+	case errors.Is(err, taxes.ErrTaxRateNotFound):
+		return ErrNotFound(err)
+	case errors.Is(err, taxes.ErrTaxRateTaken):
+		return ErrConflict(err)
+	case errors.Is(err, taxes.ErrTaxScopeInvalid),
+		errors.Is(err, taxes.ErrTaxNameBlank),
+		errors.Is(err, pricing.ErrTaxRateInvalid),
+		errors.Is(err, pricing.ErrTaxRoundingInvalid):
+		return ErrInvalidRequest(err)
+	}
+	return ErrTaxDB(err)
+}
+
+func NewTaxesHandler(taxes taxes.Service) Taxes {
+	t := Taxes{
+		Mux:   chi.NewRouter(),
+		taxes: taxes,
+	}
+
+	t.Get("/", t.TaxRateListHandler)
+	t.Post("/", t.TaxRateCreateHandler)
+	t.Get("/{id}", t.TaxRateGetHandler)
+	t.Put("/{id}", t.TaxRateUpdateHandler)
+	t.Delete("/{id}", t.TaxRateDeleteHandler)
+
+	return t
+}
@@ -0,0 +1,86 @@
+package handlers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"backend/api/handlers"
+	"backend/pricing"
+	"backend/taxes"
+
+	"github.com/go-rel/rel"
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateTaxRate(t *testing.T) {
+	var (
+		request    = handlers.TaxRateRequest{City: "Valencia", Name: "VAT", Rate: 2100, Rounding: pricing.TaxRoundingPerTotal}
+		body, _    = json.Marshal(request)
+		req, _     = http.NewRequest("POST", "/", bytes.NewBuffer(body))
+		rr         = httptest.NewRecorder()
+		repository = reltest.New()
+		handler    = handlers.NewTaxesHandler(taxes.New(repository))
+	)
+	req.Header.Add("Content-Type", "application/json")
+
+	repository.ExpectInsert().ForType("*taxes.Rate")
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+
+	var rate taxes.Rate
+	if err := json.NewDecoder(rr.Body).Decode(&rate); err != nil {
+		t.Errorf("Error decoding response body: %v", err)
+	}
+	assert.NotEmpty(t, rate.ID)
+	assert.Equal(t, 2100, rate.Rate)
+
+	repository.AssertExpectations(t)
+}
+
+func TestCreateTaxRateInvalid(t *testing.T) {
+	var (
+		request    = handlers.TaxRateRequest{City: "Valencia", Operator: "acme", Name: "VAT", Rate: 2100, Rounding: pricing.TaxRoundingPerTotal}
+		body, _    = json.Marshal(request)
+		req, _     = http.NewRequest("POST", "/", bytes.NewBuffer(body))
+		rr         = httptest.NewRecorder()
+		repository = reltest.New()
+		handler    = handlers.NewTaxesHandler(taxes.New(repository))
+	)
+	req.Header.Add("Content-Type", "application/json")
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	var resp handlers.ErrResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Errorf("Error decoding response body: %v", err)
+	}
+	assert.Equal(t, taxes.ErrTaxScopeInvalid.Error(), resp.ErrorText)
+}
+
+func TestCreateTaxRateTaken(t *testing.T) {
+	var (
+		request    = handlers.TaxRateRequest{City: "Valencia", Name: "VAT", Rate: 2100, Rounding: pricing.TaxRoundingPerTotal}
+		body, _    = json.Marshal(request)
+		req, _     = http.NewRequest("POST", "/", bytes.NewBuffer(body))
+		rr         = httptest.NewRecorder()
+		repository = reltest.New()
+		handler    = handlers.NewTaxesHandler(taxes.New(repository))
+	)
+	req.Header.Add("Content-Type", "application/json")
+
+	repository.ExpectInsert().ForType("*taxes.Rate").Error(rel.ErrUniqueConstraint)
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusConflict, rr.Code)
+
+	repository.AssertExpectations(t)
+}
@@ -0,0 +1,334 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"backend/users"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+type Users struct {
+	*chi.Mux
+	users users.Service
+}
+
+// UserRequest registers a user, or replaces its name and email.
+type UserRequest struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+// UserStatusRequest suspends or reactivates a user.
+type UserStatusRequest struct {
+	Status users.Status `json:"status" enums:"active,suspended"`
+}
+
+var (
+	ErrUserNameBlank     = errors.New("missing required Name field.")
+	ErrUserEmailBlank    = errors.New("missing required Email field.")
+	ErrUserStatusBlank   = errors.New("missing required Status field.")
+	ErrUserStatusDeleted = errors.New("users are deleted with DELETE /users/{id}.")
+	ErrUserIDInvalid     = errors.New("user ID must be a positive integer.")
+)
+
+func (user *UserRequest) Bind(r *http.Request) error {
+	if user.Name == "" {
+		return ErrUserNameBlank
+	}
+	if user.Email == "" {
+		return ErrUserEmailBlank
+	}
+	return nil
+}
+
+func (user *UserRequest) user(id uint) users.User {
+	return users.User{ID: id, Name: user.Name, Email: user.Email}
+}
+
+func (status *UserStatusRequest) Bind(r *http.Request) error {
+	switch status.Status {
+	case "":
+		return ErrUserStatusBlank
+	case users.StatusDeleted:
+		return ErrUserStatusDeleted
+	}
+	return nil
+}
+
+// UserResponse is the response payload for the User data model.
+type UserResponse struct {
+	*users.User
+}
+
+func (ur *UserResponse) Render(w http.ResponseWriter, r *http.Request) error {
+	return nil
+}
+
+// UserListResponse is the response payload for a list of users.
+type UserListResponse struct {
+	Users []users.User `json:"users"`
+}
+
+func (ul *UserListResponse) Render(w http.ResponseWriter, r *http.Request) error {
+	return nil
+}
+
+// Users godoc
+// @Summary lists the users.
+// @Description list users, the oldest first
+// @Tags users
+// @Produce json
+// @Param status query string false "Status filter" Enums(active, suspended, deleted)
+// @Success 200 {object} UserListResponse
+// @Failure 400 {object} ErrResponse
+// @Router /users [get]
+func (u Users) UserListHandler(w http.ResponseWriter, req *http.Request) {
+	status := users.Status(req.URL.Query().Get("status"))
+	if err, list := u.users.ListUsers(req.Context(), status); err != nil {
+		if err := render.Render(w, req, userErrRenderer(err)); err != nil {
+			return
+		}
+		return
+	} else {
+		resp := &UserListResponse{Users: list}
+
+		render.Status(req, http.StatusOK)
+		if err := render.Render(w, req, resp); err != nil {
+			return
+		}
+	}
+}
+
+// Users godoc
+// @Summary registers a user.
+// @Description create user, active right away
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param params body UserRequest true "User request parameters"
+// @Success 201 {object} users.User
+// @Failure 400 {object} ErrResponse
+// @Failure 409 {object} ErrResponse
+// @Router /users [post]
+func (u Users) UserCreateHandler(w http.ResponseWriter, req *http.Request) {
+	data := &UserRequest{}
+	if err := render.Bind(req, data); err != nil {
+		if err := render.Render(w, req, ErrInvalidRequest(err)); err != nil {
+			return
+		}
+		return
+	}
+
+	user := data.user(0)
+	if err, savedUser := u.users.RegisterUser(req.Context(), &user); err != nil {
+		if err := render.Render(w, req, userErrRenderer(err)); err != nil {
+			return
+		}
+		return
+	} else {
+		resp := &UserResponse{User: savedUser}
+
+		render.Status(req, http.StatusCreated)
+		if err := render.Render(w, req, resp); err != nil {
+			return
+		}
+	}
+}
+
+// Users godoc
+// @Summary returns the user that matches the given ID.
+// @Description get user
+// @Tags users
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} users.User
+// @Failure 400 {object} ErrResponse
+// @Failure 404 {object} ErrResponse
+// @Router /users/{id} [get]
+func (u Users) UserGetHandler(w http.ResponseWriter, req *http.Request) {
+	userID, err := parseUserID(req)
+	if err != nil {
+		if err := render.Render(w, req, ErrInvalidRequest(err)); err != nil {
+			return
+		}
+		return
+	}
+
+	if err, user := u.users.GetUser(req.Context(), userID); err != nil {
+		if err := render.Render(w, req, userErrRenderer(err)); err != nil {
+			return
+		}
+		return
+	} else {
+		resp := &UserResponse{User: user}
+
+		render.Status(req, http.StatusOK)
+		if err := render.Render(w, req, resp); err != nil {
+			return
+		}
+	}
+}
+
+// Users godoc
+// @Summary replaces the name and email of the user that matches the given ID.
+// @Description update user
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Param params body UserRequest true "User request parameters"
+// @Success 200 {object} users.User
+// @Failure 400 {object} ErrResponse
+// @Failure 404 {object} ErrResponse
+// @Failure 409 {object} ErrResponse
+// @Router /users/{id} [put]
+func (u Users) UserUpdateHandler(w http.ResponseWriter, req *http.Request) {
+	userID, err := parseUserID(req)
+	if err != nil {
+		if err := render.Render(w, req, ErrInvalidRequest(err)); err != nil {
+			return
+		}
+		return
+	}
+
+	data := &UserRequest{}
+	if err := render.Bind(req, data); err != nil {
+		if err := render.Render(w, req, ErrInvalidRequest(err)); err != nil {
+			return
+		}
+		return
+	}
+
+	user := data.user(userID)
+	if err, savedUser := u.users.UpdateUser(req.Context(), &user); err != nil {
+		if err := render.Render(w, req, userErrRenderer(err)); err != nil {
+			return
+		}
+		return
+	} else {
+		resp := &UserResponse{User: savedUser}
+
+		render.Status(req, http.StatusOK)
+		if err := render.Render(w, req, resp); err != nil {
+			return
+		}
+	}
+}
+
+// Users godoc
+// @Summary suspends or reactivates the user that matches the given ID.
+// @Description set user status, suspended users can't start rides
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Param params body UserStatusRequest true "User status request parameters"
+// @Success 200 {object} users.User
+// @Failure 400 {object} ErrResponse
+// @Failure 404 {object} ErrResponse
+// @Failure 409 {object} ErrResponse
+// @Router /users/{id}/status [put]
+func (u Users) UserStatusHandler(w http.ResponseWriter, req *http.Request) {
+	userID, err := parseUserID(req)
+	if err != nil {
+		if err := render.Render(w, req, ErrInvalidRequest(err)); err != nil {
+			return
+		}
+		return
+	}
+
+	data := &UserStatusRequest{}
+	if err := render.Bind(req, data); err != nil {
+		if err := render.Render(w, req, ErrInvalidRequest(err)); err != nil {
+			return
+		}
+		return
+	}
+
+	if err, user := u.users.SetStatus(req.Context(), userID, data.Status, time.Now()); err != nil {
+		if err := render.Render(w, req, userErrRenderer(err)); err != nil {
+			return
+		}
+		return
+	} else {
+		resp := &UserResponse{User: user}
+
+		render.Status(req, http.StatusOK)
+		if err := render.Render(w, req, resp); err != nil {
+			return
+		}
+	}
+}
+
+// Users godoc
+// @Summary deletes the user that matches the given ID.
+// @Description delete user, which is kept with the deleted status for its rides and invoices
+// @Tags users
+// @Param id path int true "User ID"
+// @Success 204
+// @Failure 400 {object} ErrResponse
+// @Failure 404 {object} ErrResponse
+// @Failure 409 {object} ErrResponse
+// @Router /users/{id} [delete]
+func (u Users) UserDeleteHandler(w http.ResponseWriter, req *http.Request) {
+	userID, err := parseUserID(req)
+	if err != nil {
+		if err := render.Render(w, req, ErrInvalidRequest(err)); err != nil {
+			return
+		}
+		return
+	}
+
+	if err := u.users.DeleteUser(req.Context(), userID, time.Now()); err != nil {
+		if err := render.Render(w, req, userErrRenderer(err)); err != nil {
+			return
+		}
+		return
+	}
+
+	render.NoContent(w, req)
+}
+
+func parseUserID(req *http.Request) (uint, error) {
+	userID, err := strconv.ParseUint(chi.URLParam(req, "id"), 10, 0)
+	if err != nil {
+		return 0, ErrUserIDInvalid
+	}
+	return uint(userID), nil
+}
+
+func userErrRenderer(err error) render.Renderer {
+	switch {
+	case errors.Is(err, users.ErrUserNotFound):
+		return ErrNotFound(err)
+	case errors.Is(err, users.ErrUserEmailTaken),
+		errors.Is(err, users.ErrUserDeleted):
+		return ErrConflict(err)
+	case errors.Is(err, users.ErrUserNameBlank),
+		errors.Is(err, users.ErrUserEmailInvalid),
+		errors.Is(err, users.ErrUserStatusInvalid):
+		return ErrInvalidRequest(err)
+	}
+	return ErrUserDB(err)
+}
+
+func NewUsersHandler(users users.Service) Users {
+	u := Users{
+		Mux:   chi.NewRouter(),
+		users: users,
+	}
+
+	u.Get("/", u.UserListHandler)
+	u.Post("/", u.UserCreateHandler)
+	u.Get("/{id}", u.UserGetHandler)
+	u.Put("/{id}", u.UserUpdateHandler)
+	u.Put("/{id}/status", u.UserStatusHandler)
+	u.Delete("/{id}", u.UserDeleteHandler)
+
+	return u
+}
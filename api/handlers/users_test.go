@@ -0,0 +1,152 @@
+package handlers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"backend/api/handlers"
+	"backend/users"
+
+	"github.com/go-rel/rel"
+	"github.com/go-rel/rel/where"
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterUser(t *testing.T) {
+	var (
+		request    = handlers.UserRequest{Name: "Alice", Email: "alice@example.com"}
+		body, _    = json.Marshal(request)
+		req, _     = http.NewRequest("POST", "/", bytes.NewBuffer(body))
+		rr         = httptest.NewRecorder()
+		repository = reltest.New()
+		handler    = handlers.NewUsersHandler(users.New(repository))
+	)
+	req.Header.Add("Content-Type", "application/json")
+
+	repository.ExpectInsert().ForType("*users.User")
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+
+	var user users.User
+	if err := json.NewDecoder(rr.Body).Decode(&user); err != nil {
+		t.Errorf("Error decoding response body: %v", err)
+	}
+	assert.NotEmpty(t, user.ID)
+	assert.Equal(t, users.StatusActive, user.Status)
+
+	repository.AssertExpectations(t)
+}
+
+func TestRegisterUserEmailTaken(t *testing.T) {
+	var (
+		request    = handlers.UserRequest{Name: "Alice", Email: "alice@example.com"}
+		body, _    = json.Marshal(request)
+		req, _     = http.NewRequest("POST", "/", bytes.NewBuffer(body))
+		rr         = httptest.NewRecorder()
+		repository = reltest.New()
+		handler    = handlers.NewUsersHandler(users.New(repository))
+	)
+	req.Header.Add("Content-Type", "application/json")
+
+	repository.ExpectInsert().ForType("*users.User").Error(rel.ErrUniqueConstraint)
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusConflict, rr.Code)
+
+	repository.AssertExpectations(t)
+}
+
+func TestGetUserNotFound(t *testing.T) {
+	var (
+		req, _     = http.NewRequest("GET", "/2", nil)
+		rr         = httptest.NewRecorder()
+		repository = reltest.New()
+		handler    = handlers.NewUsersHandler(users.New(repository))
+	)
+
+	repository.ExpectFind(where.Eq("id", uint(2))).NotFound()
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+
+	repository.AssertExpectations(t)
+}
+
+func TestSuspendUser(t *testing.T) {
+	var (
+		request    = handlers.UserStatusRequest{Status: users.StatusSuspended}
+		body, _    = json.Marshal(request)
+		req, _     = http.NewRequest("PUT", "/1/status", bytes.NewBuffer(body))
+		rr         = httptest.NewRecorder()
+		repository = reltest.New()
+		handler    = handlers.NewUsersHandler(users.New(repository))
+	)
+	req.Header.Add("Content-Type", "application/json")
+
+	repository.ExpectFind(where.Eq("id", uint(1))).Result(rider)
+	repository.ExpectUpdateAny(
+		rel.From("users").Where(where.Eq("id", uint(1)).AndNe("status", users.StatusDeleted)),
+		rel.Set("status", users.StatusSuspended),
+		rel.Set("updated_at", reltest.Any),
+	).UpdatedCount(1)
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var user users.User
+	if err := json.NewDecoder(rr.Body).Decode(&user); err != nil {
+		t.Errorf("Error decoding response body: %v", err)
+	}
+	assert.Equal(t, users.StatusSuspended, user.Status)
+
+	repository.AssertExpectations(t)
+}
+
+func TestSetUserStatusDeleted(t *testing.T) {
+	var (
+		request    = handlers.UserStatusRequest{Status: users.StatusDeleted}
+		body, _    = json.Marshal(request)
+		req, _     = http.NewRequest("PUT", "/1/status", bytes.NewBuffer(body))
+		rr         = httptest.NewRecorder()
+		repository = reltest.New()
+		handler    = handlers.NewUsersHandler(users.New(repository))
+	)
+	req.Header.Add("Content-Type", "application/json")
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	repository.AssertExpectations(t)
+}
+
+func TestDeleteUser(t *testing.T) {
+	var (
+		req, _     = http.NewRequest("DELETE", "/1", nil)
+		rr         = httptest.NewRecorder()
+		repository = reltest.New()
+		handler    = handlers.NewUsersHandler(users.New(repository))
+	)
+
+	repository.ExpectFind(where.Eq("id", uint(1))).Result(rider)
+	repository.ExpectUpdateAny(
+		rel.From("users").Where(where.Eq("id", uint(1)).AndNe("status", users.StatusDeleted)),
+		rel.Set("status", users.StatusDeleted),
+		rel.Set("updated_at", reltest.Any),
+	).UpdatedCount(1)
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+
+	repository.AssertExpectations(t)
+}
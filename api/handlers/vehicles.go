@@ -0,0 +1,347 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"backend/vehicles"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+type Vehicles struct {
+	*chi.Mux
+	vehicles vehicles.Service
+}
+
+// VehicleRequest adds a vehicle to the fleet, or replaces one. The status
+// defaults to available, and in_ride is only set by rides.
+type VehicleRequest struct {
+	Type      vehicles.Type   `json:"type" enums:"scooter,bike,moped"`
+	Model     string          `json:"model"`
+	City      string          `json:"city"`
+	Operator  string          `json:"operator"`
+	Status    vehicles.Status `json:"status" enums:"available,maintenance,retired"`
+	Battery   int             `json:"battery"`
+	Latitude  float64         `json:"latitude"`
+	Longitude float64         `json:"longitude"`
+}
+
+var (
+	ErrVehicleTypeBlank  = errors.New("missing required Type field.")
+	ErrVehicleModelBlank = errors.New("missing required Model field.")
+	ErrVehicleIDInvalid  = errors.New("vehicle ID must be a positive integer.")
+)
+
+func (vehicle *VehicleRequest) Bind(r *http.Request) error {
+	if vehicle.Type == "" {
+		return ErrVehicleTypeBlank
+	}
+	if vehicle.Model == "" {
+		return ErrVehicleModelBlank
+	}
+	return nil
+}
+
+func (vehicle *VehicleRequest) vehicle(id uint) vehicles.Vehicle {
+	return vehicles.Vehicle{
+		ID:        id,
+		Type:      vehicle.Type,
+		Model:     vehicle.Model,
+		City:      vehicle.City,
+		Operator:  vehicle.Operator,
+		Status:    vehicle.Status,
+		Battery:   vehicle.Battery,
+		Latitude:  vehicle.Latitude,
+		Longitude: vehicle.Longitude,
+	}
+}
+
+// TelemetryRequest is what a vehicle reports about itself.
+type TelemetryRequest struct {
+	vehicles.Telemetry
+}
+
+func (telemetry *TelemetryRequest) Bind(r *http.Request) error {
+	return nil
+}
+
+// VehicleResponse is the response payload for the Vehicle data model.
+type VehicleResponse struct {
+	*vehicles.Vehicle
+}
+
+func (vr *VehicleResponse) Render(w http.ResponseWriter, r *http.Request) error {
+	return nil
+}
+
+// VehicleListResponse is the response payload for a list of vehicles.
+type VehicleListResponse struct {
+	Vehicles []vehicles.Vehicle `json:"vehicles"`
+}
+
+func (vl *VehicleListResponse) Render(w http.ResponseWriter, r *http.Request) error {
+	return nil
+}
+
+// Vehicles godoc
+// @Summary lists the vehicles of the fleet.
+// @Description list vehicles, the oldest first
+// @Tags vehicles
+// @Produce json
+// @Param type query string false "Type filter" Enums(scooter, bike, moped)
+// @Param status query string false "Status filter" Enums(available, in_ride, maintenance, retired)
+// @Success 200 {object} VehicleListResponse
+// @Failure 400 {object} ErrResponse
+// @Router /vehicles [get]
+func (v Vehicles) VehicleListHandler(w http.ResponseWriter, req *http.Request) {
+	filter := vehicles.Filter{
+		Type:   vehicles.Type(req.URL.Query().Get("type")),
+		Status: vehicles.Status(req.URL.Query().Get("status")),
+	}
+	if err, list := v.vehicles.ListVehicles(req.Context(), filter); err != nil {
+		if err := render.Render(w, req, vehicleErrRenderer(err)); err != nil {
+			return
+		}
+		return
+	} else {
+		resp := &VehicleListResponse{Vehicles: list}
+
+		render.Status(req, http.StatusOK)
+		if err := render.Render(w, req, resp); err != nil {
+			return
+		}
+	}
+}
+
+// Vehicles godoc
+// @Summary adds a vehicle to the fleet.
+// @Description create vehicle
+// @Tags vehicles
+// @Accept json
+// @Produce json
+// @Param params body VehicleRequest true "Vehicle request parameters"
+// @Success 201 {object} vehicles.Vehicle
+// @Failure 400 {object} ErrResponse
+// @Failure 409 {object} ErrResponse
+// @Router /vehicles [post]
+func (v Vehicles) VehicleCreateHandler(w http.ResponseWriter, req *http.Request) {
+	data := &VehicleRequest{}
+	if err := render.Bind(req, data); err != nil {
+		if err := render.Render(w, req, ErrInvalidRequest(err)); err != nil {
+			return
+		}
+		return
+	}
+
+	vehicle := data.vehicle(0)
+	if err, savedVehicle := v.vehicles.CreateVehicle(req.Context(), &vehicle); err != nil {
+		if err := render.Render(w, req, vehicleErrRenderer(err)); err != nil {
+			return
+		}
+		return
+	} else {
+		resp := &VehicleResponse{Vehicle: savedVehicle}
+
+		render.Status(req, http.StatusCreated)
+		if err := render.Render(w, req, resp); err != nil {
+			return
+		}
+	}
+}
+
+// Vehicles godoc
+// @Summary returns the vehicle that matches the given ID.
+// @Description get vehicle
+// @Tags vehicles
+// @Produce json
+// @Param id path int true "Vehicle ID"
+// @Success 200 {object} vehicles.Vehicle
+// @Failure 400 {object} ErrResponse
+// @Failure 404 {object} ErrResponse
+// @Router /vehicles/{id} [get]
+func (v Vehicles) VehicleGetHandler(w http.ResponseWriter, req *http.Request) {
+	vehicleID, err := parseVehicleID(req)
+	if err != nil {
+		if err := render.Render(w, req, ErrInvalidRequest(err)); err != nil {
+			return
+		}
+		return
+	}
+
+	if err, vehicle := v.vehicles.GetVehicle(req.Context(), vehicleID); err != nil {
+		if err := render.Render(w, req, vehicleErrRenderer(err)); err != nil {
+			return
+		}
+		return
+	} else {
+		resp := &VehicleResponse{Vehicle: vehicle}
+
+		render.Status(req, http.StatusOK)
+		if err := render.Render(w, req, resp); err != nil {
+			return
+		}
+	}
+}
+
+// Vehicles godoc
+// @Summary replaces the vehicle that matches the given ID.
+// @Description update vehicle, the status of a vehicle in a ride can't change
+// @Tags vehicles
+// @Accept json
+// @Produce json
+// @Param id path int true "Vehicle ID"
+// @Param params body VehicleRequest true "Vehicle request parameters"
+// @Success 200 {object} vehicles.Vehicle
+// @Failure 400 {object} ErrResponse
+// @Failure 404 {object} ErrResponse
+// @Failure 409 {object} ErrResponse
+// @Router /vehicles/{id} [put]
+func (v Vehicles) VehicleUpdateHandler(w http.ResponseWriter, req *http.Request) {
+	vehicleID, err := parseVehicleID(req)
+	if err != nil {
+		if err := render.Render(w, req, ErrInvalidRequest(err)); err != nil {
+			return
+		}
+		return
+	}
+
+	data := &VehicleRequest{}
+	if err := render.Bind(req, data); err != nil {
+		if err := render.Render(w, req, ErrInvalidRequest(err)); err != nil {
+			return
+		}
+		return
+	}
+
+	vehicle := data.vehicle(vehicleID)
+	if err, savedVehicle := v.vehicles.UpdateVehicle(req.Context(), &vehicle, time.Now()); err != nil {
+		if err := render.Render(w, req, vehicleErrRenderer(err)); err != nil {
+			return
+		}
+		return
+	} else {
+		resp := &VehicleResponse{Vehicle: savedVehicle}
+
+		render.Status(req, http.StatusOK)
+		if err := render.Render(w, req, resp); err != nil {
+			return
+		}
+	}
+}
+
+// Vehicles godoc
+// @Summary records the battery level and position a vehicle reports.
+// @Description report vehicle telemetry, whatever the vehicle status
+// @Tags vehicles
+// @Accept json
+// @Produce json
+// @Param id path int true "Vehicle ID"
+// @Param params body TelemetryRequest true "Telemetry request parameters"
+// @Success 200 {object} vehicles.Vehicle
+// @Failure 400 {object} ErrResponse
+// @Failure 404 {object} ErrResponse
+// @Router /vehicles/{id}/telemetry [post]
+func (v Vehicles) VehicleTelemetryHandler(w http.ResponseWriter, req *http.Request) {
+	vehicleID, err := parseVehicleID(req)
+	if err != nil {
+		if err := render.Render(w, req, ErrInvalidRequest(err)); err != nil {
+			return
+		}
+		return
+	}
+
+	data := &TelemetryRequest{}
+	if err := render.Bind(req, data); err != nil {
+		if err := render.Render(w, req, ErrInvalidRequest(err)); err != nil {
+			return
+		}
+		return
+	}
+
+	if err, vehicle := v.vehicles.ReportTelemetry(req.Context(), vehicleID, data.Telemetry, time.Now()); err != nil {
+		if err := render.Render(w, req, vehicleErrRenderer(err)); err != nil {
+			return
+		}
+		return
+	} else {
+		resp := &VehicleResponse{Vehicle: vehicle}
+
+		render.Status(req, http.StatusOK)
+		if err := render.Render(w, req, resp); err != nil {
+			return
+		}
+	}
+}
+
+// Vehicles godoc
+// @Summary retires the vehicle that matches the given ID.
+// @Description delete vehicle, which is kept with the retired status for its rides
+// @Tags vehicles
+// @Param id path int true "Vehicle ID"
+// @Success 204
+// @Failure 400 {object} ErrResponse
+// @Failure 404 {object} ErrResponse
+// @Failure 409 {object} ErrResponse
+// @Router /vehicles/{id} [delete]
+func (v Vehicles) VehicleDeleteHandler(w http.ResponseWriter, req *http.Request) {
+	vehicleID, err := parseVehicleID(req)
+	if err != nil {
+		if err := render.Render(w, req, ErrInvalidRequest(err)); err != nil {
+			return
+		}
+		return
+	}
+
+	if err := v.vehicles.RetireVehicle(req.Context(), vehicleID, time.Now()); err != nil {
+		if err := render.Render(w, req, vehicleErrRenderer(err)); err != nil {
+			return
+		}
+		return
+	}
+
+	render.NoContent(w, req)
+}
+
+func parseVehicleID(req *http.Request) (uint, error) {
+	vehicleID, err := strconv.ParseUint(chi.URLParam(req, "id"), 10, 0)
+	if err != nil {
+		return 0, ErrVehicleIDInvalid
+	}
+	return uint(vehicleID), nil
+}
+
+func vehicleErrRenderer(err error) render.Renderer {
+	switch {
+	case errors.Is(err, vehicles.ErrVehicleNotFound):
+		return ErrNotFound(err)
+	case errors.Is(err, vehicles.ErrVehicleInRide):
+		return ErrConflict(err)
+	case errors.Is(err, vehicles.ErrVehicleTypeInvalid),
+		errors.Is(err, vehicles.ErrVehicleModelBlank),
+		errors.Is(err, vehicles.ErrVehicleStatusInvalid),
+		errors.Is(err, vehicles.ErrBatteryInvalid),
+		errors.Is(err, vehicles.ErrPositionInvalid):
+		return ErrInvalidRequest(err)
+	}
+	return ErrVehicleDB(err)
+}
+
+func NewVehiclesHandler(vehicles vehicles.Service) Vehicles {
+	v := Vehicles{
+		Mux:      chi.NewRouter(),
+		vehicles: vehicles,
+	}
+
+	v.Get("/", v.VehicleListHandler)
+	v.Post("/", v.VehicleCreateHandler)
+	v.Get("/{id}", v.VehicleGetHandler)
+	v.Put("/{id}", v.VehicleUpdateHandler)
+	v.Delete("/{id}", v.VehicleDeleteHandler)
+	v.Post("/{id}/telemetry", v.VehicleTelemetryHandler)
+
+	return v
+}
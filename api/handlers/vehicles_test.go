@@ -0,0 +1,161 @@
+package handlers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"backend/api/handlers"
+	"backend/vehicles"
+
+	"github.com/go-rel/rel"
+	"github.com/go-rel/rel/where"
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+)
+
+var scooter = vehicles.Vehicle{ID: 1, Type: vehicles.TypeScooter, Model: "Ninebot Max", City: "Valencia", Operator: "acme", Status: vehicles.StatusAvailable, Battery: 80}
+
+func TestCreateVehicle(t *testing.T) {
+	var (
+		request    = handlers.VehicleRequest{Type: vehicles.TypeScooter, Model: "Ninebot Max", Battery: 100}
+		body, _    = json.Marshal(request)
+		req, _     = http.NewRequest("POST", "/", bytes.NewBuffer(body))
+		rr         = httptest.NewRecorder()
+		repository = reltest.New()
+		handler    = handlers.NewVehiclesHandler(vehicles.New(repository))
+	)
+	req.Header.Add("Content-Type", "application/json")
+
+	repository.ExpectInsert().ForType("*vehicles.Vehicle")
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+
+	var vehicle vehicles.Vehicle
+	if err := json.NewDecoder(rr.Body).Decode(&vehicle); err != nil {
+		t.Errorf("Error decoding response body: %v", err)
+	}
+	assert.NotEmpty(t, vehicle.ID)
+	assert.Equal(t, vehicles.StatusAvailable, vehicle.Status)
+
+	repository.AssertExpectations(t)
+}
+
+func TestCreateVehicleInvalid(t *testing.T) {
+	var (
+		request    = handlers.VehicleRequest{Type: vehicles.TypeScooter, Model: "Ninebot Max", Battery: 120}
+		body, _    = json.Marshal(request)
+		req, _     = http.NewRequest("POST", "/", bytes.NewBuffer(body))
+		rr         = httptest.NewRecorder()
+		repository = reltest.New()
+		handler    = handlers.NewVehiclesHandler(vehicles.New(repository))
+	)
+	req.Header.Add("Content-Type", "application/json")
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	var resp handlers.ErrResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Errorf("Error decoding response body: %v", err)
+	}
+	assert.Equal(t, vehicles.ErrBatteryInvalid.Error(), resp.ErrorText)
+}
+
+func TestUpdateVehicleInRide(t *testing.T) {
+	var (
+		request    = handlers.VehicleRequest{Type: vehicles.TypeScooter, Model: "Ninebot Max", Status: vehicles.StatusMaintenance}
+		body, _    = json.Marshal(request)
+		req, _     = http.NewRequest("PUT", "/1", bytes.NewBuffer(body))
+		rr         = httptest.NewRecorder()
+		repository = reltest.New()
+		handler    = handlers.NewVehiclesHandler(vehicles.New(repository))
+		inRide     = scooter
+	)
+	req.Header.Add("Content-Type", "application/json")
+	inRide.Status = vehicles.StatusInRide
+
+	repository.ExpectFind(where.Eq("id", uint(1))).Result(inRide)
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusConflict, rr.Code)
+
+	repository.AssertExpectations(t)
+}
+
+func TestReportVehicleTelemetry(t *testing.T) {
+	var (
+		request    = handlers.TelemetryRequest{Telemetry: vehicles.Telemetry{Battery: 42, Latitude: 39.47, Longitude: -0.38}}
+		body, _    = json.Marshal(request)
+		req, _     = http.NewRequest("POST", "/1/telemetry", bytes.NewBuffer(body))
+		rr         = httptest.NewRecorder()
+		repository = reltest.New()
+		handler    = handlers.NewVehiclesHandler(vehicles.New(repository))
+		reported   = scooter
+	)
+	req.Header.Add("Content-Type", "application/json")
+	reported.Battery = 42
+
+	repository.ExpectUpdateAny(
+		rel.From("vehicles").Where(where.Eq("id", uint(1))),
+		rel.Set("battery", 42),
+		rel.Set("latitude", 39.47),
+		rel.Set("longitude", -0.38),
+		rel.Set("updated_at", reltest.Any),
+	).UpdatedCount(1)
+	repository.ExpectFind(where.Eq("id", uint(1))).Result(reported)
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var vehicle vehicles.Vehicle
+	if err := json.NewDecoder(rr.Body).Decode(&vehicle); err != nil {
+		t.Errorf("Error decoding response body: %v", err)
+	}
+	assert.Equal(t, 42, vehicle.Battery)
+
+	repository.AssertExpectations(t)
+}
+
+func TestDeleteVehicle(t *testing.T) {
+	var (
+		req, _     = http.NewRequest("DELETE", "/1", nil)
+		rr         = httptest.NewRecorder()
+		repository = reltest.New()
+		handler    = handlers.NewVehiclesHandler(vehicles.New(repository))
+	)
+
+	repository.ExpectUpdateAny(
+		rel.From("vehicles").Where(where.Eq("id", uint(1)).AndNe("status", vehicles.StatusInRide)),
+		rel.Set("status", vehicles.StatusRetired),
+		rel.Set("updated_at", reltest.Any),
+	).UpdatedCount(1)
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+
+	repository.AssertExpectations(t)
+}
+
+func TestListVehiclesBadRequest(t *testing.T) {
+	var (
+		req, _     = http.NewRequest("GET", "/?type=car", nil)
+		rr         = httptest.NewRecorder()
+		repository = reltest.New()
+		handler    = handlers.NewVehiclesHandler(vehicles.New(repository))
+	)
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	repository.AssertExpectations(t)
+}
@@ -0,0 +1,193 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"backend/money"
+	"backend/payments"
+	"backend/wallets"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+type Wallets struct {
+	*chi.Mux
+	wallets wallets.Service
+}
+
+// TopUpRequest charges amount, in the minor unit of currency, to the
+// payment method of the user and credits it to their wallet.
+type TopUpRequest struct {
+	Amount   int64          `json:"amount"`
+	Currency money.Currency `json:"currency"`
+}
+
+var (
+	ErrTopUpAmountBlank     = errors.New("missing required Amount field.")
+	ErrTopUpCurrencyBlank   = errors.New("missing required Currency field.")
+	ErrBalanceCurrencyBlank = errors.New("missing required currency query parameter.")
+)
+
+func (topUp *TopUpRequest) Bind(r *http.Request) error {
+	if topUp.Amount == 0 {
+		return ErrTopUpAmountBlank
+	}
+	if topUp.Currency == "" {
+		return ErrTopUpCurrencyBlank
+	}
+	return nil
+}
+
+// TransactionResponse is the response payload for the Transaction data
+// model.
+type TransactionResponse struct {
+	*wallets.Transaction
+}
+
+func (tr *TransactionResponse) Render(w http.ResponseWriter, r *http.Request) error {
+	return nil
+}
+
+// TransactionListResponse is the response payload for a list of wallet
+// transactions.
+type TransactionListResponse struct {
+	Transactions []wallets.Transaction `json:"transactions"`
+}
+
+func (tl *TransactionListResponse) Render(w http.ResponseWriter, r *http.Request) error {
+	return nil
+}
+
+// BalanceResponse is what the wallet of a user holds in a currency.
+type BalanceResponse struct {
+	UserID  string      `json:"user_id"`
+	Balance money.Money `json:"balance"`
+}
+
+func (br *BalanceResponse) Render(w http.ResponseWriter, r *http.Request) error {
+	return nil
+}
+
+// Wallets godoc
+// @Summary tops up the wallet of a user.
+// @Description charge the payment method of the user and credit wallet with what was paid, referenced by the ID of the payment
+// @Tags wallets
+// @Accept json
+// @Produce json
+// @Param user_id path string true "User ID"
+// @Param params body TopUpRequest true "Top-up request parameters"
+// @Success 201 {object} wallets.Transaction
+// @Failure 400 {object} ErrResponse
+// @Failure 402 {object} ErrResponse
+// @Router /wallets/{user_id}/top-ups [post]
+func (wa Wallets) TopUpHandler(w http.ResponseWriter, req *http.Request) {
+	data := &TopUpRequest{}
+	if err := render.Bind(req, data); err != nil {
+		if err := render.Render(w, req, ErrInvalidRequest(err)); err != nil {
+			return
+		}
+		return
+	}
+
+	amount := money.New(data.Amount, data.Currency)
+	if err, transaction := wa.wallets.TopUp(req.Context(), chi.URLParam(req, "user_id"), amount, time.Now()); err != nil {
+		if err := render.Render(w, req, walletErrRenderer(err)); err != nil {
+			return
+		}
+		return
+	} else {
+		resp := &TransactionResponse{Transaction: transaction}
+
+		render.Status(req, http.StatusCreated)
+		if err := render.Render(w, req, resp); err != nil {
+			return
+		}
+	}
+}
+
+// Wallets godoc
+// @Summary gets the balance of the wallet of a user.
+// @Description get balance in the given currency
+// @Tags wallets
+// @Produce json
+// @Param user_id path string true "User ID"
+// @Param currency query string true "ISO 4217 currency code"
+// @Success 200 {object} BalanceResponse
+// @Failure 400 {object} ErrResponse
+// @Router /wallets/{user_id}/balance [get]
+func (wa Wallets) BalanceHandler(w http.ResponseWriter, req *http.Request) {
+	currency := money.Currency(req.URL.Query().Get("currency"))
+	if currency == "" {
+		if err := render.Render(w, req, ErrInvalidRequest(ErrBalanceCurrencyBlank)); err != nil {
+			return
+		}
+		return
+	}
+
+	userID := chi.URLParam(req, "user_id")
+	if err, balance := wa.wallets.GetBalance(req.Context(), userID, currency); err != nil {
+		if err := render.Render(w, req, walletErrRenderer(err)); err != nil {
+			return
+		}
+		return
+	} else {
+		resp := &BalanceResponse{UserID: userID, Balance: balance}
+
+		render.Status(req, http.StatusOK)
+		if err := render.Render(w, req, resp); err != nil {
+			return
+		}
+	}
+}
+
+// Wallets godoc
+// @Summary lists the transactions of the wallet of a user.
+// @Description list top-ups, ride debits, refunds and adjustments, the latest first
+// @Tags wallets
+// @Produce json
+// @Param user_id path string true "User ID"
+// @Success 200 {object} TransactionListResponse
+// @Router /wallets/{user_id}/transactions [get]
+func (wa Wallets) TransactionListHandler(w http.ResponseWriter, req *http.Request) {
+	if err, list := wa.wallets.ListTransactions(req.Context(), chi.URLParam(req, "user_id")); err != nil {
+		if err := render.Render(w, req, ErrWalletDB(err)); err != nil {
+			return
+		}
+		return
+	} else {
+		resp := &TransactionListResponse{Transactions: list}
+
+		render.Status(req, http.StatusOK)
+		if err := render.Render(w, req, resp); err != nil {
+			return
+		}
+	}
+}
+
+func walletErrRenderer(err error) render.Renderer {
+	switch {
+	case errors.Is(err, wallets.ErrWalletUserIDBlank),
+		errors.Is(err, wallets.ErrTopUpAmountInvalid),
+		errors.Is(err, wallets.ErrWalletCurrencyInvalid):
+		return ErrInvalidRequest(err)
+	case errors.Is(err, payments.ErrDeclined):
+		return ErrPaymentDeclined(err)
+	}
+	return ErrWalletDB(err)
+}
+
+func NewWalletsHandler(wallets wallets.Service) Wallets {
+	wa := Wallets{
+		Mux:     chi.NewRouter(),
+		wallets: wallets,
+	}
+
+	wa.Post("/{user_id}/top-ups", wa.TopUpHandler)
+	wa.Get("/{user_id}/balance", wa.BalanceHandler)
+	wa.Get("/{user_id}/transactions", wa.TransactionListHandler)
+
+	return wa
+}
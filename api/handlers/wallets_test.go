@@ -0,0 +1,137 @@
+package handlers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"backend/api/handlers"
+	"backend/money"
+	"backend/payments"
+	"backend/wallets"
+
+	"github.com/go-rel/rel"
+	"github.com/go-rel/rel/where"
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTopUp(t *testing.T) {
+	var (
+		request    = handlers.TopUpRequest{Amount: 2000, Currency: "EUR"}
+		body, _    = json.Marshal(request)
+		req, _     = http.NewRequest("POST", "/1/top-ups", bytes.NewBuffer(body))
+		rr         = httptest.NewRecorder()
+		repository = reltest.New()
+		handler    = handlers.NewWalletsHandler(wallets.New(repository, payments.NewFake()))
+	)
+	req.Header.Add("Content-Type", "application/json")
+
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectInsert().ForType("*wallets.Transaction")
+		repository.ExpectInsertAll().ForType("[]wallets.Entry")
+	})
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+
+	var transaction wallets.Transaction
+	if err := json.NewDecoder(rr.Body).Decode(&transaction); err != nil {
+		t.Errorf("Error decoding response body: %v", err)
+	}
+	assert.NotEmpty(t, transaction.ID)
+	assert.Equal(t, "1", transaction.UserID)
+	assert.Equal(t, wallets.KindTopUp, transaction.Kind)
+	assert.Equal(t, int64(2000), transaction.Amount)
+	assert.Equal(t, "fake_1", transaction.Reference)
+
+	repository.AssertExpectations(t)
+}
+
+func TestTopUpDeclined(t *testing.T) {
+	var (
+		request    = handlers.TopUpRequest{Amount: 2000, Currency: "EUR"}
+		body, _    = json.Marshal(request)
+		req, _     = http.NewRequest("POST", "/1/top-ups", bytes.NewBuffer(body))
+		rr         = httptest.NewRecorder()
+		repository = reltest.New()
+		provider   = payments.NewFake()
+		handler    = handlers.NewWalletsHandler(wallets.New(repository, provider))
+	)
+	req.Header.Add("Content-Type", "application/json")
+	provider.Decline("1")
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusPaymentRequired, rr.Code)
+
+	repository.AssertExpectations(t)
+}
+
+func TestTopUpNegative(t *testing.T) {
+	var (
+		request    = handlers.TopUpRequest{Amount: -2000, Currency: "EUR"}
+		body, _    = json.Marshal(request)
+		req, _     = http.NewRequest("POST", "/1/top-ups", bytes.NewBuffer(body))
+		rr         = httptest.NewRecorder()
+		repository = reltest.New()
+		handler    = handlers.NewWalletsHandler(wallets.New(repository, payments.NewFake()))
+	)
+	req.Header.Add("Content-Type", "application/json")
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	var resp handlers.ErrResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err == nil {
+		assert.Equal(t, wallets.ErrTopUpAmountInvalid.Error(), resp.ErrorText)
+	}
+
+	repository.AssertExpectations(t)
+}
+
+func TestGetBalance(t *testing.T) {
+	var (
+		req, _     = http.NewRequest("GET", "/1/balance?currency=EUR", nil)
+		rr         = httptest.NewRecorder()
+		repository = reltest.New()
+		handler    = handlers.NewWalletsHandler(wallets.New(repository, payments.NewFake()))
+	)
+
+	repository.ExpectAggregate(
+		rel.From("wallet_entries").Where(where.Eq("account", "user:1").AndEq("currency", money.Currency("EUR"))),
+		"sum", "amount",
+	).Result(1582)
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var resp handlers.BalanceResponse
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Errorf("Error decoding response body: %v", err)
+	}
+	assert.Equal(t, "1", resp.UserID)
+	assert.Equal(t, money.New(1582, "EUR"), resp.Balance)
+
+	repository.AssertExpectations(t)
+}
+
+func TestGetBalanceWithoutCurrency(t *testing.T) {
+	var (
+		req, _     = http.NewRequest("GET", "/1/balance", nil)
+		rr         = httptest.NewRecorder()
+		repository = reltest.New()
+		handler    = handlers.NewWalletsHandler(wallets.New(repository, payments.NewFake()))
+	)
+
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	repository.AssertExpectations(t)
+}
@@ -1,8 +1,20 @@
 package api
 
 import (
+	"backend/batteries"
 	"backend/docs"
+	"backend/invoices"
+	"backend/iot"
+	"backend/passes"
+	"backend/payments"
+	"backend/pricing"
+	"backend/promos"
 	"backend/rides"
+	"backend/tariffs"
+	"backend/taxes"
+	"backend/users"
+	"backend/vehicles"
+	"backend/wallets"
 	"fmt"
 
 	h "backend/api/handlers"
@@ -18,6 +30,11 @@ import (
 	httpSwagger "github.com/swaggo/http-swagger"
 )
 
+// NewRouter serves the API, unlocking and locking the vehicles of rides
+// through gateway and authorizing hold for the rides whose tariff has no
+// maximum charge. It also returns the watch over the batteries of the
+// vehicles in a ride, for the caller to run.
+//
 // @title Rides Swagger API
 // @description This is a basic Rides API using Chi and go-rel.
 
@@ -31,11 +48,48 @@ import (
 // @host localhost:8080
 // @BasePath /
 // @schemes http https
-func NewRouter(repository rel.Repository, port string) *chi.Mux {
+func NewRouter(repository rel.Repository, port string, levels batteries.Levels, gateway iot.Gateway, hold int, mail batteries.MailConfig) (*chi.Mux, *batteries.Watch) {
 	var (
-		r            = chi.NewRouter()
-		rides        = rides.New(repository)
-		ridesHandler = h.NewRidesHandler(repository, rides)
+		r               = chi.NewRouter()
+		provider        = payments.NewFake()
+		tariffSource    = tariffs.NewSource(repository)
+		tariffs         = tariffs.New(repository)
+		tariffsHandler  = h.NewTariffsHandler(tariffs)
+		redeemer        = promos.NewRedeemer(repository)
+		promos          = promos.New(repository)
+		promosHandler   = h.NewPromosHandler(promos)
+		balances        = passes.NewBalances(repository)
+		passes          = passes.New(repository)
+		passesHandler   = h.NewPassesHandler(passes)
+		taxSource       = taxes.NewSource(repository)
+		taxes           = taxes.New(repository)
+		taxesHandler    = h.NewTaxesHandler(taxes)
+		ledger          = wallets.NewLedger(repository)
+		wallets         = wallets.New(repository, provider)
+		walletsHandler  = h.NewWalletsHandler(wallets)
+		directory       = users.NewDirectory(repository)
+		users           = users.New(repository)
+		usersHandler    = h.NewUsersHandler(users)
+		fleet           = vehicles.NewFleet(repository, levels.Start)
+		vehicles        = vehicles.New(repository)
+		vehiclesHandler = h.NewVehiclesHandler(vehicles)
+		invoices        = invoices.New(repository)
+		invoicesHandler = h.NewInvoicesHandler(invoices)
+		rides           = rides.New(repository, rides.Deps{
+			Riders:   directory,
+			Vehicles: fleet,
+			Gateway:  gateway,
+			Tariffs:  tariffSource,
+			Promos:   redeemer,
+			Passes:   balances,
+			Taxes:    taxSource,
+			Payments: provider,
+			Wallets:  ledger,
+			Pricer:   pricing.Flat{},
+			Hold:     hold,
+		})
+		ridesHandler = h.NewRidesHandler(rides)
+		watch        = batteries.NewWatch(vehicles, rides, batteries.NewMailer(directory, mail), levels)
 		mdlw         = middleware.New(middleware.Config{
 			Recorder: metrics.NewRecorder(metrics.Config{}),
 		})
@@ -48,6 +102,14 @@ func NewRouter(repository rel.Repository, port string) *chi.Mux {
 	r.Use(std.HandlerProvider("", mdlw))
 
 	r.Mount("/rides", ridesHandler)
+	r.Mount("/tariffs", tariffsHandler)
+	r.Mount("/promos", promosHandler)
+	r.Mount("/passes", passesHandler)
+	r.Mount("/taxes", taxesHandler)
+	r.Mount("/wallets", walletsHandler)
+	r.Mount("/users", usersHandler)
+	r.Mount("/vehicles", vehiclesHandler)
+	r.Mount("/users/{id}/invoices", invoicesHandler)
 	r.Mount("/metrics", promhttp.Handler())
 
 	docs.SwaggerInfo.Version = "1.0"
@@ -55,5 +117,5 @@ func NewRouter(repository rel.Repository, port string) *chi.Mux {
 		httpSwagger.URL(fmt.Sprintf("http://localhost:%s/swagger/doc.json", port)),
 	))
 
-	return r
+	return r, watch
 }
@@ -0,0 +1,49 @@
+package audit
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// Record is an entry of the audit log: what an actor did to a subject, with
+// the details needed to tell later what changed. Records are only ever
+// inserted, in the transaction of the change they describe.
+type Record struct {
+	ID        uint      `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	Actor     string    `json:"actor"`   // e.g. "agent:42"
+	Action    string    `json:"action"`  // e.g. "ride.adjusted"
+	Subject   string    `json:"subject"` // e.g. "ride:7"
+	Details   Details   `json:"details"`
+}
+
+func (Record) Table() string {
+	return "audit_records"
+}
+
+// Details are the free-form facts of a record, stored as JSON.
+type Details map[string]interface{}
+
+func (d Details) Value() (driver.Value, error) {
+	if d == nil {
+		return "{}", nil
+	}
+	value, err := json.Marshal(d)
+	return string(value), err
+}
+
+func (d *Details) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*d = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(v, d)
+	case string:
+		return json.Unmarshal([]byte(v), d)
+	}
+
+	return errors.New("audit: cannot scan details")
+}
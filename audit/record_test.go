@@ -0,0 +1,32 @@
+package audit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetailsValueScan(t *testing.T) {
+	details := Details{"reason": "goodwill", "amount": float64(-200)}
+
+	value, err := details.Value()
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"reason":"goodwill","amount":-200}`, value.(string))
+
+	var scanned Details
+	assert.Nil(t, scanned.Scan([]byte(value.(string))))
+	assert.Equal(t, details, scanned)
+
+	scanned = nil
+	assert.Nil(t, scanned.Scan(value))
+	assert.Equal(t, details, scanned)
+
+	assert.Nil(t, scanned.Scan(nil))
+	assert.Nil(t, scanned)
+
+	value, err = Details(nil).Value()
+	assert.Nil(t, err)
+	assert.Equal(t, "{}", value)
+
+	assert.NotNil(t, scanned.Scan(42))
+}
@@ -0,0 +1,15 @@
+package batteries
+
+// Levels are the battery levels, in percent, that rides start and end at.
+type Levels struct {
+	// Start is the least battery a vehicle needs to start a ride.
+	Start int
+	// Warning is the level under which riders are told to end their ride.
+	Warning int
+	// Critical is the level under which their ride is finished for them.
+	Critical int
+}
+
+// DefaultLevels are the levels the server runs with unless configured
+// otherwise.
+var DefaultLevels = Levels{Start: 20, Warning: 15, Critical: 5}
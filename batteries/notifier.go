@@ -0,0 +1,77 @@
+package batteries
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/smtp"
+	"strings"
+)
+
+var ErrNoAddress = errors.New("The rider has no email address")
+
+// Notifier sends messages to riders.
+type Notifier interface {
+	Notify(ctx context.Context, userID string, message string) error
+}
+
+// Addresses finds the email address of the riders, as users.Directory does.
+type Addresses interface {
+	Address(ctx context.Context, userID string) (string, error)
+}
+
+// MailConfig is the SMTP server the Mailer sends through. Username and
+// Password are left empty for servers that take mail without logging in.
+type MailConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+}
+
+// Mailer is a Notifier that emails riders at the address of their account.
+type Mailer struct {
+	addresses Addresses
+	config    MailConfig
+	// send is smtp.SendMail, swapped in tests.
+	send func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+}
+
+func NewMailer(addresses Addresses, config MailConfig) Mailer {
+	return Mailer{addresses: addresses, config: config, send: smtp.SendMail}
+}
+
+func (m Mailer) Notify(ctx context.Context, userID string, message string) error {
+	to, err := m.addresses.Address(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if to == "" {
+		return ErrNoAddress
+	}
+
+	var auth smtp.Auth
+	if m.config.Username != "" {
+		auth = smtp.PlainAuth("", m.config.Username, m.config.Password, m.config.Host)
+	}
+
+	addr := fmt.Sprintf("%s:%d", m.config.Host, m.config.Port)
+	return m.send(addr, auth, m.config.From, []string{to}, m.mail(to, message))
+}
+
+// mail is the message as sent over SMTP, its subject being the first
+// sentence of the message.
+func (m Mailer) mail(to, message string) []byte {
+	subject, _, _ := strings.Cut(message, ".")
+
+	var mail strings.Builder
+	fmt.Fprintf(&mail, "From: %s\r\n", m.config.From)
+	fmt.Fprintf(&mail, "To: %s\r\n", to)
+	fmt.Fprintf(&mail, "Subject: %s\r\n", subject)
+	mail.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	mail.WriteString("\r\n")
+	mail.WriteString(message)
+	mail.WriteString("\r\n")
+	return []byte(mail.String())
+}
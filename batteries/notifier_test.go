@@ -0,0 +1,84 @@
+package batteries
+
+import (
+	"context"
+	"errors"
+	"net/smtp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// addressBook knows the email address of every user.
+type addressBook map[string]string
+
+func (b addressBook) Address(ctx context.Context, userID string) (string, error) {
+	address, ok := b[userID]
+	if !ok {
+		return "", errors.New("unknown user")
+	}
+	return address, nil
+}
+
+// outbox records the mails sent instead of sending them.
+type outbox struct {
+	addr string
+	auth smtp.Auth
+	from string
+	to   []string
+	msg  string
+}
+
+func (o *outbox) send(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+	o.addr, o.auth, o.from, o.to, o.msg = addr, a, from, to, string(msg)
+	return nil
+}
+
+var config = MailConfig{Host: "localhost", Port: 1025, From: "rides@example.com"}
+
+func TestMailerNotify(t *testing.T) {
+	var (
+		sent   outbox
+		mailer = NewMailer(addressBook{"1": "alice@example.com"}, config)
+	)
+	mailer.send = sent.send
+
+	assert.Nil(t, mailer.Notify(context.TODO(), "1", "The battery of the vehicle is at 12%, please end your ride soon."))
+	assert.Equal(t, "localhost:1025", sent.addr)
+	assert.Nil(t, sent.auth)
+	assert.Equal(t, "rides@example.com", sent.from)
+	assert.Equal(t, []string{"alice@example.com"}, sent.to)
+	assert.Equal(t, "From: rides@example.com\r\n"+
+		"To: alice@example.com\r\n"+
+		"Subject: The battery of the vehicle is at 12%, please end your ride soon\r\n"+
+		"Content-Type: text/plain; charset=UTF-8\r\n"+
+		"\r\n"+
+		"The battery of the vehicle is at 12%, please end your ride soon.\r\n", sent.msg)
+}
+
+func TestMailerNotifyLogsIn(t *testing.T) {
+	var (
+		sent   outbox
+		login  = MailConfig{Host: "smtp.example.com", Port: 587, Username: "rides", Password: "secret", From: "rides@example.com"}
+		mailer = NewMailer(addressBook{"1": "alice@example.com"}, login)
+	)
+	mailer.send = sent.send
+
+	assert.Nil(t, mailer.Notify(context.TODO(), "1", "Your ride was finished."))
+	assert.Equal(t, "smtp.example.com:587", sent.addr)
+	assert.NotNil(t, sent.auth)
+}
+
+func TestMailerNotifyWithoutAddress(t *testing.T) {
+	var (
+		sent   outbox
+		mailer = NewMailer(addressBook{"1": ""}, config)
+	)
+	mailer.send = sent.send
+
+	assert.Equal(t, ErrNoAddress, mailer.Notify(context.TODO(), "1", "Your ride was finished."))
+	assert.Nil(t, sent.to)
+
+	assert.NotNil(t, mailer.Notify(context.TODO(), "2", "Your ride was finished."))
+	assert.Nil(t, sent.to)
+}
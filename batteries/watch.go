@@ -0,0 +1,133 @@
+package batteries
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"backend/rides"
+	"backend/vehicles"
+)
+
+// ReasonLowBattery is the reason recorded on the rides finished because
+// their vehicle ran out of battery.
+const ReasonLowBattery = "low_battery"
+
+// Vehicles lists the vehicles of the fleet, as vehicles.Service does.
+type Vehicles interface {
+	ListVehicles(ctx context.Context, filter vehicles.Filter) (error, []vehicles.Vehicle)
+}
+
+// Rides finds and finishes rides, as rides.Service does.
+type Rides interface {
+	ListRides(ctx context.Context, filter rides.Filter) (error, *rides.Page)
+	ForceFinishRide(ctx context.Context, ride *rides.Ride, reason string, now time.Time) (error, *rides.Ride)
+}
+
+// Watch keeps an eye on the battery of the vehicles in a ride. Riders are
+// warned once when it drops under the warning level, and their ride is
+// finished when it drops under the critical level.
+type Watch struct {
+	vehicles Vehicles
+	rides    Rides
+	notifier Notifier
+	levels   Levels
+	// warned are the rides whose rider was warned already.
+	warned map[uint]bool
+}
+
+func NewWatch(vehicles Vehicles, rides Rides, notifier Notifier, levels Levels) *Watch {
+	return &Watch{
+		vehicles: vehicles,
+		rides:    rides,
+		notifier: notifier,
+		levels:   levels,
+		warned:   map[uint]bool{},
+	}
+}
+
+// Run checks the batteries every interval until ctx is done.
+func (w *Watch) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if err := w.Check(ctx, now); err != nil {
+				log.Printf("Error checking batteries <%s>", err)
+			}
+		}
+	}
+}
+
+// Check warns the riders of the vehicles under the warning level, and
+// finishes the rides of the vehicles under the critical level. A ride that
+// can't be handled is logged and retried on the next check.
+func (w *Watch) Check(ctx context.Context, now time.Time) error {
+	err, low := w.vehicles.ListVehicles(ctx, vehicles.Filter{Status: vehicles.StatusInRide, BatteryBelow: w.levels.Warning})
+	if err != nil {
+		return err
+	}
+
+	warned := map[uint]bool{}
+	for _, vehicle := range low {
+		ride, err := w.openRide(ctx, vehicle)
+		if err != nil {
+			log.Printf("Error finding the ride of vehicle %d <%s>", vehicle.ID, err)
+			continue
+		}
+		if ride == nil {
+			continue
+		}
+
+		if vehicle.Battery < w.levels.Critical {
+			if err, _ := w.rides.ForceFinishRide(ctx, ride, ReasonLowBattery, now); err != nil {
+				log.Printf("Error finishing ride %d <%s>", ride.ID, err)
+				continue
+			}
+			w.notify(ctx, ride.UserID, fmt.Sprintf("Your ride was finished because the battery of the vehicle is at %d%%.", vehicle.Battery))
+			continue
+		}
+
+		message := fmt.Sprintf("The battery of the vehicle is at %d%%, please end your ride soon.", vehicle.Battery)
+		if !w.warned[ride.ID] && !w.notify(ctx, ride.UserID, message) {
+			continue
+		}
+		warned[ride.ID] = true
+	}
+	w.warned = warned
+
+	return nil
+}
+
+// openRide returns the active or paused ride of the vehicle, if any. A
+// vehicle in a ride has it as its latest.
+func (w *Watch) openRide(ctx context.Context, vehicle vehicles.Vehicle) (*rides.Ride, error) {
+	filter := rides.Filter{VehicleID: strconv.FormatUint(uint64(vehicle.ID), 10), Limit: 1}
+	err, page := w.rides.ListRides(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	if len(page.Rides) == 0 {
+		return nil, nil
+	}
+
+	ride := page.Rides[0]
+	if ride.Status != rides.StatusActive && ride.Status != rides.StatusPaused {
+		return nil, nil
+	}
+	return &ride, nil
+}
+
+func (w *Watch) notify(ctx context.Context, userID, message string) bool {
+	if err := w.notifier.Notify(ctx, userID, message); err != nil {
+		log.Printf("Error notifying user %s <%s>", userID, err)
+		return false
+	}
+	return true
+}
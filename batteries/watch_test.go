@@ -0,0 +1,95 @@
+package batteries
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"backend/rides"
+	"backend/vehicles"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	now    = time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)
+	levels = Levels{Start: 20, Warning: 15, Critical: 5}
+)
+
+// fleet lists its vehicles whatever the filter, which the watch is trusted
+// to set.
+type fleet struct {
+	vehicles []vehicles.Vehicle
+	filter   *vehicles.Filter
+}
+
+func (f fleet) ListVehicles(ctx context.Context, filter vehicles.Filter) (error, []vehicles.Vehicle) {
+	*f.filter = filter
+	return nil, f.vehicles
+}
+
+// rideBook knows the latest ride of every vehicle and records the rides it
+// is asked to finish.
+type rideBook struct {
+	latest   map[string]rides.Ride
+	finished *[]string
+}
+
+func (b rideBook) ListRides(ctx context.Context, filter rides.Filter) (error, *rides.Page) {
+	page := &rides.Page{}
+	if ride, ok := b.latest[filter.VehicleID]; ok {
+		page.Rides = append(page.Rides, ride)
+	}
+	return nil, page
+}
+
+func (b rideBook) ForceFinishRide(ctx context.Context, ride *rides.Ride, reason string, now time.Time) (error, *rides.Ride) {
+	*b.finished = append(*b.finished, reason)
+	ride.Status = rides.StatusForceClosed
+	return nil, ride
+}
+
+// inbox records the messages sent to every user.
+type inbox map[string][]string
+
+func (i inbox) Notify(ctx context.Context, userID string, message string) error {
+	i[userID] = append(i[userID], message)
+	return nil
+}
+
+func TestWatchCheck(t *testing.T) {
+	var (
+		filter   vehicles.Filter
+		finished []string
+		messages = inbox{}
+		low      = fleet{
+			vehicles: []vehicles.Vehicle{
+				{ID: 1, Status: vehicles.StatusInRide, Battery: 12},
+				{ID: 2, Status: vehicles.StatusInRide, Battery: 4},
+				{ID: 3, Status: vehicles.StatusInRide, Battery: 10},
+			},
+			filter: &filter,
+		}
+		book = rideBook{
+			latest: map[string]rides.Ride{
+				"1": {ID: 10, UserID: "alice", VehicleID: "1", Status: rides.StatusActive},
+				"2": {ID: 20, UserID: "bob", VehicleID: "2", Status: rides.StatusPaused},
+				"3": {ID: 30, UserID: "carol", VehicleID: "3", Status: rides.StatusFinished},
+			},
+			finished: &finished,
+		}
+		watch = NewWatch(low, book, messages, levels)
+	)
+
+	assert.Nil(t, watch.Check(context.TODO(), now))
+	assert.Equal(t, vehicles.Filter{Status: vehicles.StatusInRide, BatteryBelow: 15}, filter)
+	assert.Equal(t, []string{ReasonLowBattery}, finished)
+	assert.Equal(t, []string{"The battery of the vehicle is at 12%, please end your ride soon."}, messages["alice"])
+	assert.Equal(t, []string{"Your ride was finished because the battery of the vehicle is at 4%."}, messages["bob"])
+	assert.Empty(t, messages["carol"])
+
+	// Riders are only warned once per ride.
+	delete(book.latest, "2")
+	assert.Nil(t, watch.Check(context.TODO(), now.Add(time.Minute)))
+	assert.Len(t, messages["alice"], 1)
+}
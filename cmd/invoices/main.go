@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"time"
+
+	"github.com/go-rel/rel"
+
+	"backend/db"
+	"backend/invoices"
+)
+
+// The invoicing job invoices the rides of a month, the previous one by
+// default. It is meant to run once a month, e.g. from cron on the 1st, and
+// running it again only invoices the rides the previous runs didn't.
+func main() {
+	now := time.Now()
+	month := flag.String("month", now.UTC().AddDate(0, 0, -now.UTC().Day()).Format("2006-01"), "month to invoice, as YYYY-MM")
+	flag.Parse()
+
+	start, err := time.Parse("2006-01", *month)
+	if err != nil {
+		log.Fatalf("Invalid month %q, expected YYYY-MM", *month)
+	}
+
+	adapter, err := db.Open()
+	if err != nil {
+		log.Fatalf("Error opening the database <%s>", err)
+	}
+
+	var (
+		repository = rel.New(adapter)
+		service    = invoices.New(repository)
+	)
+	defer adapter.Close()
+
+	err, issued := service.GenerateInvoices(context.Background(), invoices.MonthOf(start), now)
+	if err != nil {
+		log.Fatalf("Error generating invoices <%s>", err)
+	}
+
+	for _, invoice := range issued {
+		log.Printf("Invoice %s: user %s, %s", invoice.Number, invoice.UserID, invoice.Total())
+	}
+	log.Printf("Issued %d invoices for %s", len(issued), *month)
+}
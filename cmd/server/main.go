@@ -1,45 +1,82 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"strings"
+	"time"
 
-	"github.com/go-rel/postgres"
 	"github.com/go-rel/rel"
-	_ "github.com/lib/pq"
 
 	"backend/api"
+	"backend/batteries"
+	"backend/db"
+	"backend/iot"
+	"backend/rides"
+	"backend/utils"
 )
 
 func main() {
+	adapter, err := db.Open()
+	if err != nil {
+		log.Fatalf("Error opening the database <%s>", err)
+	}
+
 	var (
 		httpPort   = os.Getenv("PORT")
-		adapter    = initDbAdapter()
 		repository = rel.New(adapter)
-		r          = api.NewRouter(repository, httpPort)
+		levels     = batteries.Levels{
+			Start:    utils.GetEnvAsInt("BATTERY_START_LEVEL", batteries.DefaultLevels.Start),
+			Warning:  utils.GetEnvAsInt("BATTERY_WARNING_LEVEL", batteries.DefaultLevels.Warning),
+			Critical: utils.GetEnvAsInt("BATTERY_CRITICAL_LEVEL", batteries.DefaultLevels.Critical),
+		}
+		interval = time.Duration(utils.GetEnvAsInt("BATTERY_CHECK_SECONDS", 60)) * time.Second
+		policy   = iot.Policy{
+			Timeout:  time.Duration(utils.GetEnvAsInt("IOT_TIMEOUT_MS", int(iot.DefaultPolicy.Timeout/time.Millisecond))) * time.Millisecond,
+			Attempts: utils.GetEnvAsInt("IOT_ATTEMPTS", iot.DefaultPolicy.Attempts),
+			Backoff:  time.Duration(utils.GetEnvAsInt("IOT_BACKOFF_MS", int(iot.DefaultPolicy.Backoff/time.Millisecond))) * time.Millisecond,
+		}
+		gateway = iot.NewRetrying(initSimulator(), policy)
+		hold    = utils.GetEnvAsInt("PAYMENT_HOLD_AMOUNT", rides.DefaultHold)
+		mail    = batteries.MailConfig{
+			Host:     os.Getenv("SMTP_HOST"),
+			Port:     utils.GetEnvAsInt("SMTP_PORT", 25),
+			Username: os.Getenv("SMTP_USERNAME"),
+			Password: os.Getenv("SMTP_PASSWORD"),
+			From:     os.Getenv("SMTP_FROM"),
+		}
+		r, watch = api.NewRouter(repository, httpPort, levels, gateway, hold, mail)
 	)
+	if err := policy.Validate(); err != nil {
+		log.Fatalf("Invalid IOT_TIMEOUT_MS, IOT_ATTEMPTS or IOT_BACKOFF_MS <%s>", err)
+	}
+	log.Printf("Vehicle commands give up after %s", policy.Budget())
+	if mail.Host == "" || mail.From == "" {
+		log.Fatalf("Missing SMTP_HOST or SMTP_FROM, riders can't be notified")
+	}
 	defer adapter.Close()
 
+	go watch.Run(context.Background(), interval)
+
 	log.Printf("Server listening at %s", httpPort)
 	if err := http.ListenAndServe(fmt.Sprintf("localhost:%s", httpPort), r); err != http.ErrServerClosed && err != nil {
 		log.Fatalf("Error starting http server <%s>", err)
 	}
 }
 
-func initDbAdapter() rel.Adapter {
-	dsn := fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=disable",
-		os.Getenv("POSTGRESQL_USERNAME"),
-		os.Getenv("POSTGRESQL_PASSWORD"),
-		os.Getenv("POSTGRESQL_HOST"),
-		os.Getenv("POSTGRESQL_PORT"),
-		os.Getenv("POSTGRESQL_DATABASE"))
-
-	adapter, err := postgres.Open(dsn)
-	if err != nil {
-		log.Fatalf(err.Error())
+// initSimulator stands in for the vehicles until the fleet is connected. It
+// can delay every command and leave some vehicles unreachable, to try out
+// how rides behave when their vehicle doesn't answer.
+func initSimulator() *iot.Simulator {
+	simulator := iot.NewSimulator()
+	simulator.Delay(time.Duration(utils.GetEnvAsInt("IOT_SIMULATOR_DELAY_MS", 0)) * time.Millisecond)
+	for _, vehicleID := range strings.Split(os.Getenv("IOT_SIMULATOR_UNREACHABLE"), ",") {
+		if vehicleID = strings.TrimSpace(vehicleID); vehicleID != "" {
+			simulator.Fail(vehicleID, -1)
+		}
 	}
-
-	return adapter
+	return simulator
 }
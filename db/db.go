@@ -0,0 +1,23 @@
+package db
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/go-rel/postgres"
+	"github.com/go-rel/rel"
+	_ "github.com/lib/pq"
+)
+
+// Open connects to the database configured by the POSTGRESQL_* environment
+// variables, so the server and the jobs always share one configuration.
+func Open() (rel.Adapter, error) {
+	dsn := fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=disable",
+		os.Getenv("POSTGRESQL_USERNAME"),
+		os.Getenv("POSTGRESQL_PASSWORD"),
+		os.Getenv("POSTGRESQL_HOST"),
+		os.Getenv("POSTGRESQL_PORT"),
+		os.Getenv("POSTGRESQL_DATABASE"))
+
+	return postgres.Open(dsn)
+}
@@ -0,0 +1,19 @@
+// 20261018090000_add_unfinished_rides_unique_indexes
+
+package migrations
+
+import (
+	"github.com/go-rel/rel"
+)
+
+// MigrateAddUnfinishedRidesUniqueIndexes definition
+func MigrateAddUnfinishedRidesUniqueIndexes(schema *rel.Schema) {
+	schema.Exec(rel.Raw("CREATE UNIQUE INDEX rides_unfinished_user_id_idx ON rides (user_id) WHERE finished = false;"))
+	schema.Exec(rel.Raw("CREATE UNIQUE INDEX rides_unfinished_vehicle_id_idx ON rides (vehicle_id) WHERE finished = false;"))
+}
+
+// RollbackAddUnfinishedRidesUniqueIndexes definition
+func RollbackAddUnfinishedRidesUniqueIndexes(schema *rel.Schema) {
+	schema.DropIndex("rides", "rides_unfinished_vehicle_id_idx")
+	schema.DropIndex("rides", "rides_unfinished_user_id_idx")
+}
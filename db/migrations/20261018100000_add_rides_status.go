@@ -0,0 +1,33 @@
+// 20261018100000_add_rides_status
+
+package migrations
+
+import (
+	"github.com/go-rel/rel"
+)
+
+// MigrateAddRidesStatus definition
+func MigrateAddRidesStatus(schema *rel.Schema) {
+	schema.AddColumn("rides", "status", rel.String, rel.Required(true), rel.Default("active"))
+	schema.Exec(rel.Raw("UPDATE rides SET status = CASE WHEN finished THEN 'finished' ELSE 'active' END;"))
+
+	schema.DropIndex("rides", "rides_unfinished_vehicle_id_idx")
+	schema.DropIndex("rides", "rides_unfinished_user_id_idx")
+	schema.DropColumn("rides", "finished")
+
+	schema.Exec(rel.Raw("CREATE UNIQUE INDEX rides_open_user_id_idx ON rides (user_id) WHERE status IN ('reserved', 'active', 'paused');"))
+	schema.Exec(rel.Raw("CREATE UNIQUE INDEX rides_open_vehicle_id_idx ON rides (vehicle_id) WHERE status IN ('reserved', 'active', 'paused');"))
+}
+
+// RollbackAddRidesStatus definition
+func RollbackAddRidesStatus(schema *rel.Schema) {
+	schema.DropIndex("rides", "rides_open_vehicle_id_idx")
+	schema.DropIndex("rides", "rides_open_user_id_idx")
+
+	schema.AddColumn("rides", "finished", rel.Bool, rel.Required(true), rel.Default(false))
+	schema.Exec(rel.Raw("UPDATE rides SET finished = status NOT IN ('reserved', 'active', 'paused');"))
+	schema.DropColumn("rides", "status")
+
+	schema.Exec(rel.Raw("CREATE UNIQUE INDEX rides_unfinished_user_id_idx ON rides (user_id) WHERE finished = false;"))
+	schema.Exec(rel.Raw("CREATE UNIQUE INDEX rides_unfinished_vehicle_id_idx ON rides (vehicle_id) WHERE finished = false;"))
+}
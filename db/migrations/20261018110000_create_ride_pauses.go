@@ -0,0 +1,26 @@
+// 20261018110000_create_ride_pauses
+
+package migrations
+
+import (
+	"github.com/go-rel/rel"
+)
+
+// MigrateCreateRidePauses definition
+func MigrateCreateRidePauses(schema *rel.Schema) {
+	schema.CreateTable("ride_pauses", func(t *rel.Table) {
+		t.ID("id")
+		t.Int("ride_id", rel.Required(true))
+		t.DateTime("started_at", rel.Required(true))
+		t.DateTime("ended_at")
+
+		t.ForeignKey("ride_id", "rides", "id")
+	})
+
+	schema.CreateIndex("ride_pauses", "ride_pauses_ride_id_idx", []string{"ride_id"})
+}
+
+// RollbackCreateRidePauses definition
+func RollbackCreateRidePauses(schema *rel.Schema) {
+	schema.DropTable("ride_pauses")
+}
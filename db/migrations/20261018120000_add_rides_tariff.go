@@ -0,0 +1,29 @@
+// 20261018120000_add_rides_tariff
+
+package migrations
+
+import (
+	"github.com/go-rel/rel"
+)
+
+// MigrateAddRidesTariff definition
+func MigrateAddRidesTariff(schema *rel.Schema) {
+	schema.AddColumn("rides", "tariff", rel.JSON)
+
+	// Rides started before tariffs were stored get the prices that were
+	// configured back then. Open rides were charged the unlock price they
+	// started with.
+	schema.Exec(rel.Raw(`UPDATE rides SET tariff = jsonb_build_object(
+		'version', 'legacy',
+		'currency', 'EUR',
+		'unlock_price', CASE WHEN status IN ('reserved', 'active', 'paused') THEN price ELSE 18 END,
+		'minute_price', 100,
+		'paused_minute_price', 25,
+		'rounding', 'minute_ceil'
+	);`))
+}
+
+// RollbackAddRidesTariff definition
+func RollbackAddRidesTariff(schema *rel.Schema) {
+	schema.DropColumn("rides", "tariff")
+}
@@ -0,0 +1,37 @@
+// 20261018130000_create_tariffs
+
+package migrations
+
+import (
+	"github.com/go-rel/rel"
+)
+
+// MigrateCreateTariffs definition
+func MigrateCreateTariffs(schema *rel.Schema) {
+	schema.CreateTable("tariffs", func(t *rel.Table) {
+		t.ID("id")
+		t.DateTime("created_at")
+		t.DateTime("updated_at")
+		t.String("name", rel.Required(true))
+		t.String("currency", rel.Limit(3), rel.Required(true))
+		t.Int("unlock_price", rel.Required(true))
+		t.Int("minute_price", rel.Required(true))
+		t.Int("paused_minute_price", rel.Required(true))
+		t.String("rounding", rel.Required(true))
+		t.DateTime("effective_from", rel.Required(true))
+		t.DateTime("effective_to")
+	})
+
+	schema.CreateIndex("tariffs", "tariffs_effective_from_idx", []string{"effective_from"})
+
+	// Rides kept starting with the prices from the environment until now, so
+	// those become the first version.
+	schema.Exec(rel.Raw(`INSERT INTO tariffs
+		(created_at, updated_at, name, currency, unlock_price, minute_price, paused_minute_price, rounding, effective_from)
+		VALUES (NOW(), NOW(), 'Default', 'EUR', 18, 100, 25, 'minute_ceil', '1970-01-01T00:00:00Z');`))
+}
+
+// RollbackCreateTariffs definition
+func RollbackCreateTariffs(schema *rel.Schema) {
+	schema.DropTable("tariffs")
+}
@@ -0,0 +1,19 @@
+// 20261018140000_add_tariffs_rules
+
+package migrations
+
+import (
+	"github.com/go-rel/rel"
+)
+
+// MigrateAddTariffsRules definition
+func MigrateAddTariffsRules(schema *rel.Schema) {
+	schema.AddColumn("tariffs", "time_zone", rel.String, rel.Required(true), rel.Default("UTC"))
+	schema.AddColumn("tariffs", "rules", rel.JSON, rel.Required(true), rel.Default("[]"))
+}
+
+// RollbackAddTariffsRules definition
+func RollbackAddTariffsRules(schema *rel.Schema) {
+	schema.DropColumn("tariffs", "rules")
+	schema.DropColumn("tariffs", "time_zone")
+}
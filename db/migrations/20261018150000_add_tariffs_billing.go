@@ -0,0 +1,31 @@
+// 20261018150000_add_tariffs_billing
+
+package migrations
+
+import (
+	"github.com/go-rel/rel"
+)
+
+// MigrateAddTariffsBilling definition
+func MigrateAddTariffsBilling(schema *rel.Schema) {
+	schema.AddColumn("tariffs", "billing", rel.JSON)
+
+	// minute_ceil was the only rounding rule so far.
+	schema.Exec(rel.Raw(`UPDATE tariffs SET billing = '{"unit":"minute","rounding":"ceil"}';`))
+	schema.Exec(rel.Raw(`ALTER TABLE tariffs ALTER COLUMN billing SET NOT NULL;`))
+	schema.DropColumn("tariffs", "rounding")
+
+	schema.Exec(rel.Raw(`UPDATE rides
+		SET tariff = (tariff - 'rounding') || '{"billing":{"unit":"minute","rounding":"ceil"}}'
+		WHERE tariff IS NOT NULL;`))
+}
+
+// RollbackAddTariffsBilling definition
+func RollbackAddTariffsBilling(schema *rel.Schema) {
+	schema.Exec(rel.Raw(`UPDATE rides
+		SET tariff = (tariff - 'billing') || '{"rounding":"minute_ceil"}'
+		WHERE tariff IS NOT NULL;`))
+
+	schema.AddColumn("tariffs", "rounding", rel.String, rel.Required(true), rel.Default("minute_ceil"))
+	schema.DropColumn("tariffs", "billing")
+}
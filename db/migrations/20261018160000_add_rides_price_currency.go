@@ -0,0 +1,25 @@
+// 20261018160000_add_rides_price_currency
+
+package migrations
+
+import (
+	"github.com/go-rel/rel"
+)
+
+// MigrateAddRidesPriceCurrency definition
+func MigrateAddRidesPriceCurrency(schema *rel.Schema) {
+	schema.RenameColumn("rides", "price", "price_amount")
+	schema.Exec(rel.Raw(`ALTER TABLE rides ALTER COLUMN price_amount TYPE BIGINT;`))
+	schema.AddColumn("rides", "price_currency", rel.String, rel.Limit(3))
+
+	// Rides are charged in the currency of their tariff.
+	schema.Exec(rel.Raw(`UPDATE rides SET price_currency = COALESCE(tariff->>'currency', 'EUR');`))
+	schema.Exec(rel.Raw(`ALTER TABLE rides ALTER COLUMN price_currency SET NOT NULL;`))
+}
+
+// RollbackAddRidesPriceCurrency definition
+func RollbackAddRidesPriceCurrency(schema *rel.Schema) {
+	schema.DropColumn("rides", "price_currency")
+	schema.Exec(rel.Raw(`ALTER TABLE rides ALTER COLUMN price_amount TYPE INTEGER;`))
+	schema.RenameColumn("rides", "price_amount", "price")
+}
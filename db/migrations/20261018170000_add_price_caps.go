@@ -0,0 +1,23 @@
+// 20261018170000_add_price_caps
+
+package migrations
+
+import (
+	"github.com/go-rel/rel"
+)
+
+// MigrateAddPriceCaps definition
+func MigrateAddPriceCaps(schema *rel.Schema) {
+	schema.AddColumn("tariffs", "caps", rel.JSON, rel.Required(true), rel.Default("{}"))
+
+	// Rides finished so far were not itemised.
+	schema.AddColumn("rides", "items", rel.JSON, rel.Required(true), rel.Default("[]"))
+	schema.CreateIndex("rides", "rides_user_id_created_at_idx", []string{"user_id", "created_at"})
+}
+
+// RollbackAddPriceCaps definition
+func RollbackAddPriceCaps(schema *rel.Schema) {
+	schema.DropIndex("rides", "rides_user_id_created_at_idx")
+	schema.DropColumn("rides", "items")
+	schema.DropColumn("tariffs", "caps")
+}
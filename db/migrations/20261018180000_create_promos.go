@@ -0,0 +1,51 @@
+// 20261018180000_create_promos
+
+package migrations
+
+import (
+	"github.com/go-rel/rel"
+)
+
+// MigrateCreatePromos definition
+func MigrateCreatePromos(schema *rel.Schema) {
+	schema.CreateTable("promos", func(t *rel.Table) {
+		t.ID("id")
+		t.DateTime("created_at")
+		t.DateTime("updated_at")
+		t.String("code", rel.Required(true), rel.Unique(true))
+		t.String("kind", rel.Required(true))
+		t.Int("percent", rel.Required(true), rel.Default(0))
+		t.Int("minutes", rel.Required(true), rel.Default(0))
+		t.Int("amount", rel.Required(true), rel.Default(0))
+		t.String("currency", rel.Limit(3), rel.Required(true), rel.Default(""))
+		t.DateTime("starts_at", rel.Required(true))
+		t.DateTime("ends_at")
+		t.Int("max_redemptions", rel.Required(true), rel.Default(0))
+		t.Int("max_redemptions_per_user", rel.Required(true), rel.Default(0))
+		t.Int("redemptions", rel.Required(true), rel.Default(0))
+	})
+
+	schema.CreateTable("promo_redemptions", func(t *rel.Table) {
+		t.ID("id")
+		t.DateTime("created_at")
+		t.Int("promo_id", rel.Required(true))
+		t.String("user_id", rel.Required(true))
+		t.Int("ride_id", rel.Required(true))
+
+		t.ForeignKey("promo_id", "promos", "id")
+		t.ForeignKey("ride_id", "rides", "id")
+	})
+
+	schema.CreateIndex("promo_redemptions", "promo_redemptions_promo_id_user_id_idx", []string{"promo_id", "user_id"})
+
+	schema.AddColumn("rides", "promo_code", rel.String)
+	schema.AddColumn("rides", "discount", rel.JSON)
+}
+
+// RollbackCreatePromos definition
+func RollbackCreatePromos(schema *rel.Schema) {
+	schema.DropColumn("rides", "discount")
+	schema.DropColumn("rides", "promo_code")
+	schema.DropTable("promo_redemptions")
+	schema.DropTable("promos")
+}
@@ -0,0 +1,65 @@
+// 20261018190000_create_passes
+
+package migrations
+
+import (
+	"github.com/go-rel/rel"
+)
+
+// MigrateCreatePasses definition
+func MigrateCreatePasses(schema *rel.Schema) {
+	schema.CreateTable("pass_products", func(t *rel.Table) {
+		t.ID("id")
+		t.DateTime("created_at")
+		t.DateTime("updated_at")
+		t.String("name", rel.Required(true))
+		t.Bool("free_unlocks", rel.Required(true), rel.Default(false))
+		t.Int("minutes", rel.Required(true), rel.Default(0))
+		t.Int("validity_days", rel.Required(true))
+		t.Int("price", rel.Required(true), rel.Default(0))
+		t.String("currency", rel.Limit(3), rel.Required(true))
+	})
+
+	schema.CreateTable("passes", func(t *rel.Table) {
+		t.ID("id")
+		t.DateTime("created_at")
+		t.DateTime("updated_at")
+		t.String("user_id", rel.Required(true))
+		t.Int("product_id", rel.Required(true))
+		t.String("name", rel.Required(true))
+		t.Bool("free_unlocks", rel.Required(true), rel.Default(false))
+		t.Int("minutes", rel.Required(true), rel.Default(0))
+		t.Int("minutes_left", rel.Required(true), rel.Default(0))
+		t.Int("price", rel.Required(true), rel.Default(0))
+		t.String("currency", rel.Limit(3), rel.Required(true))
+		t.DateTime("starts_at", rel.Required(true))
+		t.DateTime("ends_at", rel.Required(true))
+
+		t.ForeignKey("product_id", "pass_products", "id")
+	})
+
+	schema.CreateIndex("passes", "passes_user_id_ends_at_idx", []string{"user_id", "ends_at"})
+
+	schema.CreateTable("pass_consumptions", func(t *rel.Table) {
+		t.ID("id")
+		t.DateTime("created_at")
+		t.Int("pass_id", rel.Required(true))
+		t.Int("ride_id", rel.Required(true))
+		t.Bool("unlock", rel.Required(true), rel.Default(false))
+		t.Int("minutes", rel.Required(true), rel.Default(0))
+		t.Int("amount", rel.Required(true), rel.Default(0))
+		t.DateTime("reversed_at")
+
+		t.ForeignKey("pass_id", "passes", "id")
+		t.ForeignKey("ride_id", "rides", "id")
+	})
+
+	schema.CreateIndex("pass_consumptions", "pass_consumptions_ride_id_idx", []string{"ride_id"})
+}
+
+// RollbackCreatePasses definition
+func RollbackCreatePasses(schema *rel.Schema) {
+	schema.DropTable("pass_consumptions")
+	schema.DropTable("passes")
+	schema.DropTable("pass_products")
+}
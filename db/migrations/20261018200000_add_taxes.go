@@ -0,0 +1,44 @@
+// 20261018200000_add_taxes
+
+package migrations
+
+import (
+	"github.com/go-rel/rel"
+)
+
+// MigrateAddTaxes definition
+func MigrateAddTaxes(schema *rel.Schema) {
+	schema.CreateTable("tax_rates", func(t *rel.Table) {
+		t.ID("id")
+		t.DateTime("created_at")
+		t.DateTime("updated_at")
+		t.String("city", rel.Required(true), rel.Default(""))
+		t.String("operator", rel.Required(true), rel.Default(""))
+		t.String("name", rel.Required(true))
+		t.Int("rate", rel.Required(true))
+		t.String("rounding", rel.Required(true))
+	})
+
+	// Either the city or the operator is blank, so this is one rate per
+	// city and one per operator.
+	schema.CreateIndex("tax_rates", "tax_rates_city_operator_idx", []string{"city", "operator"}, rel.Unique(true))
+
+	schema.AddColumn("rides", "city", rel.String, rel.Required(true), rel.Default(""))
+	schema.AddColumn("rides", "operator", rel.String, rel.Required(true), rel.Default(""))
+	schema.AddColumn("rides", "tax_rate", rel.JSON, rel.Required(true), rel.Default("{}"))
+	schema.AddColumn("rides", "net_amount", rel.BigInt, rel.Required(true), rel.Default(0))
+	schema.AddColumn("rides", "tax_amount", rel.BigInt, rel.Required(true), rel.Default(0))
+
+	// Rides charged so far were not taxed.
+	schema.Exec(rel.Raw(`UPDATE rides SET net_amount = price_amount;`))
+}
+
+// RollbackAddTaxes definition
+func RollbackAddTaxes(schema *rel.Schema) {
+	schema.DropColumn("rides", "tax_amount")
+	schema.DropColumn("rides", "net_amount")
+	schema.DropColumn("rides", "tax_rate")
+	schema.DropColumn("rides", "operator")
+	schema.DropColumn("rides", "city")
+	schema.DropTable("tax_rates")
+}
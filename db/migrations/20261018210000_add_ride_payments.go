@@ -0,0 +1,22 @@
+// 20261018210000_add_ride_payments
+
+package migrations
+
+import (
+	"github.com/go-rel/rel"
+)
+
+// MigrateAddRidePayments definition
+func MigrateAddRidePayments(schema *rel.Schema) {
+	// Rides started so far were never charged, so they have no payment.
+	schema.AddColumn("rides", "payment_id", rel.String, rel.Required(true), rel.Default(""))
+	schema.AddColumn("rides", "payment_status", rel.String, rel.Required(true), rel.Default(""))
+	schema.CreateIndex("rides", "rides_payment_status_idx", []string{"payment_status"})
+}
+
+// RollbackAddRidePayments definition
+func RollbackAddRidePayments(schema *rel.Schema) {
+	schema.DropIndex("rides", "rides_payment_status_idx")
+	schema.DropColumn("rides", "payment_status")
+	schema.DropColumn("rides", "payment_id")
+}
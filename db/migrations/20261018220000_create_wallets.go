@@ -0,0 +1,50 @@
+// 20261018220000_create_wallets
+
+package migrations
+
+import (
+	"github.com/go-rel/rel"
+)
+
+// MigrateCreateWallets definition
+func MigrateCreateWallets(schema *rel.Schema) {
+	schema.CreateTable("wallet_transactions", func(t *rel.Table) {
+		t.ID("id")
+		t.DateTime("created_at")
+		t.String("user_id", rel.Required(true))
+		t.String("kind", rel.Required(true))
+		t.BigInt("amount", rel.Required(true))
+		t.String("currency", rel.Limit(3), rel.Required(true))
+		t.Int("ride_id")
+		t.String("reference", rel.Required(true), rel.Default(""))
+		t.String("description", rel.Required(true), rel.Default(""))
+
+		t.ForeignKey("ride_id", "rides", "id")
+	})
+
+	schema.CreateIndex("wallet_transactions", "wallet_transactions_user_id_idx", []string{"user_id"})
+	schema.CreateIndex("wallet_transactions", "wallet_transactions_ride_id_idx", []string{"ride_id"})
+
+	schema.CreateTable("wallet_entries", func(t *rel.Table) {
+		t.ID("id")
+		t.DateTime("created_at")
+		t.Int("transaction_id", rel.Required(true))
+		t.String("account", rel.Required(true))
+		t.BigInt("amount", rel.Required(true))
+		t.String("currency", rel.Limit(3), rel.Required(true))
+
+		t.ForeignKey("transaction_id", "wallet_transactions", "id")
+	})
+
+	schema.CreateIndex("wallet_entries", "wallet_entries_account_currency_idx", []string{"account", "currency"})
+
+	// Tariffs so far were charged to the payment methods of users.
+	schema.AddColumn("tariffs", "prepaid", rel.JSON, rel.Required(true), rel.Default("{}"))
+}
+
+// RollbackCreateWallets definition
+func RollbackCreateWallets(schema *rel.Schema) {
+	schema.DropColumn("tariffs", "prepaid")
+	schema.DropTable("wallet_entries")
+	schema.DropTable("wallet_transactions")
+}
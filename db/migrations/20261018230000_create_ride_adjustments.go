@@ -0,0 +1,46 @@
+// 20261018230000_create_ride_adjustments
+
+package migrations
+
+import (
+	"github.com/go-rel/rel"
+)
+
+// MigrateCreateRideAdjustments definition
+func MigrateCreateRideAdjustments(schema *rel.Schema) {
+	schema.CreateTable("ride_adjustments", func(t *rel.Table) {
+		t.ID("id")
+		t.DateTime("created_at")
+		t.Int("ride_id", rel.Required(true))
+		t.BigInt("amount", rel.Required(true))
+		t.String("currency", rel.Limit(3), rel.Required(true))
+		t.String("reason", rel.Required(true))
+		t.Text("note")
+		t.String("agent", rel.Required(true))
+
+		t.ForeignKey("ride_id", "rides", "id")
+	})
+
+	schema.CreateIndex("ride_adjustments", "ride_adjustments_ride_id_idx", []string{"ride_id"})
+
+	// Rides finished so far were never adjusted.
+	schema.AddColumn("rides", "adjustments_amount", rel.BigInt, rel.Required(true), rel.Default(0))
+
+	schema.CreateTable("audit_records", func(t *rel.Table) {
+		t.ID("id")
+		t.DateTime("created_at")
+		t.String("actor", rel.Required(true))
+		t.String("action", rel.Required(true))
+		t.String("subject", rel.Required(true))
+		t.JSON("details", rel.Required(true), rel.Default("{}"))
+	})
+
+	schema.CreateIndex("audit_records", "audit_records_subject_idx", []string{"subject"})
+}
+
+// RollbackCreateRideAdjustments definition
+func RollbackCreateRideAdjustments(schema *rel.Schema) {
+	schema.DropTable("audit_records")
+	schema.DropColumn("rides", "adjustments_amount")
+	schema.DropTable("ride_adjustments")
+}
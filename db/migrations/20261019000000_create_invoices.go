@@ -0,0 +1,48 @@
+// 20261019000000_create_invoices
+
+package migrations
+
+import (
+	"github.com/go-rel/rel"
+)
+
+// MigrateCreateInvoices definition
+func MigrateCreateInvoices(schema *rel.Schema) {
+	schema.CreateTable("invoice_counters", func(t *rel.Table) {
+		t.ID("id")
+		t.String("series", rel.Required(true))
+		t.Int("last_number", rel.Required(true), rel.Default(0))
+	})
+
+	schema.CreateIndex("invoice_counters", "invoice_counters_series_idx", []string{"series"}, rel.Unique(true))
+
+	schema.CreateTable("invoices", func(t *rel.Table) {
+		t.ID("id")
+		t.DateTime("created_at")
+		t.String("number", rel.Required(true))
+		t.String("user_id", rel.Required(true))
+		t.DateTime("period_start", rel.Required(true))
+		t.DateTime("period_end", rel.Required(true))
+		t.String("currency", rel.Limit(3), rel.Required(true))
+		t.BigInt("net_amount", rel.Required(true))
+		t.BigInt("tax_amount", rel.Required(true))
+		t.BigInt("total_amount", rel.Required(true))
+		t.JSON("taxes", rel.Required(true), rel.Default("[]"))
+		t.JSON("lines", rel.Required(true), rel.Default("[]"))
+	})
+
+	schema.CreateIndex("invoices", "invoices_number_idx", []string{"number"}, rel.Unique(true))
+	schema.CreateIndex("invoices", "invoices_user_id_idx", []string{"user_id"})
+
+	// Rides finished so far get invoiced by the first run.
+	schema.AddColumn("rides", "invoice_id", rel.Int)
+	schema.CreateIndex("rides", "rides_status_invoice_id_idx", []string{"status", "invoice_id"})
+}
+
+// RollbackCreateInvoices definition
+func RollbackCreateInvoices(schema *rel.Schema) {
+	schema.DropIndex("rides", "rides_status_invoice_id_idx")
+	schema.DropColumn("rides", "invoice_id")
+	schema.DropTable("invoices")
+	schema.DropTable("invoice_counters")
+}
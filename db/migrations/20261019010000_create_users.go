@@ -0,0 +1,28 @@
+// 20261019010000_create_users
+
+package migrations
+
+import (
+	"github.com/go-rel/rel"
+)
+
+// MigrateCreateUsers definition
+func MigrateCreateUsers(schema *rel.Schema) {
+	schema.CreateTable("users", func(t *rel.Table) {
+		t.ID("id")
+		t.DateTime("created_at")
+		t.DateTime("updated_at")
+		t.String("name", rel.Required(true))
+		t.String("email", rel.Required(true))
+		t.String("status", rel.Required(true), rel.Default("active"))
+
+		t.Unique([]string{"email"})
+	})
+
+	schema.CreateIndex("users", "users_status_idx", []string{"status"})
+}
+
+// RollbackCreateUsers definition
+func RollbackCreateUsers(schema *rel.Schema) {
+	schema.DropTable("users")
+}
@@ -0,0 +1,29 @@
+// 20261019020000_create_vehicles
+
+package migrations
+
+import (
+	"github.com/go-rel/rel"
+)
+
+// MigrateCreateVehicles definition
+func MigrateCreateVehicles(schema *rel.Schema) {
+	schema.CreateTable("vehicles", func(t *rel.Table) {
+		t.ID("id")
+		t.DateTime("created_at")
+		t.DateTime("updated_at")
+		t.String("type", rel.Required(true))
+		t.String("model", rel.Required(true))
+		t.String("status", rel.Required(true), rel.Default("available"))
+		t.SmallInt("battery", rel.Required(true), rel.Default(0))
+		t.Float("latitude", rel.Required(true), rel.Default(0))
+		t.Float("longitude", rel.Required(true), rel.Default(0))
+	})
+
+	schema.CreateIndex("vehicles", "vehicles_status_idx", []string{"status"})
+}
+
+// RollbackCreateVehicles definition
+func RollbackCreateVehicles(schema *rel.Schema) {
+	schema.DropTable("vehicles")
+}
@@ -0,0 +1,22 @@
+// 20261019030000_add_tariffs_scope
+
+package migrations
+
+import (
+	"github.com/go-rel/rel"
+)
+
+// MigrateAddTariffsScope definition
+func MigrateAddTariffsScope(schema *rel.Schema) {
+	schema.AddColumn("tariffs", "vehicle_type", rel.String, rel.Required(true), rel.Default(""))
+	schema.AddColumn("tariffs", "city", rel.String, rel.Required(true), rel.Default(""))
+
+	schema.CreateIndex("tariffs", "tariffs_scope_idx", []string{"vehicle_type", "city"})
+}
+
+// RollbackAddTariffsScope definition
+func RollbackAddTariffsScope(schema *rel.Schema) {
+	schema.DropIndex("tariffs", "tariffs_scope_idx")
+	schema.DropColumn("tariffs", "city")
+	schema.DropColumn("tariffs", "vehicle_type")
+}
@@ -0,0 +1,22 @@
+// 20261019040000_add_ride_adjustments_status
+
+package migrations
+
+import (
+	"github.com/go-rel/rel"
+)
+
+// MigrateAddRideAdjustmentsStatus definition
+func MigrateAddRideAdjustmentsStatus(schema *rel.Schema) {
+	schema.AddColumn("ride_adjustments", "status", rel.String, rel.Required(true), rel.Default("settled"))
+	schema.AddColumn("ride_adjustments", "settled_at", rel.DateTime)
+
+	schema.CreateIndex("ride_adjustments", "ride_adjustments_status_idx", []string{"status"})
+}
+
+// RollbackAddRideAdjustmentsStatus definition
+func RollbackAddRideAdjustmentsStatus(schema *rel.Schema) {
+	schema.DropIndex("ride_adjustments", "ride_adjustments_status_idx")
+	schema.DropColumn("ride_adjustments", "settled_at")
+	schema.DropColumn("ride_adjustments", "status")
+}
@@ -0,0 +1,17 @@
+// 20261019050000_add_vehicles_city
+
+package migrations
+
+import (
+	"github.com/go-rel/rel"
+)
+
+// MigrateAddVehiclesCity definition
+func MigrateAddVehiclesCity(schema *rel.Schema) {
+	schema.AddColumn("vehicles", "city", rel.String, rel.Required(true), rel.Default(""))
+}
+
+// RollbackAddVehiclesCity definition
+func RollbackAddVehiclesCity(schema *rel.Schema) {
+	schema.DropColumn("vehicles", "city")
+}
@@ -0,0 +1,17 @@
+// 20261019060000_add_rides_hold_amount
+
+package migrations
+
+import (
+	"github.com/go-rel/rel"
+)
+
+// MigrateAddRidesHoldAmount definition
+func MigrateAddRidesHoldAmount(schema *rel.Schema) {
+	schema.AddColumn("rides", "hold_amount", rel.BigInt, rel.Required(true), rel.Default(0))
+}
+
+// RollbackAddRidesHoldAmount definition
+func RollbackAddRidesHoldAmount(schema *rel.Schema) {
+	schema.DropColumn("rides", "hold_amount")
+}
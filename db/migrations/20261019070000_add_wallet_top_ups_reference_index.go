@@ -0,0 +1,17 @@
+// 20261019070000_add_wallet_top_ups_reference_index
+
+package migrations
+
+import (
+	"github.com/go-rel/rel"
+)
+
+// MigrateAddWalletTopUpsReferenceIndex definition
+func MigrateAddWalletTopUpsReferenceIndex(schema *rel.Schema) {
+	schema.Exec(rel.Raw("CREATE UNIQUE INDEX wallet_transactions_top_up_reference_idx ON wallet_transactions (reference) WHERE kind = 'top_up' AND reference <> '';"))
+}
+
+// RollbackAddWalletTopUpsReferenceIndex definition
+func RollbackAddWalletTopUpsReferenceIndex(schema *rel.Schema) {
+	schema.DropIndex("wallet_transactions", "wallet_transactions_top_up_reference_idx")
+}
@@ -0,0 +1,17 @@
+// 20261019080000_add_vehicles_operator
+
+package migrations
+
+import (
+	"github.com/go-rel/rel"
+)
+
+// MigrateAddVehiclesOperator definition
+func MigrateAddVehiclesOperator(schema *rel.Schema) {
+	schema.AddColumn("vehicles", "operator", rel.String, rel.Required(true), rel.Default(""))
+}
+
+// RollbackAddVehiclesOperator definition
+func RollbackAddVehiclesOperator(schema *rel.Schema) {
+	schema.DropColumn("vehicles", "operator")
+}
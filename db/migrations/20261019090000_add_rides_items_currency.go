@@ -0,0 +1,30 @@
+// 20261019090000_add_rides_items_currency
+
+package migrations
+
+import (
+	"github.com/go-rel/rel"
+)
+
+// MigrateAddRidesItemsCurrency definition
+func MigrateAddRidesItemsCurrency(schema *rel.Schema) {
+	// The items of a ride are charged in the currency of its price.
+	schema.Exec(rel.Raw(`UPDATE rides SET items = (
+		SELECT COALESCE(jsonb_agg(item || jsonb_build_object(
+			'unit_price', jsonb_build_object('amount', item->'unit_price', 'currency', price_currency),
+			'amount', jsonb_build_object('amount', item->'amount', 'currency', price_currency)
+		) ORDER BY position), '[]'::jsonb)
+		FROM jsonb_array_elements(items) WITH ORDINALITY AS elements(item, position)
+	);`))
+}
+
+// RollbackAddRidesItemsCurrency definition
+func RollbackAddRidesItemsCurrency(schema *rel.Schema) {
+	schema.Exec(rel.Raw(`UPDATE rides SET items = (
+		SELECT COALESCE(jsonb_agg(item || jsonb_build_object(
+			'unit_price', item->'unit_price'->'amount',
+			'amount', item->'amount'->'amount'
+		) ORDER BY position), '[]'::jsonb)
+		FROM jsonb_array_elements(items) WITH ORDINALITY AS elements(item, position)
+	);`))
+}
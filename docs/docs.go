@@ -24,7 +24,396 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/passes": {
+            "get": {
+                "description": "list passes with what they have left, the latest to expire first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "passes"
+                ],
+                "summary": "lists the passes of a user.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.PassListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "purchase pass, valid from now for the validity days of its product",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "passes"
+                ],
+                "summary": "purchases a pass.",
+                "parameters": [
+                    {
+                        "description": "Purchase request parameters",
+                        "name": "params",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.PurchaseRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/passes.Pass"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/passes/products": {
+            "get": {
+                "description": "list pass products",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "passes"
+                ],
+                "summary": "lists every pass product.",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ProductListResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "create pass product: free unlocks, minutes or both",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "passes"
+                ],
+                "summary": "puts a pass on sale.",
+                "parameters": [
+                    {
+                        "description": "Product request parameters",
+                        "name": "params",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ProductRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/passes.Product"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/passes/rides/{id}/reverse": {
+            "post": {
+                "description": "reverse the pass consumption of a ride whose charge was refunded, reversing twice gives nothing back",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "passes"
+                ],
+                "summary": "gives back what a ride consumed of the passes.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Ride ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ConsumptionListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/promos": {
+            "get": {
+                "description": "list promos, the latest to start first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "promos"
+                ],
+                "summary": "lists every promo code.",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.PromoListResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "create promo: free_unlock, percent_off, free_minutes or amount_off",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "promos"
+                ],
+                "summary": "creates a promo code.",
+                "parameters": [
+                    {
+                        "description": "Promo request parameters",
+                        "name": "params",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.PromoRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/promos.Promo"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/promos/{code}": {
+            "get": {
+                "description": "get promo, codes are case-insensitive",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "promos"
+                ],
+                "summary": "returns the promo that matches the given code.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Promo code",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/promos.Promo"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/rides": {
+            "get": {
+                "description": "list rides using cursor-based pagination",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rides"
+                ],
+                "summary": "lists rides from newest to oldest.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter by user ID",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by vehicle ID",
+                        "name": "vehicle_id",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "reserved",
+                            "active",
+                            "paused",
+                            "finished",
+                            "cancelled",
+                            "force_closed"
+                        ],
+                        "type": "string",
+                        "description": "Filter by status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only rides created at or after this RFC 3339 timestamp",
+                        "name": "created_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only rides created before this RFC 3339 timestamp",
+                        "name": "created_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor returned as next_cursor by the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 20, max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/rides.Page"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "rides": {
+                                            "type": "array",
+                                            "items": {
+                                                "allOf": [
+                                                    {
+                                                        "$ref": "#/definitions/rides.Ride"
+                                                    },
+                                                    {
+                                                        "type": "object",
+                                                        "properties": {
+                                                            "adjustments": {
+                                                                "$ref": "#/definitions/money.Money"
+                                                            },
+                                                            "effective_price": {
+                                                                "$ref": "#/definitions/money.Money"
+                                                            },
+                                                            "hold": {
+                                                                "$ref": "#/definitions/money.Money"
+                                                            },
+                                                            "net": {
+                                                                "$ref": "#/definitions/money.Money"
+                                                            },
+                                                            "price": {
+                                                                "$ref": "#/definitions/money.Money"
+                                                            },
+                                                            "tax": {
+                                                                "$ref": "#/definitions/money.Money"
+                                                            }
+                                                        }
+                                                    }
+                                                ]
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrResponse"
+                        }
+                    }
+                }
+            },
             "post": {
                 "description": "create ride",
                 "consumes": [
@@ -52,28 +441,82 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/rides.Ride"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/rides.Ride"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "adjustments": {
+                                            "$ref": "#/definitions/money.Money"
+                                        },
+                                        "effective_price": {
+                                            "$ref": "#/definitions/money.Money"
+                                        },
+                                        "hold": {
+                                            "$ref": "#/definitions/money.Money"
+                                        },
+                                        "net": {
+                                            "$ref": "#/definitions/money.Money"
+                                        },
+                                        "price": {
+                                            "$ref": "#/definitions/money.Money"
+                                        },
+                                        "tax": {
+                                            "$ref": "#/definitions/money.Money"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "402": {
+                        "description": "Payment Required",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrResponse"
                         }
                     }
                 }
             }
         },
-        "/rides/:id/finish": {
-            "post": {
-                "description": "finish ride",
-                "consumes": [
-                    "application/json"
-                ],
+        "/rides/{id}": {
+            "get": {
+                "description": "get ride",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "rides"
                 ],
-                "summary": "finishes the ride that matches the given ID.",
+                "summary": "returns the ride that matches the given ID.",
                 "parameters": [
                     {
-                        "type": "string",
+                        "type": "integer",
                         "description": "Ride ID",
                         "name": "id",
                         "in": "path",
@@ -84,40 +527,2908 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/rides.Ride"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/rides.Ride"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "adjustments": {
+                                            "$ref": "#/definitions/money.Money"
+                                        },
+                                        "effective_price": {
+                                            "$ref": "#/definitions/money.Money"
+                                        },
+                                        "hold": {
+                                            "$ref": "#/definitions/money.Money"
+                                        },
+                                        "net": {
+                                            "$ref": "#/definitions/money.Money"
+                                        },
+                                        "price": {
+                                            "$ref": "#/definitions/money.Money"
+                                        },
+                                        "tax": {
+                                            "$ref": "#/definitions/money.Money"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrResponse"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "handlers.RideRequest": {
-            "type": "object",
-            "properties": {
-                "user_id": {
-                    "type": "string"
-                },
-                "vehicle_id": {
-                    "type": "string"
-                }
-            }
         },
-        "rides.Ride": {
-            "type": "object",
-            "properties": {
-                "created_at": {
-                    "type": "string"
-                },
-                "finished": {
-                    "type": "boolean"
-                },
-                "id": {
+        "/rides/{id}/adjustments": {
+            "get": {
+                "description": "list adjustments, the oldest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rides"
+                ],
+                "summary": "lists the adjustments of the ride that matches the given ID.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Ride ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.AdjustmentListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "adjust the price of a finished ride, which is kept as charged, and record who did it and why. The adjustment is refunded or charged on the card of the user, and answered with 202 and left pending when the provider fails",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rides"
+                ],
+                "summary": "adjusts the price of the ride that matches the given ID.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Ride ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Adjustment request parameters",
+                        "name": "params",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.AdjustmentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.AdjustmentResponse"
+                        }
+                    },
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.AdjustmentResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/rides/{id}/adjustments/{adjustmentID}/settle": {
+            "post": {
+                "description": "refund or charge a pending adjustment on the card of the user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rides"
+                ],
+                "summary": "retries settling an adjustment of the ride that matches the given ID.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Ride ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Adjustment ID",
+                        "name": "adjustmentID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.AdjustmentResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/rides/{id}/cancel": {
+            "post": {
+                "description": "cancel a reserved ride, or an active one within 2 minutes of its start, without charging it, locking its vehicle, giving back its promo code and releasing its payment hold",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rides"
+                ],
+                "summary": "cancels the ride that matches the given ID.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Ride ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/rides.Ride"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "adjustments": {
+                                            "$ref": "#/definitions/money.Money"
+                                        },
+                                        "effective_price": {
+                                            "$ref": "#/definitions/money.Money"
+                                        },
+                                        "hold": {
+                                            "$ref": "#/definitions/money.Money"
+                                        },
+                                        "net": {
+                                            "$ref": "#/definitions/money.Money"
+                                        },
+                                        "price": {
+                                            "$ref": "#/definitions/money.Money"
+                                        },
+                                        "tax": {
+                                            "$ref": "#/definitions/money.Money"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/rides/{id}/capture": {
+            "post": {
+                "description": "capture the payment of a finished ride whose payment is pending",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rides"
+                ],
+                "summary": "retries capturing the price of the ride that matches the given ID.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Ride ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/rides.Ride"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "adjustments": {
+                                            "$ref": "#/definitions/money.Money"
+                                        },
+                                        "effective_price": {
+                                            "$ref": "#/definitions/money.Money"
+                                        },
+                                        "hold": {
+                                            "$ref": "#/definitions/money.Money"
+                                        },
+                                        "net": {
+                                            "$ref": "#/definitions/money.Money"
+                                        },
+                                        "price": {
+                                            "$ref": "#/definitions/money.Money"
+                                        },
+                                        "tax": {
+                                            "$ref": "#/definitions/money.Money"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/rides/{id}/finish": {
+            "post": {
+                "description": "finish ride, locking its vehicle. The ride stays open when the vehicle can't be locked",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rides"
+                ],
+                "summary": "finishes the ride that matches the given ID.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Ride ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/rides.Ride"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "adjustments": {
+                                            "$ref": "#/definitions/money.Money"
+                                        },
+                                        "effective_price": {
+                                            "$ref": "#/definitions/money.Money"
+                                        },
+                                        "hold": {
+                                            "$ref": "#/definitions/money.Money"
+                                        },
+                                        "net": {
+                                            "$ref": "#/definitions/money.Money"
+                                        },
+                                        "price": {
+                                            "$ref": "#/definitions/money.Money"
+                                        },
+                                        "tax": {
+                                            "$ref": "#/definitions/money.Money"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/rides/{id}/pause": {
+            "post": {
+                "description": "pause ride, paused minutes are billed at a lower rate",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rides"
+                ],
+                "summary": "pauses the ride that matches the given ID.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Ride ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/rides.Ride"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "adjustments": {
+                                            "$ref": "#/definitions/money.Money"
+                                        },
+                                        "effective_price": {
+                                            "$ref": "#/definitions/money.Money"
+                                        },
+                                        "hold": {
+                                            "$ref": "#/definitions/money.Money"
+                                        },
+                                        "net": {
+                                            "$ref": "#/definitions/money.Money"
+                                        },
+                                        "price": {
+                                            "$ref": "#/definitions/money.Money"
+                                        },
+                                        "tax": {
+                                            "$ref": "#/definitions/money.Money"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/rides/{id}/quote": {
+            "get": {
+                "description": "quote ride, priced as if it finished at the given instant without storing anything",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rides"
+                ],
+                "summary": "returns the running price of the ride that matches the given ID.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Ride ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "RFC 3339 timestamp, defaults to now",
+                        "name": "at",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/rides.Quote"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/rides/{id}/resume": {
+            "post": {
+                "description": "resume ride",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rides"
+                ],
+                "summary": "resumes the paused ride that matches the given ID.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Ride ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/rides.Ride"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "adjustments": {
+                                            "$ref": "#/definitions/money.Money"
+                                        },
+                                        "effective_price": {
+                                            "$ref": "#/definitions/money.Money"
+                                        },
+                                        "hold": {
+                                            "$ref": "#/definitions/money.Money"
+                                        },
+                                        "net": {
+                                            "$ref": "#/definitions/money.Money"
+                                        },
+                                        "price": {
+                                            "$ref": "#/definitions/money.Money"
+                                        },
+                                        "tax": {
+                                            "$ref": "#/definitions/money.Money"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tariffs": {
+            "get": {
+                "description": "list tariffs, the latest to become effective first. Given a vehicle_type or city, only the versions for it and the generic ones are listed, so the app can show prices before unlocking",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tariffs"
+                ],
+                "summary": "lists the tariff versions, optionally those for a vehicle type or city.",
+                "parameters": [
+                    {
+                        "enum": [
+                            "scooter",
+                            "bike",
+                            "moped"
+                        ],
+                        "type": "string",
+                        "description": "Vehicle type",
+                        "name": "vehicle_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "City",
+                        "name": "city",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.TariffListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "create tariff, effective right away unless effective_from is given",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tariffs"
+                ],
+                "summary": "schedules a tariff version.",
+                "parameters": [
+                    {
+                        "description": "Tariff request parameters",
+                        "name": "params",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.TariffRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/tariffs.Tariff"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tariffs/active": {
+            "get": {
+                "description": "preview the tariff rides on the vehicle type in the city starting at the given instant are billed with, falling back to the generic versions",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tariffs"
+                ],
+                "summary": "returns the tariff active at the given instant.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "RFC 3339 timestamp, defaults to now",
+                        "name": "at",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "scooter",
+                            "bike",
+                            "moped"
+                        ],
+                        "type": "string",
+                        "description": "Vehicle type",
+                        "name": "vehicle_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "City",
+                        "name": "city",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/tariffs.Tariff"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tariffs/{id}": {
+            "get": {
+                "description": "get tariff",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tariffs"
+                ],
+                "summary": "returns the tariff that matches the given ID.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Tariff ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/tariffs.Tariff"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "update tariff, only the effective_to of a tariff that is already effective can change",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tariffs"
+                ],
+                "summary": "replaces the tariff that matches the given ID.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Tariff ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Tariff request parameters",
+                        "name": "params",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.TariffRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/tariffs.Tariff"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "delete tariff, only tariffs that are not effective yet can be deleted",
+                "tags": [
+                    "tariffs"
+                ],
+                "summary": "deletes the tariff that matches the given ID.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Tariff ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": ""
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/taxes": {
+            "get": {
+                "description": "list tax rates, cities first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "taxes"
+                ],
+                "summary": "lists every tax rate.",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.TaxRateListResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "create tax rate, applied to the rides started from now on",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "taxes"
+                ],
+                "summary": "sets the tax rate of a city or an operator.",
+                "parameters": [
+                    {
+                        "description": "Tax rate request parameters",
+                        "name": "params",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.TaxRateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/taxes.Rate"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/taxes/{id}": {
+            "get": {
+                "description": "get tax rate",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "taxes"
+                ],
+                "summary": "returns the tax rate that matches the given ID.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Tax rate ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/taxes.Rate"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "update tax rate, rides already started keep the rate they started with",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "taxes"
+                ],
+                "summary": "replaces the tax rate that matches the given ID.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Tax rate ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Tax rate request parameters",
+                        "name": "params",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.TaxRateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/taxes.Rate"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "delete tax rate, rides already started keep the rate they started with",
+                "tags": [
+                    "taxes"
+                ],
+                "summary": "deletes the tax rate that matches the given ID.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Tax rate ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": ""
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users": {
+            "get": {
+                "description": "list users, the oldest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "lists the users.",
+                "parameters": [
+                    {
+                        "enum": [
+                            "active",
+                            "suspended",
+                            "deleted"
+                        ],
+                        "type": "string",
+                        "description": "Status filter",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.UserListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "create user, active right away",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "registers a user.",
+                "parameters": [
+                    {
+                        "description": "User request parameters",
+                        "name": "params",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.UserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/users.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}": {
+            "get": {
+                "description": "get user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "returns the user that matches the given ID.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/users.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "update user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "replaces the name and email of the user that matches the given ID.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "User request parameters",
+                        "name": "params",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.UserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/users.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "delete user, which is kept with the deleted status for its rides and invoices",
+                "tags": [
+                    "users"
+                ],
+                "summary": "deletes the user that matches the given ID.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": ""
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/invoices": {
+            "get": {
+                "description": "list monthly invoices, the latest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "invoices"
+                ],
+                "summary": "lists the invoices of a user.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.InvoiceListResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/invoices/{number}": {
+            "get": {
+                "description": "get invoice as JSON, or as a printable HTML document with format=html",
+                "produces": [
+                    "application/json",
+                    "text/html"
+                ],
+                "tags": [
+                    "invoices"
+                ],
+                "summary": "returns the invoice of a user that matches the given number.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Invoice number",
+                        "name": "number",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "html"
+                        ],
+                        "type": "string",
+                        "description": "Document format (default json)",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/invoices.Invoice"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/status": {
+            "put": {
+                "description": "set user status, suspended users can't start rides",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "suspends or reactivates the user that matches the given ID.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "User status request parameters",
+                        "name": "params",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.UserStatusRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/users.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/vehicles": {
+            "get": {
+                "description": "list vehicles, the oldest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "vehicles"
+                ],
+                "summary": "lists the vehicles of the fleet.",
+                "parameters": [
+                    {
+                        "enum": [
+                            "scooter",
+                            "bike",
+                            "moped"
+                        ],
+                        "type": "string",
+                        "description": "Type filter",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "available",
+                            "in_ride",
+                            "maintenance",
+                            "retired"
+                        ],
+                        "type": "string",
+                        "description": "Status filter",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.VehicleListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "create vehicle",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "vehicles"
+                ],
+                "summary": "adds a vehicle to the fleet.",
+                "parameters": [
+                    {
+                        "description": "Vehicle request parameters",
+                        "name": "params",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.VehicleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/vehicles.Vehicle"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/vehicles/{id}": {
+            "get": {
+                "description": "get vehicle",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "vehicles"
+                ],
+                "summary": "returns the vehicle that matches the given ID.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Vehicle ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/vehicles.Vehicle"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "update vehicle, the status of a vehicle in a ride can't change",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "vehicles"
+                ],
+                "summary": "replaces the vehicle that matches the given ID.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Vehicle ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Vehicle request parameters",
+                        "name": "params",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.VehicleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/vehicles.Vehicle"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "delete vehicle, which is kept with the retired status for its rides",
+                "tags": [
+                    "vehicles"
+                ],
+                "summary": "retires the vehicle that matches the given ID.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Vehicle ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": ""
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/vehicles/{id}/telemetry": {
+            "post": {
+                "description": "report vehicle telemetry, whatever the vehicle status",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "vehicles"
+                ],
+                "summary": "records the battery level and position a vehicle reports.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Vehicle ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Telemetry request parameters",
+                        "name": "params",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.TelemetryRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/vehicles.Vehicle"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/wallets/{user_id}/balance": {
+            "get": {
+                "description": "get balance in the given currency",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "wallets"
+                ],
+                "summary": "gets the balance of the wallet of a user.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ISO 4217 currency code",
+                        "name": "currency",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.BalanceResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/wallets/{user_id}/top-ups": {
+            "post": {
+                "description": "charge the payment method of the user and credit wallet with what was paid, referenced by the ID of the payment",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "wallets"
+                ],
+                "summary": "tops up the wallet of a user.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Top-up request parameters",
+                        "name": "params",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.TopUpRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/wallets.Transaction"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrResponse"
+                        }
+                    },
+                    "402": {
+                        "description": "Payment Required",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/wallets/{user_id}/transactions": {
+            "get": {
+                "description": "list top-ups, ride debits, refunds and adjustments, the latest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "wallets"
+                ],
+                "summary": "lists the transactions of the wallet of a user.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.TransactionListResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "handlers.AdjustmentListResponse": {
+            "type": "object",
+            "properties": {
+                "adjustments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/rides.Adjustment"
+                    }
+                }
+            }
+        },
+        "handlers.AdjustmentRequest": {
+            "type": "object",
+            "properties": {
+                "agent": {
+                    "type": "string"
+                },
+                "amount": {
+                    "type": "integer"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string",
+                    "enum": [
+                        "overcharge",
+                        "undercharge",
+                        "vehicle_issue",
+                        "goodwill",
+                        "other"
+                    ]
+                }
+            }
+        },
+        "handlers.AdjustmentResponse": {
+            "type": "object",
+            "properties": {
+                "agent": {
+                    "type": "string"
+                },
+                "amount": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "effective_price": {
+                    "$ref": "#/definitions/money.Money"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "ride_id": {
+                    "type": "integer"
+                },
+                "settled_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.BalanceResponse": {
+            "type": "object",
+            "properties": {
+                "balance": {
+                    "$ref": "#/definitions/money.Money"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.ConsumptionListResponse": {
+            "type": "object",
+            "properties": {
+                "consumptions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/passes.Consumption"
+                    }
+                }
+            }
+        },
+        "handlers.ErrResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "application-specific error code",
+                    "type": "integer"
+                },
+                "error": {
+                    "description": "application-level error message, for debugging",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "user-level status message",
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.InvoiceListResponse": {
+            "type": "object",
+            "properties": {
+                "invoices": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/invoices.Invoice"
+                    }
+                }
+            }
+        },
+        "handlers.PassListResponse": {
+            "type": "object",
+            "properties": {
+                "passes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/passes.Pass"
+                    }
+                }
+            }
+        },
+        "handlers.ProductListResponse": {
+            "type": "object",
+            "properties": {
+                "products": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/passes.Product"
+                    }
+                }
+            }
+        },
+        "handlers.ProductRequest": {
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "type": "string"
+                },
+                "free_unlocks": {
+                    "type": "boolean"
+                },
+                "minutes": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "integer"
+                },
+                "validity_days": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.PromoListResponse": {
+            "type": "object",
+            "properties": {
+                "promos": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/promos.Promo"
+                    }
+                }
+            }
+        },
+        "handlers.PromoRequest": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer"
+                },
+                "code": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "ends_at": {
+                    "type": "string"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "max_redemptions": {
+                    "type": "integer"
+                },
+                "max_redemptions_per_user": {
+                    "type": "integer"
+                },
+                "minutes": {
+                    "type": "integer"
+                },
+                "percent": {
+                    "type": "integer"
+                },
+                "starts_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.PurchaseRequest": {
+            "type": "object",
+            "properties": {
+                "product_id": {
+                    "type": "integer"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.RideRequest": {
+            "type": "object",
+            "properties": {
+                "promo_code": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                },
+                "vehicle_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.TariffListResponse": {
+            "type": "object",
+            "properties": {
+                "tariffs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/tariffs.Tariff"
+                    }
+                }
+            }
+        },
+        "handlers.TariffRequest": {
+            "type": "object",
+            "properties": {
+                "billing": {
+                    "$ref": "#/definitions/pricing.Billing"
+                },
+                "caps": {
+                    "$ref": "#/definitions/pricing.Caps"
+                },
+                "city": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "effective_from": {
+                    "type": "string"
+                },
+                "effective_to": {
+                    "type": "string"
+                },
+                "minute_price": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "paused_minute_price": {
+                    "type": "integer"
+                },
+                "prepaid": {
+                    "$ref": "#/definitions/pricing.Prepaid"
+                },
+                "rules": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/pricing.Rule"
+                    }
+                },
+                "time_zone": {
+                    "type": "string"
+                },
+                "unlock_price": {
+                    "type": "integer"
+                },
+                "vehicle_type": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.TaxRateListResponse": {
+            "type": "object",
+            "properties": {
+                "rates": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/taxes.Rate"
+                    }
+                }
+            }
+        },
+        "handlers.TaxRateRequest": {
+            "type": "object",
+            "properties": {
+                "city": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "operator": {
+                    "type": "string"
+                },
+                "rate": {
+                    "type": "integer"
+                },
+                "rounding": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.TelemetryRequest": {
+            "type": "object",
+            "properties": {
+                "battery": {
+                    "type": "integer"
+                },
+                "latitude": {
+                    "type": "number"
+                },
+                "longitude": {
+                    "type": "number"
+                }
+            }
+        },
+        "handlers.TopUpRequest": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer"
+                },
+                "currency": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.TransactionListResponse": {
+            "type": "object",
+            "properties": {
+                "transactions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/wallets.Transaction"
+                    }
+                }
+            }
+        },
+        "handlers.UserListResponse": {
+            "type": "object",
+            "properties": {
+                "users": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/users.User"
+                    }
+                }
+            }
+        },
+        "handlers.UserRequest": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.UserStatusRequest": {
+            "type": "object",
+            "properties": {
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "active",
+                        "suspended"
+                    ]
+                }
+            }
+        },
+        "handlers.VehicleListResponse": {
+            "type": "object",
+            "properties": {
+                "vehicles": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/vehicles.Vehicle"
+                    }
+                }
+            }
+        },
+        "handlers.VehicleRequest": {
+            "type": "object",
+            "properties": {
+                "battery": {
+                    "type": "integer"
+                },
+                "city": {
+                    "type": "string"
+                },
+                "latitude": {
+                    "type": "number"
+                },
+                "longitude": {
+                    "type": "number"
+                },
+                "model": {
+                    "type": "string"
+                },
+                "operator": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "available",
+                        "maintenance",
+                        "retired"
+                    ]
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "scooter",
+                        "bike",
+                        "moped"
+                    ]
+                }
+            }
+        },
+        "invoices.Invoice": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "lines": {
+                    "description": "one per ride",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/invoices.Line"
+                    }
+                },
+                "net_amount": {
+                    "type": "integer"
+                },
+                "number": {
+                    "type": "string"
+                },
+                "period_end": {
+                    "description": "exclusive",
+                    "type": "string"
+                },
+                "period_start": {
+                    "type": "string"
+                },
+                "tax_amount": {
+                    "type": "integer"
+                },
+                "taxes": {
+                    "description": "net and tax per rate",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/invoices.TaxLine"
+                    }
+                },
+                "total_amount": {
+                    "type": "integer"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "invoices.Line": {
+            "type": "object",
+            "properties": {
+                "adjustments_amount": {
+                    "description": "tax included",
+                    "type": "integer"
+                },
+                "amount": {
+                    "type": "integer"
+                },
+                "net_amount": {
+                    "type": "integer"
+                },
+                "ride_id": {
+                    "type": "integer"
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "tax_amount": {
+                    "type": "integer"
+                },
+                "tax_rate": {
+                    "$ref": "#/definitions/pricing.TaxRate"
+                },
+                "vehicle_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "invoices.TaxLine": {
+            "type": "object",
+            "properties": {
+                "net_amount": {
+                    "type": "integer"
+                },
+                "tax_amount": {
+                    "type": "integer"
+                },
+                "tax_rate": {
+                    "$ref": "#/definitions/pricing.TaxRate"
+                }
+            }
+        },
+        "money.Money": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer"
+                },
+                "currency": {
+                    "type": "string"
+                }
+            }
+        },
+        "passes.Consumption": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "minutes": {
+                    "type": "integer"
+                },
+                "pass_id": {
+                    "type": "integer"
+                },
+                "reversed_at": {
+                    "type": "string"
+                },
+                "ride_id": {
+                    "type": "integer"
+                },
+                "unlock": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "passes.Pass": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "ends_at": {
+                    "type": "string"
+                },
+                "free_unlocks": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "minutes": {
+                    "type": "integer"
+                },
+                "minutes_left": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "integer"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "starts_at": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "passes.Product": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "free_unlocks": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "minutes": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "validity_days": {
+                    "type": "integer"
+                }
+            }
+        },
+        "pricing.Billing": {
+            "type": "object",
+            "properties": {
+                "block_minutes": {
+                    "type": "integer"
+                },
+                "grace_seconds": {
+                    "type": "integer"
+                },
+                "maximum_charge": {
+                    "description": "MaximumCharge is the most charged for a ride, net of tax.",
+                    "type": "integer"
+                },
+                "minimum_charge": {
+                    "description": "MinimumCharge is the least charged for a ride, net of tax.",
+                    "type": "integer"
+                },
+                "rounding": {
+                    "type": "string"
+                },
+                "unit": {
+                    "type": "string"
+                }
+            }
+        },
+        "pricing.Caps": {
+            "type": "object",
+            "properties": {
+                "daily": {
+                    "description": "Daily is the most charged for the rides of a day, net of tax.",
+                    "type": "integer"
+                },
+                "weekly": {
+                    "description": "Weekly is the most charged for the rides of 7 days, net of tax.",
+                    "type": "integer"
+                }
+            }
+        },
+        "pricing.Discount": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer"
+                },
+                "code": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "minutes": {
+                    "type": "integer"
+                },
+                "percent": {
+                    "type": "integer"
+                }
+            }
+        },
+        "pricing.LineItem": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "$ref": "#/definitions/money.Money"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "unit_price": {
+                    "$ref": "#/definitions/money.Money"
+                }
+            }
+        },
+        "pricing.Prepaid": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "type": "boolean"
+                },
+                "minimum_balance": {
+                    "type": "integer"
+                }
+            }
+        },
+        "pricing.Rule": {
+            "type": "object",
+            "properties": {
+                "days": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "from": {
+                    "type": "string"
+                },
+                "minute_price": {
+                    "type": "integer"
+                },
+                "multiplier_percent": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "paused_minute_price": {
+                    "type": "integer"
+                },
+                "to": {
+                    "type": "string"
+                }
+            }
+        },
+        "pricing.Tariff": {
+            "type": "object",
+            "properties": {
+                "billing": {
+                    "$ref": "#/definitions/pricing.Billing"
+                },
+                "caps": {
+                    "$ref": "#/definitions/pricing.Caps"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "minute_price": {
+                    "type": "integer"
+                },
+                "paused_minute_price": {
+                    "type": "integer"
+                },
+                "prepaid": {
+                    "$ref": "#/definitions/pricing.Prepaid"
+                },
+                "rules": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/pricing.Rule"
+                    }
+                },
+                "time_zone": {
+                    "description": "IANA name, UTC when empty",
+                    "type": "string"
+                },
+                "unlock_price": {
+                    "type": "integer"
+                },
+                "version": {
+                    "type": "string"
+                }
+            }
+        },
+        "pricing.TaxRate": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "rate": {
+                    "type": "integer"
+                },
+                "rounding": {
+                    "type": "string"
+                }
+            }
+        },
+        "promos.Promo": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer"
+                },
+                "code": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "ends_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "max_redemptions": {
+                    "type": "integer"
+                },
+                "max_redemptions_per_user": {
+                    "type": "integer"
+                },
+                "minutes": {
+                    "type": "integer"
+                },
+                "percent": {
+                    "type": "integer"
+                },
+                "redemptions": {
+                    "type": "integer"
+                },
+                "starts_at": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "rides.Adjustment": {
+            "type": "object",
+            "properties": {
+                "agent": {
+                    "type": "string"
+                },
+                "amount": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "ride_id": {
+                    "type": "integer"
+                },
+                "settled_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "rides.Page": {
+            "type": "object",
+            "properties": {
+                "next_cursor": {
+                    "type": "string"
+                },
+                "rides": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/rides.Ride"
+                    }
+                }
+            }
+        },
+        "rides.Quote": {
+            "type": "object",
+            "properties": {
+                "at": {
+                    "type": "string"
+                },
+                "items": {
+                    "description": "what the price is made of",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/pricing.LineItem"
+                    }
+                },
+                "net": {
+                    "$ref": "#/definitions/money.Money"
+                },
+                "paused_units": {
                     "type": "integer"
                 },
                 "price": {
+                    "$ref": "#/definitions/money.Money"
+                },
+                "ride_id": {
+                    "type": "integer"
+                },
+                "riding_units": {
+                    "type": "integer"
+                },
+                "tax": {
+                    "$ref": "#/definitions/money.Money"
+                },
+                "unit": {
+                    "type": "string"
+                }
+            }
+        },
+        "rides.Ride": {
+            "type": "object",
+            "properties": {
+                "city": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "discount": {
+                    "description": "of PromoCode when the ride started",
+                    "$ref": "#/definitions/pricing.Discount"
+                },
+                "id": {
                     "type": "integer"
                 },
+                "items": {
+                    "description": "what the price is made of",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/pricing.LineItem"
+                    }
+                },
+                "operator": {
+                    "type": "string"
+                },
+                "payment_id": {
+                    "type": "string"
+                },
+                "payment_status": {
+                    "description": "PaymentStatus is pending from the moment a ride finishes until its\nprice is captured.",
+                    "type": "string"
+                },
+                "promo_code": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "tariff": {
+                    "description": "in effect when the ride started",
+                    "$ref": "#/definitions/pricing.Tariff"
+                },
+                "tax_rate": {
+                    "description": "of City or Operator when the ride started",
+                    "$ref": "#/definitions/pricing.TaxRate"
+                },
                 "updated_at": {
                     "type": "string"
                 },
@@ -128,6 +3439,192 @@ const docTemplate = `{
                     "type": "string"
                 }
             }
+        },
+        "tariffs.Tariff": {
+            "type": "object",
+            "properties": {
+                "billing": {
+                    "$ref": "#/definitions/pricing.Billing"
+                },
+                "caps": {
+                    "$ref": "#/definitions/pricing.Caps"
+                },
+                "city": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "effective_from": {
+                    "type": "string"
+                },
+                "effective_to": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "minute_price": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "paused_minute_price": {
+                    "type": "integer"
+                },
+                "prepaid": {
+                    "$ref": "#/definitions/pricing.Prepaid"
+                },
+                "rules": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/pricing.Rule"
+                    }
+                },
+                "time_zone": {
+                    "type": "string"
+                },
+                "unlock_price": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "vehicle_type": {
+                    "type": "string"
+                }
+            }
+        },
+        "taxes.Rate": {
+            "type": "object",
+            "properties": {
+                "city": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "operator": {
+                    "type": "string"
+                },
+                "rate": {
+                    "description": "in basis points, 2100 for 21%",
+                    "type": "integer"
+                },
+                "rounding": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "users.User": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "vehicles.Vehicle": {
+            "type": "object",
+            "properties": {
+                "battery": {
+                    "description": "in percent",
+                    "type": "integer"
+                },
+                "city": {
+                    "description": "City is where the vehicle operates, which picks the tariff and the\ntax rate of the rides on it along with its type.",
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "latitude": {
+                    "description": "Latitude and Longitude are its last known position.",
+                    "type": "number"
+                },
+                "longitude": {
+                    "type": "number"
+                },
+                "model": {
+                    "type": "string"
+                },
+                "operator": {
+                    "description": "Operator runs the vehicle, and picks the tax rate of its rides when\nCity has none.",
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "wallets.Transaction": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "reference": {
+                    "type": "string"
+                },
+                "ride_id": {
+                    "type": "integer"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
         }
     }
 }`
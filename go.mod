@@ -1,6 +1,6 @@
 module backend
 
-go 1.18
+go 1.21
 
 require (
 	github.com/go-chi/chi/v5 v5.0.7
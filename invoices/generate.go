@@ -0,0 +1,199 @@
+package invoices
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"backend/money"
+	"backend/pricing"
+
+	"github.com/go-rel/rel"
+	"github.com/go-rel/rel/where"
+)
+
+// closedStatuses are the statuses of the rides that were charged, finished
+// by their user or force closed by the system.
+var closedStatuses = []interface{}{"finished", "force_closed"}
+
+// billedRide is what an invoice needs of a finished ride.
+type billedRide struct {
+	ID                uint
+	CreatedAt         time.Time
+	UserID            string
+	VehicleID         string
+	PriceAmount       int64
+	PriceCurrency     money.Currency
+	TaxRate           pricing.TaxRate
+	NetAmount         int64
+	TaxAmount         int64
+	AdjustmentsAmount int64
+}
+
+func (billedRide) Table() string {
+	return "rides"
+}
+
+// draft is an invoice to issue with the rides it bills.
+type draft struct {
+	invoice Invoice
+	rideIDs []interface{}
+}
+
+type generateInvoices struct {
+	repository rel.Repository
+}
+
+// GenerateInvoices invoices the closed rides started before the end of
+// the period that weren't invoiced yet, with one invoice per user and
+// currency. Rides that finish after the run for their period go on the
+// invoice of the next one.
+func (c generateInvoices) GenerateInvoices(ctx context.Context, period Period, now time.Time) (error, []Invoice) {
+	if !period.End.After(period.Start) {
+		return ErrPeriodInvalid, nil
+	}
+
+	var (
+		billed []billedRide
+		query  = rel.Select("id", "created_at", "user_id", "vehicle_id", "price_amount", "price_currency", "tax_rate", "net_amount", "tax_amount", "adjustments_amount").
+			From("rides").
+			Where(where.In("status", closedStatuses...).AndNil("invoice_id").AndLt("created_at", period.End)).
+			SortAsc("user_id", "price_currency", "id")
+	)
+	if err := c.repository.FindAll(ctx, &billed, query); err != nil {
+		return err, nil
+	}
+
+	invoices := []Invoice{}
+	drafts, err := drafts(billed, period, now)
+	if err != nil {
+		return err, nil
+	}
+	for _, draft := range drafts {
+		if err := c.issue(ctx, &draft); err != nil {
+			return err, nil
+		}
+		invoices = append(invoices, draft.invoice)
+	}
+
+	return nil, invoices
+}
+
+// drafts groups the rides, sorted by user and currency, into invoices.
+func drafts(billed []billedRide, period Period, now time.Time) ([]draft, error) {
+	var drafts []draft
+	for _, ride := range billed {
+		last := len(drafts) - 1
+		if last < 0 || drafts[last].invoice.UserID != ride.UserID || drafts[last].invoice.Currency != ride.PriceCurrency {
+			drafts = append(drafts, draft{invoice: Invoice{
+				CreatedAt:   now,
+				UserID:      ride.UserID,
+				PeriodStart: period.Start,
+				PeriodEnd:   period.End,
+				Currency:    ride.PriceCurrency,
+				Taxes:       TaxLines{},
+				Lines:       Lines{},
+			}})
+			last++
+		}
+
+		line, err := ride.line()
+		if err != nil {
+			return nil, err
+		}
+		if err := drafts[last].invoice.add(line); err != nil {
+			return nil, err
+		}
+		drafts[last].rideIDs = append(drafts[last].rideIDs, ride.ID)
+	}
+	return drafts, nil
+}
+
+// line bills the ride at its effective price. Adjustments are amounts the
+// tax is included in, at the rate of the ride.
+func (r billedRide) line() (Line, error) {
+	adjustments := money.New(r.AdjustmentsAmount, r.PriceCurrency)
+	adjustmentNet, adjustmentTax, err := r.TaxRate.Split(adjustments)
+	if err != nil {
+		return Line{}, err
+	}
+	net, err := money.New(r.NetAmount, r.PriceCurrency).Add(adjustmentNet)
+	if err != nil {
+		return Line{}, err
+	}
+	tax, err := money.New(r.TaxAmount, r.PriceCurrency).Add(adjustmentTax)
+	if err != nil {
+		return Line{}, err
+	}
+	amount, err := money.New(r.PriceAmount, r.PriceCurrency).Add(adjustments)
+	if err != nil {
+		return Line{}, err
+	}
+
+	return Line{
+		RideID:            r.ID,
+		StartedAt:         r.CreatedAt,
+		VehicleID:         r.VehicleID,
+		TaxRate:           r.TaxRate,
+		AdjustmentsAmount: r.AdjustmentsAmount,
+		NetAmount:         net.Amount,
+		TaxAmount:         tax.Amount,
+		Amount:            amount.Amount,
+	}, nil
+}
+
+// issue numbers and stores the invoice, and marks its rides as invoiced.
+// The number is taken in the same transaction, so a failed invoice gives
+// its number back.
+func (c generateInvoices) issue(ctx context.Context, draft *draft) error {
+	return c.repository.Transaction(ctx, func(ctx context.Context) error {
+		series := Period{Start: draft.invoice.PeriodStart, End: draft.invoice.PeriodEnd}.Series()
+		number, err := nextNumber(ctx, c.repository, series)
+		if err != nil {
+			return err
+		}
+		draft.invoice.Number = fmt.Sprintf("%s-%06d", series, number)
+
+		if err := c.repository.Insert(ctx, &draft.invoice); err != nil {
+			return err
+		}
+
+		updated, err := c.repository.UpdateAny(ctx,
+			rel.From("rides").Where(where.In("id", draft.rideIDs...).AndNil("invoice_id")),
+			rel.Set("invoice_id", draft.invoice.ID),
+		)
+		if err != nil {
+			return err
+		}
+		if updated != len(draft.rideIDs) {
+			return ErrRidesAlreadyInvoiced
+		}
+		return nil
+	})
+}
+
+// nextNumber takes the next number of the series. The counter row stays
+// locked by the increment until the transaction ends, so invoices get their
+// numbers in the order they commit.
+func nextNumber(ctx context.Context, repository rel.Repository, series string) (int, error) {
+	updated, err := repository.UpdateAny(ctx,
+		rel.From("invoice_counters").Where(where.Eq("series", series)),
+		rel.IncBy("last_number", 1),
+	)
+	if err != nil {
+		return 0, err
+	}
+	if updated == 0 {
+		counter := Counter{Series: series, LastNumber: 1}
+		if err := repository.Insert(ctx, &counter); err != nil {
+			return 0, err
+		}
+		return counter.LastNumber, nil
+	}
+
+	var counter Counter
+	if err := repository.Find(ctx, &counter, where.Eq("series", series)); err != nil {
+		return 0, err
+	}
+	return counter.LastNumber, nil
+}
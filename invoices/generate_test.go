@@ -0,0 +1,145 @@
+package invoices
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-rel/rel"
+	"github.com/go-rel/rel/where"
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func expectBilled(repository *reltest.Repository) *reltest.MockFindAll {
+	return repository.ExpectFindAll(
+		rel.Select("id", "created_at", "user_id", "vehicle_id", "price_amount", "price_currency", "tax_rate", "net_amount", "tax_amount", "adjustments_amount").
+			From("rides").
+			Where(where.In("status", closedStatuses...).AndNil("invoice_id").AndLt("created_at", october.End)).
+			SortAsc("user_id", "price_currency", "id"),
+	)
+}
+
+func expectIssue(repository *reltest.Repository, counter *Counter, rideIDs ...interface{}) {
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectUpdateAny(
+			rel.From("invoice_counters").Where(where.Eq("series", "2026")),
+			rel.IncBy("last_number", 1),
+		).UpdatedCount(1)
+		repository.ExpectFind(where.Eq("series", "2026")).Result(*counter)
+		repository.ExpectInsert().ForType("*invoices.Invoice")
+		repository.ExpectUpdateAny(
+			rel.From("rides").Where(where.In("id", rideIDs...).AndNil("invoice_id")),
+			rel.Set("invoice_id", reltest.Any),
+		).UpdatedCount(len(rideIDs))
+	})
+	counter.LastNumber++
+}
+
+func TestGenerateInvoices(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository)
+		counter    = Counter{ID: 1, Series: "2026", LastNumber: 42}
+	)
+
+	expectBilled(repository).Result(billed)
+	expectIssue(repository, &counter, uint(1), uint(4), uint(6))
+	expectIssue(repository, &counter, uint(5))
+	expectIssue(repository, &counter, uint(2))
+
+	err, invoices := service.GenerateInvoices(ctx, october, now)
+	assert.Nil(t, err)
+	assert.Len(t, invoices, 3)
+	assert.Equal(t, "2026-000042", invoices[0].Number)
+	assert.Equal(t, "2026-000043", invoices[1].Number)
+	assert.Equal(t, "2026-000044", invoices[2].Number)
+	assert.NotEmpty(t, invoices[0].ID)
+
+	repository.AssertExpectations(t)
+}
+
+func TestGenerateFirstInvoiceOfTheYear(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository)
+	)
+
+	expectBilled(repository).Result(billed[4:])
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectUpdateAny(
+			rel.From("invoice_counters").Where(where.Eq("series", "2026")),
+			rel.IncBy("last_number", 1),
+		).UpdatedCount(0)
+		repository.ExpectInsert().For(&Counter{Series: "2026", LastNumber: 1})
+		repository.ExpectInsert().ForType("*invoices.Invoice")
+		repository.ExpectUpdateAny(
+			rel.From("rides").Where(where.In("id", uint(2)).AndNil("invoice_id")),
+			rel.Set("invoice_id", reltest.Any),
+		).UpdatedCount(1)
+	})
+
+	err, invoices := service.GenerateInvoices(ctx, october, now)
+	assert.Nil(t, err)
+	assert.Equal(t, "2026-000001", invoices[0].Number)
+
+	repository.AssertExpectations(t)
+}
+
+func TestGenerateInvoicesRidesAlreadyInvoiced(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository)
+	)
+
+	expectBilled(repository).Result(billed[4:])
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectUpdateAny(
+			rel.From("invoice_counters").Where(where.Eq("series", "2026")),
+			rel.IncBy("last_number", 1),
+		).UpdatedCount(1)
+		repository.ExpectFind(where.Eq("series", "2026")).Result(Counter{ID: 1, Series: "2026", LastNumber: 7})
+		repository.ExpectInsert().ForType("*invoices.Invoice")
+		repository.ExpectUpdateAny(
+			rel.From("rides").Where(where.In("id", uint(2)).AndNil("invoice_id")),
+			rel.Set("invoice_id", reltest.Any),
+		).UpdatedCount(0)
+	})
+
+	err, invoices := service.GenerateInvoices(ctx, october, now)
+	assert.Equal(t, ErrRidesAlreadyInvoiced, err)
+	assert.Nil(t, invoices)
+
+	repository.AssertExpectations(t)
+}
+
+func TestGenerateInvoicesWithoutRides(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository)
+	)
+
+	expectBilled(repository).Result([]billedRide{})
+
+	err, invoices := service.GenerateInvoices(ctx, october, now)
+	assert.Nil(t, err)
+	assert.Empty(t, invoices)
+
+	repository.AssertExpectations(t)
+}
+
+func TestGenerateInvoicesPeriodInvalid(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository)
+	)
+
+	err, _ := service.GenerateInvoices(ctx, Period{Start: october.End, End: october.Start}, now)
+	assert.Equal(t, ErrPeriodInvalid, err)
+
+	repository.AssertExpectations(t)
+}
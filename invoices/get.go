@@ -0,0 +1,39 @@
+package invoices
+
+import (
+	"context"
+	"errors"
+
+	"github.com/go-rel/rel"
+	"github.com/go-rel/rel/where"
+)
+
+type listInvoices struct {
+	repository rel.Repository
+}
+
+// ListInvoices returns the invoices of the user, the latest first.
+func (c listInvoices) ListInvoices(ctx context.Context, userID string) (error, []Invoice) {
+	invoices := []Invoice{}
+	if err := c.repository.FindAll(ctx, &invoices, rel.Where(where.Eq("user_id", userID)).SortDesc("id")); err != nil {
+		return err, nil
+	}
+
+	return nil, invoices
+}
+
+type getInvoice struct {
+	repository rel.Repository
+}
+
+func (c getInvoice) GetInvoice(ctx context.Context, userID string, number string) (error, *Invoice) {
+	var invoice Invoice
+	if err := c.repository.Find(ctx, &invoice, where.Eq("user_id", userID).AndEq("number", number)); err != nil {
+		if errors.Is(err, rel.ErrNotFound) {
+			return ErrInvoiceNotFound, nil
+		}
+		return err, nil
+	}
+
+	return nil, &invoice
+}
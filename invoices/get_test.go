@@ -0,0 +1,44 @@
+package invoices
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-rel/rel"
+	"github.com/go-rel/rel/where"
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListInvoices(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository)
+		invoices   = []Invoice{{ID: 2, Number: "2026-000002", UserID: "1"}, {ID: 1, Number: "2026-000001", UserID: "1"}}
+	)
+
+	repository.ExpectFindAll(rel.Where(where.Eq("user_id", "1")).SortDesc("id")).Result(invoices)
+
+	err, list := service.ListInvoices(ctx, "1")
+	assert.Nil(t, err)
+	assert.Equal(t, invoices, list)
+
+	repository.AssertExpectations(t)
+}
+
+func TestGetInvoiceOfAnotherUser(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository)
+	)
+
+	repository.ExpectFind(where.Eq("user_id", "2").AndEq("number", "2026-000001")).NotFound()
+
+	err, invoice := service.GetInvoice(ctx, "2", "2026-000001")
+	assert.Equal(t, ErrInvoiceNotFound, err)
+	assert.Nil(t, invoice)
+
+	repository.AssertExpectations(t)
+}
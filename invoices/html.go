@@ -0,0 +1,79 @@
+package invoices
+
+import (
+	"html/template"
+	"io"
+	"time"
+
+	"backend/money"
+)
+
+// document lays an invoice out as a page meant to be printed, or saved as
+// PDF from the browser.
+var document = template.Must(template.New("invoice").Funcs(template.FuncMap{
+	"money": func(amount int64, currency money.Currency) string {
+		return money.New(amount, currency).String()
+	},
+	"date": func(at time.Time) string {
+		return at.UTC().Format("2006-01-02")
+	},
+	"time": func(at time.Time) string {
+		return at.UTC().Format("2006-01-02 15:04 UTC")
+	},
+	"last": func(end time.Time) string {
+		return end.UTC().AddDate(0, 0, -1).Format("2006-01-02")
+	},
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Invoice {{.Number}}</title>
+<style>
+body { font-family: Helvetica, Arial, sans-serif; font-size: 12px; margin: 2em; color: #222; }
+h1 { font-size: 20px; margin-bottom: 0.2em; }
+table { border-collapse: collapse; width: 100%; margin-top: 1.5em; }
+th, td { padding: 4px 8px; border-bottom: 1px solid #ddd; text-align: left; }
+td.amount, th.amount { text-align: right; }
+tfoot td { font-weight: bold; border-bottom: none; }
+@media print { body { margin: 0; } }
+</style>
+</head>
+<body>
+<h1>Invoice {{.Number}}</h1>
+<p>
+Issued on {{date .CreatedAt}} to user {{.UserID}}<br>
+Period from {{date .PeriodStart}} to {{last .PeriodEnd}}
+</p>
+<table>
+<thead>
+<tr><th>Ride</th><th>Started</th><th>Vehicle</th><th>Tax</th><th class="amount">Adjustments</th><th class="amount">Net</th><th class="amount">Tax</th><th class="amount">Amount</th></tr>
+</thead>
+<tbody>
+{{- range .Lines}}
+<tr><td>{{.RideID}}</td><td>{{time .StartedAt}}</td><td>{{.VehicleID}}</td><td>{{.TaxRate.Name}} {{.TaxRate.Percent}}</td><td class="amount">{{money .AdjustmentsAmount $.Currency}}</td><td class="amount">{{money .NetAmount $.Currency}}</td><td class="amount">{{money .TaxAmount $.Currency}}</td><td class="amount">{{money .Amount $.Currency}}</td></tr>
+{{- end}}
+</tbody>
+</table>
+<table>
+<thead>
+<tr><th>Tax</th><th class="amount">Net</th><th class="amount">Tax</th></tr>
+</thead>
+<tbody>
+{{- range .Taxes}}
+<tr><td>{{.TaxRate.Name}} {{.TaxRate.Percent}}</td><td class="amount">{{money .NetAmount $.Currency}}</td><td class="amount">{{money .TaxAmount $.Currency}}</td></tr>
+{{- end}}
+</tbody>
+<tfoot>
+<tr><td>Net</td><td></td><td class="amount">{{.Net}}</td></tr>
+<tr><td>Tax</td><td></td><td class="amount">{{.Tax}}</td></tr>
+<tr><td>Total</td><td></td><td class="amount">{{.Total}}</td></tr>
+</tfoot>
+</table>
+</body>
+</html>
+`))
+
+// WriteHTML renders the invoice as a printable HTML document.
+func (i Invoice) WriteHTML(w io.Writer) error {
+	return document.Execute(w, i)
+}
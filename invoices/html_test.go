@@ -0,0 +1,26 @@
+package invoices
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteHTML(t *testing.T) {
+	drafts, err := drafts(billed, october, now)
+	assert.Nil(t, err)
+	invoice := drafts[0].invoice
+	invoice.Number = "2026-000042"
+	invoice.UserID = "<script>"
+
+	var page bytes.Buffer
+	assert.Nil(t, invoice.WriteHTML(&page))
+
+	html := page.String()
+	assert.Contains(t, html, "<title>Invoice 2026-000042</title>")
+	assert.Contains(t, html, "Period from 2026-10-01 to 2026-10-31")
+	assert.Contains(t, html, "to user &lt;script&gt;")
+	assert.Contains(t, html, "<td>VAT 21%</td><td class=\"amount\">4.00 EUR</td><td class=\"amount\">0.84 EUR</td>")
+	assert.Contains(t, html, "<td>Total</td><td></td><td class=\"amount\">7.04 EUR</td>")
+}
@@ -0,0 +1,184 @@
+package invoices
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"backend/money"
+	"backend/pricing"
+)
+
+// Invoice bills a user for the rides they finished that weren't invoiced
+// yet, in one currency. Numbers are sequential within the year of the
+// period, with no gaps, e.g. "2026-000042".
+type Invoice struct {
+	ID          uint           `json:"id"`
+	CreatedAt   time.Time      `json:"created_at"`
+	Number      string         `json:"number"`
+	UserID      string         `json:"user_id"`
+	PeriodStart time.Time      `json:"period_start"`
+	PeriodEnd   time.Time      `json:"period_end"` // exclusive
+	Currency    money.Currency `json:"currency"`
+	NetAmount   int64          `json:"net_amount"`
+	TaxAmount   int64          `json:"tax_amount"`
+	TotalAmount int64          `json:"total_amount"`
+	Taxes       TaxLines       `json:"taxes"` // net and tax per rate
+	Lines       Lines          `json:"lines"` // one per ride
+}
+
+// Line is a ride on an invoice, billed its effective price: what it was
+// charged with plus the adjustments of its price, whose tax is taken out at
+// the rate of the ride.
+type Line struct {
+	RideID            uint            `json:"ride_id"`
+	StartedAt         time.Time       `json:"started_at"`
+	VehicleID         string          `json:"vehicle_id"`
+	TaxRate           pricing.TaxRate `json:"tax_rate"`
+	AdjustmentsAmount int64           `json:"adjustments_amount"` // tax included
+	NetAmount         int64           `json:"net_amount"`
+	TaxAmount         int64           `json:"tax_amount"`
+	Amount            int64           `json:"amount"`
+}
+
+type Lines []Line
+
+// TaxLine sums up the lines of an invoice taxed at the same rate.
+type TaxLine struct {
+	TaxRate   pricing.TaxRate `json:"tax_rate"`
+	NetAmount int64           `json:"net_amount"`
+	TaxAmount int64           `json:"tax_amount"`
+}
+
+type TaxLines []TaxLine
+
+// Period is the span of time an invoice covers, from Start until End.
+type Period struct {
+	Start time.Time
+	End   time.Time
+}
+
+// MonthOf is the calendar month in UTC the given instant belongs to.
+func MonthOf(at time.Time) Period {
+	at = at.UTC()
+	start := time.Date(at.Year(), at.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return Period{Start: start, End: start.AddDate(0, 1, 0)}
+}
+
+// Series is the numbering sequence of the invoices of the period.
+func (p Period) Series() string {
+	return p.Start.Format("2006")
+}
+
+// Counter is the last number given to an invoice of a series.
+type Counter struct {
+	ID         uint   `json:"id"`
+	Series     string `json:"series"`
+	LastNumber int    `json:"last_number"`
+}
+
+func (Counter) Table() string {
+	return "invoice_counters"
+}
+
+var (
+	ErrInvoiceNotFound      = errors.New("No invoice of the user matches the given number")
+	ErrPeriodInvalid        = errors.New("A period must end after it starts")
+	ErrRidesAlreadyInvoiced = errors.New("Some of the rides were invoiced by another run")
+)
+
+// add puts the ride on the invoice and adds it to the totals.
+func (i *Invoice) add(line Line) error {
+	var err error
+	if i.NetAmount, err = i.sum(i.NetAmount, line.NetAmount); err != nil {
+		return err
+	}
+	if i.TaxAmount, err = i.sum(i.TaxAmount, line.TaxAmount); err != nil {
+		return err
+	}
+	if i.TotalAmount, err = i.sum(i.TotalAmount, line.Amount); err != nil {
+		return err
+	}
+	i.Lines = append(i.Lines, line)
+
+	for j := range i.Taxes {
+		if i.Taxes[j].TaxRate != line.TaxRate {
+			continue
+		}
+		if i.Taxes[j].NetAmount, err = i.sum(i.Taxes[j].NetAmount, line.NetAmount); err != nil {
+			return err
+		}
+		if i.Taxes[j].TaxAmount, err = i.sum(i.Taxes[j].TaxAmount, line.TaxAmount); err != nil {
+			return err
+		}
+		return nil
+	}
+	i.Taxes = append(i.Taxes, TaxLine{TaxRate: line.TaxRate, NetAmount: line.NetAmount, TaxAmount: line.TaxAmount})
+	return nil
+}
+
+// sum adds up two amounts in the currency of the invoice.
+func (i Invoice) sum(a, b int64) (int64, error) {
+	total, err := money.New(a, i.Currency).Add(money.New(b, i.Currency))
+	return total.Amount, err
+}
+
+// Net is the invoice total before tax.
+func (i Invoice) Net() money.Money {
+	return money.New(i.NetAmount, i.Currency)
+}
+
+// Tax is the tax included in the invoice total.
+func (i Invoice) Tax() money.Money {
+	return money.New(i.TaxAmount, i.Currency)
+}
+
+// Total is what the invoice bills.
+func (i Invoice) Total() money.Money {
+	return money.New(i.TotalAmount, i.Currency)
+}
+
+func (l Lines) Value() (driver.Value, error) {
+	if l == nil {
+		l = Lines{}
+	}
+	value, err := json.Marshal(l)
+	return string(value), err
+}
+
+func (l *Lines) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*l = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(v, l)
+	case string:
+		return json.Unmarshal([]byte(v), l)
+	}
+
+	return errors.New("invoices: cannot scan lines")
+}
+
+func (t TaxLines) Value() (driver.Value, error) {
+	if t == nil {
+		t = TaxLines{}
+	}
+	value, err := json.Marshal(t)
+	return string(value), err
+}
+
+func (t *TaxLines) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*t = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(v, t)
+	case string:
+		return json.Unmarshal([]byte(v), t)
+	}
+
+	return errors.New("invoices: cannot scan tax lines")
+}
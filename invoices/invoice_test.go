@@ -0,0 +1,111 @@
+package invoices
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"backend/money"
+	"backend/pricing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	now     = time.Date(2026, 11, 1, 3, 0, 0, 0, time.UTC)
+	october = MonthOf(time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC))
+	vat     = pricing.TaxRate{Name: "VAT", Rate: 2100, Rounding: pricing.TaxRoundingPerTotal}
+	reduced = pricing.TaxRate{Name: "VAT", Rate: 1000, Rounding: pricing.TaxRoundingPerTotal}
+	billed  = []billedRide{
+		{ID: 1, CreatedAt: time.Date(2026, 9, 30, 23, 50, 0, 0, time.UTC), UserID: "1", VehicleID: "7", PriceAmount: 121, PriceCurrency: "EUR", TaxRate: vat, NetAmount: 100, TaxAmount: 21},
+		{ID: 4, CreatedAt: time.Date(2026, 10, 2, 8, 0, 0, 0, time.UTC), UserID: "1", VehicleID: "8", PriceAmount: 220, PriceCurrency: "EUR", TaxRate: reduced, NetAmount: 200, TaxAmount: 20},
+		{ID: 6, CreatedAt: time.Date(2026, 10, 9, 8, 0, 0, 0, time.UTC), UserID: "1", VehicleID: "7", PriceAmount: 363, PriceCurrency: "EUR", TaxRate: vat, NetAmount: 300, TaxAmount: 63},
+		{ID: 5, CreatedAt: time.Date(2026, 10, 3, 8, 0, 0, 0, time.UTC), UserID: "1", VehicleID: "9", PriceAmount: 500, PriceCurrency: "USD", NetAmount: 500},
+		{ID: 2, CreatedAt: time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC), UserID: "2", VehicleID: "7", PriceAmount: 118, PriceCurrency: "EUR", NetAmount: 118},
+	}
+)
+
+func TestMonthOf(t *testing.T) {
+	assert.Equal(t, Period{
+		Start: time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC),
+	}, october)
+	assert.Equal(t, october, MonthOf(time.Date(2026, 10, 31, 23, 59, 59, 0, time.UTC)))
+	assert.Equal(t, "2026", october.Series())
+
+	madrid, _ := time.LoadLocation("Europe/Madrid")
+	assert.Equal(t, MonthOf(time.Date(2026, 12, 1, 0, 30, 0, 0, madrid)).Start, time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC))
+}
+
+func TestDrafts(t *testing.T) {
+	drafts, err := drafts(billed, october, now)
+	assert.Nil(t, err)
+	assert.Len(t, drafts, 3)
+
+	invoice := drafts[0].invoice
+	assert.Equal(t, []interface{}{uint(1), uint(4), uint(6)}, drafts[0].rideIDs)
+	assert.Equal(t, "1", invoice.UserID)
+	assert.Equal(t, october.Start, invoice.PeriodStart)
+	assert.Equal(t, int64(600), invoice.NetAmount)
+	assert.Equal(t, int64(104), invoice.TaxAmount)
+	assert.Equal(t, int64(704), invoice.TotalAmount)
+	assert.Equal(t, TaxLines{
+		{TaxRate: vat, NetAmount: 400, TaxAmount: 84},
+		{TaxRate: reduced, NetAmount: 200, TaxAmount: 20},
+	}, invoice.Taxes)
+	assert.Len(t, invoice.Lines, 3)
+	assert.Equal(t, billed[0].CreatedAt, invoice.Lines[0].StartedAt)
+
+	assert.Equal(t, "USD", string(drafts[1].invoice.Currency))
+	assert.Equal(t, TaxLines{{NetAmount: 500}}, drafts[1].invoice.Taxes)
+	assert.Equal(t, "2", drafts[2].invoice.UserID)
+}
+
+func TestDraftsWithAdjustments(t *testing.T) {
+	adjusted := []billedRide{
+		{ID: 1, CreatedAt: time.Date(2026, 10, 2, 8, 0, 0, 0, time.UTC), UserID: "1", VehicleID: "7", PriceAmount: 363, PriceCurrency: "EUR", TaxRate: vat, NetAmount: 300, TaxAmount: 63, AdjustmentsAmount: -121},
+		{ID: 4, CreatedAt: time.Date(2026, 10, 9, 8, 0, 0, 0, time.UTC), UserID: "1", VehicleID: "8", PriceAmount: 220, PriceCurrency: "EUR", TaxRate: reduced, NetAmount: 200, TaxAmount: 20, AdjustmentsAmount: 55},
+	}
+
+	drafts, err := drafts(adjusted, october, now)
+	assert.Nil(t, err)
+	invoice := drafts[0].invoice
+	assert.Equal(t, Line{RideID: 1, StartedAt: adjusted[0].CreatedAt, VehicleID: "7", TaxRate: vat, AdjustmentsAmount: -121, NetAmount: 200, TaxAmount: 42, Amount: 242}, invoice.Lines[0])
+	assert.Equal(t, Line{RideID: 4, StartedAt: adjusted[1].CreatedAt, VehicleID: "8", TaxRate: reduced, AdjustmentsAmount: 55, NetAmount: 250, TaxAmount: 25, Amount: 275}, invoice.Lines[1])
+	assert.Equal(t, int64(450), invoice.NetAmount)
+	assert.Equal(t, int64(67), invoice.TaxAmount)
+	assert.Equal(t, int64(517), invoice.TotalAmount)
+	assert.Equal(t, TaxLines{
+		{TaxRate: vat, NetAmount: 200, TaxAmount: 42},
+		{TaxRate: reduced, NetAmount: 250, TaxAmount: 25},
+	}, invoice.Taxes)
+}
+
+func TestDraftsOverflow(t *testing.T) {
+	overflowing := []billedRide{
+		{ID: 1, UserID: "1", PriceAmount: math.MaxInt64, PriceCurrency: "EUR", NetAmount: math.MaxInt64},
+		{ID: 2, UserID: "1", PriceAmount: 1, PriceCurrency: "EUR", NetAmount: 1},
+	}
+
+	_, err := drafts(overflowing, october, now)
+	assert.Equal(t, money.ErrOverflow, err)
+}
+
+func TestLinesValueScan(t *testing.T) {
+	drafts, err := drafts(billed, october, now)
+	assert.Nil(t, err)
+	lines := drafts[0].invoice.Lines
+
+	value, err := lines.Value()
+	assert.Nil(t, err)
+
+	var scanned Lines
+	assert.Nil(t, scanned.Scan([]byte(value.(string))))
+	assert.Equal(t, lines, scanned)
+
+	value, err = Lines(nil).Value()
+	assert.Nil(t, err)
+	assert.Equal(t, "[]", value)
+
+	assert.NotNil(t, scanned.Scan(42))
+}
@@ -0,0 +1,28 @@
+package invoices
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-rel/rel"
+)
+
+type Service interface {
+	GenerateInvoices(ctx context.Context, period Period, now time.Time) (error, []Invoice)
+	ListInvoices(ctx context.Context, userID string) (error, []Invoice)
+	GetInvoice(ctx context.Context, userID string, number string) (error, *Invoice)
+}
+
+type service struct {
+	generateInvoices
+	listInvoices
+	getInvoice
+}
+
+func New(repository rel.Repository) Service {
+	return service{
+		generateInvoices: generateInvoices{repository: repository},
+		listInvoices:     listInvoices{repository: repository},
+		getInvoice:       getInvoice{repository: repository},
+	}
+}
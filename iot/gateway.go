@@ -0,0 +1,21 @@
+package iot
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	ErrUnreachable = errors.New("The vehicle can't be reached")
+	ErrTimeout     = errors.New("The vehicle didn't answer in time")
+)
+
+// Gateway sends commands to the locks of the vehicles. Commands are
+// idempotent: unlocking an unlocked vehicle or locking a locked one
+// succeeds.
+type Gateway interface {
+	// Unlock opens the lock of the vehicle so a ride can start on it.
+	Unlock(ctx context.Context, vehicleID string) error
+	// Lock closes the lock of the vehicle once its ride ended.
+	Lock(ctx context.Context, vehicleID string) error
+}
@@ -0,0 +1,112 @@
+package iot
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Policy bounds how long a command may take. Each attempt gets Timeout to
+// be answered, or as long as it takes when Timeout is zero, and a failed
+// attempt is retried after Backoff, doubled after every retry, until
+// Attempts were made.
+type Policy struct {
+	Timeout  time.Duration
+	Attempts int
+	Backoff  time.Duration
+}
+
+// DefaultPolicy gives up on a vehicle within 1.8s, 3 attempts of 500ms
+// and two backoffs.
+var DefaultPolicy = Policy{Timeout: 500 * time.Millisecond, Attempts: 3, Backoff: 100 * time.Millisecond}
+
+var ErrPolicyInvalid = errors.New("iot: a policy needs a positive timeout, at least one attempt and a backoff that isn't negative")
+
+// Validate rejects the policies that never give up on a vehicle, which
+// would keep the rides waiting on it.
+func (p Policy) Validate() error {
+	if p.Timeout <= 0 || p.Attempts < 1 || p.Backoff < 0 {
+		return ErrPolicyInvalid
+	}
+	return nil
+}
+
+// Budget is the longest a command may take under the policy: every attempt
+// running out of time, and the backoffs between them. It is zero, for no
+// bound, when Timeout is.
+func (p Policy) Budget() time.Duration {
+	if p.Timeout == 0 {
+		return 0
+	}
+
+	var (
+		budget  time.Duration
+		backoff = p.Backoff
+	)
+	for attempt := 1; attempt <= p.Attempts; attempt++ {
+		budget += p.Timeout
+		if attempt < p.Attempts {
+			budget += backoff
+			backoff *= 2
+		}
+	}
+	return budget
+}
+
+// Retrying is a Gateway that sends the commands of another one under a
+// Policy. A command that still fails returns the error of its last attempt,
+// ErrTimeout when that attempt ran out of time, be it its own or the one of
+// ctx.
+type Retrying struct {
+	gateway Gateway
+	policy  Policy
+}
+
+func NewRetrying(gateway Gateway, policy Policy) Retrying {
+	return Retrying{gateway: gateway, policy: policy}
+}
+
+func (r Retrying) Unlock(ctx context.Context, vehicleID string) error {
+	return r.send(ctx, func(ctx context.Context) error {
+		return r.gateway.Unlock(ctx, vehicleID)
+	})
+}
+
+func (r Retrying) Lock(ctx context.Context, vehicleID string) error {
+	return r.send(ctx, func(ctx context.Context) error {
+		return r.gateway.Lock(ctx, vehicleID)
+	})
+}
+
+// send stops retrying as soon as ctx is done.
+func (r Retrying) send(ctx context.Context, command func(context.Context) error) error {
+	backoff := r.policy.Backoff
+	for attempt := 1; ; attempt++ {
+		err := r.attempt(ctx, command)
+		if err == nil || attempt >= r.policy.Attempts {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+func (r Retrying) attempt(ctx context.Context, command func(context.Context) error) error {
+	if r.policy.Timeout == 0 {
+		return command(ctx)
+	}
+
+	attemptCtx, cancel := context.WithTimeout(ctx, r.policy.Timeout)
+	defer cancel()
+
+	err := command(attemptCtx)
+	if err != nil && errors.Is(attemptCtx.Err(), context.DeadlineExceeded) {
+		return ErrTimeout
+	}
+	return err
+}
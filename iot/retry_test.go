@@ -0,0 +1,73 @@
+package iot
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var policy = Policy{Timeout: 50 * time.Millisecond, Attempts: 3, Backoff: time.Millisecond}
+
+func TestRetryingRecovers(t *testing.T) {
+	var (
+		ctx       = context.TODO()
+		simulator = NewSimulator()
+		gateway   = NewRetrying(simulator, policy)
+	)
+	simulator.Fail("1", 2)
+
+	assert.Nil(t, gateway.Unlock(ctx, "1"))
+	assert.True(t, simulator.Unlocked("1"))
+}
+
+func TestRetryingGivesUp(t *testing.T) {
+	var (
+		ctx       = context.TODO()
+		simulator = NewSimulator()
+		gateway   = NewRetrying(simulator, policy)
+	)
+	simulator.Fail("1", 3)
+
+	assert.Equal(t, ErrUnreachable, gateway.Unlock(ctx, "1"))
+	assert.False(t, simulator.Unlocked("1"))
+
+	assert.Nil(t, gateway.Unlock(ctx, "1"))
+}
+
+func TestRetryingTimeout(t *testing.T) {
+	var (
+		ctx       = context.TODO()
+		simulator = NewSimulator()
+		gateway   = NewRetrying(simulator, policy)
+	)
+	simulator.Delay(time.Hour)
+
+	assert.Equal(t, ErrTimeout, gateway.Lock(ctx, "1"))
+}
+
+func TestRetryingCancelled(t *testing.T) {
+	var (
+		simulator   = NewSimulator()
+		gateway     = NewRetrying(simulator, Policy{Attempts: 3, Backoff: time.Hour})
+		ctx, cancel = context.WithCancel(context.TODO())
+	)
+	simulator.Fail("1", -1)
+
+	go cancel()
+	assert.Equal(t, ErrUnreachable, gateway.Unlock(ctx, "1"))
+}
+
+func TestPolicyBudget(t *testing.T) {
+	assert.Equal(t, 1800*time.Millisecond, DefaultPolicy.Budget())
+	assert.Equal(t, 3300*time.Millisecond, Policy{Timeout: time.Second, Attempts: 3, Backoff: 100 * time.Millisecond}.Budget())
+	assert.Equal(t, time.Duration(0), Policy{Attempts: 3, Backoff: time.Second}.Budget())
+}
+
+func TestPolicyValidate(t *testing.T) {
+	assert.Nil(t, DefaultPolicy.Validate())
+	assert.Equal(t, ErrPolicyInvalid, Policy{Attempts: 3}.Validate())
+	assert.Equal(t, ErrPolicyInvalid, Policy{Timeout: time.Second}.Validate())
+	assert.Equal(t, ErrPolicyInvalid, Policy{Timeout: time.Second, Attempts: 1, Backoff: -time.Second}.Validate())
+}
@@ -0,0 +1,82 @@
+package iot
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Simulator is an in-process Gateway standing in for the vehicles, for
+// tests and local use. Vehicles start locked. Commands take the configured
+// delay, and fail with ErrUnreachable for the vehicles told to.
+type Simulator struct {
+	mu       sync.Mutex
+	unlocked map[string]bool
+	failures map[string]int
+	delay    time.Duration
+}
+
+func NewSimulator() *Simulator {
+	return &Simulator{
+		unlocked: map[string]bool{},
+		failures: map[string]int{},
+	}
+}
+
+// Delay makes every command take d to be answered, or none when d is zero.
+// A command whose context is done first is never received.
+func (s *Simulator) Delay(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.delay = d
+}
+
+// Fail makes the next times commands to the vehicle fail, or every one of
+// them when times is negative.
+func (s *Simulator) Fail(vehicleID string, times int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures[vehicleID] = times
+}
+
+// Unlocked reports whether the vehicle is unlocked.
+func (s *Simulator) Unlocked(vehicleID string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.unlocked[vehicleID]
+}
+
+func (s *Simulator) Unlock(ctx context.Context, vehicleID string) error {
+	return s.command(ctx, vehicleID, true)
+}
+
+func (s *Simulator) Lock(ctx context.Context, vehicleID string) error {
+	return s.command(ctx, vehicleID, false)
+}
+
+func (s *Simulator) command(ctx context.Context, vehicleID string, unlocked bool) error {
+	s.mu.Lock()
+	delay := s.delay
+	s.mu.Unlock()
+
+	if delay > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch failures := s.failures[vehicleID]; {
+	case failures < 0:
+		return ErrUnreachable
+	case failures > 0:
+		s.failures[vehicleID]--
+		return ErrUnreachable
+	}
+
+	s.unlocked[vehicleID] = unlocked
+	return nil
+}
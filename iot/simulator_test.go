@@ -0,0 +1,54 @@
+package iot
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSimulatorUnlockAndLock(t *testing.T) {
+	var (
+		ctx       = context.TODO()
+		simulator = NewSimulator()
+	)
+	assert.False(t, simulator.Unlocked("1"))
+
+	assert.Nil(t, simulator.Unlock(ctx, "1"))
+	assert.True(t, simulator.Unlocked("1"))
+	assert.Nil(t, simulator.Unlock(ctx, "1"))
+	assert.True(t, simulator.Unlocked("1"))
+
+	assert.Nil(t, simulator.Lock(ctx, "1"))
+	assert.False(t, simulator.Unlocked("1"))
+}
+
+func TestSimulatorFail(t *testing.T) {
+	var (
+		ctx       = context.TODO()
+		simulator = NewSimulator()
+	)
+	simulator.Fail("1", 1)
+
+	assert.Equal(t, ErrUnreachable, simulator.Unlock(ctx, "1"))
+	assert.False(t, simulator.Unlocked("1"))
+	assert.Nil(t, simulator.Unlock(ctx, "1"))
+	assert.True(t, simulator.Unlocked("1"))
+
+	simulator.Fail("2", -1)
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, ErrUnreachable, simulator.Unlock(ctx, "2"))
+	}
+}
+
+func TestSimulatorDelay(t *testing.T) {
+	simulator := NewSimulator()
+	simulator.Delay(time.Hour)
+
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Millisecond)
+	defer cancel()
+
+	assert.Equal(t, context.DeadlineExceeded, simulator.Unlock(ctx, "1"))
+	assert.False(t, simulator.Unlocked("1"))
+}
@@ -0,0 +1,39 @@
+package money
+
+import (
+	"strconv"
+	"strings"
+)
+
+// zeroDecimal are the currencies whose minor unit is the major one.
+var zeroDecimal = map[Currency]bool{
+	"CLP": true, "ISK": true, "JPY": true, "KRW": true, "PYG": true,
+	"UGX": true, "VND": true, "XAF": true, "XOF": true,
+}
+
+// Decimals is the number of digits of the minor unit of the currency, 2 for
+// most of them.
+func (c Currency) Decimals() int {
+	if zeroDecimal[c] {
+		return 0
+	}
+	return 2
+}
+
+// String writes the amount in the major unit followed by the currency, e.g.
+// "1.18 EUR" or "-0.05 EUR".
+func (m Money) String() string {
+	decimals := m.Currency.Decimals()
+	digits := strconv.FormatInt(m.Amount, 10)
+	sign := ""
+	if strings.HasPrefix(digits, "-") {
+		sign, digits = "-", digits[1:]
+	}
+	if decimals > 0 {
+		if len(digits) <= decimals {
+			digits = strings.Repeat("0", decimals-len(digits)+1) + digits
+		}
+		digits = digits[:len(digits)-decimals] + "." + digits[len(digits)-decimals:]
+	}
+	return sign + digits + " " + string(m.Currency)
+}
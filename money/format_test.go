@@ -0,0 +1,18 @@
+package money
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestString(t *testing.T) {
+	assert.Equal(t, "1.18 EUR", New(118, "EUR").String())
+	assert.Equal(t, "0.05 EUR", New(5, "EUR").String())
+	assert.Equal(t, "0.00 EUR", Zero("EUR").String())
+	assert.Equal(t, "-0.05 EUR", New(-5, "EUR").String())
+	assert.Equal(t, "-12.50 USD", New(-1250, "USD").String())
+	assert.Equal(t, "118 JPY", New(118, "JPY").String())
+	assert.Equal(t, "-92233720368547758.08 EUR", New(math.MinInt64, "EUR").String())
+}
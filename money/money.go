@@ -0,0 +1,125 @@
+package money
+
+import (
+	"errors"
+	"math"
+)
+
+// Currency is an ISO 4217 alphabetic code, e.g. "EUR".
+type Currency string
+
+func (c Currency) Valid() bool {
+	if len(c) != 3 {
+		return false
+	}
+	for _, r := range c {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// Money is an amount in the minor unit of its currency, e.g. cents for EUR,
+// so 118 EUR is 1.18 euros.
+type Money struct {
+	Amount   int64    `json:"amount"`
+	Currency Currency `json:"currency"`
+}
+
+var (
+	ErrCurrencyMismatch = errors.New("money: can't mix currencies")
+	ErrOverflow         = errors.New("money: amount out of range")
+)
+
+func New(amount int64, currency Currency) Money {
+	return Money{Amount: amount, Currency: currency}
+}
+
+// Zero is no money in the given currency.
+func Zero(currency Currency) Money {
+	return Money{Currency: currency}
+}
+
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+func (m Money) Add(other Money) (Money, error) {
+	if m.Currency != other.Currency {
+		return Money{}, ErrCurrencyMismatch
+	}
+	if (other.Amount > 0 && m.Amount > math.MaxInt64-other.Amount) ||
+		(other.Amount < 0 && m.Amount < math.MinInt64-other.Amount) {
+		return Money{}, ErrOverflow
+	}
+	return Money{Amount: m.Amount + other.Amount, Currency: m.Currency}, nil
+}
+
+func (m Money) Sub(other Money) (Money, error) {
+	if other.Amount == math.MinInt64 {
+		return Money{}, ErrOverflow
+	}
+	return m.Add(other.Neg())
+}
+
+// Mul multiplies the amount, e.g. a unit price by a quantity.
+func (m Money) Mul(n int64) (Money, error) {
+	if m.Amount != 0 && n != 0 {
+		product := m.Amount * n
+		if product/n != m.Amount || (m.Amount == -1 && n == math.MinInt64) || (n == -1 && m.Amount == math.MinInt64) {
+			return Money{}, ErrOverflow
+		}
+		return Money{Amount: product, Currency: m.Currency}, nil
+	}
+	return Money{Currency: m.Currency}, nil
+}
+
+// Scale multiplies the amount by num/den, e.g. a price by a rate in basis
+// points over 10000, rounding half away from zero to the minor unit. den
+// must be positive.
+func (m Money) Scale(num, den int64) (Money, error) {
+	product, err := m.Mul(num)
+	if err != nil {
+		return Money{}, err
+	}
+	quotient, remainder := product.Amount/den, product.Amount%den
+	switch {
+	case remainder >= den-remainder:
+		quotient++
+	case -remainder >= den+remainder:
+		quotient--
+	}
+	return Money{Amount: quotient, Currency: m.Currency}, nil
+}
+
+func (m Money) Neg() Money {
+	return Money{Amount: -m.Amount, Currency: m.Currency}
+}
+
+// Cmp returns -1, 0 or +1 depending on whether m is less than, equal to or
+// greater than other.
+func (m Money) Cmp(other Money) (int, error) {
+	if m.Currency != other.Currency {
+		return 0, ErrCurrencyMismatch
+	}
+	switch {
+	case m.Amount < other.Amount:
+		return -1, nil
+	case m.Amount > other.Amount:
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// Sum adds up amounts in the given currency.
+func Sum(currency Currency, amounts ...Money) (Money, error) {
+	total := Zero(currency)
+	for _, amount := range amounts {
+		var err error
+		if total, err = total.Add(amount); err != nil {
+			return Money{}, err
+		}
+	}
+	return total, nil
+}
@@ -0,0 +1,113 @@
+package money
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCurrencyValid(t *testing.T) {
+	assert.True(t, Currency("EUR").Valid())
+	assert.False(t, Currency("").Valid())
+	assert.False(t, Currency("eur").Valid())
+	assert.False(t, Currency("EURO").Valid())
+}
+
+func TestAdd(t *testing.T) {
+	sum, err := New(118, "EUR").Add(New(100, "EUR"))
+	assert.Nil(t, err)
+	assert.Equal(t, New(218, "EUR"), sum)
+
+	_, err = New(118, "EUR").Add(New(100, "USD"))
+	assert.Equal(t, ErrCurrencyMismatch, err)
+
+	_, err = New(math.MaxInt64, "EUR").Add(New(1, "EUR"))
+	assert.Equal(t, ErrOverflow, err)
+
+	_, err = New(math.MinInt64, "EUR").Add(New(-1, "EUR"))
+	assert.Equal(t, ErrOverflow, err)
+}
+
+func TestSub(t *testing.T) {
+	difference, err := New(118, "EUR").Sub(New(200, "EUR"))
+	assert.Nil(t, err)
+	assert.Equal(t, New(-82, "EUR"), difference)
+
+	_, err = New(118, "EUR").Sub(New(100, "USD"))
+	assert.Equal(t, ErrCurrencyMismatch, err)
+
+	_, err = New(0, "EUR").Sub(New(math.MinInt64, "EUR"))
+	assert.Equal(t, ErrOverflow, err)
+}
+
+func TestMul(t *testing.T) {
+	product, err := New(100, "EUR").Mul(27)
+	assert.Nil(t, err)
+	assert.Equal(t, New(2700, "EUR"), product)
+
+	product, err = New(100, "EUR").Mul(0)
+	assert.Nil(t, err)
+	assert.Equal(t, Zero("EUR"), product)
+
+	_, err = New(math.MaxInt64/2+1, "EUR").Mul(2)
+	assert.Equal(t, ErrOverflow, err)
+
+	_, err = New(math.MinInt64, "EUR").Mul(-1)
+	assert.Equal(t, ErrOverflow, err)
+}
+
+func TestScale(t *testing.T) {
+	tests := []struct {
+		amount   int64
+		num, den int64
+		scaled   int64
+	}{
+		{1000, 2100, 10000, 210},
+		{1005, 50, 100, 503},
+		{1004, 50, 100, 502},
+		{-1005, 50, 100, -503},
+		{-1004, 50, 100, -502},
+		{250, 30, 60, 125},
+		{0, 2100, 10000, 0},
+	}
+
+	for _, test := range tests {
+		scaled, err := New(test.amount, "EUR").Scale(test.num, test.den)
+		assert.Nil(t, err)
+		assert.Equal(t, New(test.scaled, "EUR"), scaled)
+	}
+
+	_, err := New(math.MaxInt64/2, "EUR").Scale(3, 4)
+	assert.Equal(t, ErrOverflow, err)
+}
+
+func TestCmp(t *testing.T) {
+	cmp, err := New(118, "EUR").Cmp(New(100, "EUR"))
+	assert.Nil(t, err)
+	assert.Equal(t, 1, cmp)
+
+	cmp, err = New(100, "EUR").Cmp(New(100, "EUR"))
+	assert.Nil(t, err)
+	assert.Equal(t, 0, cmp)
+
+	cmp, err = New(-1, "EUR").Cmp(Zero("EUR"))
+	assert.Nil(t, err)
+	assert.Equal(t, -1, cmp)
+
+	_, err = New(118, "EUR").Cmp(New(118, "USD"))
+	assert.Equal(t, ErrCurrencyMismatch, err)
+}
+
+func TestSum(t *testing.T) {
+	total, err := Sum("EUR", New(18, "EUR"), New(100, "EUR"), New(-10, "EUR"))
+	assert.Nil(t, err)
+	assert.Equal(t, New(108, "EUR"), total)
+
+	total, err = Sum("EUR")
+	assert.Nil(t, err)
+	assert.Equal(t, Zero("EUR"), total)
+
+	_, err = Sum("EUR", New(18, "EUR"), New(100, "USD"))
+	assert.Equal(t, ErrCurrencyMismatch, err)
+}
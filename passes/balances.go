@@ -0,0 +1,73 @@
+package passes
+
+import (
+	"context"
+	"time"
+
+	"backend/pricing"
+
+	"github.com/go-rel/rel"
+	"github.com/go-rel/rel/where"
+)
+
+// Balances is the pricing.Passes backed by the stored passes.
+type Balances struct {
+	repository rel.Repository
+}
+
+func NewBalances(repository rel.Repository) Balances {
+	return Balances{repository: repository}
+}
+
+func (b Balances) Allowances(ctx context.Context, userID string, at time.Time) ([]pricing.Allowance, error) {
+	var (
+		passes []Pass
+		query  = rel.Where(
+			where.Eq("user_id", userID).
+				AndLte("starts_at", at).
+				AndGt("ends_at", at).
+				And(where.Eq("free_unlocks", true).OrGt("minutes_left", 0)),
+		).SortAsc("ends_at", "id")
+	)
+	if err := b.repository.FindAll(ctx, &passes, query); err != nil {
+		return nil, err
+	}
+
+	allowances := make([]pricing.Allowance, len(passes))
+	for i, pass := range passes {
+		allowances[i] = pass.Allowance()
+	}
+	return allowances, nil
+}
+
+// Consume takes the minutes off each pass in the statement that checks they
+// are still there.
+func (b Balances) Consume(ctx context.Context, rideID uint, consumptions []pricing.Consumption, at time.Time) error {
+	for _, consumption := range consumptions {
+		if consumption.Minutes > 0 {
+			updated, err := b.repository.UpdateAny(ctx,
+				rel.From("passes").Where(where.Eq("id", consumption.PassID).AndGte("minutes_left", consumption.Minutes)),
+				rel.DecBy("minutes_left", consumption.Minutes),
+			)
+			if err != nil {
+				return err
+			}
+			if updated == 0 {
+				return ErrPassMinutesChanged
+			}
+		}
+
+		if err := b.repository.Insert(ctx, &Consumption{
+			CreatedAt: at,
+			PassID:    consumption.PassID,
+			RideID:    rideID,
+			Unlock:    consumption.Unlock,
+			Minutes:   consumption.Minutes,
+			Amount:    int(consumption.Amount.Amount),
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,81 @@
+package passes
+
+import (
+	"context"
+	"testing"
+
+	"backend/money"
+	"backend/pricing"
+
+	"github.com/go-rel/rel"
+	"github.com/go-rel/rel/where"
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAllowances(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		balances   = NewBalances(repository)
+		unlocks    = Pass{ID: 3, UserID: "1", Name: "Unlimited unlocks", FreeUnlocks: true, StartsAt: now, EndsAt: now.AddDate(0, 0, 7)}
+		minutes    = Pass{ID: 4, UserID: "1", Name: "100 minutes", Minutes: 100, MinutesLeft: 40, StartsAt: now, EndsAt: now.AddDate(0, 0, 30)}
+	)
+
+	repository.ExpectFindAll(rel.Where(
+		where.Eq("user_id", "1").
+			AndLte("starts_at", now).
+			AndGt("ends_at", now).
+			And(where.Eq("free_unlocks", true).OrGt("minutes_left", 0)),
+	).SortAsc("ends_at", "id")).Result([]Pass{unlocks, minutes})
+
+	allowances, err := balances.Allowances(ctx, "1", now)
+	assert.Nil(t, err)
+	assert.Equal(t, []pricing.Allowance{
+		{PassID: 3, Name: "Unlimited unlocks", Unlock: true},
+		{PassID: 4, Name: "100 minutes", Minutes: 40},
+	}, allowances)
+
+	repository.AssertExpectations(t)
+}
+
+func TestConsume(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		balances   = NewBalances(repository)
+	)
+
+	repository.ExpectInsert().For(&Consumption{CreatedAt: now, PassID: 3, RideID: 1, Unlock: true, Amount: 18})
+	repository.ExpectUpdateAny(
+		rel.From("passes").Where(where.Eq("id", uint(4)).AndGte("minutes_left", 5)),
+		rel.DecBy("minutes_left", 5),
+	).UpdatedCount(1)
+	repository.ExpectInsert().For(&Consumption{CreatedAt: now, PassID: 4, RideID: 1, Minutes: 5, Amount: 500})
+
+	err := balances.Consume(ctx, 1, []pricing.Consumption{
+		{PassID: 3, Unlock: true, Amount: money.New(18, "EUR")},
+		{PassID: 4, Minutes: 5, Amount: money.New(500, "EUR")},
+	}, now)
+	assert.Nil(t, err)
+
+	repository.AssertExpectations(t)
+}
+
+func TestConsumeMinutesChanged(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		balances   = NewBalances(repository)
+	)
+
+	repository.ExpectUpdateAny(
+		rel.From("passes").Where(where.Eq("id", uint(4)).AndGte("minutes_left", 5)),
+		rel.DecBy("minutes_left", 5),
+	).UpdatedCount(0)
+
+	err := balances.Consume(ctx, 1, []pricing.Consumption{{PassID: 4, Minutes: 5, Amount: money.New(500, "EUR")}}, now)
+	assert.Equal(t, ErrPassMinutesChanged, err)
+
+	repository.AssertExpectations(t)
+}
@@ -0,0 +1,115 @@
+package passes
+
+import (
+	"errors"
+	"time"
+
+	"backend/money"
+	"backend/pricing"
+)
+
+// Product is a pass on sale: it covers every unlock fee when FreeUnlocks
+// is set, and Minutes minutes of riding time, during ValidityDays days
+// from its purchase.
+type Product struct {
+	ID           uint           `json:"id"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+	Name         string         `json:"name"`
+	FreeUnlocks  bool           `json:"free_unlocks"`
+	Minutes      int            `json:"minutes"`
+	ValidityDays int            `json:"validity_days"`
+	Price        int            `json:"price"`
+	Currency     money.Currency `json:"currency"`
+}
+
+func (Product) Table() string {
+	return "pass_products"
+}
+
+// Pass is a product bought by a user, with what it has left to cover.
+type Pass struct {
+	ID          uint           `json:"id"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	UserID      string         `json:"user_id"`
+	ProductID   uint           `json:"product_id"`
+	Name        string         `json:"name"`
+	FreeUnlocks bool           `json:"free_unlocks"`
+	Minutes     int            `json:"minutes"`
+	MinutesLeft int            `json:"minutes_left"`
+	Price       int            `json:"price"`
+	Currency    money.Currency `json:"currency"`
+	StartsAt    time.Time      `json:"starts_at"`
+	EndsAt      time.Time      `json:"ends_at"`
+}
+
+// Consumption is what a ride used up of a pass. ReversedAt is set once it
+// was given back to the pass.
+type Consumption struct {
+	ID         uint       `json:"id"`
+	CreatedAt  time.Time  `json:"created_at"`
+	PassID     uint       `json:"pass_id"`
+	RideID     uint       `json:"ride_id"`
+	Unlock     bool       `json:"unlock"`
+	Minutes    int        `json:"minutes"`
+	Amount     int        `json:"amount"`
+	ReversedAt *time.Time `json:"reversed_at"`
+}
+
+func (Consumption) Table() string {
+	return "pass_consumptions"
+}
+
+var (
+	ErrProductNameBlank       = errors.New("Name can't be blank")
+	ErrProductEmpty           = errors.New("A pass must cover unlocks or a positive number of minutes")
+	ErrProductMinutesNegative = errors.New("Minutes can't be negative")
+	ErrProductValidityInvalid = errors.New("ValidityDays must be positive")
+	ErrProductPriceInvalid    = errors.New("Price can't be negative and needs a three-letter ISO 4217 currency")
+	ErrProductNotFound        = errors.New("No pass product matches the given ID")
+	ErrPassUserIDBlank        = errors.New("UserID can't be blank")
+	ErrPassMinutesChanged     = errors.New("The pass no longer has the minutes the ride consumed")
+)
+
+func (p Product) Validate() error {
+	switch {
+	case p.Name == "":
+		return ErrProductNameBlank
+	case p.Minutes < 0:
+		return ErrProductMinutesNegative
+	case !p.FreeUnlocks && p.Minutes == 0:
+		return ErrProductEmpty
+	case p.ValidityDays < 1:
+		return ErrProductValidityInvalid
+	case p.Price < 0 || !p.Currency.Valid():
+		return ErrProductPriceInvalid
+	}
+	return nil
+}
+
+// Pass is the product as bought by the user at the given instant.
+func (p Product) Pass(userID string, at time.Time) Pass {
+	return Pass{
+		UserID:      userID,
+		ProductID:   p.ID,
+		Name:        p.Name,
+		FreeUnlocks: p.FreeUnlocks,
+		Minutes:     p.Minutes,
+		MinutesLeft: p.Minutes,
+		Price:       p.Price,
+		Currency:    p.Currency,
+		StartsAt:    at,
+		EndsAt:      at.AddDate(0, 0, p.ValidityDays),
+	}
+}
+
+// Allowance is what the pass has left to cover rides with.
+func (p Pass) Allowance() pricing.Allowance {
+	return pricing.Allowance{
+		PassID:  p.ID,
+		Name:    p.Name,
+		Unlock:  p.FreeUnlocks,
+		Minutes: p.MinutesLeft,
+	}
+}
@@ -0,0 +1,58 @@
+package passes
+
+import (
+	"testing"
+	"time"
+
+	"backend/pricing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	now     = time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	product = Product{ID: 1, Name: "100 minutes", Minutes: 100, ValidityDays: 30, Price: 1500, Currency: "EUR"}
+)
+
+func TestProductValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		product Product
+		err     error
+	}{
+		{"valid", product, nil},
+		{"unlocks only", Product{Name: "Unlimited unlocks", FreeUnlocks: true, ValidityDays: 30, Currency: "EUR"}, nil},
+		{"name blank", Product{Minutes: 100, ValidityDays: 30, Currency: "EUR"}, ErrProductNameBlank},
+		{"minutes negative", Product{Name: "x", FreeUnlocks: true, Minutes: -1, ValidityDays: 30, Currency: "EUR"}, ErrProductMinutesNegative},
+		{"empty", Product{Name: "x", ValidityDays: 30, Currency: "EUR"}, ErrProductEmpty},
+		{"validity", Product{Name: "x", Minutes: 100, Currency: "EUR"}, ErrProductValidityInvalid},
+		{"price negative", Product{Name: "x", Minutes: 100, ValidityDays: 30, Price: -1, Currency: "EUR"}, ErrProductPriceInvalid},
+		{"currency", Product{Name: "x", Minutes: 100, ValidityDays: 30, Currency: "eur"}, ErrProductPriceInvalid},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.err, test.product.Validate())
+		})
+	}
+}
+
+func TestProductPass(t *testing.T) {
+	pass := product.Pass("1", now)
+
+	assert.Equal(t, Pass{
+		UserID:      "1",
+		ProductID:   1,
+		Name:        "100 minutes",
+		Minutes:     100,
+		MinutesLeft: 100,
+		Price:       1500,
+		Currency:    "EUR",
+		StartsAt:    now,
+		EndsAt:      now.AddDate(0, 0, 30),
+	}, pass)
+
+	pass.ID = 3
+	pass.MinutesLeft = 40
+	assert.Equal(t, pricing.Allowance{PassID: 3, Name: "100 minutes", Minutes: 40}, pass.Allowance())
+}
@@ -0,0 +1,55 @@
+package passes
+
+import (
+	"context"
+	"errors"
+
+	"github.com/go-rel/rel"
+	"github.com/go-rel/rel/where"
+)
+
+type createProduct struct {
+	repository rel.Repository
+}
+
+// CreateProduct puts a pass on sale.
+func (c createProduct) CreateProduct(ctx context.Context, product *Product) (error, *Product) {
+	if err := product.Validate(); err != nil {
+		return err, nil
+	}
+
+	if err := c.repository.Insert(ctx, product); err != nil {
+		return err, nil
+	}
+
+	return nil, product
+}
+
+type getProduct struct {
+	repository rel.Repository
+}
+
+func (c getProduct) GetProduct(ctx context.Context, id uint) (error, *Product) {
+	var product Product
+	if err := c.repository.Find(ctx, &product, where.Eq("id", id)); err != nil {
+		if errors.Is(err, rel.ErrNotFound) {
+			return ErrProductNotFound, nil
+		}
+		return err, nil
+	}
+
+	return nil, &product
+}
+
+type listProducts struct {
+	repository rel.Repository
+}
+
+func (c listProducts) ListProducts(ctx context.Context) (error, []Product) {
+	products := []Product{}
+	if err := c.repository.FindAll(ctx, &products, rel.From("pass_products").SortAsc("id")); err != nil {
+		return err, nil
+	}
+
+	return nil, products
+}
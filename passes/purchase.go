@@ -0,0 +1,47 @@
+package passes
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-rel/rel"
+	"github.com/go-rel/rel/where"
+)
+
+type purchasePass struct {
+	repository rel.Repository
+}
+
+// PurchasePass gives the user a pass of the product, valid from the given
+// instant.
+func (c purchasePass) PurchasePass(ctx context.Context, userID string, productID uint, now time.Time) (error, *Pass) {
+	if userID == "" {
+		return ErrPassUserIDBlank, nil
+	}
+
+	err, product := getProduct{repository: c.repository}.GetProduct(ctx, productID)
+	if err != nil {
+		return err, nil
+	}
+
+	pass := product.Pass(userID, now)
+	if err := c.repository.Insert(ctx, &pass); err != nil {
+		return err, nil
+	}
+
+	return nil, &pass
+}
+
+type listPasses struct {
+	repository rel.Repository
+}
+
+// ListPasses returns the passes of the user, the latest to expire first.
+func (c listPasses) ListPasses(ctx context.Context, userID string) (error, []Pass) {
+	passes := []Pass{}
+	if err := c.repository.FindAll(ctx, &passes, rel.Where(where.Eq("user_id", userID)).SortDesc("ends_at", "id")); err != nil {
+		return err, nil
+	}
+
+	return nil, passes
+}
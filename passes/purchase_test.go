@@ -0,0 +1,60 @@
+package passes
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-rel/rel/where"
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPurchasePass(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository)
+		pass       = product.Pass("1", now)
+	)
+
+	repository.ExpectFind(where.Eq("id", product.ID)).Result(product)
+	repository.ExpectInsert().For(&pass)
+
+	err, savedPass := service.PurchasePass(ctx, "1", product.ID, now)
+	assert.Nil(t, err)
+	assert.NotEmpty(t, savedPass.ID)
+	assert.Equal(t, 100, savedPass.MinutesLeft)
+	assert.Equal(t, now.AddDate(0, 0, 30), savedPass.EndsAt)
+
+	repository.AssertExpectations(t)
+}
+
+func TestPurchasePassProductNotFound(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository)
+	)
+
+	repository.ExpectFind(where.Eq("id", uint(2))).NotFound()
+
+	err, savedPass := service.PurchasePass(ctx, "1", 2, now)
+	assert.Equal(t, ErrProductNotFound, err)
+	assert.Nil(t, savedPass)
+
+	repository.AssertExpectations(t)
+}
+
+func TestPurchasePassUserIDBlank(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository)
+	)
+
+	err, savedPass := service.PurchasePass(ctx, "", product.ID, now)
+	assert.Equal(t, ErrPassUserIDBlank, err)
+	assert.Nil(t, savedPass)
+
+	repository.AssertExpectations(t)
+}
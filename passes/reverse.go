@@ -0,0 +1,60 @@
+package passes
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-rel/rel"
+	"github.com/go-rel/rel/where"
+)
+
+type reverseRide struct {
+	repository rel.Repository
+}
+
+// ReverseRide gives back to the passes what a ride consumed, for instance
+// when its charge is refunded, and returns the consumptions reversed.
+// Each consumption is claimed in the statement that marks it reversed, so
+// reversing a ride twice, even concurrently, gives nothing back the second
+// time.
+func (c reverseRide) ReverseRide(ctx context.Context, rideID uint, now time.Time) (error, []Consumption) {
+	reversed := []Consumption{}
+	err := c.repository.Transaction(ctx, func(ctx context.Context) error {
+		var consumptions []Consumption
+		if err := c.repository.FindAll(ctx, &consumptions, where.Eq("ride_id", rideID).AndNil("reversed_at")); err != nil {
+			return err
+		}
+
+		for _, consumption := range consumptions {
+			claimed, err := c.repository.UpdateAny(ctx,
+				rel.From("pass_consumptions").Where(where.Eq("id", consumption.ID).AndNil("reversed_at")),
+				rel.Set("reversed_at", now),
+			)
+			if err != nil {
+				return err
+			}
+			if claimed != 1 {
+				continue
+			}
+
+			if consumption.Minutes > 0 {
+				if _, err := c.repository.UpdateAny(ctx,
+					rel.From("passes").Where(where.Eq("id", consumption.PassID)),
+					rel.IncBy("minutes_left", consumption.Minutes),
+				); err != nil {
+					return err
+				}
+			}
+
+			consumption.ReversedAt = &now
+			reversed = append(reversed, consumption)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err, nil
+	}
+
+	return nil, reversed
+}
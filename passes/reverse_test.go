@@ -0,0 +1,89 @@
+package passes
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-rel/rel"
+	"github.com/go-rel/rel/where"
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReverseRide(t *testing.T) {
+	var (
+		ctx          = context.TODO()
+		repository   = reltest.New()
+		service      = New(repository)
+		consumptions = []Consumption{
+			{ID: 1, PassID: 3, RideID: 1, Unlock: true, Amount: 18},
+			{ID: 2, PassID: 4, RideID: 1, Minutes: 5, Amount: 500},
+		}
+	)
+
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectFindAll(where.Eq("ride_id", uint(1)).AndNil("reversed_at")).Result(consumptions)
+		repository.ExpectUpdateAny(
+			rel.From("pass_consumptions").Where(where.Eq("id", uint(1)).AndNil("reversed_at")),
+			rel.Set("reversed_at", now),
+		).UpdatedCount(1)
+		repository.ExpectUpdateAny(
+			rel.From("pass_consumptions").Where(where.Eq("id", uint(2)).AndNil("reversed_at")),
+			rel.Set("reversed_at", now),
+		).UpdatedCount(1)
+		repository.ExpectUpdateAny(
+			rel.From("passes").Where(where.Eq("id", uint(4))),
+			rel.IncBy("minutes_left", 5),
+		).UpdatedCount(1)
+	})
+
+	err, reversed := service.ReverseRide(ctx, 1, now)
+	assert.Nil(t, err)
+	assert.Len(t, reversed, 2)
+	assert.Equal(t, &now, reversed[1].ReversedAt)
+
+	repository.AssertExpectations(t)
+}
+
+func TestReverseRideTwice(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository)
+	)
+
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectFindAll(where.Eq("ride_id", uint(1)).AndNil("reversed_at")).Result([]Consumption{})
+	})
+
+	err, reversed := service.ReverseRide(ctx, 1, now)
+	assert.Nil(t, err)
+	assert.Empty(t, reversed)
+
+	repository.AssertExpectations(t)
+}
+
+func TestReverseRideConcurrently(t *testing.T) {
+	var (
+		ctx          = context.TODO()
+		repository   = reltest.New()
+		service      = New(repository)
+		consumptions = []Consumption{
+			{ID: 2, PassID: 4, RideID: 1, Minutes: 5, Amount: 500},
+		}
+	)
+
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectFindAll(where.Eq("ride_id", uint(1)).AndNil("reversed_at")).Result(consumptions)
+		repository.ExpectUpdateAny(
+			rel.From("pass_consumptions").Where(where.Eq("id", uint(2)).AndNil("reversed_at")),
+			rel.Set("reversed_at", now),
+		).UpdatedCount(0)
+	})
+
+	err, reversed := service.ReverseRide(ctx, 1, now)
+	assert.Nil(t, err)
+	assert.Empty(t, reversed)
+
+	repository.AssertExpectations(t)
+}
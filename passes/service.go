@@ -0,0 +1,37 @@
+package passes
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-rel/rel"
+)
+
+type Service interface {
+	CreateProduct(ctx context.Context, product *Product) (error, *Product)
+	GetProduct(ctx context.Context, id uint) (error, *Product)
+	ListProducts(ctx context.Context) (error, []Product)
+	PurchasePass(ctx context.Context, userID string, productID uint, now time.Time) (error, *Pass)
+	ListPasses(ctx context.Context, userID string) (error, []Pass)
+	ReverseRide(ctx context.Context, rideID uint, now time.Time) (error, []Consumption)
+}
+
+type service struct {
+	createProduct
+	getProduct
+	listProducts
+	purchasePass
+	listPasses
+	reverseRide
+}
+
+func New(repository rel.Repository) Service {
+	return service{
+		createProduct: createProduct{repository: repository},
+		getProduct:    getProduct{repository: repository},
+		listProducts:  listProducts{repository: repository},
+		purchasePass:  purchasePass{repository: repository},
+		listPasses:    listPasses{repository: repository},
+		reverseRide:   reverseRide{repository: repository},
+	}
+}
@@ -0,0 +1,192 @@
+package payments
+
+import (
+	"context"
+	"strconv"
+	"sync"
+
+	"backend/money"
+)
+
+// Hold is an authorization placed on the Fake provider.
+type Hold struct {
+	ID       string
+	UserID   string
+	Amount   money.Money
+	Captured money.Money
+	Refunded money.Money
+	Status   Status
+}
+
+// Fake is an in-memory Provider for tests and local use. It authorizes and
+// charges every user but the declined ones, and captures up to the hold and
+// refunds up to the capture unless told to fail.
+type Fake struct {
+	mu         sync.Mutex
+	holds      map[string]*Hold
+	declined   map[string]bool
+	keys       map[string]bool
+	charges    map[string][]money.Money
+	captureErr error
+	refundErr  error
+}
+
+func NewFake() *Fake {
+	return &Fake{
+		holds:    map[string]*Hold{},
+		declined: map[string]bool{},
+		keys:     map[string]bool{},
+		charges:  map[string][]money.Money{},
+	}
+}
+
+// Decline makes every authorization of the user fail.
+func (f *Fake) Decline(userID string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.declined[userID] = true
+}
+
+// FailCaptures makes every capture fail with err, or succeed again when
+// err is nil.
+func (f *Fake) FailCaptures(err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.captureErr = err
+}
+
+// FailRefunds makes every refund fail with err, or succeed again when err
+// is nil.
+func (f *Fake) FailRefunds(err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.refundErr = err
+}
+
+// Charges returns what was charged to the user without a hold.
+func (f *Fake) Charges(userID string) []money.Money {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]money.Money(nil), f.charges[userID]...)
+}
+
+// Hold returns the authorization with the given ID.
+func (f *Fake) Hold(id string) (Hold, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	hold, ok := f.holds[id]
+	if !ok {
+		return Hold{}, false
+	}
+	return *hold, true
+}
+
+func (f *Fake) Authorize(ctx context.Context, userID string, amount money.Money) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.declined[userID] {
+		return "", ErrDeclined
+	}
+
+	id := "fake_" + strconv.Itoa(len(f.holds)+1)
+	f.holds[id] = &Hold{ID: id, UserID: userID, Amount: amount, Status: StatusAuthorized}
+	return id, nil
+}
+
+func (f *Fake) Capture(ctx context.Context, authorizationID string, amount money.Money, idempotencyKey string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.keys[idempotencyKey] {
+		return nil
+	}
+	hold, err := f.authorized(authorizationID)
+	if err != nil {
+		return err
+	}
+	if cmp, err := amount.Cmp(hold.Amount); err != nil {
+		return err
+	} else if cmp > 0 {
+		return ErrCaptureExceedsHold
+	}
+	if f.captureErr != nil {
+		return f.captureErr
+	}
+
+	hold.Captured = amount
+	hold.Refunded = money.Zero(amount.Currency)
+	hold.Status = StatusCaptured
+	f.keys[idempotencyKey] = true
+	return nil
+}
+
+func (f *Fake) Void(ctx context.Context, authorizationID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if hold, ok := f.holds[authorizationID]; ok && hold.Status == StatusVoided {
+		return nil
+	}
+	hold, err := f.authorized(authorizationID)
+	if err != nil {
+		return err
+	}
+
+	hold.Status = StatusVoided
+	return nil
+}
+
+func (f *Fake) Refund(ctx context.Context, authorizationID string, amount money.Money, idempotencyKey string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.keys[idempotencyKey] {
+		return nil
+	}
+	hold, ok := f.holds[authorizationID]
+	if !ok {
+		return ErrAuthorizationNotFound
+	}
+	if hold.Status != StatusCaptured {
+		return ErrNotCaptured
+	}
+	refunded, err := hold.Refunded.Add(amount)
+	if err != nil {
+		return err
+	}
+	if cmp, err := refunded.Cmp(hold.Captured); err != nil {
+		return err
+	} else if cmp > 0 {
+		return ErrRefundExceedsCapture
+	}
+	if f.refundErr != nil {
+		return f.refundErr
+	}
+
+	hold.Refunded = refunded
+	f.keys[idempotencyKey] = true
+	return nil
+}
+
+func (f *Fake) Charge(ctx context.Context, userID string, amount money.Money, idempotencyKey string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.keys[idempotencyKey] {
+		return nil
+	}
+	if f.declined[userID] {
+		return ErrDeclined
+	}
+
+	f.charges[userID] = append(f.charges[userID], amount)
+	f.keys[idempotencyKey] = true
+	return nil
+}
+
+func (f *Fake) authorized(id string) (*Hold, error) {
+	hold, ok := f.holds[id]
+	if !ok {
+		return nil, ErrAuthorizationNotFound
+	}
+	if hold.Status != StatusAuthorized {
+		return nil, ErrAuthorizationClosed
+	}
+	return hold, nil
+}
@@ -0,0 +1,132 @@
+package payments
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"backend/money"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFakeCapture(t *testing.T) {
+	var (
+		ctx  = context.TODO()
+		fake = NewFake()
+	)
+
+	id, err := fake.Authorize(ctx, "1", money.New(3000, "EUR"))
+	assert.Nil(t, err)
+
+	assert.Equal(t, ErrCaptureExceedsHold, fake.Capture(ctx, id, money.New(3001, "EUR"), "ride-1-capture"))
+	assert.Equal(t, money.ErrCurrencyMismatch, fake.Capture(ctx, id, money.New(1118, "USD"), "ride-1-capture"))
+	assert.Nil(t, fake.Capture(ctx, id, money.New(1118, "EUR"), "ride-1-capture"))
+	hold, _ := fake.Hold(id)
+	assert.Equal(t, Hold{ID: id, UserID: "1", Amount: money.New(3000, "EUR"), Captured: money.New(1118, "EUR"), Refunded: money.Zero("EUR"), Status: StatusCaptured}, hold)
+
+	// A repeat of the same capture charges nothing more.
+	assert.Nil(t, fake.Capture(ctx, id, money.New(1118, "EUR"), "ride-1-capture"))
+	hold, _ = fake.Hold(id)
+	assert.Equal(t, money.New(1118, "EUR"), hold.Captured)
+
+	assert.Equal(t, ErrAuthorizationClosed, fake.Capture(ctx, id, money.New(1118, "EUR"), "ride-2-capture"))
+	assert.Equal(t, ErrAuthorizationClosed, fake.Void(ctx, id))
+}
+
+func TestFakeDecline(t *testing.T) {
+	var (
+		ctx  = context.TODO()
+		fake = NewFake()
+	)
+	fake.Decline("1")
+
+	_, err := fake.Authorize(ctx, "1", money.New(18, "EUR"))
+	assert.Equal(t, ErrDeclined, err)
+
+	_, err = fake.Authorize(ctx, "2", money.New(18, "EUR"))
+	assert.Nil(t, err)
+}
+
+func TestFakeFailCaptures(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		fake       = NewFake()
+		errTimeout = errors.New("timeout")
+	)
+
+	id, _ := fake.Authorize(ctx, "1", money.New(3000, "EUR"))
+	fake.FailCaptures(errTimeout)
+	assert.Equal(t, errTimeout, fake.Capture(ctx, id, money.New(118, "EUR"), "ride-1-capture"))
+
+	hold, _ := fake.Hold(id)
+	assert.Equal(t, StatusAuthorized, hold.Status)
+
+	fake.FailCaptures(nil)
+	assert.Nil(t, fake.Capture(ctx, id, money.New(118, "EUR"), "ride-1-capture"))
+}
+
+func TestFakeVoid(t *testing.T) {
+	var (
+		ctx  = context.TODO()
+		fake = NewFake()
+	)
+
+	id, _ := fake.Authorize(ctx, "1", money.New(18, "EUR"))
+	assert.Nil(t, fake.Void(ctx, id))
+
+	hold, _ := fake.Hold(id)
+	assert.Equal(t, StatusVoided, hold.Status)
+	assert.Nil(t, fake.Void(ctx, id))
+	assert.Equal(t, ErrAuthorizationNotFound, fake.Void(ctx, "fake_2"))
+}
+
+func TestFakeRefund(t *testing.T) {
+	var (
+		ctx  = context.TODO()
+		fake = NewFake()
+	)
+
+	id, _ := fake.Authorize(ctx, "1", money.New(3000, "EUR"))
+	assert.Equal(t, ErrNotCaptured, fake.Refund(ctx, id, money.New(200, "EUR"), "adjustment-1"))
+
+	assert.Nil(t, fake.Capture(ctx, id, money.New(418, "EUR"), "ride-1-capture"))
+	assert.Nil(t, fake.Refund(ctx, id, money.New(200, "EUR"), "adjustment-1"))
+	assert.Nil(t, fake.Refund(ctx, id, money.New(200, "EUR"), "adjustment-1"))
+	assert.Equal(t, ErrRefundExceedsCapture, fake.Refund(ctx, id, money.New(219, "EUR"), "adjustment-2"))
+
+	hold, _ := fake.Hold(id)
+	assert.Equal(t, money.New(200, "EUR"), hold.Refunded)
+	assert.Equal(t, ErrAuthorizationNotFound, fake.Refund(ctx, "fake_2", money.New(200, "EUR"), "adjustment-2"))
+}
+
+func TestFakeFailRefunds(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		fake       = NewFake()
+		errTimeout = errors.New("timeout")
+	)
+
+	id, _ := fake.Authorize(ctx, "1", money.New(3000, "EUR"))
+	assert.Nil(t, fake.Capture(ctx, id, money.New(418, "EUR"), "ride-1-capture"))
+	fake.FailRefunds(errTimeout)
+	assert.Equal(t, errTimeout, fake.Refund(ctx, id, money.New(200, "EUR"), "adjustment-1"))
+
+	fake.FailRefunds(nil)
+	assert.Nil(t, fake.Refund(ctx, id, money.New(200, "EUR"), "adjustment-1"))
+}
+
+func TestFakeCharge(t *testing.T) {
+	var (
+		ctx  = context.TODO()
+		fake = NewFake()
+	)
+	fake.Decline("2")
+
+	assert.Nil(t, fake.Charge(ctx, "1", money.New(100, "EUR"), "adjustment-1"))
+	assert.Nil(t, fake.Charge(ctx, "1", money.New(100, "EUR"), "adjustment-1"))
+	assert.Equal(t, []money.Money{money.New(100, "EUR")}, fake.Charges("1"))
+
+	assert.Equal(t, ErrDeclined, fake.Charge(ctx, "2", money.New(100, "EUR"), "adjustment-2"))
+	assert.Empty(t, fake.Charges("2"))
+}
@@ -0,0 +1,82 @@
+package payments
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"backend/money"
+)
+
+// Status is where the payment of a ride stands.
+type Status string
+
+const (
+	// StatusAuthorized is a hold placed when the ride started.
+	StatusAuthorized Status = "authorized"
+	// StatusPending is a finished ride whose price is still to be
+	// captured, because capturing failed or didn't start yet.
+	StatusPending Status = "pending"
+	// StatusCapturing is a pending payment claimed by the request
+	// capturing it.
+	StatusCapturing Status = "capturing"
+	StatusCaptured  Status = "captured"
+	StatusVoided    Status = "voided"
+	// StatusDebited is a prepaid ride charged to the wallet of the user.
+	StatusDebited Status = "debited"
+)
+
+var (
+	ErrDeclined              = errors.New("The payment method of the user was declined")
+	ErrAuthorizationNotFound = errors.New("No authorization matches the given ID")
+	ErrAuthorizationClosed   = errors.New("The authorization was already captured or voided")
+	ErrInsufficientBalance   = errors.New("The wallet balance is below the minimum to start a ride")
+	ErrNotCaptured           = errors.New("Only captured authorizations can be refunded")
+	ErrCaptureExceedsHold    = errors.New("Can't capture more than the authorized amount")
+	ErrRefundExceedsCapture  = errors.New("Can't refund more than was captured")
+)
+
+// Provider moves money through a payment service provider. A ride places a
+// hold when it starts, and the hold is captured with the final price when
+// it finishes or voided when it is cancelled. Providers never capture more
+// than the hold, so a price above it is charged on its own. Adjustments of
+// the price are refunded from the capture or charged to the payment method
+// of the user.
+type Provider interface {
+	// Authorize places a hold of amount on the payment method of the user
+	// and returns its ID. It fails with ErrDeclined when the provider
+	// refuses it.
+	Authorize(ctx context.Context, userID string, amount money.Money) (string, error)
+	// Capture charges amount against the hold, failing with
+	// ErrCaptureExceedsHold when it is more than the hold. Captures sent
+	// again with the same idempotency key charge nothing more and succeed.
+	Capture(ctx context.Context, authorizationID string, amount money.Money, idempotencyKey string) error
+	// Void releases the hold without charging anything. Voiding a hold
+	// again succeeds.
+	Void(ctx context.Context, authorizationID string) error
+	// Refund gives amount back from what was captured against the hold. It
+	// fails with ErrNotCaptured when nothing was, and with
+	// ErrRefundExceedsCapture when amount is more than what is left of the
+	// capture. Refunds sent again with the same idempotency key give
+	// nothing more back and succeed.
+	Refund(ctx context.Context, authorizationID string, amount money.Money, idempotencyKey string) error
+	// Charge charges amount to the payment method of the user, without a
+	// hold. It fails with ErrDeclined when the provider refuses it. Charges
+	// sent again with the same idempotency key charge nothing more and
+	// succeed.
+	Charge(ctx context.Context, userID string, amount money.Money, idempotencyKey string) error
+}
+
+// Wallets hold the prepaid balances of users, which pay for the rides of
+// prepaid tariffs instead of a provider.
+type Wallets interface {
+	// Balance is what the wallet of the user holds in the given currency.
+	Balance(ctx context.Context, userID string, currency money.Currency) (money.Money, error)
+	// DebitRide charges amount for the ride to the wallet of the user, which
+	// may leave it negative. It runs in the transaction of its caller.
+	DebitRide(ctx context.Context, userID string, rideID uint, amount money.Money, at time.Time) error
+	// AdjustRide settles a change of amount to the price of a debited ride:
+	// the wallet gets back what the price goes down, and pays what it goes
+	// up. It runs in the transaction of its caller.
+	AdjustRide(ctx context.Context, userID string, rideID uint, amount money.Money, description string, at time.Time) error
+}
@@ -0,0 +1,169 @@
+package pricing
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+
+	"backend/money"
+)
+
+type Unit string
+
+const (
+	UnitSecond Unit = "second"
+	UnitMinute Unit = "minute"
+	// UnitBlock bills blocks of BlockMinutes minutes.
+	UnitBlock Unit = "block"
+)
+
+type Rounding string
+
+const (
+	// RoundingCeil bills every started unit as a whole unit.
+	RoundingCeil Rounding = "ceil"
+	// RoundingFloor only bills whole units.
+	RoundingFloor Rounding = "floor"
+	// RoundingHalfUp bills a started unit once half of it has elapsed.
+	RoundingHalfUp Rounding = "half_up"
+)
+
+func (r Rounding) Valid() bool {
+	switch r {
+	case RoundingCeil, RoundingFloor, RoundingHalfUp:
+		return true
+	}
+	return false
+}
+
+// Billing is how ride time turns into billed units. Prices stay per minute
+// whatever the unit. The riding time within GraceSeconds is not billed, and
+// the whole ride, unlock fee included, is charged at least MinimumCharge
+// and at most MaximumCharge, when not zero. Both are net of tax, as tax is
+// added on top of them.
+//
+// The zero Billing bills every started minute.
+type Billing struct {
+	Unit         Unit     `json:"unit"`
+	BlockMinutes int      `json:"block_minutes,omitempty"`
+	Rounding     Rounding `json:"rounding"`
+	GraceSeconds int      `json:"grace_seconds,omitempty"`
+	// MinimumCharge is the least charged for a ride, net of tax.
+	MinimumCharge int `json:"minimum_charge,omitempty"`
+	// MaximumCharge is the most charged for a ride, net of tax.
+	MaximumCharge int `json:"maximum_charge,omitempty"`
+}
+
+var (
+	ErrBillingUnitInvalid     = errors.New("Billing unit must be one of: second, minute, block")
+	ErrBillingBlockInvalid    = errors.New("Billing block minutes must be positive for block units, and only for them")
+	ErrBillingRoundingInvalid = errors.New("Billing rounding must be one of: ceil, floor, half_up")
+	ErrBillingGraceNegative   = errors.New("Billing grace period can't be negative")
+	ErrBillingChargeInvalid   = errors.New("Billing minimum and maximum charges can't be negative, and the maximum can't be below the minimum")
+)
+
+func (b Billing) Validate() error {
+	switch b.Unit {
+	case UnitSecond, UnitMinute, UnitBlock:
+	default:
+		return ErrBillingUnitInvalid
+	}
+	if (b.Unit == UnitBlock) != (b.BlockMinutes > 0) {
+		return ErrBillingBlockInvalid
+	}
+	if !b.Rounding.Valid() {
+		return ErrBillingRoundingInvalid
+	}
+	if b.GraceSeconds < 0 {
+		return ErrBillingGraceNegative
+	}
+	if b.MinimumCharge < 0 || b.MaximumCharge < 0 || (b.MaximumCharge > 0 && b.MaximumCharge < b.MinimumCharge) {
+		return ErrBillingChargeInvalid
+	}
+	return nil
+}
+
+func (b Billing) unit() time.Duration {
+	switch b.Unit {
+	case UnitSecond:
+		return time.Second
+	case UnitBlock:
+		return time.Duration(b.BlockMinutes) * time.Minute
+	}
+	return time.Minute
+}
+
+func (b Billing) grace() time.Duration {
+	return time.Duration(b.GraceSeconds) * time.Second
+}
+
+// units rounds a duration to whole billing units.
+func (b Billing) units(duration time.Duration) int {
+	var (
+		unit      = b.unit()
+		units     = int(duration / unit)
+		remainder = duration % unit
+	)
+	switch b.Rounding {
+	case RoundingFloor:
+	case RoundingHalfUp:
+		if remainder > 0 && remainder*2 >= unit {
+			units++
+		}
+	default:
+		if remainder > 0 {
+			units++
+		}
+	}
+	return units
+}
+
+// amount is the charge for units at a price per minute, rounded half up to
+// the minor unit.
+func (b Billing) amount(units int, minutePrice money.Money) (money.Money, error) {
+	seconds := int64(units) * int64(b.unit()/time.Second)
+	return minutePrice.Scale(seconds, 60)
+}
+
+// unitPrice is the price of a single unit. Seconds can't be priced in
+// whole minor units, so their lines show the price per minute.
+func (b Billing) unitPrice(minutePrice money.Money) (money.Money, error) {
+	if b.Unit == UnitSecond {
+		return minutePrice, nil
+	}
+	return b.amount(1, minutePrice)
+}
+
+// describe names the units of the time lines, e.g. "Riding time (minutes)".
+func (b Billing) describe(what string) string {
+	switch b.Unit {
+	case UnitSecond:
+		return what + " (seconds, price per minute)"
+	case UnitBlock:
+		return what + " (blocks of " + strconv.Itoa(b.BlockMinutes) + " minutes)"
+	}
+	return what + " (minutes)"
+}
+
+// Value stores the billing policy as a JSON document.
+func (b Billing) Value() (driver.Value, error) {
+	value, err := json.Marshal(b)
+	return string(value), err
+}
+
+// Scan reads a billing policy stored by Value.
+func (b *Billing) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*b = Billing{}
+		return nil
+	case []byte:
+		return json.Unmarshal(v, b)
+	case string:
+		return json.Unmarshal([]byte(v), b)
+	}
+
+	return errors.New("pricing: cannot scan billing")
+}
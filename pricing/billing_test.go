@@ -0,0 +1,215 @@
+package pricing
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"backend/money"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBillingValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		billing Billing
+		err     error
+	}{
+		{"minute", Billing{Unit: UnitMinute, Rounding: RoundingCeil}, nil},
+		{"second", Billing{Unit: UnitSecond, Rounding: RoundingFloor}, nil},
+		{"block", Billing{Unit: UnitBlock, BlockMinutes: 15, Rounding: RoundingHalfUp}, nil},
+		{"unit blank", Billing{Rounding: RoundingCeil}, ErrBillingUnitInvalid},
+		{"unit unknown", Billing{Unit: "hour", Rounding: RoundingCeil}, ErrBillingUnitInvalid},
+		{"block without minutes", Billing{Unit: UnitBlock, Rounding: RoundingCeil}, ErrBillingBlockInvalid},
+		{"minutes without block", Billing{Unit: UnitMinute, BlockMinutes: 15, Rounding: RoundingCeil}, ErrBillingBlockInvalid},
+		{"rounding blank", Billing{Unit: UnitMinute}, ErrBillingRoundingInvalid},
+		{"rounding unknown", Billing{Unit: UnitMinute, Rounding: "minute_ceil"}, ErrBillingRoundingInvalid},
+		{"grace", Billing{Unit: UnitMinute, Rounding: RoundingCeil, GraceSeconds: 30}, nil},
+		{"grace negative", Billing{Unit: UnitMinute, Rounding: RoundingCeil, GraceSeconds: -1}, ErrBillingGraceNegative},
+		{"charges", Billing{Unit: UnitMinute, Rounding: RoundingCeil, MinimumCharge: 100, MaximumCharge: 100}, nil},
+		{"minimum only", Billing{Unit: UnitMinute, Rounding: RoundingCeil, MinimumCharge: 100}, nil},
+		{"minimum negative", Billing{Unit: UnitMinute, Rounding: RoundingCeil, MinimumCharge: -1}, ErrBillingChargeInvalid},
+		{"maximum negative", Billing{Unit: UnitMinute, Rounding: RoundingCeil, MaximumCharge: -1}, ErrBillingChargeInvalid},
+		{"maximum below minimum", Billing{Unit: UnitMinute, Rounding: RoundingCeil, MinimumCharge: 100, MaximumCharge: 99}, ErrBillingChargeInvalid},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.err, test.billing.Validate())
+		})
+	}
+}
+
+func TestBillingUnits(t *testing.T) {
+	var (
+		minuteCeil   = Billing{Unit: UnitMinute, Rounding: RoundingCeil}
+		minuteFloor  = Billing{Unit: UnitMinute, Rounding: RoundingFloor}
+		minuteHalfUp = Billing{Unit: UnitMinute, Rounding: RoundingHalfUp}
+		secondCeil   = Billing{Unit: UnitSecond, Rounding: RoundingCeil}
+		blockCeil    = Billing{Unit: UnitBlock, BlockMinutes: 15, Rounding: RoundingCeil}
+		blockHalfUp  = Billing{Unit: UnitBlock, BlockMinutes: 15, Rounding: RoundingHalfUp}
+	)
+
+	tests := []struct {
+		name     string
+		billing  Billing
+		duration time.Duration
+		units    int
+	}{
+		{"zero billing is minute ceil", Billing{}, 61 * time.Second, 2},
+		{"minute ceil 0s", minuteCeil, 0, 0},
+		{"minute ceil 1s", minuteCeil, time.Second, 1},
+		{"minute ceil 60s", minuteCeil, 60 * time.Second, 1},
+		{"minute ceil 61s", minuteCeil, 61 * time.Second, 2},
+		{"minute floor 59s", minuteFloor, 59 * time.Second, 0},
+		{"minute floor 60s", minuteFloor, 60 * time.Second, 1},
+		{"minute floor 119s", minuteFloor, 119 * time.Second, 1},
+		{"minute half up 29s", minuteHalfUp, 29 * time.Second, 0},
+		{"minute half up 30s", minuteHalfUp, 30 * time.Second, 1},
+		{"minute half up 89s", minuteHalfUp, 89 * time.Second, 1},
+		{"minute half up 90s", minuteHalfUp, 90 * time.Second, 2},
+		{"second ceil 1.5s", secondCeil, 1500 * time.Millisecond, 2},
+		{"second ceil 61s", secondCeil, 61 * time.Second, 61},
+		{"block ceil 1s", blockCeil, time.Second, 1},
+		{"block ceil 15m", blockCeil, 15 * time.Minute, 1},
+		{"block ceil 15m1s", blockCeil, 15*time.Minute + time.Second, 2},
+		{"block half up 22m29s", blockHalfUp, 22*time.Minute + 29*time.Second, 1},
+		{"block half up 22m30s", blockHalfUp, 22*time.Minute + 30*time.Second, 2},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.units, test.billing.units(test.duration))
+		})
+	}
+}
+
+func TestBillingAmount(t *testing.T) {
+	var (
+		minute = Billing{Unit: UnitMinute, Rounding: RoundingCeil}
+		second = Billing{Unit: UnitSecond, Rounding: RoundingCeil}
+		block  = Billing{Unit: UnitBlock, BlockMinutes: 15, Rounding: RoundingCeil}
+	)
+
+	tests := []struct {
+		name  string
+		price func() (money.Money, error)
+		want  int64
+	}{
+		{"minutes", func() (money.Money, error) { return minute.amount(3, eur(100)) }, 300},
+		{"minute price", func() (money.Money, error) { return minute.unitPrice(eur(100)) }, 100},
+		{"blocks", func() (money.Money, error) { return block.amount(2, eur(100)) }, 3000},
+		{"block price", func() (money.Money, error) { return block.unitPrice(eur(100)) }, 1500},
+		{"seconds", func() (money.Money, error) { return second.amount(61, eur(100)) }, 102},
+		{"half a cent", func() (money.Money, error) { return second.amount(1, eur(30)) }, 1},
+		{"under half a cent", func() (money.Money, error) { return second.amount(1, eur(29)) }, 0},
+		{"second price", func() (money.Money, error) { return second.unitPrice(eur(100)) }, 100},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			price, err := test.price()
+			assert.Nil(t, err)
+			assert.Equal(t, eur(test.want), price)
+		})
+	}
+
+	_, err := minute.amount(2, eur(math.MaxInt64/100))
+	assert.Equal(t, money.ErrOverflow, err)
+}
+
+func TestBillingValueScan(t *testing.T) {
+	billing := Billing{Unit: UnitBlock, BlockMinutes: 15, Rounding: RoundingHalfUp, GraceSeconds: 30, MinimumCharge: 100, MaximumCharge: 2000}
+
+	value, err := billing.Value()
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"unit":"block","block_minutes":15,"rounding":"half_up","grace_seconds":30,"minimum_charge":100,"maximum_charge":2000}`, value.(string))
+
+	var scanned Billing
+	assert.Nil(t, scanned.Scan(value))
+	assert.Equal(t, billing, scanned)
+
+	assert.Nil(t, scanned.Scan(nil))
+	assert.Equal(t, Billing{}, scanned)
+
+	assert.NotNil(t, scanned.Scan(42))
+}
+
+func TestFlatQuoteBilling(t *testing.T) {
+	flat := Flat{}
+
+	tests := []struct {
+		name    string
+		billing Billing
+		usage   Usage
+		items   []LineItem
+	}{
+		{
+			name:    "per second",
+			billing: Billing{Unit: UnitSecond, Rounding: RoundingCeil},
+			usage:   ride(61*time.Second, 0),
+			items: []LineItem{
+				{Kind: KindTime, Description: "Riding time (seconds, price per minute)", Quantity: 61, UnitPrice: eur(100), Amount: eur(102)},
+			},
+		},
+		{
+			name:    "blocks",
+			billing: Billing{Unit: UnitBlock, BlockMinutes: 15, Rounding: RoundingCeil},
+			usage:   ride(16*time.Minute, 0),
+			items: []LineItem{
+				{Kind: KindTime, Description: "Riding time (blocks of 15 minutes)", Quantity: 2, UnitPrice: eur(1500), Amount: eur(3000)},
+			},
+		},
+		{
+			name:    "grace period",
+			billing: Billing{Unit: UnitMinute, Rounding: RoundingCeil, GraceSeconds: 60},
+			usage:   ride(3*time.Minute+time.Second, 0),
+			items: []LineItem{
+				{Kind: KindTime, Description: "Riding time (minutes)", Quantity: 3, UnitPrice: eur(100), Amount: eur(300)},
+			},
+		},
+		{
+			name:    "within grace period",
+			billing: Billing{Unit: UnitMinute, Rounding: RoundingCeil, GraceSeconds: 60},
+			usage:   ride(time.Minute, 0),
+			items:   []LineItem{},
+		},
+		{
+			name:    "grace period leaves paused time",
+			billing: Billing{Unit: UnitMinute, Rounding: RoundingCeil, GraceSeconds: 60},
+			usage:   ride(time.Minute, 2*time.Minute),
+			items: []LineItem{
+				{Kind: KindPausedTime, Description: "Paused time (minutes)", Quantity: 2, UnitPrice: eur(25), Amount: eur(50)},
+			},
+		},
+		{
+			name:    "minimum charge",
+			billing: Billing{Unit: UnitMinute, Rounding: RoundingCeil, MinimumCharge: 250},
+			usage:   ride(time.Minute, 0),
+			items: []LineItem{
+				{Kind: KindTime, Description: "Riding time (minutes)", Quantity: 1, UnitPrice: eur(100), Amount: eur(100)},
+				{Kind: KindMinimumCharge, Description: "Minimum ride charge", Quantity: 1, UnitPrice: eur(132), Amount: eur(132)},
+			},
+		},
+		{
+			name:    "maximum charge",
+			billing: Billing{Unit: UnitMinute, Rounding: RoundingCeil, MaximumCharge: 1000},
+			usage:   ride(20*time.Minute, 0),
+			items: []LineItem{
+				{Kind: KindTime, Description: "Riding time (minutes)", Quantity: 20, UnitPrice: eur(100), Amount: eur(2000)},
+				{Kind: KindMaximumCharge, Description: "Maximum ride charge", Quantity: 1, UnitPrice: eur(-1018), Amount: eur(-1018)},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			billed := tariff
+			billed.Billing = test.billing
+
+			quote := flat.Quote(billed, test.usage)
+			assert.Equal(t, test.items, append([]LineItem{}, quote.Items[1:]...))
+		})
+	}
+}
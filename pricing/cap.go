@@ -0,0 +1,90 @@
+package pricing
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"backend/money"
+)
+
+// Caps limit what a user is charged for all the rides started within a
+// calendar day in the tariff time zone, or within the last 7 days, when not
+// zero. The charge of a single ride is limited by Billing.MaximumCharge.
+// Like every tariff price, caps are net of tax: tax is added on top of the
+// capped amount, so the rider pays up to the cap plus its tax.
+type Caps struct {
+	// Daily is the most charged for the rides of a day, net of tax.
+	Daily int `json:"daily,omitempty"`
+	// Weekly is the most charged for the rides of 7 days, net of tax.
+	Weekly int `json:"weekly,omitempty"`
+}
+
+var ErrCapNegative = errors.New("Caps can't be negative")
+
+func (c Caps) Validate() error {
+	if c.Daily < 0 || c.Weekly < 0 {
+		return ErrCapNegative
+	}
+	return nil
+}
+
+// Window is a span of time whose rides are charged at most Limit together.
+type Window struct {
+	Description string
+	Since       time.Time
+	Limit       money.Money
+}
+
+// Windows returns the capped spans a ride started at the given instant
+// belongs to. Each of them ends at that instant.
+func (t Tariff) Windows(at time.Time) []Window {
+	var windows []Window
+	if t.Caps.Daily > 0 {
+		local := at.In(t.Location())
+		windows = append(windows, Window{
+			Description: "Daily cap",
+			Since:       time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, local.Location()),
+			Limit:       t.price(t.Caps.Daily),
+		})
+	}
+	if t.Caps.Weekly > 0 {
+		windows = append(windows, Window{
+			Description: "Weekly cap",
+			Since:       at.Add(-7 * 24 * time.Hour),
+			Limit:       t.price(t.Caps.Weekly),
+		})
+	}
+	return windows
+}
+
+// Cap brings the total of the quote down to limit, or to zero when limit is
+// negative, recording the difference as a cap item.
+func (q *Quote) Cap(limit money.Money, description string) {
+	if limit.Amount < 0 {
+		limit = money.Zero(limit.Currency)
+	}
+	q.lower(limit, KindCap, description)
+}
+
+// Value stores the caps as a JSON document.
+func (c Caps) Value() (driver.Value, error) {
+	value, err := json.Marshal(c)
+	return string(value), err
+}
+
+// Scan reads caps stored by Value.
+func (c *Caps) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*c = Caps{}
+		return nil
+	case []byte:
+		return json.Unmarshal(v, c)
+	case string:
+		return json.Unmarshal([]byte(v), c)
+	}
+
+	return errors.New("pricing: cannot scan caps")
+}
@@ -0,0 +1,107 @@
+package pricing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCapsValidation(t *testing.T) {
+	assert.Nil(t, Caps{}.Validate())
+	assert.Nil(t, Caps{Daily: 1500, Weekly: 6000}.Validate())
+	assert.Equal(t, ErrCapNegative, Caps{Daily: -1}.Validate())
+	assert.Equal(t, ErrCapNegative, Caps{Weekly: -1}.Validate())
+}
+
+func TestTariffWindows(t *testing.T) {
+	var (
+		capped = Tariff{Currency: "EUR", TimeZone: "Europe/Madrid", Caps: Caps{Daily: 1500, Weekly: 6000}}
+		at     = time.Date(2026, 10, 17, 23, 30, 0, 0, time.UTC) // 01:30 on the 18th in Madrid
+	)
+
+	assert.Equal(t, []Window{
+		{Description: "Daily cap", Since: time.Date(2026, 10, 17, 22, 0, 0, 0, time.UTC), Limit: eur(1500)},
+		{Description: "Weekly cap", Since: time.Date(2026, 10, 10, 23, 30, 0, 0, time.UTC), Limit: eur(6000)},
+	}, normalize(capped.Windows(at)))
+
+	assert.Empty(t, tariff.Windows(at))
+}
+
+// normalize makes the windows comparable regardless of their location.
+func normalize(windows []Window) []Window {
+	for i := range windows {
+		windows[i].Since = windows[i].Since.UTC()
+	}
+	return windows
+}
+
+func TestQuoteCap(t *testing.T) {
+	tests := []struct {
+		name  string
+		limit int64
+		total int64
+		items int
+	}{
+		{"below", 1000, 718, 3},
+		{"equal", 718, 718, 3},
+		{"above", 500, 500, 4},
+		{"exhausted", 0, 0, 4},
+		{"overspent", -200, 0, 4},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			quote := Flat{}.Quote(tariff, ride(6*time.Minute, 4*time.Minute))
+			quote.Cap(eur(test.limit), "Daily cap")
+			assert.Nil(t, quote.Err())
+			assert.Equal(t, eur(test.total), quote.Total)
+			assert.Len(t, quote.Items, test.items)
+		})
+	}
+}
+
+func TestQuoteCapItem(t *testing.T) {
+	quote := Flat{}.Quote(tariff, ride(6*time.Minute, 4*time.Minute))
+	quote.Cap(eur(500), "Weekly cap")
+
+	assert.Equal(t, LineItem{Kind: KindCap, Description: "Weekly cap", Quantity: 1, UnitPrice: eur(-218), Amount: eur(-218)}, quote.Items[3])
+}
+
+func TestCapsValueScan(t *testing.T) {
+	caps := Caps{Daily: 1500, Weekly: 6000}
+
+	value, err := caps.Value()
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"daily":1500,"weekly":6000}`, value.(string))
+
+	var scanned Caps
+	assert.Nil(t, scanned.Scan(value))
+	assert.Equal(t, caps, scanned)
+
+	assert.Nil(t, scanned.Scan(nil))
+	assert.Equal(t, Caps{}, scanned)
+
+	assert.NotNil(t, scanned.Scan(42))
+}
+
+func TestLineItemsValueScan(t *testing.T) {
+	items := LineItems{{Kind: KindUnlock, Description: "Unlock fee", Quantity: 1, UnitPrice: eur(18), Amount: eur(18)}}
+
+	value, err := items.Value()
+	assert.Nil(t, err)
+	assert.JSONEq(t, `[{"kind":"unlock","description":"Unlock fee","quantity":1,"unit_price":{"amount":18,"currency":"EUR"},"amount":{"amount":18,"currency":"EUR"}}]`, value.(string))
+
+	var scanned LineItems
+	assert.Nil(t, scanned.Scan([]byte(value.(string))))
+	assert.Equal(t, items, scanned)
+
+	value, err = LineItems(nil).Value()
+	assert.Nil(t, err)
+	assert.Equal(t, "[]", value)
+
+	assert.Nil(t, scanned.Scan(nil))
+	assert.Nil(t, scanned)
+
+	assert.NotNil(t, scanned.Scan(42))
+}
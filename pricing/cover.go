@@ -0,0 +1,160 @@
+package pricing
+
+import (
+	"time"
+
+	"backend/money"
+)
+
+// Allowance is what a prepaid pass has left to cover rides with: the unlock
+// fees when Unlock is set, and Minutes minutes of riding time.
+type Allowance struct {
+	PassID  uint
+	Name    string
+	Unlock  bool
+	Minutes int
+}
+
+// Consumption is what a ride used up of a pass, and what it was worth.
+type Consumption struct {
+	PassID  uint        `json:"pass_id"`
+	Unlock  bool        `json:"unlock"`
+	Minutes int         `json:"minutes"`
+	Amount  money.Money `json:"amount"`
+}
+
+// coverage is what was taken off a quote without being charged: the unlock
+// fee, and the first minutes of riding time.
+type coverage struct {
+	unlock  bool
+	minutes int
+}
+
+// Cover takes what the allowances cover off the quote of the usage under
+// the tariff, in order, and returns what the ride consumed of each of them.
+// Passes only spend minutes on the started minutes of riding time that are
+// billed, after the grace period, and not covered yet, and nothing on what
+// is worth nothing to the ride, as when its minimum charge applies anyway.
+func (q *Quote) Cover(pricer Pricer, tariff Tariff, usage Usage, allowances []Allowance) []Consumption {
+	riding := ridingMinutes(usage, tariff.Billing.grace())
+
+	var consumptions []Consumption
+	for _, allowance := range allowances {
+		unlock := allowance.Unlock && !q.covered.unlock
+		minutes := riding - q.covered.minutes
+		if minutes > allowance.Minutes {
+			minutes = allowance.Minutes
+		}
+		if minutes < 0 {
+			minutes = 0
+		}
+		if !unlock && minutes == 0 {
+			continue
+		}
+
+		covered := q.covered
+		amount := q.cover(pricer, tariff, usage, unlock, minutes, KindPass, "Pass "+allowance.Name)
+		if amount.IsZero() {
+			q.covered = covered
+			continue
+		}
+		consumptions = append(consumptions, Consumption{
+			PassID:  allowance.PassID,
+			Unlock:  unlock,
+			Minutes: minutes,
+			Amount:  amount,
+		})
+	}
+
+	return consumptions
+}
+
+// cover takes the unlock fee, when unlock is set, and the next minutes of
+// riding time off the quote, pricing them by quoting the ride again with
+// them free. It returns the amount taken off.
+func (q *Quote) cover(pricer Pricer, tariff Tariff, usage Usage, unlock bool, minutes int, kind Kind, description string) money.Money {
+	next := coverage{unlock: q.covered.unlock || unlock, minutes: q.covered.minutes + minutes}
+	before, err := worth(pricer, tariff, usage, q.covered)
+	if err != nil {
+		q.fail(err)
+		return money.Zero(q.Currency())
+	}
+	after, err := worth(pricer, tariff, usage, next)
+	if err != nil {
+		q.fail(err)
+		return money.Zero(q.Currency())
+	}
+	amount, err := after.Sub(before)
+	if err != nil {
+		q.fail(err)
+		return money.Zero(q.Currency())
+	}
+	q.covered = next
+
+	return q.takeOff(amount, kind, description)
+}
+
+// takeOff adds an item taking the amount off the quote, without bringing
+// its total below zero, and returns the amount taken off.
+func (q *Quote) takeOff(amount money.Money, kind Kind, description string) money.Money {
+	cmp, err := amount.Cmp(q.Total)
+	if err != nil {
+		q.fail(err)
+		return money.Zero(q.Currency())
+	}
+	if cmp > 0 {
+		amount = q.Total
+	}
+	if amount.Amount <= 0 || q.err != nil {
+		return money.Zero(q.Currency())
+	}
+
+	q.Add(LineItem{
+		Kind:        kind,
+		Description: description,
+		Quantity:    1,
+		UnitPrice:   amount.Neg(),
+		Amount:      amount.Neg(),
+	})
+	return amount
+}
+
+// worth is what the coverage is worth in the quote of the usage.
+func worth(pricer Pricer, tariff Tariff, usage Usage, covered coverage) (money.Money, error) {
+	if covered == (coverage{}) {
+		return tariff.price(0), nil
+	}
+
+	free := tariff
+	if covered.unlock {
+		free.UnlockPrice = 0
+	}
+	free.Billing.GraceSeconds += covered.minutes * 60
+
+	full, discounted := pricer.Quote(tariff, usage), pricer.Quote(free, usage)
+	if err := full.Err(); err != nil {
+		return money.Money{}, err
+	}
+	if err := discounted.Err(); err != nil {
+		return money.Money{}, err
+	}
+	return full.Total.Sub(discounted.Total)
+}
+
+// ridingMinutes is the started minutes of riding time after the grace
+// period.
+func ridingMinutes(usage Usage, grace time.Duration) int {
+	riding := -grace
+	for _, interval := range usage.Active {
+		riding += interval.Duration()
+	}
+	if riding <= 0 {
+		return 0
+	}
+
+	minutes := int(riding / time.Minute)
+	if riding%time.Minute != 0 {
+		minutes++
+	}
+	return minutes
+}
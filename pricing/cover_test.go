@@ -0,0 +1,124 @@
+package pricing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuoteCover(t *testing.T) {
+	var (
+		unlocks = Allowance{PassID: 1, Name: "Unlimited unlocks", Unlock: true}
+		small   = Allowance{PassID: 2, Name: "2 minutes", Minutes: 2}
+		bundle  = Allowance{PassID: 3, Name: "100 minutes", Minutes: 100}
+	)
+
+	tests := []struct {
+		name         string
+		allowances   []Allowance
+		total        int64
+		consumptions []Consumption
+	}{
+		{"no passes", nil, 718, nil},
+		{"unlocks", []Allowance{unlocks}, 700, []Consumption{{PassID: 1, Unlock: true, Amount: eur(18)}}},
+		{"bundle", []Allowance{bundle}, 118, []Consumption{{PassID: 3, Minutes: 6, Amount: eur(600)}}},
+		{"bundle too small", []Allowance{small}, 518, []Consumption{{PassID: 2, Minutes: 2, Amount: eur(200)}}},
+		{"several passes", []Allowance{unlocks, small, bundle}, 100, []Consumption{
+			{PassID: 1, Unlock: true, Amount: eur(18)},
+			{PassID: 2, Minutes: 2, Amount: eur(200)},
+			{PassID: 3, Minutes: 4, Amount: eur(400)},
+		}},
+		{"nothing left to cover", []Allowance{bundle, small}, 118, []Consumption{{PassID: 3, Minutes: 6, Amount: eur(600)}}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				usage = ride(6*time.Minute, 4*time.Minute)
+				quote = Flat{}.Quote(tariff, usage)
+			)
+
+			assert.Equal(t, test.consumptions, quote.Cover(Flat{}, tariff, usage, test.allowances))
+			assert.Equal(t, eur(test.total), quote.Total)
+		})
+	}
+}
+
+func TestQuoteCoverItem(t *testing.T) {
+	var (
+		usage = ride(6*time.Minute, 4*time.Minute)
+		quote = Flat{}.Quote(tariff, usage)
+	)
+
+	quote.Cover(Flat{}, tariff, usage, []Allowance{{PassID: 3, Name: "100 minutes", Minutes: 100}})
+	assert.Equal(t, LineItem{Kind: KindPass, Description: "Pass 100 minutes", Quantity: 1, UnitPrice: eur(-600), Amount: eur(-600)}, quote.Items[3])
+}
+
+func TestQuoteCoverStartedMinutes(t *testing.T) {
+	var (
+		usage = ride(90*time.Second, 0)
+		quote = Flat{}.Quote(tariff, usage)
+	)
+
+	consumptions := quote.Cover(Flat{}, tariff, usage, []Allowance{{PassID: 3, Minutes: 100}})
+	assert.Equal(t, 2, consumptions[0].Minutes)
+	assert.Equal(t, eur(18), quote.Total)
+}
+
+func TestQuoteCoverAfterGrace(t *testing.T) {
+	var (
+		graced = tariff
+		usage  = ride(3*time.Minute, 0)
+	)
+	graced.Billing.GraceSeconds = 120
+
+	// Only the minute billed after the grace period comes off the pass.
+	quote := Flat{}.Quote(graced, usage)
+	assert.Equal(t, []Consumption{{PassID: 3, Minutes: 1, Amount: eur(100)}}, quote.Cover(Flat{}, graced, usage, []Allowance{{PassID: 3, Minutes: 100}}))
+	assert.Equal(t, eur(18), quote.Total)
+
+	// A ride within the grace period takes nothing off it.
+	usage = ride(90*time.Second, 0)
+	quote = Flat{}.Quote(graced, usage)
+	assert.Empty(t, quote.Cover(Flat{}, graced, usage, []Allowance{{PassID: 3, Minutes: 100}}))
+}
+
+func TestQuoteCoverWorthless(t *testing.T) {
+	var (
+		minimum = tariff
+		usage   = ride(3*time.Minute, 0)
+	)
+	minimum.Billing.MinimumCharge = 1000
+
+	// The minimum charge applies whatever the pass covers, so it is
+	// spared.
+	quote := Flat{}.Quote(minimum, usage)
+	assert.Empty(t, quote.Cover(Flat{}, minimum, usage, []Allowance{{PassID: 3, Minutes: 100}}))
+	assert.Equal(t, eur(1000), quote.Total)
+}
+
+func TestDiscountAfterPasses(t *testing.T) {
+	tests := []struct {
+		name     string
+		discount Discount
+		total    int64
+	}{
+		{"free unlock already covered", Discount{Kind: DiscountFreeUnlock}, 400},
+		{"free minutes after the pass ones", Discount{Kind: DiscountFreeMinutes, Minutes: 2}, 200},
+		{"percent off what is left", Discount{Kind: DiscountPercentOff, Percent: 50}, 200},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				usage = ride(6*time.Minute, 4*time.Minute)
+				quote = Flat{}.Quote(tariff, usage)
+			)
+
+			quote.Cover(Flat{}, tariff, usage, []Allowance{{PassID: 1, Unlock: true, Minutes: 3}})
+			test.discount.Apply(Flat{}, tariff, usage, &quote)
+			assert.Equal(t, eur(test.total), quote.Total)
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package pricing
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+
+	"backend/money"
+)
+
+type DiscountKind string
+
+const (
+	DiscountFreeUnlock  DiscountKind = "free_unlock"
+	DiscountPercentOff  DiscountKind = "percent_off"
+	DiscountFreeMinutes DiscountKind = "free_minutes"
+	DiscountAmountOff   DiscountKind = "amount_off"
+)
+
+// Discount is what a promo code takes off the price of a ride: the unlock
+// fee, Percent percent of the total, the first Minutes minutes of riding
+// time or Amount in the minor unit of Currency. A discount never brings the
+// total below zero.
+type Discount struct {
+	Code     string         `json:"code"`
+	Kind     DiscountKind   `json:"kind"`
+	Percent  int            `json:"percent,omitempty"`
+	Minutes  int            `json:"minutes,omitempty"`
+	Amount   int            `json:"amount,omitempty"`
+	Currency money.Currency `json:"currency,omitempty"`
+}
+
+var (
+	ErrDiscountKindInvalid   = errors.New("Discount kind must be one of: free_unlock, percent_off, free_minutes, amount_off")
+	ErrDiscountInvalid       = errors.New("Discounts take off a percent between 1 and 100, a positive number of minutes or a positive amount in a valid currency")
+	ErrDiscountNotApplicable = errors.New("The promo code doesn't apply to rides billed in this currency")
+)
+
+func (d Discount) Validate() error {
+	switch d.Kind {
+	case DiscountFreeUnlock:
+		return nil
+	case DiscountPercentOff:
+		if d.Percent < 1 || d.Percent > 100 {
+			return ErrDiscountInvalid
+		}
+	case DiscountFreeMinutes:
+		if d.Minutes < 1 {
+			return ErrDiscountInvalid
+		}
+	case DiscountAmountOff:
+		if d.Amount < 1 || !d.Currency.Valid() {
+			return ErrDiscountInvalid
+		}
+	default:
+		return ErrDiscountKindInvalid
+	}
+	return nil
+}
+
+// Applies reports whether the discount can be applied to rides billed with
+// the tariff.
+func (d Discount) Applies(tariff Tariff) bool {
+	return d.Kind != DiscountAmountOff || d.Currency == tariff.Currency
+}
+
+// Apply adds the discount to the quote of the given usage under the tariff.
+// Free unlocks and minutes only take off what passes didn't cover already.
+func (d Discount) Apply(pricer Pricer, tariff Tariff, usage Usage, quote *Quote) {
+	description := "Promo code " + d.Code
+	switch d.Kind {
+	case DiscountFreeUnlock:
+		quote.cover(pricer, tariff, usage, true, 0, KindDiscount, description)
+	case DiscountPercentOff:
+		off, err := quote.Total.Scale(int64(d.Percent), 100)
+		if err != nil {
+			quote.fail(err)
+			return
+		}
+		quote.takeOff(off, KindDiscount, description)
+	case DiscountFreeMinutes:
+		quote.cover(pricer, tariff, usage, false, d.Minutes, KindDiscount, description)
+	case DiscountAmountOff:
+		if d.Applies(tariff) {
+			quote.takeOff(money.New(int64(d.Amount), d.Currency), KindDiscount, description)
+		}
+	}
+}
+
+// Value stores the discount as a JSON document.
+func (d Discount) Value() (driver.Value, error) {
+	value, err := json.Marshal(d)
+	return string(value), err
+}
+
+// Scan reads a discount stored by Value.
+func (d *Discount) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*d = Discount{}
+		return nil
+	case []byte:
+		return json.Unmarshal(v, d)
+	case string:
+		return json.Unmarshal([]byte(v), d)
+	}
+
+	return errors.New("pricing: cannot scan discount")
+}
@@ -0,0 +1,91 @@
+package pricing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiscountValidation(t *testing.T) {
+	tests := []struct {
+		name     string
+		discount Discount
+		err      error
+	}{
+		{"free unlock", Discount{Kind: DiscountFreeUnlock}, nil},
+		{"percent off", Discount{Kind: DiscountPercentOff, Percent: 20}, nil},
+		{"percent off everything", Discount{Kind: DiscountPercentOff, Percent: 100}, nil},
+		{"percent off nothing", Discount{Kind: DiscountPercentOff}, ErrDiscountInvalid},
+		{"percent off too much", Discount{Kind: DiscountPercentOff, Percent: 101}, ErrDiscountInvalid},
+		{"free minutes", Discount{Kind: DiscountFreeMinutes, Minutes: 10}, nil},
+		{"free minutes none", Discount{Kind: DiscountFreeMinutes}, ErrDiscountInvalid},
+		{"amount off", Discount{Kind: DiscountAmountOff, Amount: 500, Currency: "EUR"}, nil},
+		{"amount off nothing", Discount{Kind: DiscountAmountOff, Currency: "EUR"}, ErrDiscountInvalid},
+		{"amount off without currency", Discount{Kind: DiscountAmountOff, Amount: 500}, ErrDiscountInvalid},
+		{"kind blank", Discount{}, ErrDiscountKindInvalid},
+		{"kind unknown", Discount{Kind: "free_ride"}, ErrDiscountKindInvalid},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.err, test.discount.Validate())
+		})
+	}
+}
+
+func TestDiscountApply(t *testing.T) {
+	tests := []struct {
+		name     string
+		discount Discount
+		total    int64
+	}{
+		{"free unlock", Discount{Kind: DiscountFreeUnlock}, 700},
+		{"percent off", Discount{Kind: DiscountPercentOff, Percent: 10}, 646},
+		{"percent off everything", Discount{Kind: DiscountPercentOff, Percent: 100}, 0},
+		{"free minutes", Discount{Kind: DiscountFreeMinutes, Minutes: 5}, 218},
+		{"free minutes longer than the ride", Discount{Kind: DiscountFreeMinutes, Minutes: 30}, 118},
+		{"amount off", Discount{Kind: DiscountAmountOff, Amount: 200, Currency: "EUR"}, 518},
+		{"amount off more than the total", Discount{Kind: DiscountAmountOff, Amount: 1000, Currency: "EUR"}, 0},
+		{"amount off in another currency", Discount{Kind: DiscountAmountOff, Amount: 200, Currency: "USD"}, 718},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				usage = ride(6*time.Minute, 4*time.Minute)
+				quote = Flat{}.Quote(tariff, usage)
+			)
+
+			test.discount.Apply(Flat{}, tariff, usage, &quote)
+			assert.Equal(t, eur(test.total), quote.Total)
+		})
+	}
+}
+
+func TestDiscountApplyItem(t *testing.T) {
+	var (
+		usage = ride(6*time.Minute, 4*time.Minute)
+		quote = Flat{}.Quote(tariff, usage)
+	)
+
+	Discount{Code: "SPRING", Kind: DiscountFreeUnlock}.Apply(Flat{}, tariff, usage, &quote)
+	assert.Equal(t, LineItem{Kind: KindDiscount, Description: "Promo code SPRING", Quantity: 1, UnitPrice: eur(-18), Amount: eur(-18)}, quote.Items[3])
+}
+
+func TestDiscountValueScan(t *testing.T) {
+	discount := Discount{Code: "SPRING", Kind: DiscountAmountOff, Amount: 200, Currency: "EUR"}
+
+	value, err := discount.Value()
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"code":"SPRING","kind":"amount_off","amount":200,"currency":"EUR"}`, value.(string))
+
+	var scanned Discount
+	assert.Nil(t, scanned.Scan(value))
+	assert.Equal(t, discount, scanned)
+
+	assert.Nil(t, scanned.Scan(nil))
+	assert.Equal(t, Discount{}, scanned)
+
+	assert.NotNil(t, scanned.Scan(42))
+}
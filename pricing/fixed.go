@@ -0,0 +1,33 @@
+package pricing
+
+import (
+	"context"
+	"time"
+)
+
+// Fixed is a TariffSource that offers the same tariff in every scope.
+type Fixed struct {
+	Tariff Tariff
+}
+
+func (f Fixed) Active(ctx context.Context, scope Scope, at time.Time) (Tariff, error) {
+	return f.Tariff, nil
+}
+
+// NoPasses is a Passes with nothing to cover rides with.
+type NoPasses struct{}
+
+func (NoPasses) Allowances(ctx context.Context, userID string, at time.Time) ([]Allowance, error) {
+	return nil, nil
+}
+
+func (NoPasses) Consume(ctx context.Context, rideID uint, consumptions []Consumption, at time.Time) error {
+	return nil
+}
+
+// NoTaxes is a Taxes that taxes nothing.
+type NoTaxes struct{}
+
+func (NoTaxes) Rate(ctx context.Context, city, operator string) (TaxRate, error) {
+	return TaxRate{}, nil
+}
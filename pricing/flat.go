@@ -0,0 +1,209 @@
+package pricing
+
+import (
+	"time"
+
+	"backend/money"
+)
+
+// Flat bills a fixed unlock fee plus a price per minute, with paused
+// minutes billed at their own rate. The tariff billing policy sets the unit
+// time is billed in, and the tariff rules adjust the minute prices: every
+// billed unit is charged at the price in effect when it started, and
+// consecutive units charged the same way make one line item.
+type Flat struct{}
+
+func (Flat) Quote(tariff Tariff, usage Usage) Quote {
+	var (
+		quote    = NewQuote(tariff.Currency)
+		billing  = tariff.Billing
+		location = tariff.Location()
+	)
+
+	quote.Add(LineItem{
+		Kind:        KindUnlock,
+		Description: "Unlock fee",
+		Quantity:    1,
+		UnitPrice:   tariff.price(tariff.UnlockPrice),
+		Amount:      tariff.price(tariff.UnlockPrice),
+	})
+
+	next := func(at time.Time) time.Time {
+		return tariff.nextBoundary(at, location)
+	}
+
+	riding, err := meter(billing, usage.Active, billing.grace(), func(at time.Time) (money.Money, *Rule, error) {
+		rule := tariff.ruleAt(at, location)
+		if rule == nil {
+			return tariff.price(tariff.MinutePrice), nil, nil
+		}
+		price, err := rule.price(tariff.price(tariff.MinutePrice), rule.MinutePrice)
+		return price, rule, err
+	}, next)
+	if err != nil {
+		quote.fail(err)
+		return quote
+	}
+	for _, segment := range riding {
+		quote.addSegment(segment, billing, KindTime, "Riding time")
+	}
+
+	paused, err := meter(billing, usage.Paused, 0, func(at time.Time) (money.Money, *Rule, error) {
+		rule := tariff.ruleAt(at, location)
+		if rule == nil {
+			return tariff.price(tariff.PausedMinutePrice), nil, nil
+		}
+		price, err := rule.price(tariff.price(tariff.PausedMinutePrice), rule.PausedMinutePrice)
+		return price, rule, err
+	}, next)
+	if err != nil {
+		quote.fail(err)
+		return quote
+	}
+	for _, segment := range paused {
+		quote.addSegment(segment, billing, KindPausedTime, "Paused time")
+	}
+
+	if billing.MinimumCharge > 0 {
+		quote.raise(tariff.price(billing.MinimumCharge), KindMinimumCharge, "Minimum ride charge")
+	}
+	if billing.MaximumCharge > 0 {
+		quote.lower(tariff.price(billing.MaximumCharge), KindMaximumCharge, "Maximum ride charge")
+	}
+
+	return quote
+}
+
+// raise brings the total of the quote up to minimum with an item of the
+// kind, when it is below.
+func (q *Quote) raise(minimum money.Money, kind Kind, description string) {
+	if cmp, err := q.Total.Cmp(minimum); err != nil {
+		q.fail(err)
+		return
+	} else if cmp >= 0 {
+		return
+	}
+	q.bring(minimum, kind, description)
+}
+
+// lower brings the total of the quote down to maximum with an item of the
+// kind, when it is above.
+func (q *Quote) lower(maximum money.Money, kind Kind, description string) {
+	if cmp, err := q.Total.Cmp(maximum); err != nil {
+		q.fail(err)
+		return
+	} else if cmp <= 0 {
+		return
+	}
+	q.bring(maximum, kind, description)
+}
+
+func (q *Quote) bring(total money.Money, kind Kind, description string) {
+	difference, err := total.Sub(q.Total)
+	if err != nil {
+		q.fail(err)
+		return
+	}
+	q.Add(LineItem{
+		Kind:        kind,
+		Description: description,
+		Quantity:    1,
+		UnitPrice:   difference,
+		Amount:      difference,
+	})
+}
+
+// segment is a run of consecutive billed units charged at the same price.
+type segment struct {
+	rule        *Rule
+	units       int
+	minutePrice money.Money
+}
+
+// addSegment adds the segment as an item of the kind.
+func (q *Quote) addSegment(s segment, billing Billing, kind Kind, what string) {
+	description := billing.describe(what)
+	if s.rule != nil {
+		description += ", " + s.rule.Name
+	}
+
+	unitPrice, err := billing.unitPrice(s.minutePrice)
+	if err != nil {
+		q.fail(err)
+		return
+	}
+	amount, err := billing.amount(s.units, s.minutePrice)
+	if err != nil {
+		q.fail(err)
+		return
+	}
+	q.Add(LineItem{
+		Kind:        kind,
+		Description: description,
+		Quantity:    s.units,
+		UnitPrice:   unitPrice,
+		Amount:      amount,
+	})
+}
+
+// meter splits the intervals into billed units, counted on the time spent
+// in them past the grace period so that a unit may continue in the next
+// interval, and prices each unit at the instant it started. Prices only
+// change at the boundaries given by next, so units are priced a run at a
+// time.
+func meter(billing Billing, intervals []Interval, grace time.Duration, priceAt func(time.Time) (money.Money, *Rule, error), next func(time.Time) time.Time) ([]segment, error) {
+	var total time.Duration
+	for _, interval := range intervals {
+		total += interval.Duration()
+	}
+	if total < grace {
+		return nil, nil
+	}
+
+	var (
+		segments []segment
+		unit     = billing.unit()
+		units    = billing.units(total - grace)
+		billed   = 0
+		current  = 0
+		before   time.Duration // time spent in the intervals before current
+	)
+	for billed < units {
+		offset := grace + time.Duration(billed)*unit
+		for current < len(intervals)-1 && offset >= before+intervals[current].Duration() {
+			before += intervals[current].Duration()
+			current++
+		}
+
+		var (
+			at  = intervals[current].Start.Add(offset - before)
+			end = intervals[current].End
+		)
+		if boundary := next(at); !boundary.IsZero() && boundary.Before(end) {
+			end = boundary
+		}
+
+		// Every unit starting before the end of the run costs the same.
+		length := end.Sub(at)
+		run := int(length / unit)
+		if length%unit != 0 || run < 1 {
+			run++
+		}
+		if run > units-billed {
+			run = units - billed
+		}
+		billed += run
+
+		minutePrice, rule, err := priceAt(at)
+		if err != nil {
+			return nil, err
+		}
+		if n := len(segments); n > 0 && segments[n-1].rule == rule && segments[n-1].minutePrice == minutePrice {
+			segments[n-1].units += run
+			continue
+		}
+		segments = append(segments, segment{rule: rule, units: run, minutePrice: minutePrice})
+	}
+
+	return segments, nil
+}
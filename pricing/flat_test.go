@@ -0,0 +1,120 @@
+package pricing
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"backend/money"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var tariff = Tariff{Version: "1", Currency: "EUR", UnlockPrice: 18, MinutePrice: 100, PausedMinutePrice: 25, Billing: Billing{Unit: UnitMinute, Rounding: RoundingCeil}}
+
+func eur(amount int64) money.Money {
+	return money.New(amount, "EUR")
+}
+
+var start = time.Date(2022, 6, 17, 12, 0, 0, 0, time.UTC)
+
+// ride is the usage of a ride that started at start, rode and then stayed
+// paused until it finished.
+func ride(active, paused time.Duration) Usage {
+	usage := Usage{Active: []Interval{{Start: start, End: start.Add(active)}}}
+	if paused > 0 {
+		usage.Paused = []Interval{{Start: start.Add(active), End: start.Add(active + paused)}}
+	}
+	return usage
+}
+
+func TestFlatQuoteUnlock(t *testing.T) {
+	flat := Flat{}
+
+	quote := flat.Quote(tariff, Usage{})
+	assert.Equal(t, eur(18), quote.Total)
+	assert.Equal(t, []LineItem{
+		{Kind: KindUnlock, Description: "Unlock fee", Quantity: 1, UnitPrice: eur(18), Amount: eur(18)},
+	}, quote.Items)
+}
+
+func TestFlatQuote(t *testing.T) {
+	flat := Flat{}
+
+	tests := []struct {
+		name  string
+		usage Usage
+		total int64
+	}{
+		{"1 second", ride(time.Second, 0), 118},
+		{"59 seconds", ride(59*time.Second, 0), 118},
+		{"60 seconds", ride(60*time.Second, 0), 118},
+		{"61 seconds", ride(61*time.Second, 0), 218},
+		{"1565 seconds", ride(1565*time.Second, 0), 2718},
+		{"paused", ride(6*time.Minute, 4*time.Minute), 718},
+		{"paused 1 second", ride(time.Minute, time.Second), 143},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, eur(test.total), flat.Quote(tariff, test.usage).Total)
+		})
+	}
+}
+
+func TestFlatQuoteItems(t *testing.T) {
+	flat := Flat{}
+
+	quote := flat.Quote(tariff, ride(6*time.Minute, 4*time.Minute))
+	assert.Equal(t, []LineItem{
+		{Kind: KindUnlock, Description: "Unlock fee", Quantity: 1, UnitPrice: eur(18), Amount: eur(18)},
+		{Kind: KindTime, Description: "Riding time (minutes)", Quantity: 6, UnitPrice: eur(100), Amount: eur(600)},
+		{Kind: KindPausedTime, Description: "Paused time (minutes)", Quantity: 4, UnitPrice: eur(25), Amount: eur(100)},
+	}, quote.Items)
+}
+
+func TestQuoteUnits(t *testing.T) {
+	quote := Flat{}.Quote(tariff, ride(6*time.Minute, 4*time.Minute))
+	assert.Equal(t, 6, quote.Units(KindTime))
+	assert.Equal(t, 4, quote.Units(KindPausedTime))
+	assert.Equal(t, 0, quote.Units(KindDiscount))
+}
+
+func TestFlatQuoteOverflow(t *testing.T) {
+	expensive := tariff
+	expensive.MinutePrice = math.MaxInt64 / 2
+
+	quote := Flat{}.Quote(expensive, ride(3*time.Minute, 0))
+	assert.Equal(t, money.ErrOverflow, quote.Err())
+	assert.Len(t, quote.Items, 1)
+}
+
+func TestFlatQuoteUsesGivenTariff(t *testing.T) {
+	var (
+		flat      = Flat{}
+		newTariff = Tariff{Version: "2", Currency: "EUR", UnlockPrice: 30, MinutePrice: 200}
+	)
+
+	assert.Equal(t, eur(118), flat.Quote(tariff, ride(time.Minute, 0)).Total)
+	assert.Equal(t, eur(230), flat.Quote(newTariff, ride(time.Minute, 0)).Total)
+}
+
+func TestFlatQuoteCountsMinutesAcrossIntervals(t *testing.T) {
+	var (
+		flat  = Flat{}
+		usage = Usage{
+			Active: []Interval{
+				{Start: start, End: start.Add(30 * time.Second)},
+				{Start: start.Add(10 * time.Minute), End: start.Add(10*time.Minute + 30*time.Second)},
+			},
+			Paused: []Interval{{Start: start.Add(30 * time.Second), End: start.Add(10 * time.Minute)}},
+		}
+	)
+
+	quote := flat.Quote(tariff, usage)
+	assert.Equal(t, []LineItem{
+		{Kind: KindUnlock, Description: "Unlock fee", Quantity: 1, UnitPrice: eur(18), Amount: eur(18)},
+		{Kind: KindTime, Description: "Riding time (minutes)", Quantity: 1, UnitPrice: eur(100), Amount: eur(100)},
+		{Kind: KindPausedTime, Description: "Paused time (minutes)", Quantity: 10, UnitPrice: eur(25), Amount: eur(250)},
+	}, quote.Items)
+}
@@ -0,0 +1,44 @@
+package pricing
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+)
+
+// Prepaid makes the rides of a tariff paid from the wallet of the user
+// rather than with a hold on their payment method, as in the markets that
+// are prepaid only. Such rides only start while the wallet holds at least
+// MinimumBalance, in the currency of the tariff.
+type Prepaid struct {
+	Enabled        bool `json:"enabled,omitempty"`
+	MinimumBalance int  `json:"minimum_balance,omitempty"`
+}
+
+var ErrPrepaidMinimumNegative = errors.New("The minimum balance can't be negative")
+
+func (p Prepaid) Validate() error {
+	if p.MinimumBalance < 0 {
+		return ErrPrepaidMinimumNegative
+	}
+	return nil
+}
+
+func (p Prepaid) Value() (driver.Value, error) {
+	value, err := json.Marshal(p)
+	return string(value), err
+}
+
+func (p *Prepaid) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*p = Prepaid{}
+		return nil
+	case []byte:
+		return json.Unmarshal(v, p)
+	case string:
+		return json.Unmarshal([]byte(v), p)
+	}
+
+	return errors.New("pricing: cannot scan prepaid")
+}
@@ -0,0 +1,204 @@
+package pricing
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"backend/money"
+)
+
+type Kind string
+
+const (
+	KindUnlock     Kind = "unlock"
+	KindTime       Kind = "time"
+	KindPausedTime Kind = "paused_time"
+	KindDiscount   Kind = "discount"
+	KindTax        Kind = "tax"
+	// KindMinimumCharge and KindMaximumCharge bring the total to the
+	// minimum or maximum ride charge of the tariff.
+	KindMinimumCharge Kind = "minimum_charge"
+	KindMaximumCharge Kind = "maximum_charge"
+	// KindCap brings the total down to what is left of a daily or weekly
+	// cap.
+	KindCap Kind = "cap"
+	// KindPass is what a prepaid pass covers.
+	KindPass Kind = "pass"
+)
+
+// LineItem is a single charge of a quote. Discounts have negative amounts.
+// Amount is Quantity times UnitPrice, except for time billed per second,
+// whose UnitPrice is the price per minute.
+type LineItem struct {
+	Kind        Kind        `json:"kind"`
+	Description string      `json:"description"`
+	Quantity    int         `json:"quantity"`
+	UnitPrice   money.Money `json:"unit_price"`
+	Amount      money.Money `json:"amount"`
+}
+
+// LineItems are the items a ride was charged, stored as a JSON document.
+type LineItems []LineItem
+
+// Value stores the items as a JSON document.
+func (l LineItems) Value() (driver.Value, error) {
+	if l == nil {
+		l = LineItems{}
+	}
+	value, err := json.Marshal(l)
+	return string(value), err
+}
+
+// Scan reads items stored by Value.
+func (l *LineItems) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*l = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(v, l)
+	case string:
+		return json.Unmarshal([]byte(v), l)
+	}
+
+	return errors.New("pricing: cannot scan line items")
+}
+
+// Quote is an itemised price in a single currency, whose Total is the sum
+// of the item amounts. Its arithmetic is checked: the first error it runs
+// into, such as an amount out of range, stops it and is kept in Err.
+type Quote struct {
+	Items []LineItem  `json:"items"`
+	Total money.Money `json:"total"`
+
+	covered coverage
+	err     error
+}
+
+// NewQuote is a quote with no items in the given currency.
+func NewQuote(currency money.Currency) Quote {
+	return Quote{Total: money.Zero(currency)}
+}
+
+// Price is the total of the quote.
+func (q Quote) Price() money.Money {
+	return q.Total
+}
+
+// Currency is the currency every amount of the quote is in.
+func (q Quote) Currency() money.Currency {
+	return q.Total.Currency
+}
+
+// Err is the error that stopped the quote, if any. A quote with an error
+// is not to be charged.
+func (q Quote) Err() error {
+	return q.err
+}
+
+// fail stops the quote on its first error.
+func (q *Quote) fail(err error) {
+	if q.err == nil {
+		q.err = err
+	}
+}
+
+// Units is the quantity billed by the items of the given kind, e.g. the
+// riding time units for KindTime.
+func (q Quote) Units(kind Kind) int {
+	units := 0
+	for _, item := range q.Items {
+		if item.Kind == kind {
+			units += item.Quantity
+		}
+	}
+	return units
+}
+
+func (q *Quote) Add(item LineItem) {
+	if q.err != nil {
+		return
+	}
+	total, err := q.Total.Add(item.Amount)
+	if err != nil {
+		q.fail(err)
+		return
+	}
+	q.Items = append(q.Items, item)
+	q.Total = total
+}
+
+// Interval is the span of time from Start to End.
+type Interval struct {
+	Start time.Time
+	End   time.Time
+}
+
+func (i Interval) Duration() time.Duration {
+	if i.End.Before(i.Start) {
+		return 0
+	}
+	return i.End.Sub(i.Start)
+}
+
+// Usage is what a ride consumed: the intervals it spent riding and paused,
+// each in chronological order. The zero Usage is a ride that was just
+// unlocked.
+type Usage struct {
+	Active []Interval
+	Paused []Interval
+}
+
+// Scope is what a tariff can be specific to: rides on a type of vehicle, in
+// a city. Blank fields match every vehicle type or city.
+type Scope struct {
+	VehicleType string
+	City        string
+}
+
+// TariffSource resolves the tariff rides in a scope starting at a given
+// instant are billed with.
+type TariffSource interface {
+	Active(ctx context.Context, scope Scope, at time.Time) (Tariff, error)
+}
+
+// Promotions validates and redeems the promo codes rides start with.
+type Promotions interface {
+	// Discount returns the discount of a code the user can redeem at the
+	// given instant.
+	Discount(ctx context.Context, code string, userID string, at time.Time) (Discount, error)
+	// Redeem records that the ride redeemed the code, failing when a limit
+	// of the code was reached in the meantime. It is called within the
+	// transaction that starts the ride.
+	Redeem(ctx context.Context, code string, userID string, rideID uint, at time.Time) error
+	// Release gives back what the ride redeemed, if anything, when it is
+	// called off. It is called within the transaction that cancels the
+	// ride.
+	Release(ctx context.Context, rideID uint) error
+}
+
+// Taxes resolves the tax rate of rides in a city or run by an operator.
+type Taxes interface {
+	// Rate returns the rate of the city if it has one, else the rate of
+	// the operator, else the zero TaxRate.
+	Rate(ctx context.Context, city, operator string) (TaxRate, error)
+}
+
+// Passes cover rides with what their users prepaid.
+type Passes interface {
+	// Allowances returns what the passes of the user active at the given
+	// instant have left, the first to expire first.
+	Allowances(ctx context.Context, userID string, at time.Time) ([]Allowance, error)
+	// Consume records what a ride used up of the passes, failing when a
+	// pass no longer has the minutes. It is called within the transaction
+	// that finishes the ride.
+	Consume(ctx context.Context, rideID uint, consumptions []Consumption, at time.Time) error
+}
+
+// Pricer turns the usage of a ride into a quote under a tariff.
+type Pricer interface {
+	Quote(tariff Tariff, usage Usage) Quote
+}
@@ -0,0 +1,205 @@
+package pricing
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+
+	// Tariffs name their time zone, so the zone database is embedded rather
+	// than read from the host.
+	_ "time/tzdata"
+
+	"backend/money"
+)
+
+type Day string
+
+const (
+	Monday    Day = "mon"
+	Tuesday   Day = "tue"
+	Wednesday Day = "wed"
+	Thursday  Day = "thu"
+	Friday    Day = "fri"
+	Saturday  Day = "sat"
+	Sunday    Day = "sun"
+)
+
+var weekdays = map[Day]time.Weekday{
+	Monday:    time.Monday,
+	Tuesday:   time.Tuesday,
+	Wednesday: time.Wednesday,
+	Thursday:  time.Thursday,
+	Friday:    time.Friday,
+	Saturday:  time.Saturday,
+	Sunday:    time.Sunday,
+}
+
+// Clock is a wall clock time in the tariff time zone, formatted as "15:04".
+type Clock string
+
+func (c Clock) minutes() (int, bool) {
+	t, err := time.Parse("15:04", string(c))
+	if err != nil {
+		return 0, false
+	}
+	return t.Hour()*60 + t.Minute(), true
+}
+
+// Rule changes the minute prices during a window of the week, such as a
+// cheaper night rate or a weekend surge. The window opens at From on each
+// of Days, or on every day when Days is empty, and closes at To. A window
+// whose To is not after its From closes the next day, so "22:00" to "06:00"
+// on Friday covers Friday night until Saturday morning, and equal From and
+// To cover 24 hours.
+//
+// MinutePrice and PausedMinutePrice override the tariff prices, and
+// MultiplierPercent then scales them, e.g. 150 bills minutes at 1.5 times
+// the price. Zero leaves them unscaled.
+type Rule struct {
+	Name              string `json:"name"`
+	Days              []Day  `json:"days,omitempty"`
+	From              Clock  `json:"from"`
+	To                Clock  `json:"to"`
+	MultiplierPercent int    `json:"multiplier_percent,omitempty"`
+	MinutePrice       *int   `json:"minute_price,omitempty"`
+	PausedMinutePrice *int   `json:"paused_minute_price,omitempty"`
+}
+
+var (
+	ErrRuleNameBlank          = errors.New("Rule name can't be blank")
+	ErrRuleClockInvalid       = errors.New("Rule from and to must be wall clock times such as 22:00")
+	ErrRuleDayInvalid         = errors.New("Rule days must be one of: mon, tue, wed, thu, fri, sat, sun")
+	ErrRuleMultiplierNegative = errors.New("Rule multiplier can't be negative")
+	ErrRulePriceNegative      = errors.New("Rule prices can't be negative")
+)
+
+func (r Rule) Validate() error {
+	if r.Name == "" {
+		return ErrRuleNameBlank
+	}
+	if _, ok := r.From.minutes(); !ok {
+		return ErrRuleClockInvalid
+	}
+	if _, ok := r.To.minutes(); !ok {
+		return ErrRuleClockInvalid
+	}
+	for _, day := range r.Days {
+		if _, ok := weekdays[day]; !ok {
+			return ErrRuleDayInvalid
+		}
+	}
+	if r.MultiplierPercent < 0 {
+		return ErrRuleMultiplierNegative
+	}
+	if (r.MinutePrice != nil && *r.MinutePrice < 0) || (r.PausedMinutePrice != nil && *r.PausedMinutePrice < 0) {
+		return ErrRulePriceNegative
+	}
+	return nil
+}
+
+// matches reports whether the window is open at the given wall clock time.
+func (r Rule) matches(local time.Time) bool {
+	from, _ := r.From.minutes()
+	to, _ := r.To.minutes()
+	now := local.Hour()*60 + local.Minute()
+
+	if from < to {
+		return now >= from && now < to && r.on(local.Weekday())
+	}
+
+	// The window spans midnight: it is open from From until midnight on its
+	// days, and from midnight until To on the day after.
+	if now >= from {
+		return r.on(local.Weekday())
+	}
+	return now < to && r.on((local.Weekday()+6)%7)
+}
+
+func (r Rule) on(weekday time.Weekday) bool {
+	if len(r.Days) == 0 {
+		return true
+	}
+	for _, day := range r.Days {
+		if weekdays[day] == weekday {
+			return true
+		}
+	}
+	return false
+}
+
+// price applies the rule to a minute price, rounding half up to the minor
+// unit.
+func (r Rule) price(base money.Money, override *int) (money.Money, error) {
+	if override != nil {
+		base = money.New(int64(*override), base.Currency)
+	}
+	if r.MultiplierPercent == 0 {
+		return base, nil
+	}
+	return base.Scale(int64(r.MultiplierPercent), 100)
+}
+
+// Rules are evaluated in order and the first one open applies.
+type Rules []Rule
+
+// Equal reports whether both hold the same rules in the same order, no
+// rules at all being equal whether nil or empty.
+func (r Rules) Equal(other Rules) bool {
+	if len(r) != len(other) {
+		return false
+	}
+	for i := range r {
+		if !r[i].equal(other[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func (r Rule) equal(other Rule) bool {
+	if r.Name != other.Name || r.From != other.From || r.To != other.To ||
+		r.MultiplierPercent != other.MultiplierPercent ||
+		!samePrice(r.MinutePrice, other.MinutePrice) ||
+		!samePrice(r.PausedMinutePrice, other.PausedMinutePrice) ||
+		len(r.Days) != len(other.Days) {
+		return false
+	}
+	for i := range r.Days {
+		if r.Days[i] != other.Days[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func samePrice(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// Value stores the rules as a JSON document.
+func (r Rules) Value() (driver.Value, error) {
+	if r == nil {
+		r = Rules{}
+	}
+	value, err := json.Marshal(r)
+	return string(value), err
+}
+
+// Scan reads rules stored by Value.
+func (r *Rules) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*r = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(v, r)
+	case string:
+		return json.Unmarshal([]byte(v), r)
+	}
+
+	return errors.New("pricing: cannot scan rules")
+}
@@ -0,0 +1,340 @@
+package pricing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func price(p int) *int {
+	return &p
+}
+
+func TestRuleValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		rule Rule
+		err  error
+	}{
+		{"valid", Rule{Name: "Night", From: "22:00", To: "06:00", MinutePrice: price(50)}, nil},
+		{"name blank", Rule{From: "22:00", To: "06:00"}, ErrRuleNameBlank},
+		{"from invalid", Rule{Name: "Night", From: "10pm", To: "06:00"}, ErrRuleClockInvalid},
+		{"to invalid", Rule{Name: "Night", From: "22:00", To: "24:00"}, ErrRuleClockInvalid},
+		{"day invalid", Rule{Name: "Weekend", From: "00:00", To: "00:00", Days: []Day{Saturday, "sunday"}}, ErrRuleDayInvalid},
+		{"multiplier negative", Rule{Name: "Night", From: "22:00", To: "06:00", MultiplierPercent: -50}, ErrRuleMultiplierNegative},
+		{"minute price negative", Rule{Name: "Night", From: "22:00", To: "06:00", MinutePrice: price(-1)}, ErrRulePriceNegative},
+		{"paused minute price negative", Rule{Name: "Night", From: "22:00", To: "06:00", PausedMinutePrice: price(-1)}, ErrRulePriceNegative},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.err, test.rule.Validate())
+		})
+	}
+}
+
+func TestRuleMatches(t *testing.T) {
+	var (
+		lunch   = Rule{Name: "Lunch", From: "12:00", To: "14:00"}
+		night   = Rule{Name: "Night", From: "22:00", To: "06:00"}
+		friday  = Rule{Name: "Friday night", From: "22:00", To: "06:00", Days: []Day{Friday}}
+		weekend = Rule{Name: "Weekend", From: "00:00", To: "00:00", Days: []Day{Saturday, Sunday}}
+	)
+
+	tests := []struct {
+		name    string
+		rule    Rule
+		at      string
+		matches bool
+	}{
+		{"before window", lunch, "2026-10-16T11:59:59Z", false},
+		{"window opens", lunch, "2026-10-16T12:00:00Z", true},
+		{"window closes", lunch, "2026-10-16T14:00:00Z", false},
+		{"before midnight", night, "2026-10-16T23:59:59Z", true},
+		{"after midnight", night, "2026-10-17T05:59:59Z", true},
+		{"after night", night, "2026-10-17T06:00:00Z", false},
+		{"friday night", friday, "2026-10-16T22:00:00Z", true},
+		{"friday night after midnight", friday, "2026-10-17T05:00:00Z", true},
+		{"saturday night", friday, "2026-10-17T22:00:00Z", false},
+		{"friday morning", friday, "2026-10-16T05:00:00Z", false},
+		{"weekend starts", weekend, "2026-10-17T00:00:00Z", true},
+		{"weekend ends", weekend, "2026-10-19T00:00:00Z", false},
+		{"friday before weekend", weekend, "2026-10-16T23:59:59Z", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			at, err := time.Parse(time.RFC3339, test.at)
+			assert.Nil(t, err)
+			assert.Equal(t, test.matches, test.rule.matches(at))
+		})
+	}
+}
+
+func TestRulePrice(t *testing.T) {
+	tests := []struct {
+		rule     Rule
+		base     int64
+		override *int
+		price    int64
+	}{
+		{Rule{}, 100, nil, 100},
+		{Rule{}, 100, price(50), 50},
+		{Rule{MultiplierPercent: 150}, 100, nil, 150},
+		{Rule{MultiplierPercent: 150}, 100, price(50), 75},
+		{Rule{MultiplierPercent: 150}, 25, nil, 38},
+		{Rule{MultiplierPercent: 33}, 25, nil, 8},
+	}
+
+	for _, test := range tests {
+		price, err := test.rule.price(eur(test.base), test.override)
+		assert.Nil(t, err)
+		assert.Equal(t, eur(test.price), price)
+	}
+}
+
+func TestRulesValueScan(t *testing.T) {
+	rules := Rules{{Name: "Night", From: "22:00", To: "06:00", Days: []Day{Friday}, MultiplierPercent: 80, MinutePrice: price(50)}}
+
+	value, err := rules.Value()
+	assert.Nil(t, err)
+	assert.JSONEq(t, `[{"name":"Night","days":["fri"],"from":"22:00","to":"06:00","multiplier_percent":80,"minute_price":50}]`, value.(string))
+
+	var scanned Rules
+	assert.Nil(t, scanned.Scan(value))
+	assert.Equal(t, rules, scanned)
+
+	value, err = Rules(nil).Value()
+	assert.Nil(t, err)
+	assert.Equal(t, "[]", value)
+
+	assert.Nil(t, scanned.Scan(nil))
+	assert.Nil(t, scanned)
+
+	assert.NotNil(t, scanned.Scan(42))
+}
+
+func TestRulesEqual(t *testing.T) {
+	night := Rule{Name: "Night", From: "22:00", To: "06:00", Days: []Day{Friday}, MinutePrice: price(50)}
+
+	assert.True(t, Rules(nil).Equal(Rules{}))
+	assert.True(t, Rules{night}.Equal(Rules{{Name: "Night", From: "22:00", To: "06:00", Days: []Day{Friday}, MinutePrice: price(50)}}))
+	assert.False(t, Rules{night}.Equal(nil))
+	assert.False(t, Rules{night}.Equal(Rules{{Name: "Night", From: "22:00", To: "06:00", Days: []Day{Friday}, MinutePrice: price(60)}}))
+	assert.False(t, Rules{night}.Equal(Rules{{Name: "Night", From: "22:00", To: "06:00", MinutePrice: price(50)}}))
+	assert.False(t, Rules{night}.Equal(Rules{{Name: "Night", From: "22:00", To: "06:00", Days: []Day{Friday}}}))
+}
+
+func TestFlatQuoteWithRules(t *testing.T) {
+	var (
+		flat   = Flat{}
+		madrid = Tariff{
+			Version:           "surge",
+			Currency:          "EUR",
+			UnlockPrice:       18,
+			MinutePrice:       100,
+			PausedMinutePrice: 25,
+			Billing:           Billing{Unit: UnitMinute, Rounding: RoundingCeil},
+			TimeZone:          "Europe/Madrid",
+			Rules: Rules{
+				{Name: "Night", From: "22:00", To: "06:00", MinutePrice: price(50), PausedMinutePrice: price(10)},
+				{Name: "Weekend", From: "00:00", To: "00:00", Days: []Day{Saturday, Sunday}, MultiplierPercent: 150},
+			},
+		}
+		utc = madrid
+	)
+	utc.TimeZone = ""
+
+	tests := []struct {
+		name   string
+		tariff Tariff
+		start  string
+		active time.Duration
+		paused time.Duration
+		items  []LineItem
+	}{
+		{
+			name:   "outside every window",
+			tariff: madrid,
+			start:  "2026-10-16T12:00:00+02:00",
+			active: 10 * time.Minute,
+			items: []LineItem{
+				{Kind: KindTime, Description: "Riding time (minutes)", Quantity: 10, UnitPrice: eur(100), Amount: eur(1000)},
+			},
+		},
+		{
+			name:   "into the night",
+			tariff: madrid,
+			start:  "2026-10-16T21:50:00+02:00",
+			active: 20 * time.Minute,
+			items: []LineItem{
+				{Kind: KindTime, Description: "Riding time (minutes)", Quantity: 10, UnitPrice: eur(100), Amount: eur(1000)},
+				{Kind: KindTime, Description: "Riding time (minutes), Night", Quantity: 10, UnitPrice: eur(50), Amount: eur(500)},
+			},
+		},
+		{
+			name:   "started minute billed at its start",
+			tariff: madrid,
+			start:  "2026-10-16T21:59:30+02:00",
+			active: time.Minute,
+			items: []LineItem{
+				{Kind: KindTime, Description: "Riding time (minutes)", Quantity: 1, UnitPrice: eur(100), Amount: eur(100)},
+			},
+		},
+		{
+			name:   "out of the night",
+			tariff: madrid,
+			start:  "2026-10-16T05:55:00+02:00",
+			active: 10 * time.Minute,
+			items: []LineItem{
+				{Kind: KindTime, Description: "Riding time (minutes), Night", Quantity: 5, UnitPrice: eur(50), Amount: eur(250)},
+				{Kind: KindTime, Description: "Riding time (minutes)", Quantity: 5, UnitPrice: eur(100), Amount: eur(500)},
+			},
+		},
+		{
+			name:   "paused across the window",
+			tariff: madrid,
+			start:  "2026-10-16T21:50:00+02:00",
+			active: 5 * time.Minute,
+			paused: 10 * time.Minute,
+			items: []LineItem{
+				{Kind: KindTime, Description: "Riding time (minutes)", Quantity: 5, UnitPrice: eur(100), Amount: eur(500)},
+				{Kind: KindPausedTime, Description: "Paused time (minutes)", Quantity: 5, UnitPrice: eur(25), Amount: eur(125)},
+				{Kind: KindPausedTime, Description: "Paused time (minutes), Night", Quantity: 5, UnitPrice: eur(10), Amount: eur(50)},
+			},
+		},
+		{
+			name:   "night rule comes first on weekends",
+			tariff: madrid,
+			start:  "2026-10-17T05:50:00+02:00",
+			active: 20 * time.Minute,
+			items: []LineItem{
+				{Kind: KindTime, Description: "Riding time (minutes), Night", Quantity: 10, UnitPrice: eur(50), Amount: eur(500)},
+				{Kind: KindTime, Description: "Riding time (minutes), Weekend", Quantity: 10, UnitPrice: eur(150), Amount: eur(1500)},
+			},
+		},
+		{
+			name:   "weekend ends at midnight",
+			tariff: Tariff{Currency: "EUR", MinutePrice: 100, Rules: Rules{madrid.Rules[1]}},
+			start:  "2026-10-18T23:50:00Z",
+			active: 20 * time.Minute,
+			items: []LineItem{
+				{Kind: KindTime, Description: "Riding time (minutes), Weekend", Quantity: 10, UnitPrice: eur(150), Amount: eur(1500)},
+				{Kind: KindTime, Description: "Riding time (minutes)", Quantity: 10, UnitPrice: eur(100), Amount: eur(1000)},
+			},
+		},
+		{
+			name:   "windows follow the tariff time zone",
+			tariff: madrid,
+			start:  "2026-10-16T20:00:00Z",
+			active: 10 * time.Minute,
+			items: []LineItem{
+				{Kind: KindTime, Description: "Riding time (minutes), Night", Quantity: 10, UnitPrice: eur(50), Amount: eur(500)},
+			},
+		},
+		{
+			name:   "windows default to UTC",
+			tariff: utc,
+			start:  "2026-10-16T20:00:00Z",
+			active: 10 * time.Minute,
+			items: []LineItem{
+				{Kind: KindTime, Description: "Riding time (minutes)", Quantity: 10, UnitPrice: eur(100), Amount: eur(1000)},
+			},
+		},
+		{
+			name:   "several nights",
+			tariff: utc,
+			start:  "2026-10-19T21:00:00Z",
+			active: 49 * time.Hour,
+			items: []LineItem{
+				{Kind: KindTime, Description: "Riding time (minutes)", Quantity: 60, UnitPrice: eur(100), Amount: eur(6000)},
+				{Kind: KindTime, Description: "Riding time (minutes), Night", Quantity: 480, UnitPrice: eur(50), Amount: eur(24000)},
+				{Kind: KindTime, Description: "Riding time (minutes)", Quantity: 960, UnitPrice: eur(100), Amount: eur(96000)},
+				{Kind: KindTime, Description: "Riding time (minutes), Night", Quantity: 480, UnitPrice: eur(50), Amount: eur(24000)},
+				{Kind: KindTime, Description: "Riding time (minutes)", Quantity: 960, UnitPrice: eur(100), Amount: eur(96000)},
+			},
+		},
+		{
+			// Clocks jump from 02:00 to 03:00: the ride lasts 20 minutes,
+			// not 80, and leaves the night at 03:00.
+			name:   "spring forward",
+			tariff: Tariff{Currency: "EUR", MinutePrice: 100, TimeZone: "Europe/Madrid", Rules: Rules{{Name: "Night", From: "22:00", To: "03:00", MinutePrice: price(50)}}},
+			start:  "2026-03-29T01:50:00+01:00",
+			active: 20 * time.Minute,
+			items: []LineItem{
+				{Kind: KindTime, Description: "Riding time (minutes), Night", Quantity: 10, UnitPrice: eur(50), Amount: eur(500)},
+				{Kind: KindTime, Description: "Riding time (minutes)", Quantity: 10, UnitPrice: eur(100), Amount: eur(1000)},
+			},
+		},
+		{
+			// Clocks go back from 03:00 to 02:00: the ride ends at the same
+			// wall time it started, yet lasted an hour, all of it between
+			// 02:00 and 03:00.
+			name:   "fall back",
+			tariff: Tariff{Currency: "EUR", MinutePrice: 100, TimeZone: "Europe/Madrid", Rules: Rules{{Name: "Late night", From: "02:00", To: "03:00", MultiplierPercent: 200}}},
+			start:  "2026-10-25T02:30:00+02:00",
+			active: time.Hour,
+			items: []LineItem{
+				{Kind: KindTime, Description: "Riding time (minutes), Late night", Quantity: 60, UnitPrice: eur(200), Amount: eur(12000)},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			start, err := time.Parse(time.RFC3339, test.start)
+			assert.Nil(t, err)
+
+			usage := Usage{Active: []Interval{{Start: start, End: start.Add(test.active)}}}
+			if test.paused > 0 {
+				usage.Paused = []Interval{{Start: start.Add(test.active), End: start.Add(test.active + test.paused)}}
+			}
+
+			quote := flat.Quote(test.tariff, usage)
+			assert.Equal(t, test.items, quote.Items[1:])
+
+			total := eur(int64(test.tariff.UnlockPrice))
+			for _, item := range test.items {
+				total, err = total.Add(item.Amount)
+				assert.Nil(t, err)
+			}
+			assert.Equal(t, total, quote.Total)
+		})
+	}
+}
+
+func TestTariffNextBoundary(t *testing.T) {
+	var (
+		madrid, _ = time.LoadLocation("Europe/Madrid")
+		night     = Tariff{Rules: Rules{{Name: "Night", From: "22:00", To: "06:00"}}}
+		late      = Tariff{Rules: Rules{{Name: "Late night", From: "02:00", To: "03:00"}}}
+	)
+
+	tests := []struct {
+		name   string
+		tariff Tariff
+		at     string
+		next   string
+	}{
+		{"window opens", night, "2026-10-16T21:00:00+02:00", "2026-10-16T22:00:00+02:00"},
+		{"midnight", night, "2026-10-16T22:00:00+02:00", "2026-10-17T00:00:00+02:00"},
+		{"window closes", night, "2026-10-17T00:00:00+02:00", "2026-10-17T06:00:00+02:00"},
+		{"spring forward", late, "2026-03-29T01:30:00+01:00", "2026-03-29T03:00:00+02:00"},
+		{"fall back", late, "2026-10-25T02:30:00+02:00", "2026-10-25T02:00:00+01:00"},
+		{"after fall back", late, "2026-10-25T02:00:00+01:00", "2026-10-25T03:00:00+01:00"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			at, err := time.Parse(time.RFC3339, test.at)
+			assert.Nil(t, err)
+			next, err := time.Parse(time.RFC3339, test.next)
+			assert.Nil(t, err)
+
+			assert.True(t, next.Equal(test.tariff.nextBoundary(at, madrid)), "got %s", test.tariff.nextBoundary(at, madrid))
+		})
+	}
+
+	assert.True(t, Tariff{}.nextBoundary(time.Now(), madrid).IsZero())
+}
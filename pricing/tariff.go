@@ -0,0 +1,137 @@
+package pricing
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"backend/money"
+)
+
+// Tariff is the set of prices a ride is billed with, in the minor unit of
+// its Currency. Rides keep a copy of the tariff in effect when they
+// started, so later price changes don't affect them.
+type Tariff struct {
+	Version           string         `json:"version"`
+	Currency          money.Currency `json:"currency"`
+	UnlockPrice       int            `json:"unlock_price"`
+	MinutePrice       int            `json:"minute_price"`
+	PausedMinutePrice int            `json:"paused_minute_price"`
+	Billing           Billing        `json:"billing"`
+	Caps              Caps           `json:"caps"`
+	Prepaid           Prepaid        `json:"prepaid"`
+	TimeZone          string         `json:"time_zone,omitempty"` // IANA name, UTC when empty
+	Rules             Rules          `json:"rules,omitempty"`
+}
+
+// Location is the time zone the rule windows are in.
+func (t Tariff) Location() *time.Location {
+	location, err := time.LoadLocation(t.TimeZone)
+	if err != nil {
+		return time.UTC
+	}
+	return location
+}
+
+// Hold is what a ride on the tariff is authorized for when it starts: its
+// maximum charge with the tax of the given rate, or fallback, in the minor
+// unit of the tariff, when its billing has none.
+func (t Tariff) Hold(rate TaxRate, fallback int) (money.Money, error) {
+	if t.Billing.MaximumCharge == 0 {
+		return t.price(fallback), nil
+	}
+	maximum := t.price(t.Billing.MaximumCharge)
+	tax, err := rate.of(maximum)
+	if err != nil {
+		return money.Money{}, err
+	}
+	return maximum.Add(tax)
+}
+
+// price is an amount of the tariff as money in its currency.
+func (t Tariff) price(amount int) money.Money {
+	return money.New(int64(amount), t.Currency)
+}
+
+// ruleAt returns the first rule open at the given instant, if any.
+func (t Tariff) ruleAt(at time.Time, location *time.Location) *Rule {
+	local := at.In(location)
+	for i := range t.Rules {
+		if t.Rules[i].matches(local) {
+			return &t.Rules[i]
+		}
+	}
+	return nil
+}
+
+// nextBoundary returns the first instant after at when the open rules may
+// change: a window opening or closing, midnight, or the time zone changing
+// its offset. It returns the zero time when the tariff has no rules.
+func (t Tariff) nextBoundary(at time.Time, location *time.Location) time.Time {
+	if len(t.Rules) == 0 {
+		return time.Time{}
+	}
+
+	clocks := []int{0}
+	for _, rule := range t.Rules {
+		from, _ := rule.From.minutes()
+		to, _ := rule.To.minutes()
+		clocks = append(clocks, from, to)
+	}
+
+	var (
+		local = at.In(location)
+		next  time.Time
+	)
+	for day := 0; day <= 1; day++ {
+		for _, clock := range clocks {
+			boundary := time.Date(local.Year(), local.Month(), local.Day()+day, clock/60, clock%60, 0, 0, location)
+			if boundary.After(at) && (next.IsZero() || boundary.Before(next)) {
+				next = boundary
+			}
+		}
+	}
+
+	// Wall clock times are ambiguous around an offset change, so the change
+	// itself is a boundary too.
+	if _, offset := local.Zone(); offset != zoneOffset(next, location) {
+		from := at
+		for next.Sub(from) > time.Second {
+			middle := from.Add(next.Sub(from) / 2)
+			if zoneOffset(middle, location) == offset {
+				from = middle
+			} else {
+				next = middle
+			}
+		}
+	}
+
+	return next
+}
+
+func zoneOffset(at time.Time, location *time.Location) int {
+	_, offset := at.In(location).Zone()
+	return offset
+}
+
+// Value stores the tariff as a JSON document.
+func (t Tariff) Value() (driver.Value, error) {
+	value, err := json.Marshal(t)
+	return string(value), err
+}
+
+// Scan reads a tariff stored by Value.
+func (t *Tariff) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*t = Tariff{}
+		return nil
+	case []byte:
+		return json.Unmarshal(v, t)
+	case string:
+		return json.Unmarshal([]byte(v), t)
+	}
+
+	return errors.New("pricing: cannot scan tariff")
+}
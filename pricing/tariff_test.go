@@ -0,0 +1,51 @@
+package pricing
+
+import (
+	"testing"
+
+	"backend/money"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTariffValueScan(t *testing.T) {
+	value, err := tariff.Value()
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"version":"1","currency":"EUR","unlock_price":18,"minute_price":100,"paused_minute_price":25,"billing":{"unit":"minute","rounding":"ceil"},"caps":{},"prepaid":{}}`, value.(string))
+
+	var scanned Tariff
+	assert.Nil(t, scanned.Scan([]byte(value.(string))))
+	assert.Equal(t, tariff, scanned)
+
+	scanned = Tariff{}
+	assert.Nil(t, scanned.Scan(value))
+	assert.Equal(t, tariff, scanned)
+
+	assert.Nil(t, scanned.Scan(nil))
+	assert.Equal(t, Tariff{}, scanned)
+
+	assert.NotNil(t, scanned.Scan(42))
+}
+
+func TestTariffHold(t *testing.T) {
+	var (
+		vat    = TaxRate{Name: "VAT", Rate: 2100, Rounding: TaxRoundingPerTotal}
+		capped = Tariff{Currency: "EUR", Billing: Billing{MaximumCharge: 1000}}
+	)
+
+	tests := []struct {
+		tariff Tariff
+		rate   TaxRate
+		hold   money.Money
+	}{
+		{capped, vat, money.New(1210, "EUR")},
+		{capped, TaxRate{}, money.New(1000, "EUR")},
+		{Tariff{Currency: "EUR"}, vat, money.New(3000, "EUR")},
+	}
+
+	for _, test := range tests {
+		hold, err := test.tariff.Hold(test.rate, 3000)
+		assert.Nil(t, err)
+		assert.Equal(t, test.hold, hold)
+	}
+}
@@ -0,0 +1,171 @@
+package pricing
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"strconv"
+
+	"backend/money"
+)
+
+type TaxRounding string
+
+const (
+	// TaxRoundingPerLine rounds the tax of every line item, then adds them
+	// up.
+	TaxRoundingPerLine TaxRounding = "per_line"
+	// TaxRoundingPerTotal rounds the tax of the net total.
+	TaxRoundingPerTotal TaxRounding = "per_total"
+)
+
+// TaxRate is the tax added on top of the net price of a ride, Rate basis
+// points of it, e.g. 2100 for 21%. Amounts are rounded half away from zero
+// to the minor unit, per line or per total. The zero TaxRate adds no tax.
+type TaxRate struct {
+	Name     string      `json:"name,omitempty"`
+	Rate     int         `json:"rate"`
+	Rounding TaxRounding `json:"rounding,omitempty"`
+}
+
+var (
+	ErrTaxRateInvalid     = errors.New("Tax rate must be between 0 and 10000 basis points")
+	ErrTaxRoundingInvalid = errors.New("Tax rounding must be one of: per_line, per_total")
+)
+
+func (t TaxRate) Validate() error {
+	if t.Rate < 0 || t.Rate > 10000 {
+		return ErrTaxRateInvalid
+	}
+	switch t.Rounding {
+	case TaxRoundingPerLine, TaxRoundingPerTotal:
+		return nil
+	}
+	return ErrTaxRoundingInvalid
+}
+
+// Percent writes the rate as a percentage, e.g. "21%" or "5.5%".
+func (t TaxRate) Percent() string {
+	return percent(t.Rate)
+}
+
+// Tax adds the tax on the quote as a tax item. It is the last item a quote
+// gets, so everything before it is the net price.
+func (q *Quote) Tax(rate TaxRate) {
+	if rate.Rate == 0 {
+		return
+	}
+
+	tax, err := q.taxOf(rate)
+	if err != nil {
+		q.fail(err)
+		return
+	}
+
+	description := rate.Name
+	if description == "" {
+		description = "Tax"
+	}
+	q.Add(LineItem{
+		Kind:        KindTax,
+		Description: description + " " + percent(rate.Rate),
+		Quantity:    1,
+		UnitPrice:   tax,
+		Amount:      tax,
+	})
+}
+
+// taxOf is the tax on the items of the quote at the rate, rounded per line
+// or on the total.
+func (q Quote) taxOf(rate TaxRate) (money.Money, error) {
+	if rate.Rounding != TaxRoundingPerLine {
+		return rate.of(q.Total)
+	}
+
+	tax := money.Zero(q.Currency())
+	for _, item := range q.Items {
+		line, err := rate.of(item.Amount)
+		if err != nil {
+			return money.Money{}, err
+		}
+		if tax, err = tax.Add(line); err != nil {
+			return money.Money{}, err
+		}
+	}
+	return tax, nil
+}
+
+// Split breaks an amount the tax is already included in, such as the
+// adjustment of a price, into its net and its tax. The net is rounded half
+// away from zero and the tax is what is left.
+func (t TaxRate) Split(amount money.Money) (net, tax money.Money, err error) {
+	if net, err = amount.Scale(10000, int64(10000+t.Rate)); err != nil {
+		return money.Money{}, money.Money{}, err
+	}
+	if tax, err = amount.Sub(net); err != nil {
+		return money.Money{}, money.Money{}, err
+	}
+	return net, tax, nil
+}
+
+// Breakdown splits the total of the quote into its net price and its tax.
+// It fails with the error that stopped the quote, if any, so a quote that
+// is broken down can be charged.
+func (q Quote) Breakdown() (net, tax money.Money, err error) {
+	if q.err != nil {
+		return money.Money{}, money.Money{}, q.err
+	}
+
+	tax = money.Zero(q.Currency())
+	for _, item := range q.Items {
+		if item.Kind != KindTax {
+			continue
+		}
+		if tax, err = tax.Add(item.Amount); err != nil {
+			return money.Money{}, money.Money{}, err
+		}
+	}
+	if net, err = q.Total.Sub(tax); err != nil {
+		return money.Money{}, money.Money{}, err
+	}
+	return net, tax, nil
+}
+
+// of is the tax on the given amount, rounded half away from zero.
+func (t TaxRate) of(amount money.Money) (money.Money, error) {
+	return amount.Scale(int64(t.Rate), 10000)
+}
+
+// percent writes basis points as a percentage, e.g. "21%" or "5.5%".
+func percent(basisPoints int) string {
+	whole, fraction := basisPoints/100, basisPoints%100
+	if fraction == 0 {
+		return strconv.Itoa(whole) + "%"
+	}
+	decimals := strconv.Itoa(fraction + 100)[1:]
+	if decimals[1] == '0' {
+		decimals = decimals[:1]
+	}
+	return strconv.Itoa(whole) + "." + decimals + "%"
+}
+
+// Value stores the tax rate as a JSON document.
+func (t TaxRate) Value() (driver.Value, error) {
+	value, err := json.Marshal(t)
+	return string(value), err
+}
+
+// Scan reads a tax rate stored by Value.
+func (t *TaxRate) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*t = TaxRate{}
+		return nil
+	case []byte:
+		return json.Unmarshal(v, t)
+	case string:
+		return json.Unmarshal([]byte(v), t)
+	}
+
+	return errors.New("pricing: cannot scan tax rate")
+}
@@ -0,0 +1,105 @@
+package pricing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTaxRateValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		rate TaxRate
+		err  error
+	}{
+		{"per line", TaxRate{Name: "VAT", Rate: 2100, Rounding: TaxRoundingPerLine}, nil},
+		{"per total", TaxRate{Name: "VAT", Rate: 550, Rounding: TaxRoundingPerTotal}, nil},
+		{"negative", TaxRate{Rate: -1, Rounding: TaxRoundingPerLine}, ErrTaxRateInvalid},
+		{"over 100%", TaxRate{Rate: 10001, Rounding: TaxRoundingPerLine}, ErrTaxRateInvalid},
+		{"rounding", TaxRate{Rate: 2100}, ErrTaxRoundingInvalid},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.err, test.rate.Validate())
+		})
+	}
+}
+
+func TestQuoteTax(t *testing.T) {
+	// 18 unlock + 6*100 riding + 4*25 paused = 718 net.
+	tests := []struct {
+		name string
+		rate TaxRate
+		item LineItem
+	}{
+		{"per total", TaxRate{Name: "VAT", Rate: 2100, Rounding: TaxRoundingPerTotal}, LineItem{Kind: KindTax, Description: "VAT 21%", Quantity: 1, UnitPrice: eur(151), Amount: eur(151)}},
+		// 3.78 rounds up to 4, 126 and 21 are exact: 151 too.
+		{"per line", TaxRate{Name: "VAT", Rate: 2100, Rounding: TaxRoundingPerLine}, LineItem{Kind: KindTax, Description: "VAT 21%", Quantity: 1, UnitPrice: eur(151), Amount: eur(151)}},
+		// 0.99 + 33 + 5.5 = 40.49 per total, 1 + 33 + 6 per line.
+		{"per total, fraction", TaxRate{Rate: 550, Rounding: TaxRoundingPerTotal}, LineItem{Kind: KindTax, Description: "Tax 5.5%", Quantity: 1, UnitPrice: eur(39), Amount: eur(39)}},
+		{"per line, fraction", TaxRate{Rate: 550, Rounding: TaxRoundingPerLine}, LineItem{Kind: KindTax, Description: "Tax 5.5%", Quantity: 1, UnitPrice: eur(40), Amount: eur(40)}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			quote := Flat{}.Quote(tariff, ride(6*time.Minute, 4*time.Minute))
+			quote.Tax(test.rate)
+
+			assert.Equal(t, test.item, quote.Items[len(quote.Items)-1])
+			net, tax, err := quote.Breakdown()
+			assert.Nil(t, err)
+			assert.Equal(t, eur(718), net)
+			assert.Equal(t, test.item.Amount, tax)
+			assert.Equal(t, eur(718+test.item.Amount.Amount), quote.Total)
+		})
+	}
+}
+
+func TestQuoteTaxOnDiscount(t *testing.T) {
+	quote := Flat{}.Quote(tariff, ride(6*time.Minute, 0))
+	quote.takeOff(eur(105), KindDiscount, "Promo code SPRING")
+	quote.Tax(TaxRate{Rate: 1000, Rounding: TaxRoundingPerLine})
+
+	// 1.8 + 60 - 10.5: 2 + 60 - 11.
+	net, tax, err := quote.Breakdown()
+	assert.Nil(t, err)
+	assert.Equal(t, eur(513), net)
+	assert.Equal(t, eur(51), tax)
+}
+
+func TestQuoteWithoutTax(t *testing.T) {
+	quote := Flat{}.Quote(tariff, ride(6*time.Minute, 0))
+	quote.Tax(TaxRate{})
+
+	assert.Len(t, quote.Items, 2)
+	net, tax, err := quote.Breakdown()
+	assert.Nil(t, err)
+	assert.Equal(t, eur(618), net)
+	assert.Equal(t, eur(0), tax)
+}
+
+func TestTaxRateSplit(t *testing.T) {
+	vat := TaxRate{Name: "VAT", Rate: 2100, Rounding: TaxRoundingPerTotal}
+
+	tests := []struct {
+		rate     TaxRate
+		amount   int64
+		net, tax int64
+	}{
+		{vat, 121, 100, 21},
+		{vat, -121, -100, -21},
+		{vat, 100, 83, 17},
+		{vat, -100, -83, -17},
+		{TaxRate{Rate: 550}, 211, 200, 11},
+		{TaxRate{}, 118, 118, 0},
+	}
+
+	for _, test := range tests {
+		net, tax, err := test.rate.Split(eur(test.amount))
+		assert.Nil(t, err)
+		assert.Equal(t, eur(test.net), net, test.amount)
+		assert.Equal(t, eur(test.tax), tax, test.amount)
+	}
+}
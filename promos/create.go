@@ -0,0 +1,36 @@
+package promos
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/go-rel/rel"
+)
+
+type createPromo struct {
+	repository rel.Repository
+}
+
+// CreatePromo adds a promo code. A promo without StartsAt can be redeemed
+// right away.
+func (c createPromo) CreatePromo(ctx context.Context, promo *Promo, now time.Time) (error, *Promo) {
+	promo.Code = normalizeCode(promo.Code)
+	promo.Redemptions = 0
+	if promo.StartsAt.IsZero() {
+		promo.StartsAt = now
+	}
+
+	if err := promo.Validate(); err != nil {
+		return err, nil
+	}
+
+	if err := c.repository.Insert(ctx, promo); err != nil {
+		if errors.Is(err, rel.ErrUniqueConstraint) {
+			return ErrPromoCodeTaken, nil
+		}
+		return err, nil
+	}
+
+	return nil, promo
+}
@@ -0,0 +1,64 @@
+package promos
+
+import (
+	"context"
+	"testing"
+
+	"backend/pricing"
+
+	"github.com/go-rel/rel"
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreatePromo(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository)
+		promo      = Promo{Code: " welcome ", Kind: pricing.DiscountFreeMinutes, Minutes: 10, MaxRedemptionsPerUser: 1, Redemptions: 5}
+	)
+
+	repository.ExpectInsert().For(&promo)
+
+	err, savedPromo := service.CreatePromo(ctx, &promo, now)
+	assert.Nil(t, err)
+	assert.NotEmpty(t, savedPromo.ID)
+	assert.Equal(t, "WELCOME", savedPromo.Code)
+	assert.Equal(t, now, savedPromo.StartsAt)
+	assert.Equal(t, 0, savedPromo.Redemptions)
+
+	repository.AssertExpectations(t)
+}
+
+func TestCreatePromoInvalid(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository)
+		promo      = Promo{Code: "WELCOME", Kind: pricing.DiscountAmountOff, Amount: 500}
+	)
+
+	err, savedPromo := service.CreatePromo(ctx, &promo, now)
+	assert.Equal(t, pricing.ErrDiscountInvalid, err)
+	assert.Nil(t, savedPromo)
+
+	repository.AssertExpectations(t)
+}
+
+func TestCreatePromoCodeTaken(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository)
+		promo      = Promo{Code: "SPRING", Kind: pricing.DiscountFreeUnlock}
+	)
+
+	repository.ExpectInsert().For(&promo).Error(rel.ErrUniqueConstraint)
+
+	err, savedPromo := service.CreatePromo(ctx, &promo, now)
+	assert.Equal(t, ErrPromoCodeTaken, err)
+	assert.Nil(t, savedPromo)
+
+	repository.AssertExpectations(t)
+}
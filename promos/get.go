@@ -0,0 +1,39 @@
+package promos
+
+import (
+	"context"
+	"errors"
+
+	"github.com/go-rel/rel"
+	"github.com/go-rel/rel/where"
+)
+
+type getPromo struct {
+	repository rel.Repository
+}
+
+func (c getPromo) GetPromo(ctx context.Context, code string) (error, *Promo) {
+	var promo Promo
+	if err := c.repository.Find(ctx, &promo, where.Eq("code", normalizeCode(code))); err != nil {
+		if errors.Is(err, rel.ErrNotFound) {
+			return ErrPromoNotFound, nil
+		}
+		return err, nil
+	}
+
+	return nil, &promo
+}
+
+type listPromos struct {
+	repository rel.Repository
+}
+
+// ListPromos returns every promo, the latest to start first.
+func (c listPromos) ListPromos(ctx context.Context) (error, []Promo) {
+	promos := []Promo{}
+	if err := c.repository.FindAll(ctx, &promos, rel.From("promos").SortDesc("starts_at", "id")); err != nil {
+		return err, nil
+	}
+
+	return nil, promos
+}
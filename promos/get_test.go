@@ -0,0 +1,59 @@
+package promos
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-rel/rel"
+	"github.com/go-rel/rel/where"
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetPromo(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository)
+	)
+
+	repository.ExpectFind(where.Eq("code", "SPRING")).Result(promo)
+
+	err, foundPromo := service.GetPromo(ctx, "spring")
+	assert.Nil(t, err)
+	assert.Equal(t, promo, *foundPromo)
+
+	repository.AssertExpectations(t)
+}
+
+func TestGetPromoNotFound(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository)
+	)
+
+	repository.ExpectFind(where.Eq("code", "WINTER")).NotFound()
+
+	err, foundPromo := service.GetPromo(ctx, "WINTER")
+	assert.Equal(t, ErrPromoNotFound, err)
+	assert.Nil(t, foundPromo)
+
+	repository.AssertExpectations(t)
+}
+
+func TestListPromos(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository)
+	)
+
+	repository.ExpectFindAll(rel.From("promos").SortDesc("starts_at", "id")).Result([]Promo{promo})
+
+	err, promos := service.ListPromos(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, []Promo{promo}, promos)
+
+	repository.AssertExpectations(t)
+}
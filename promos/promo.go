@@ -0,0 +1,91 @@
+package promos
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"backend/money"
+	"backend/pricing"
+)
+
+// Promo is a code that takes a discount off the price of the rides started
+// with it between StartsAt and EndsAt, or indefinitely when EndsAt is nil.
+// It can be redeemed MaxRedemptions times in total and MaxRedemptionsPerUser
+// times by each user, without limit when zero.
+type Promo struct {
+	ID                    uint                 `json:"id"`
+	CreatedAt             time.Time            `json:"created_at"`
+	UpdatedAt             time.Time            `json:"updated_at"`
+	Code                  string               `json:"code"`
+	Kind                  pricing.DiscountKind `json:"kind"`
+	Percent               int                  `json:"percent"`
+	Minutes               int                  `json:"minutes"`
+	Amount                int                  `json:"amount"`
+	Currency              money.Currency       `json:"currency"`
+	StartsAt              time.Time            `json:"starts_at"`
+	EndsAt                *time.Time           `json:"ends_at"`
+	MaxRedemptions        int                  `json:"max_redemptions"`
+	MaxRedemptionsPerUser int                  `json:"max_redemptions_per_user"`
+	Redemptions           int                  `json:"redemptions"`
+}
+
+// Redemption is the use of a promo code by a ride.
+type Redemption struct {
+	ID        uint      `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	PromoID   uint      `json:"promo_id"`
+	UserID    string    `json:"user_id"`
+	RideID    uint      `json:"ride_id"`
+}
+
+func (Redemption) Table() string {
+	return "promo_redemptions"
+}
+
+var (
+	ErrPromoCodeBlank        = errors.New("Code can't be blank")
+	ErrPromoLimitNegative    = errors.New("Redemption limits can't be negative")
+	ErrPromoEndsAtInvalid    = errors.New("EndsAt must be after StartsAt")
+	ErrPromoCodeTaken        = errors.New("Another promo already uses this code")
+	ErrPromoNotFound         = errors.New("No promo matches the given code")
+	ErrPromoNotActive        = errors.New("The promo code is not valid at this time")
+	ErrPromoExhausted        = errors.New("The promo code has been redeemed too many times")
+	ErrPromoUserLimitReached = errors.New("The promo code has been redeemed too many times by this user")
+)
+
+func (p Promo) Validate() error {
+	switch {
+	case p.Code == "":
+		return ErrPromoCodeBlank
+	case p.MaxRedemptions < 0 || p.MaxRedemptionsPerUser < 0:
+		return ErrPromoLimitNegative
+	case p.EndsAt != nil && !p.EndsAt.After(p.StartsAt):
+		return ErrPromoEndsAtInvalid
+	}
+
+	return p.Discount().Validate()
+}
+
+// Active reports whether the code can be redeemed at the given instant.
+func (p Promo) Active(at time.Time) bool {
+	return !p.StartsAt.After(at) && (p.EndsAt == nil || p.EndsAt.After(at))
+}
+
+// Discount is the copy of the discount stored on the rides started with
+// this code.
+func (p Promo) Discount() pricing.Discount {
+	return pricing.Discount{
+		Code:     p.Code,
+		Kind:     p.Kind,
+		Percent:  p.Percent,
+		Minutes:  p.Minutes,
+		Amount:   p.Amount,
+		Currency: p.Currency,
+	}
+}
+
+// normalizeCode makes codes case-insensitive.
+func normalizeCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
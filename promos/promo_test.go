@@ -0,0 +1,68 @@
+package promos
+
+import (
+	"testing"
+	"time"
+
+	"backend/pricing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	now       = time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	yesterday = now.Add(-24 * time.Hour)
+	tomorrow  = now.Add(24 * time.Hour)
+	promo     = Promo{
+		ID:                    1,
+		Code:                  "SPRING",
+		Kind:                  pricing.DiscountPercentOff,
+		Percent:               20,
+		StartsAt:              yesterday,
+		EndsAt:                &tomorrow,
+		MaxRedemptions:        100,
+		MaxRedemptionsPerUser: 1,
+		Redemptions:           10,
+	}
+)
+
+func TestPromoValidation(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(*Promo)
+		err    error
+	}{
+		{"valid", func(p *Promo) {}, nil},
+		{"code blank", func(p *Promo) { p.Code = "" }, ErrPromoCodeBlank},
+		{"limit negative", func(p *Promo) { p.MaxRedemptions = -1 }, ErrPromoLimitNegative},
+		{"user limit negative", func(p *Promo) { p.MaxRedemptionsPerUser = -1 }, ErrPromoLimitNegative},
+		{"unlimited", func(p *Promo) { p.MaxRedemptions, p.MaxRedemptionsPerUser = 0, 0 }, nil},
+		{"ends before it starts", func(p *Promo) { p.EndsAt = &yesterday; p.StartsAt = now }, ErrPromoEndsAtInvalid},
+		{"never ends", func(p *Promo) { p.EndsAt = nil }, nil},
+		{"kind unknown", func(p *Promo) { p.Kind = "free_ride" }, pricing.ErrDiscountKindInvalid},
+		{"discount invalid", func(p *Promo) { p.Percent = 0 }, pricing.ErrDiscountInvalid},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			changed := promo
+			test.change(&changed)
+			assert.Equal(t, test.err, changed.Validate())
+		})
+	}
+}
+
+func TestPromoActive(t *testing.T) {
+	assert.False(t, promo.Active(yesterday.Add(-time.Second)))
+	assert.True(t, promo.Active(yesterday))
+	assert.True(t, promo.Active(now))
+	assert.False(t, promo.Active(tomorrow))
+
+	endless := promo
+	endless.EndsAt = nil
+	assert.True(t, endless.Active(tomorrow.Add(365*24*time.Hour)))
+}
+
+func TestPromoDiscount(t *testing.T) {
+	assert.Equal(t, pricing.Discount{Code: "SPRING", Kind: pricing.DiscountPercentOff, Percent: 20}, promo.Discount())
+}
@@ -0,0 +1,114 @@
+package promos
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"backend/pricing"
+
+	"github.com/go-rel/rel"
+	"github.com/go-rel/rel/where"
+)
+
+// Redeemer is the pricing.Promotions backed by the stored promos.
+type Redeemer struct {
+	repository rel.Repository
+}
+
+func NewRedeemer(repository rel.Repository) Redeemer {
+	return Redeemer{repository: repository}
+}
+
+func (r Redeemer) Discount(ctx context.Context, code string, userID string, at time.Time) (pricing.Discount, error) {
+	promo, err := r.redeemable(ctx, code, userID, at)
+	if err != nil {
+		return pricing.Discount{}, err
+	}
+	if promo.MaxRedemptions > 0 && promo.Redemptions >= promo.MaxRedemptions {
+		return pricing.Discount{}, ErrPromoExhausted
+	}
+
+	return promo.Discount(), nil
+}
+
+// Redeem counts the redemption against the total limit in the same
+// statement that checks it, so concurrent starts can't take more
+// redemptions than there are left. The per-user limit needs no such care:
+// a user only has one open ride at a time.
+func (r Redeemer) Redeem(ctx context.Context, code string, userID string, rideID uint, at time.Time) error {
+	promo, err := r.redeemable(ctx, code, userID, at)
+	if err != nil {
+		return err
+	}
+
+	updated, err := r.repository.UpdateAny(ctx,
+		rel.From("promos").Where(
+			where.Eq("id", promo.ID).And(
+				where.Eq("max_redemptions", 0).Or(where.Fragment("redemptions < max_redemptions")),
+			),
+		),
+		rel.Inc("redemptions"),
+	)
+	if err != nil {
+		return err
+	}
+	if updated == 0 {
+		return ErrPromoExhausted
+	}
+
+	return r.repository.Insert(ctx, &Redemption{
+		CreatedAt: at,
+		PromoID:   promo.ID,
+		UserID:    userID,
+		RideID:    rideID,
+	})
+}
+
+// Release gives the redemption of a ride back to the total limit of its
+// promo and to the user. The ride is claimed by its cancellation, so it is
+// only released once.
+func (r Redeemer) Release(ctx context.Context, rideID uint) error {
+	var redemption Redemption
+	if err := r.repository.Find(ctx, &redemption, where.Eq("ride_id", rideID)); err != nil {
+		if errors.Is(err, rel.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	if err := r.repository.Delete(ctx, &redemption); err != nil {
+		return err
+	}
+	_, err := r.repository.UpdateAny(ctx,
+		rel.From("promos").Where(where.Eq("id", redemption.PromoID).AndGt("redemptions", 0)),
+		rel.Dec("redemptions"),
+	)
+	return err
+}
+
+// redeemable returns the promo of a code the user can redeem at the given
+// instant, without looking at its total limit.
+func (r Redeemer) redeemable(ctx context.Context, code string, userID string, at time.Time) (*Promo, error) {
+	err, promo := getPromo{repository: r.repository}.GetPromo(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	if !promo.Active(at) {
+		return nil, ErrPromoNotActive
+	}
+
+	if promo.MaxRedemptionsPerUser > 0 {
+		count, err := r.repository.Count(ctx, "promo_redemptions",
+			where.Eq("promo_id", promo.ID).AndEq("user_id", userID),
+		)
+		if err != nil {
+			return nil, err
+		}
+		if count >= promo.MaxRedemptionsPerUser {
+			return nil, ErrPromoUserLimitReached
+		}
+	}
+
+	return promo, nil
+}
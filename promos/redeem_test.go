@@ -0,0 +1,166 @@
+package promos
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"backend/pricing"
+
+	"github.com/go-rel/rel"
+	"github.com/go-rel/rel/where"
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiscount(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		redeemer   = NewRedeemer(repository)
+	)
+
+	repository.ExpectFind(where.Eq("code", "SPRING")).Result(promo)
+	repository.ExpectCount("promo_redemptions", where.Eq("promo_id", promo.ID).AndEq("user_id", "1")).Result(0)
+
+	discount, err := redeemer.Discount(ctx, "spring", "1", now)
+	assert.Nil(t, err)
+	assert.Equal(t, pricing.Discount{Code: "SPRING", Kind: pricing.DiscountPercentOff, Percent: 20}, discount)
+
+	repository.AssertExpectations(t)
+}
+
+func TestDiscountNotRedeemable(t *testing.T) {
+	exhausted := promo
+	exhausted.Redemptions = exhausted.MaxRedemptions
+
+	tests := []struct {
+		name  string
+		at    time.Time
+		promo Promo
+		count int
+		err   error
+	}{
+		{"not started", yesterday.Add(-1), promo, -1, ErrPromoNotActive},
+		{"ended", tomorrow, promo, -1, ErrPromoNotActive},
+		{"user limit reached", now, promo, 1, ErrPromoUserLimitReached},
+		{"exhausted", now, exhausted, 0, ErrPromoExhausted},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				ctx        = context.TODO()
+				repository = reltest.New()
+				redeemer   = NewRedeemer(repository)
+			)
+
+			repository.ExpectFind(where.Eq("code", "SPRING")).Result(test.promo)
+			if test.count >= 0 {
+				repository.ExpectCount("promo_redemptions", where.Eq("promo_id", promo.ID).AndEq("user_id", "1")).Result(test.count)
+			}
+
+			_, err := redeemer.Discount(ctx, "SPRING", "1", test.at)
+			assert.Equal(t, test.err, err)
+
+			repository.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDiscountNotFound(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		redeemer   = NewRedeemer(repository)
+	)
+
+	repository.ExpectFind(where.Eq("code", "WINTER")).NotFound()
+
+	_, err := redeemer.Discount(ctx, "WINTER", "1", now)
+	assert.Equal(t, ErrPromoNotFound, err)
+
+	repository.AssertExpectations(t)
+}
+
+func TestRedeem(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		redeemer   = NewRedeemer(repository)
+	)
+
+	repository.ExpectFind(where.Eq("code", "SPRING")).Result(promo)
+	repository.ExpectCount("promo_redemptions", where.Eq("promo_id", promo.ID).AndEq("user_id", "1")).Result(0)
+	repository.ExpectUpdateAny(
+		rel.From("promos").Where(
+			where.Eq("id", promo.ID).And(
+				where.Eq("max_redemptions", 0).Or(where.Fragment("redemptions < max_redemptions")),
+			),
+		),
+		rel.Inc("redemptions"),
+	).UpdatedCount(1)
+	repository.ExpectInsert().For(&Redemption{CreatedAt: now, PromoID: promo.ID, UserID: "1", RideID: 7})
+
+	assert.Nil(t, redeemer.Redeem(ctx, "SPRING", "1", 7, now))
+
+	repository.AssertExpectations(t)
+}
+
+func TestRedeemExhaustedConcurrently(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		redeemer   = NewRedeemer(repository)
+	)
+
+	// Another start took the last redemption after this one was validated.
+	repository.ExpectFind(where.Eq("code", "SPRING")).Result(promo)
+	repository.ExpectCount("promo_redemptions", where.Eq("promo_id", promo.ID).AndEq("user_id", "1")).Result(0)
+	repository.ExpectUpdateAny(
+		rel.From("promos").Where(
+			where.Eq("id", promo.ID).And(
+				where.Eq("max_redemptions", 0).Or(where.Fragment("redemptions < max_redemptions")),
+			),
+		),
+		rel.Inc("redemptions"),
+	).UpdatedCount(0)
+
+	assert.Equal(t, ErrPromoExhausted, redeemer.Redeem(ctx, "SPRING", "1", 7, now))
+
+	repository.AssertExpectations(t)
+}
+
+func TestRelease(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		redeemer   = NewRedeemer(repository)
+		redemption = Redemption{ID: 3, CreatedAt: now, PromoID: promo.ID, UserID: "1", RideID: 7}
+	)
+
+	repository.ExpectFind(where.Eq("ride_id", uint(7))).Result(redemption)
+	repository.ExpectDelete().For(&redemption)
+	repository.ExpectUpdateAny(
+		rel.From("promos").Where(where.Eq("id", promo.ID).AndGt("redemptions", 0)),
+		rel.Dec("redemptions"),
+	).UpdatedCount(1)
+
+	assert.Nil(t, redeemer.Release(ctx, 7))
+
+	repository.AssertExpectations(t)
+}
+
+func TestReleaseNothingRedeemed(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		redeemer   = NewRedeemer(repository)
+	)
+
+	repository.ExpectFind(where.Eq("ride_id", uint(7))).NotFound()
+
+	assert.Nil(t, redeemer.Release(ctx, 7))
+
+	repository.AssertExpectations(t)
+}
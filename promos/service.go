@@ -0,0 +1,28 @@
+package promos
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-rel/rel"
+)
+
+type Service interface {
+	CreatePromo(ctx context.Context, promo *Promo, now time.Time) (error, *Promo)
+	GetPromo(ctx context.Context, code string) (error, *Promo)
+	ListPromos(ctx context.Context) (error, []Promo)
+}
+
+type service struct {
+	createPromo
+	getPromo
+	listPromos
+}
+
+func New(repository rel.Repository) Service {
+	return service{
+		createPromo: createPromo{repository: repository},
+		getPromo:    getPromo{repository: repository},
+		listPromos:  listPromos{repository: repository},
+	}
+}
@@ -0,0 +1,182 @@
+package rides
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"backend/audit"
+	"backend/payments"
+
+	"github.com/go-rel/rel"
+	"github.com/go-rel/rel/where"
+)
+
+type adjustRide struct {
+	repository rel.Repository
+	payments   payments.Provider
+	wallets    payments.Wallets
+}
+
+// AdjustRide records the adjustment of the price of a finished ride and its
+// audit record. Prepaid rides get it settled with the wallet of the user in
+// the same transaction. Rides paid by card get it refunded or charged once
+// it is recorded, and it stays pending when the provider fails, for
+// SettleAdjustment to retry. The adjustment defaults to the currency of the
+// ride.
+func (c adjustRide) AdjustRide(ctx context.Context, ride *Ride, adjustment *Adjustment, now time.Time) (error, *Adjustment) {
+	if !ride.Status.Closed() {
+		return ErrRideNotFinished, nil
+	}
+	if adjustment.Currency == "" {
+		adjustment.Currency = ride.Price.Currency
+	}
+	if err := adjustment.Validate(ride); err != nil {
+		return err, nil
+	}
+
+	adjustment.RideID = ride.ID
+	adjustment.CreatedAt = now
+	byCard := paidByCard(ride)
+	if byCard {
+		adjustment.Status = AdjustmentPending
+	} else {
+		adjustment.Status = AdjustmentSettled
+		adjustment.SettledAt = &now
+	}
+	floor, err := ride.Price.Add(adjustment.Money())
+	if err != nil {
+		return err, nil
+	}
+	adjustments, err := ride.Adjustments().Add(adjustment.Money())
+	if err != nil {
+		return err, nil
+	}
+	err = c.repository.Transaction(ctx, func(ctx context.Context) error {
+		// The sum of the adjustments is checked in the statement that
+		// updates it, so concurrent refunds can't take the ride below zero.
+		updated, err := c.repository.UpdateAny(ctx,
+			rel.From("rides").Where(
+				where.Eq("id", ride.ID).
+					AndIn("status", closedStatuses...).
+					AndGte("adjustments_amount", floor.Neg().Amount),
+			),
+			rel.IncBy("adjustments_amount", int(adjustment.Amount)),
+			rel.Set("updated_at", now),
+		)
+		if err != nil {
+			return err
+		}
+		if updated == 0 {
+			return ErrAdjustmentExceedsPrice
+		}
+
+		if err := c.repository.Insert(ctx, adjustment); err != nil {
+			return err
+		}
+
+		if err := c.repository.Insert(ctx, &audit.Record{
+			CreatedAt: now,
+			Actor:     agentActor(adjustment.Agent),
+			Action:    "ride.adjusted",
+			Subject:   "ride:" + strconv.FormatUint(uint64(ride.ID), 10),
+			Details: audit.Details{
+				"adjustment_id": adjustment.ID,
+				"amount":        adjustment.Amount,
+				"currency":      adjustment.Currency,
+				"reason":        adjustment.Reason,
+				"note":          adjustment.Note,
+			},
+		}); err != nil {
+			return err
+		}
+
+		if ride.PaymentStatus != payments.StatusDebited {
+			return nil
+		}
+		return c.wallets.AdjustRide(ctx, ride.UserID, ride.ID, adjustment.Money(), string(adjustment.Reason), now)
+	})
+	if err != nil {
+		return err, nil
+	}
+
+	ride.AdjustmentsAmount = adjustments.Amount
+	ride.UpdatedAt = now
+
+	// The adjustment is recorded whether or not its money can move now.
+	// When it can't, it stays pending for SettleAdjustment to retry, so the
+	// error is left to that retry.
+	if byCard {
+		_ = c.settle(ctx, ride, adjustment, now)
+	}
+
+	return nil, adjustment
+}
+
+// SettleAdjustment retries refunding or charging a pending adjustment of a
+// ride paid by card.
+func (c adjustRide) SettleAdjustment(ctx context.Context, ride *Ride, adjustmentID uint, now time.Time) (error, *Adjustment) {
+	var adjustment Adjustment
+	if err := c.repository.Find(ctx, &adjustment, where.Eq("id", adjustmentID).AndEq("ride_id", ride.ID)); err != nil {
+		if errors.Is(err, rel.ErrNotFound) {
+			return ErrAdjustmentNotFound, nil
+		}
+		return err, nil
+	}
+	if adjustment.Status != AdjustmentPending {
+		return ErrAdjustmentNotPending, nil
+	}
+
+	if err := c.settle(ctx, ride, &adjustment, now); err != nil {
+		return err, nil
+	}
+
+	return nil, &adjustment
+}
+
+// settle refunds or charges the adjustment on the card of the user, keyed
+// by the adjustment so that a retry doesn't move its money twice.
+func (c adjustRide) settle(ctx context.Context, ride *Ride, adjustment *Adjustment, now time.Time) error {
+	var err error
+	if adjustment.Amount < 0 {
+		err = c.payments.Refund(ctx, ride.PaymentID, adjustment.Money().Neg(), adjustment.key())
+	} else {
+		err = c.payments.Charge(ctx, ride.UserID, adjustment.Money(), adjustment.key())
+	}
+	if err != nil {
+		return err
+	}
+
+	if _, err := c.repository.UpdateAny(ctx,
+		rel.From("ride_adjustments").Where(where.Eq("id", adjustment.ID).AndEq("status", AdjustmentPending)),
+		rel.Set("status", AdjustmentSettled),
+		rel.Set("settled_at", now),
+	); err != nil {
+		return err
+	}
+
+	adjustment.Status = AdjustmentSettled
+	adjustment.SettledAt = &now
+	return nil
+}
+
+// paidByCard reports whether the money of the ride went through the
+// provider rather than a wallet.
+func paidByCard(ride *Ride) bool {
+	return ride.PaymentID != "" && ride.PaymentStatus != payments.StatusDebited
+}
+
+type listAdjustments struct {
+	repository rel.Repository
+}
+
+// ListAdjustments returns the adjustments of the ride, the oldest first.
+func (c listAdjustments) ListAdjustments(ctx context.Context, rideID uint) (error, []Adjustment) {
+	adjustments := []Adjustment{}
+	if err := c.repository.FindAll(ctx, &adjustments, rel.Where(where.Eq("ride_id", rideID)).SortAsc("id")); err != nil {
+		return err, nil
+	}
+
+	return nil, adjustments
+}
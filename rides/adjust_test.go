@@ -0,0 +1,333 @@
+package rides
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"backend/money"
+	"backend/payments"
+
+	"github.com/go-rel/rel"
+	"github.com/go-rel/rel/where"
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+)
+
+// finishedRide is a ride charged 4.18 € with a card on the provider.
+func finishedRide(provider payments.Provider) Ride {
+	id, _ := provider.Authorize(context.TODO(), "1", eur(3000))
+	_ = provider.Capture(context.TODO(), id, eur(418), "ride-1-capture")
+	return Ride{ID: 1, UserID: "1", VehicleID: "1", Price: eur(418), Status: StatusFinished, Tariff: tariff, PaymentID: id, HoldAmount: 3000, PaymentStatus: payments.StatusCaptured}
+}
+
+func expectSettlement(repository *reltest.Repository, adjustmentID uint, now time.Time) {
+	repository.ExpectUpdateAny(
+		rel.From("ride_adjustments").Where(where.Eq("id", adjustmentID).AndEq("status", AdjustmentPending)),
+		rel.Set("status", AdjustmentSettled),
+		rel.Set("settled_at", now),
+	).UpdatedCount(1)
+}
+
+func expectAdjustment(repository *reltest.Repository, ride Ride, amount int64, now time.Time) *reltest.MockUpdateAny {
+	return repository.ExpectUpdateAny(
+		rel.From("rides").Where(
+			where.Eq("id", ride.ID).
+				AndIn("status", closedStatuses...).
+				AndGte("adjustments_amount", -(ride.Price.Amount+amount)),
+		),
+		rel.IncBy("adjustments_amount", int(amount)),
+		rel.Set("updated_at", now),
+	)
+}
+
+func TestAdjustRide(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		provider   = payments.NewFake()
+		service    = newService(repository, func(d *Deps) { d.Payments = provider })
+		now        = time.Now()
+		ride       = finishedRide(provider)
+		adjustment = Adjustment{Amount: -200, Reason: ReasonVehicleIssue, Note: "Brakes failed", Agent: "42"}
+	)
+
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		expectAdjustment(repository, ride, -200, now).UpdatedCount(1)
+		repository.ExpectInsert().For(&adjustment)
+		repository.ExpectInsert().ForType("*audit.Record")
+	})
+	expectSettlement(repository, 1, now)
+
+	err, saved := service.AdjustRide(ctx, &ride, &adjustment, now)
+	assert.Nil(t, err)
+	assert.NotEmpty(t, saved.ID)
+	assert.Equal(t, ride.ID, saved.RideID)
+	assert.Equal(t, money.Currency("EUR"), saved.Currency)
+	assert.Equal(t, AdjustmentSettled, saved.Status)
+	assert.Equal(t, &now, saved.SettledAt)
+	assert.Equal(t, eur(418), ride.Price)
+	effective, err := ride.EffectivePrice()
+	assert.Nil(t, err)
+	assert.Equal(t, eur(218), effective)
+
+	hold, _ := provider.Hold(ride.PaymentID)
+	assert.Equal(t, eur(200), hold.Refunded)
+
+	repository.AssertExpectations(t)
+}
+
+func TestAdjustForceClosedRide(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		provider   = payments.NewFake()
+		service    = newService(repository, func(d *Deps) { d.Payments = provider })
+		now        = time.Now()
+		ride       = finishedRide(provider)
+		adjustment = Adjustment{Amount: -200, Reason: ReasonVehicleIssue, Note: "Battery ran out", Agent: "42"}
+	)
+	ride.Status = StatusForceClosed
+
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		expectAdjustment(repository, ride, -200, now).UpdatedCount(1)
+		repository.ExpectInsert().For(&adjustment)
+		repository.ExpectInsert().ForType("*audit.Record")
+	})
+	expectSettlement(repository, 1, now)
+
+	err, saved := service.AdjustRide(ctx, &ride, &adjustment, now)
+	assert.Nil(t, err)
+	assert.Equal(t, AdjustmentSettled, saved.Status)
+	effective, err := ride.EffectivePrice()
+	assert.Nil(t, err)
+	assert.Equal(t, eur(218), effective)
+
+	repository.AssertExpectations(t)
+}
+
+func TestAdjustRideCharges(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		provider   = payments.NewFake()
+		service    = newService(repository, func(d *Deps) { d.Payments = provider })
+		now        = time.Now()
+		ride       = finishedRide(provider)
+		adjustment = Adjustment{Amount: 100, Reason: ReasonUndercharge, Agent: "42"}
+	)
+
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		expectAdjustment(repository, ride, 100, now).UpdatedCount(1)
+		repository.ExpectInsert().For(&adjustment)
+		repository.ExpectInsert().ForType("*audit.Record")
+	})
+	expectSettlement(repository, 1, now)
+
+	err, saved := service.AdjustRide(ctx, &ride, &adjustment, now)
+	assert.Nil(t, err)
+	assert.Equal(t, AdjustmentSettled, saved.Status)
+	assert.Equal(t, []money.Money{eur(100)}, provider.Charges("1"))
+
+	repository.AssertExpectations(t)
+}
+
+func TestAdjustRideRefundFails(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		provider   = payments.NewFake()
+		service    = newService(repository, func(d *Deps) { d.Payments = provider })
+		now        = time.Now()
+		ride       = finishedRide(provider)
+		adjustment = Adjustment{Amount: -200, Reason: ReasonOvercharge, Agent: "42"}
+	)
+	provider.FailRefunds(errProviderDown)
+
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		expectAdjustment(repository, ride, -200, now).UpdatedCount(1)
+		repository.ExpectInsert().For(&adjustment)
+		repository.ExpectInsert().ForType("*audit.Record")
+	})
+
+	// The adjustment is recorded, and its refund is left for a retry.
+	err, saved := service.AdjustRide(ctx, &ride, &adjustment, now)
+	assert.Nil(t, err)
+	assert.Equal(t, AdjustmentPending, saved.Status)
+	assert.Nil(t, saved.SettledAt)
+
+	hold, _ := provider.Hold(ride.PaymentID)
+	assert.Equal(t, int64(0), hold.Refunded.Amount)
+
+	repository.AssertExpectations(t)
+}
+
+func TestSettleAdjustment(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		provider   = payments.NewFake()
+		service    = newService(repository, func(d *Deps) { d.Payments = provider })
+		now        = time.Now()
+		ride       = finishedRide(provider)
+		adjustment = Adjustment{ID: 3, RideID: 1, Amount: -200, Currency: "EUR", Reason: ReasonOvercharge, Agent: "42", Status: AdjustmentPending}
+	)
+
+	repository.ExpectFind(where.Eq("id", uint(3)).AndEq("ride_id", ride.ID)).Result(adjustment)
+	expectSettlement(repository, 3, now)
+
+	err, settled := service.SettleAdjustment(ctx, &ride, 3, now)
+	assert.Nil(t, err)
+	assert.Equal(t, AdjustmentSettled, settled.Status)
+
+	hold, _ := provider.Hold(ride.PaymentID)
+	assert.Equal(t, eur(200), hold.Refunded)
+
+	repository.AssertExpectations(t)
+}
+
+func TestSettleAdjustmentNotPending(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		provider   = payments.NewFake()
+		service    = newService(repository, func(d *Deps) { d.Payments = provider })
+		now        = time.Now()
+		ride       = finishedRide(provider)
+		adjustment = Adjustment{ID: 3, RideID: 1, Amount: -200, Currency: "EUR", Reason: ReasonOvercharge, Agent: "42", Status: AdjustmentSettled, SettledAt: &now}
+	)
+
+	repository.ExpectFind(where.Eq("id", uint(3)).AndEq("ride_id", ride.ID)).Result(adjustment)
+
+	err, settled := service.SettleAdjustment(ctx, &ride, 3, now)
+	assert.Equal(t, ErrAdjustmentNotPending, err)
+	assert.Nil(t, settled)
+
+	repository.AssertExpectations(t)
+}
+
+func TestSettleAdjustmentNotFound(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		provider   = payments.NewFake()
+		service    = newService(repository, func(d *Deps) { d.Payments = provider })
+		ride       = finishedRide(provider)
+	)
+
+	repository.ExpectFind(where.Eq("id", uint(3)).AndEq("ride_id", ride.ID)).NotFound()
+
+	err, settled := service.SettleAdjustment(ctx, &ride, 3, time.Now())
+	assert.Equal(t, ErrAdjustmentNotFound, err)
+	assert.Nil(t, settled)
+
+	repository.AssertExpectations(t)
+}
+
+func TestAdjustPrepaidRide(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		adjusted   []money.Money
+		service    = newService(repository, func(d *Deps) { d.Wallets = purse{adjusted: &adjusted} })
+		now        = time.Now()
+		ride       = finishedRide(payments.NewFake())
+		adjustment = Adjustment{Amount: 100, Reason: ReasonUndercharge, Agent: "42"}
+	)
+	ride.PaymentID, ride.PaymentStatus = "", payments.StatusDebited
+
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		expectAdjustment(repository, ride, 100, now).UpdatedCount(1)
+		repository.ExpectInsert().For(&adjustment)
+		repository.ExpectInsert().ForType("*audit.Record")
+	})
+
+	err, _ := service.AdjustRide(ctx, &ride, &adjustment, now)
+	assert.Nil(t, err)
+	assert.Equal(t, []money.Money{eur(100)}, adjusted)
+	effective, err := ride.EffectivePrice()
+	assert.Nil(t, err)
+	assert.Equal(t, eur(518), effective)
+
+	repository.AssertExpectations(t)
+}
+
+func TestAdjustRideBelowZero(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = newService(repository)
+		now        = time.Now()
+		ride       = finishedRide(payments.NewFake())
+		adjustment = Adjustment{Amount: -419, Reason: ReasonOvercharge, Agent: "42"}
+	)
+
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		expectAdjustment(repository, ride, -419, now).UpdatedCount(0)
+	})
+
+	err, saved := service.AdjustRide(ctx, &ride, &adjustment, now)
+	assert.Equal(t, ErrAdjustmentExceedsPrice, err)
+	assert.Nil(t, saved)
+	effective, err := ride.EffectivePrice()
+	assert.Nil(t, err)
+	assert.Equal(t, eur(418), effective)
+
+	repository.AssertExpectations(t)
+}
+
+func TestAdjustRideNotFinished(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = newService(repository)
+		ride       = finishedRide(payments.NewFake())
+		adjustment = Adjustment{Amount: -200, Reason: ReasonGoodwill, Agent: "42"}
+	)
+	ride.Status = StatusActive
+
+	err, saved := service.AdjustRide(ctx, &ride, &adjustment, time.Now())
+	assert.Equal(t, ErrRideNotFinished, err)
+	assert.Nil(t, saved)
+
+	repository.AssertExpectations(t)
+}
+
+func TestAdjustmentValidation(t *testing.T) {
+	ride := finishedRide(payments.NewFake())
+
+	tests := []struct {
+		name       string
+		adjustment Adjustment
+		err        error
+	}{
+		{"valid", Adjustment{Amount: -200, Currency: "EUR", Reason: ReasonGoodwill, Agent: "42"}, nil},
+		{"zero", Adjustment{Currency: "EUR", Reason: ReasonGoodwill, Agent: "42"}, ErrAdjustmentAmountZero},
+		{"currency", Adjustment{Amount: -200, Currency: "USD", Reason: ReasonGoodwill, Agent: "42"}, ErrAdjustmentCurrencyMismatch},
+		{"reason", Adjustment{Amount: -200, Currency: "EUR", Reason: "bored", Agent: "42"}, ErrAdjustmentReasonInvalid},
+		{"agent", Adjustment{Amount: -200, Currency: "EUR", Reason: ReasonGoodwill}, ErrAdjustmentAgentBlank},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.err, test.adjustment.Validate(&ride))
+		})
+	}
+}
+
+func TestListAdjustments(t *testing.T) {
+	var (
+		ctx         = context.TODO()
+		repository  = reltest.New()
+		service     = newService(repository)
+		adjustments = []Adjustment{{ID: 1, RideID: 1, Amount: -200, Currency: "EUR", Reason: ReasonGoodwill, Agent: "42"}}
+	)
+
+	repository.ExpectFindAll(rel.Where(where.Eq("ride_id", uint(1))).SortAsc("id")).Result(adjustments)
+
+	err, list := service.ListAdjustments(ctx, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, adjustments, list)
+
+	repository.AssertExpectations(t)
+}
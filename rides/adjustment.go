@@ -0,0 +1,102 @@
+package rides
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"backend/money"
+)
+
+// Reason is why a support agent adjusted the price of a ride.
+type Reason string
+
+const (
+	ReasonOvercharge   Reason = "overcharge"
+	ReasonUndercharge  Reason = "undercharge"
+	ReasonVehicleIssue Reason = "vehicle_issue"
+	ReasonGoodwill     Reason = "goodwill"
+	ReasonOther        Reason = "other"
+)
+
+func (r Reason) Valid() bool {
+	switch r {
+	case ReasonOvercharge, ReasonUndercharge, ReasonVehicleIssue, ReasonGoodwill, ReasonOther:
+		return true
+	}
+	return false
+}
+
+// AdjustmentStatus is whether the money of an adjustment moved.
+type AdjustmentStatus string
+
+const (
+	// AdjustmentSettled adjustments were refunded or charged, or had no
+	// money to move.
+	AdjustmentSettled AdjustmentStatus = "settled"
+	// AdjustmentPending adjustments are still to be refunded or charged
+	// on the card of the user, as the provider failed.
+	AdjustmentPending AdjustmentStatus = "pending"
+)
+
+// Adjustment is a correction of the price of a finished ride by a support
+// agent: negative amounts give money back, positive ones charge more. The
+// price of the ride is never changed, its effective price is the price plus
+// its adjustments.
+type Adjustment struct {
+	ID        uint             `json:"id"`
+	CreatedAt time.Time        `json:"created_at"`
+	RideID    uint             `json:"ride_id"`
+	Amount    int64            `json:"amount"`
+	Currency  money.Currency   `json:"currency"`
+	Reason    Reason           `json:"reason"`
+	Note      string           `json:"note,omitempty"`
+	Agent     string           `json:"agent"`
+	Status    AdjustmentStatus `json:"status"`
+	SettledAt *time.Time       `json:"settled_at"`
+}
+
+func (Adjustment) Table() string {
+	return "ride_adjustments"
+}
+
+var (
+	ErrAdjustmentAmountZero       = errors.New("Amount can't be zero")
+	ErrAdjustmentCurrencyMismatch = errors.New("Currency must be the currency of the ride price")
+	ErrAdjustmentReasonInvalid    = errors.New("Reason must be overcharge, undercharge, vehicle_issue, goodwill or other")
+	ErrAdjustmentAgentBlank       = errors.New("Agent can't be blank")
+	ErrRideNotFinished            = errors.New("Only finished rides can be adjusted")
+	ErrAdjustmentExceedsPrice     = errors.New("Adjustments can't take the price of a ride below zero")
+	ErrAdjustmentNotFound         = errors.New("No adjustment of the ride matches the given ID")
+	ErrAdjustmentNotPending       = errors.New("Only pending adjustments can be settled")
+)
+
+// Validate checks the adjustment can apply to the given ride.
+func (a Adjustment) Validate(ride *Ride) error {
+	switch {
+	case a.Amount == 0:
+		return ErrAdjustmentAmountZero
+	case a.Currency != ride.Price.Currency:
+		return ErrAdjustmentCurrencyMismatch
+	case !a.Reason.Valid():
+		return ErrAdjustmentReasonInvalid
+	case a.Agent == "":
+		return ErrAdjustmentAgentBlank
+	}
+	return nil
+}
+
+// Money is the change of price the adjustment makes.
+func (a Adjustment) Money() money.Money {
+	return money.New(a.Amount, a.Currency)
+}
+
+// key is the idempotency key the adjustment is refunded or charged with.
+func (a Adjustment) key() string {
+	return "adjustment-" + strconv.FormatUint(uint64(a.ID), 10)
+}
+
+// agentActor identifies the support agent as the author of a change.
+func agentActor(agent string) string {
+	return "agent:" + agent
+}
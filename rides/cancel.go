@@ -0,0 +1,87 @@
+package rides
+
+import (
+	"context"
+	"time"
+
+	"backend/iot"
+	"backend/money"
+	"backend/payments"
+	"backend/pricing"
+
+	"github.com/go-rel/rel"
+	"github.com/go-rel/rel/where"
+)
+
+// cancelGrace is how long after its start an active ride can still be
+// called off free of charge, as when its vehicle turns out to be unfit.
+const cancelGrace = 2 * time.Minute
+
+type cancelRide struct {
+	repository rel.Repository
+	vehicles   Vehicles
+	gateway    iot.Gateway
+	promos     pricing.Promotions
+	payments   payments.Provider
+}
+
+// CancelRide calls off a reserved ride, or an active one within cancelGrace
+// of its start, without charging it. Rides past that have been used and
+// have to be finished. It locks and frees the vehicle, gives back the promo
+// code the ride redeemed and releases the hold placed when it started. The
+// ride stays open when its vehicle was unlocked and can't be locked again. A
+// hold that fails to void stays authorized until it expires with the
+// provider.
+func (c cancelRide) CancelRide(ctx context.Context, ride *Ride, now time.Time) (error, *Ride) {
+	if ride.Status == StatusActive && now.Sub(ride.CreatedAt) > cancelGrace {
+		return ErrCancelTooLate, nil
+	}
+
+	err := c.repository.Transaction(ctx, func(ctx context.Context) error {
+		err := transition(ctx, c.repository, ride, StatusCancelled, userActor(ride), now,
+			rel.Set("price_amount", int64(0)),
+			rel.Set("net_amount", int64(0)),
+			rel.Set("tax_amount", int64(0)),
+			rel.Set("items", pricing.LineItems{}),
+		)
+		if err != nil {
+			return err
+		}
+		if err := c.vehicles.Release(ctx, ride.VehicleID, now); err != nil {
+			return err
+		}
+		if ride.PromoCode != "" {
+			if err := c.promos.Release(ctx, ride.ID); err != nil {
+				return err
+			}
+		}
+		if ride.Status == StatusReserved {
+			return nil
+		}
+		return lock(ctx, c.gateway, ride.VehicleID)
+	})
+	if err != nil {
+		return err, nil
+	}
+
+	ride.Status = StatusCancelled
+	ride.UpdatedAt = now
+	nothing := money.Zero(ride.Price.Currency)
+	ride.charge(pricing.NewQuote(nothing.Currency), nothing, nothing)
+
+	if ride.PaymentStatus != payments.StatusAuthorized {
+		return nil, ride
+	}
+	if err := c.payments.Void(ctx, ride.PaymentID); err != nil {
+		return nil, ride
+	}
+	if _, err := c.repository.UpdateAny(ctx,
+		rel.From("rides").Where(where.Eq("id", ride.ID)),
+		rel.Set("payment_status", payments.StatusVoided),
+	); err != nil {
+		return err, nil
+	}
+	ride.PaymentStatus = payments.StatusVoided
+
+	return nil, ride
+}
@@ -0,0 +1,184 @@
+package rides
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"backend/iot"
+	"backend/payments"
+	"backend/pricing"
+
+	"github.com/go-rel/rel"
+	"github.com/go-rel/rel/where"
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCancelRide(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		provider   = payments.NewFake()
+		released   []string
+		service    = newService(repository, func(d *Deps) {
+			d.Vehicles = garage{released: &released}
+			d.Payments = provider
+		})
+		now  = time.Now()
+		ride = authorizedRide(provider, now)
+	)
+	ride.CreatedAt = now.Add(-time.Minute)
+
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectUpdateAny(
+			rel.From("rides").Where(where.Eq("id", ride.ID).AndEq("status", StatusActive)),
+			rel.Set("status", StatusCancelled),
+			rel.Set("updated_at", now),
+			rel.Set("price_amount", int64(0)),
+			rel.Set("net_amount", int64(0)),
+			rel.Set("tax_amount", int64(0)),
+			rel.Set("items", pricing.LineItems{}),
+		).UpdatedCount(1)
+		repository.ExpectInsert().For(&Transition{RideID: ride.ID, From: StatusActive, To: StatusCancelled, Actor: "user:1", CreatedAt: now})
+	})
+	repository.ExpectUpdateAny(
+		rel.From("rides").Where(where.Eq("id", ride.ID)),
+		rel.Set("payment_status", payments.StatusVoided),
+	).UpdatedCount(1)
+
+	err, _ := service.CancelRide(ctx, &ride, now)
+	assert.Nil(t, err)
+	assert.Equal(t, StatusCancelled, ride.Status)
+	assert.Equal(t, []string{"1"}, released)
+	assert.Equal(t, eur(0), ride.Price)
+	assert.Equal(t, payments.StatusVoided, ride.PaymentStatus)
+
+	hold, _ := provider.Hold(ride.PaymentID)
+	assert.Equal(t, payments.StatusVoided, hold.Status)
+
+	repository.AssertExpectations(t)
+}
+
+func TestCancelRideLockFails(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		provider   = payments.NewFake()
+		simulator  = iot.NewSimulator()
+		service    = newService(repository, func(d *Deps) {
+			d.Gateway = simulator
+			d.Payments = provider
+		})
+		now  = time.Now()
+		ride = authorizedRide(provider, now)
+	)
+	ride.CreatedAt = now.Add(-time.Minute)
+	simulator.Fail("1", -1)
+
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectUpdateAny(
+			rel.From("rides").Where(where.Eq("id", ride.ID).AndEq("status", StatusActive)),
+			rel.Set("status", StatusCancelled),
+			rel.Set("updated_at", now),
+			rel.Set("price_amount", int64(0)),
+			rel.Set("net_amount", int64(0)),
+			rel.Set("tax_amount", int64(0)),
+			rel.Set("items", pricing.LineItems{}),
+		).UpdatedCount(1)
+		repository.ExpectInsert().For(&Transition{RideID: ride.ID, From: StatusActive, To: StatusCancelled, Actor: "user:1", CreatedAt: now})
+	})
+
+	err, cancelledRide := service.CancelRide(ctx, &ride, now)
+	assert.Equal(t, iot.ErrUnreachable, err)
+	assert.Nil(t, cancelledRide)
+	assert.Equal(t, StatusActive, ride.Status)
+
+	hold, _ := provider.Hold(ride.PaymentID)
+	assert.Equal(t, payments.StatusAuthorized, hold.Status)
+
+	repository.AssertExpectations(t)
+}
+
+func TestCancelPausedRide(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		provider   = payments.NewFake()
+		service    = newService(repository, func(d *Deps) { d.Payments = provider })
+		now        = time.Now()
+		ride       = authorizedRide(provider, now)
+	)
+	ride.Status = StatusPaused
+
+	repository.ExpectTransaction(func(repository *reltest.Repository) {})
+
+	err, _ := service.CancelRide(ctx, &ride, now)
+	assert.Equal(t, TransitionError{From: StatusPaused, To: StatusCancelled}, err)
+
+	hold, _ := provider.Hold(ride.PaymentID)
+	assert.Equal(t, payments.StatusAuthorized, hold.Status)
+
+	repository.AssertExpectations(t)
+}
+
+func TestCancelRideTooLate(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		provider   = payments.NewFake()
+		service    = newService(repository, func(d *Deps) { d.Payments = provider })
+		now        = time.Now()
+		ride       = authorizedRide(provider, now)
+	)
+
+	err, cancelledRide := service.CancelRide(ctx, &ride, now)
+	assert.Equal(t, ErrCancelTooLate, err)
+	assert.Nil(t, cancelledRide)
+	assert.Equal(t, StatusActive, ride.Status)
+
+	hold, _ := provider.Hold(ride.PaymentID)
+	assert.Equal(t, payments.StatusAuthorized, hold.Status)
+
+	repository.AssertExpectations(t)
+}
+
+func TestCancelRideReleasesPromoCode(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		provider   = payments.NewFake()
+		redeemed   = []uint{1}
+		service    = newService(repository, func(d *Deps) {
+			d.Promos = promotions{redeemed: &redeemed}
+			d.Payments = provider
+		})
+		now  = time.Now()
+		ride = authorizedRide(provider, now)
+	)
+	ride.CreatedAt = now.Add(-time.Minute)
+	ride.PromoCode = "SPRING"
+
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectUpdateAny(
+			rel.From("rides").Where(where.Eq("id", ride.ID).AndEq("status", StatusActive)),
+			rel.Set("status", StatusCancelled),
+			rel.Set("updated_at", now),
+			rel.Set("price_amount", int64(0)),
+			rel.Set("net_amount", int64(0)),
+			rel.Set("tax_amount", int64(0)),
+			rel.Set("items", pricing.LineItems{}),
+		).UpdatedCount(1)
+		repository.ExpectInsert().For(&Transition{RideID: ride.ID, From: StatusActive, To: StatusCancelled, Actor: "user:1", CreatedAt: now})
+	})
+	repository.ExpectUpdateAny(
+		rel.From("rides").Where(where.Eq("id", ride.ID)),
+		rel.Set("payment_status", payments.StatusVoided),
+	).UpdatedCount(1)
+
+	err, _ := service.CancelRide(ctx, &ride, now)
+	assert.Nil(t, err)
+	assert.Empty(t, redeemed)
+
+	repository.AssertExpectations(t)
+}
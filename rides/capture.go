@@ -0,0 +1,126 @@
+package rides
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"backend/money"
+	"backend/payments"
+
+	"github.com/go-rel/rel"
+	"github.com/go-rel/rel/where"
+)
+
+// captureClaimTimeout is how long a capture may be under way before
+// another request takes it over, as when the one that claimed it stopped
+// midway.
+const captureClaimTimeout = time.Minute
+
+type capturePayment struct {
+	repository rel.Repository
+	payments   payments.Provider
+}
+
+// CapturePayment charges the price of a finished ride against the hold
+// placed when it started, voiding the hold when there is nothing to charge.
+// What the price goes over the hold is charged to the user on its own, as
+// providers capture no more than they authorized. The ride is claimed
+// first, so that concurrent requests don't both reach the provider, and the
+// capture is keyed by the ride so that a retry after a capture the ride
+// wasn't updated for doesn't charge it twice. The payment goes back to
+// pending when the provider fails, so it can be retried.
+func (c capturePayment) CapturePayment(ctx context.Context, ride *Ride, now time.Time) (error, *Ride) {
+	if ride.PaymentStatus != payments.StatusPending && ride.PaymentStatus != payments.StatusCapturing {
+		return ErrPaymentNotPending, nil
+	}
+
+	held, overage, err := split(ride.Price, ride.Hold())
+	if err != nil {
+		return err, nil
+	}
+
+	claimed, err := c.repository.UpdateAny(ctx,
+		rel.From("rides").Where(where.Eq("id", ride.ID).And(
+			where.Eq("payment_status", payments.StatusPending).Or(
+				where.Eq("payment_status", payments.StatusCapturing).AndLt("updated_at", now.Add(-captureClaimTimeout)),
+			),
+		)),
+		rel.Set("payment_status", payments.StatusCapturing),
+		rel.Set("updated_at", now),
+	)
+	if err != nil {
+		return err, nil
+	}
+	if claimed != 1 {
+		return ErrPaymentNotPending, nil
+	}
+
+	status := payments.StatusCaptured
+	if ride.Price.IsZero() {
+		status = payments.StatusVoided
+	}
+	if held.IsZero() {
+		err = c.payments.Void(ctx, ride.PaymentID)
+	} else {
+		err = c.payments.Capture(ctx, ride.PaymentID, held, captureKey(ride))
+	}
+	if err == nil && !overage.IsZero() {
+		err = c.payments.Charge(ctx, ride.UserID, overage, overageKey(ride))
+	}
+	if err != nil {
+		if _, releaseErr := c.settle(ctx, ride, payments.StatusPending, now); releaseErr != nil {
+			return releaseErr, nil
+		}
+		ride.PaymentStatus = payments.StatusPending
+		ride.UpdatedAt = now
+		return err, nil
+	}
+
+	if _, err := c.settle(ctx, ride, status, now); err != nil {
+		return err, nil
+	}
+
+	ride.PaymentStatus = status
+	ride.UpdatedAt = now
+
+	return nil, ride
+}
+
+// split parts the price of a ride into what its hold covers and what goes
+// over it.
+func split(price, hold money.Money) (held, overage money.Money, err error) {
+	cmp, err := price.Cmp(hold)
+	if err != nil {
+		return money.Money{}, money.Money{}, err
+	}
+
+	held = price
+	if cmp > 0 {
+		held = hold
+	}
+	overage, err = price.Sub(held)
+
+	return held, overage, err
+}
+
+// settle ends the capture of the ride claimed by CapturePayment.
+func (c capturePayment) settle(ctx context.Context, ride *Ride, status payments.Status, now time.Time) (int, error) {
+	return c.repository.UpdateAny(ctx,
+		rel.From("rides").Where(where.Eq("id", ride.ID).AndEq("payment_status", payments.StatusCapturing)),
+		rel.Set("payment_status", status),
+		rel.Set("updated_at", now),
+	)
+}
+
+// captureKey is the idempotency key of the capture of a ride, which the
+// provider charges once however many times it is sent.
+func captureKey(ride *Ride) string {
+	return "ride-" + strconv.FormatUint(uint64(ride.ID), 10) + "-capture"
+}
+
+// overageKey is the idempotency key of the charge of what the price of a
+// ride goes over its hold.
+func overageKey(ride *Ride) string {
+	return "ride-" + strconv.FormatUint(uint64(ride.ID), 10) + "-overage"
+}
@@ -0,0 +1,384 @@
+package rides
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"backend/money"
+	"backend/payments"
+	"backend/pricing"
+
+	"github.com/go-rel/rel"
+	"github.com/go-rel/rel/where"
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+)
+
+var errProviderDown = errors.New("payment provider unavailable")
+
+func TestStartRideDeclined(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		provider   = payments.NewFake()
+		service    = newService(repository, func(d *Deps) { d.Payments = provider })
+		ride       = Ride{UserID: "1", VehicleID: "1"}
+	)
+	provider.Decline("1")
+
+	err, savedRide := service.StartRide(ctx, &ride)
+	assert.Equal(t, payments.ErrDeclined, err)
+	assert.Nil(t, savedRide)
+
+	repository.AssertExpectations(t)
+}
+
+func TestStartRideAuthorizes(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		provider   = payments.NewFake()
+		service    = newService(repository, func(d *Deps) { d.Payments = provider })
+		ride       = Ride{UserID: "1", VehicleID: "1"}
+	)
+
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectCount("rides",
+			rel.In("status", openStatuses...).And(
+				rel.Eq("user_id", ride.UserID).OrEq("vehicle_id", ride.VehicleID),
+			),
+		).Result(0)
+		repository.ExpectInsert().For(&ride)
+		repository.ExpectInsert().ForType("rides.Transition")
+	})
+
+	err, _ := service.StartRide(ctx, &ride)
+	assert.Nil(t, err)
+	assert.Equal(t, payments.StatusAuthorized, ride.PaymentStatus)
+
+	hold, ok := provider.Hold(ride.PaymentID)
+	assert.True(t, ok)
+	assert.Equal(t, eur(3000), hold.Amount)
+	assert.Equal(t, payments.StatusAuthorized, hold.Status)
+	assert.Equal(t, int64(3000), ride.HoldAmount)
+
+	repository.AssertExpectations(t)
+}
+
+func TestStartRideAuthorizesMaximumCharge(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		provider   = payments.NewFake()
+		vat        = pricing.TaxRate{Name: "VAT", Rate: 2100, Rounding: pricing.TaxRoundingPerTotal}
+		capped     = pricing.Tariff{Version: "1", Currency: "EUR", UnlockPrice: 18, MinutePrice: 100, Billing: pricing.Billing{Unit: pricing.UnitMinute, Rounding: pricing.RoundingCeil, MaximumCharge: 1000}}
+		service    = newService(repository, func(d *Deps) {
+			d.Payments = provider
+			d.Tariffs = pricing.Fixed{Tariff: capped}
+			d.Taxes = taxed{rate: vat}
+		})
+		ride = Ride{UserID: "1", VehicleID: "1"}
+	)
+
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectCount("rides",
+			rel.In("status", openStatuses...).And(
+				rel.Eq("user_id", ride.UserID).OrEq("vehicle_id", ride.VehicleID),
+			),
+		).Result(0)
+		repository.ExpectInsert().For(&ride)
+		repository.ExpectInsert().ForType("rides.Transition")
+	})
+
+	err, _ := service.StartRide(ctx, &ride)
+	assert.Nil(t, err)
+
+	// The maximum charge of 10.00 € plus 21% of tax.
+	hold, _ := provider.Hold(ride.PaymentID)
+	assert.Equal(t, eur(1210), hold.Amount)
+	assert.Equal(t, int64(1210), ride.HoldAmount)
+
+	repository.AssertExpectations(t)
+}
+
+func TestStartRideVoidsHoldWhenNotStarted(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		provider   = payments.NewFake()
+		service    = newService(repository, func(d *Deps) { d.Payments = provider })
+		ride       = Ride{UserID: "1", VehicleID: "1"}
+	)
+
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectCount("rides",
+			rel.In("status", openStatuses...).And(
+				rel.Eq("user_id", ride.UserID).OrEq("vehicle_id", ride.VehicleID),
+			),
+		).Result(1)
+	})
+
+	err, _ := service.StartRide(ctx, &ride)
+	assert.Equal(t, ErrRideAlreadyStarted, err)
+
+	hold, _ := provider.Hold("fake_1")
+	assert.Equal(t, payments.StatusVoided, hold.Status)
+
+	repository.AssertExpectations(t)
+}
+
+// authorizedRide is a ride that started 10 minutes before now with a hold
+// on the provider.
+func authorizedRide(provider payments.Provider, now time.Time) Ride {
+	createdAt := now.Add(-10 * time.Minute)
+	id, _ := provider.Authorize(context.TODO(), "1", eur(3000))
+	return Ride{ID: 1, UserID: "1", VehicleID: "1", Price: eur(18), CreatedAt: createdAt, UpdatedAt: createdAt, Status: StatusActive, Tariff: tariff, PaymentID: id, HoldAmount: 3000, PaymentStatus: payments.StatusAuthorized}
+}
+
+func expectFinish(repository *reltest.Repository, ride Ride, now time.Time) {
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectFindAll(where.Eq("ride_id", ride.ID)).Result([]Pause{})
+		repository.ExpectUpdateAny(
+			rel.From("rides").Where(where.Eq("id", ride.ID).AndEq("status", StatusActive)),
+			rel.Set("status", StatusFinished),
+			rel.Set("updated_at", now),
+			rel.Set("price_amount", int64(1018)),
+			rel.Set("price_currency", tariff.Currency),
+			rel.Set("net_amount", int64(1018)),
+			rel.Set("tax_amount", int64(0)),
+			rel.Set("items", reltest.Any),
+			rel.Set("payment_status", payments.StatusPending),
+		).UpdatedCount(1)
+		repository.ExpectInsert().ForType("rides.Transition")
+	})
+}
+
+func expectClaim(repository *reltest.Repository, ride Ride, now time.Time, claimed int) {
+	repository.ExpectUpdateAny(
+		rel.From("rides").Where(where.Eq("id", ride.ID).And(
+			where.Eq("payment_status", payments.StatusPending).Or(
+				where.Eq("payment_status", payments.StatusCapturing).AndLt("updated_at", now.Add(-captureClaimTimeout)),
+			),
+		)),
+		rel.Set("payment_status", payments.StatusCapturing),
+		rel.Set("updated_at", now),
+	).UpdatedCount(claimed)
+}
+
+func expectSettle(repository *reltest.Repository, ride Ride, status payments.Status, now time.Time) *reltest.MockUpdateAny {
+	return repository.ExpectUpdateAny(
+		rel.From("rides").Where(where.Eq("id", ride.ID).AndEq("payment_status", payments.StatusCapturing)),
+		rel.Set("payment_status", status),
+		rel.Set("updated_at", now),
+	)
+}
+
+func TestFinishRideCaptures(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		provider   = payments.NewFake()
+		service    = newService(repository, func(d *Deps) { d.Payments = provider })
+		now        = time.Now()
+		ride       = authorizedRide(provider, now)
+	)
+
+	expectFinish(repository, ride, now)
+	expectClaim(repository, ride, now, 1)
+	expectSettle(repository, ride, payments.StatusCaptured, now).UpdatedCount(1)
+
+	err, _ := service.FinishRide(ctx, &ride, now)
+	assert.Nil(t, err)
+	assert.Equal(t, payments.StatusCaptured, ride.PaymentStatus)
+
+	hold, _ := provider.Hold(ride.PaymentID)
+	assert.Equal(t, eur(1018), hold.Captured)
+
+	repository.AssertExpectations(t)
+}
+
+func TestFinishRideCaptureFails(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		provider   = payments.NewFake()
+		service    = newService(repository, func(d *Deps) { d.Payments = provider })
+		now        = time.Now()
+		ride       = authorizedRide(provider, now)
+	)
+	provider.FailCaptures(errProviderDown)
+
+	expectFinish(repository, ride, now)
+	expectClaim(repository, ride, now, 1)
+	expectSettle(repository, ride, payments.StatusPending, now).UpdatedCount(1)
+
+	// The ride is finished, and its payment is left for a retry.
+	err, _ := service.FinishRide(ctx, &ride, now)
+	assert.Nil(t, err)
+	assert.Equal(t, StatusFinished, ride.Status)
+	assert.Equal(t, payments.StatusPending, ride.PaymentStatus)
+
+	repository.AssertExpectations(t)
+}
+
+func TestCapturePaymentRetry(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		provider   = payments.NewFake()
+		service    = newService(repository, func(d *Deps) { d.Payments = provider })
+		now        = time.Now()
+		ride       = authorizedRide(provider, now)
+	)
+	ride.Status = StatusFinished
+	ride.Price = eur(1018)
+	ride.PaymentStatus = payments.StatusPending
+
+	provider.FailCaptures(errProviderDown)
+	expectClaim(repository, ride, now, 1)
+	expectSettle(repository, ride, payments.StatusPending, now).UpdatedCount(1)
+	err, _ := service.CapturePayment(ctx, &ride, now)
+	assert.Equal(t, errProviderDown, err)
+	assert.Equal(t, payments.StatusPending, ride.PaymentStatus)
+
+	provider.FailCaptures(nil)
+	expectClaim(repository, ride, now, 1)
+	expectSettle(repository, ride, payments.StatusCaptured, now).UpdatedCount(1)
+
+	err, _ = service.CapturePayment(ctx, &ride, now)
+	assert.Nil(t, err)
+	assert.Equal(t, payments.StatusCaptured, ride.PaymentStatus)
+
+	repository.AssertExpectations(t)
+}
+
+func TestCapturePaymentOverHold(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		provider   = payments.NewFake()
+		service    = newService(repository, func(d *Deps) { d.Payments = provider })
+		now        = time.Now()
+		ride       = authorizedRide(provider, now)
+	)
+	ride.Status = StatusFinished
+	ride.Price = eur(3418)
+	ride.PaymentStatus = payments.StatusPending
+
+	// The hold is captured, and the rest charged on its own.
+	expectClaim(repository, ride, now, 1)
+	expectSettle(repository, ride, payments.StatusCaptured, now).UpdatedCount(1)
+	err, _ := service.CapturePayment(ctx, &ride, now)
+	assert.Nil(t, err)
+
+	hold, _ := provider.Hold(ride.PaymentID)
+	assert.Equal(t, eur(3000), hold.Captured)
+	assert.Equal(t, []money.Money{eur(418)}, provider.Charges(ride.UserID))
+
+	repository.AssertExpectations(t)
+}
+
+func TestCapturePaymentFree(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		provider   = payments.NewFake()
+		service    = newService(repository, func(d *Deps) { d.Payments = provider })
+		now        = time.Now()
+		ride       = authorizedRide(provider, now)
+	)
+	ride.Status = StatusFinished
+	ride.Price = eur(0)
+	ride.PaymentStatus = payments.StatusPending
+
+	expectClaim(repository, ride, now, 1)
+	expectSettle(repository, ride, payments.StatusVoided, now).UpdatedCount(1)
+
+	err, _ := service.CapturePayment(ctx, &ride, now)
+	assert.Nil(t, err)
+
+	hold, _ := provider.Hold(ride.PaymentID)
+	assert.Equal(t, payments.StatusVoided, hold.Status)
+
+	repository.AssertExpectations(t)
+}
+
+func TestCapturePaymentNotPending(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		provider   = payments.NewFake()
+		service    = newService(repository, func(d *Deps) { d.Payments = provider })
+		now        = time.Now()
+		ride       = authorizedRide(provider, now)
+	)
+
+	err, savedRide := service.CapturePayment(ctx, &ride, now)
+	assert.Equal(t, ErrPaymentNotPending, err)
+	assert.Nil(t, savedRide)
+
+	repository.AssertExpectations(t)
+}
+
+func TestCapturePaymentClaimedByAnotherRequest(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		provider   = payments.NewFake()
+		service    = newService(repository, func(d *Deps) { d.Payments = provider })
+		now        = time.Now()
+		ride       = authorizedRide(provider, now)
+	)
+	ride.Status = StatusFinished
+	ride.Price = eur(1018)
+	ride.PaymentStatus = payments.StatusPending
+
+	expectClaim(repository, ride, now, 0)
+
+	err, savedRide := service.CapturePayment(ctx, &ride, now)
+	assert.Equal(t, ErrPaymentNotPending, err)
+	assert.Nil(t, savedRide)
+
+	hold, _ := provider.Hold(ride.PaymentID)
+	assert.Equal(t, payments.StatusAuthorized, hold.Status)
+
+	repository.AssertExpectations(t)
+}
+
+func TestCapturePaymentRetryAfterCaptured(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		provider   = payments.NewFake()
+		service    = newService(repository, func(d *Deps) { d.Payments = provider })
+		now        = time.Now()
+		ride       = authorizedRide(provider, now)
+		errDB      = errors.New("connection reset")
+	)
+	ride.Status = StatusFinished
+	ride.Price = eur(1018)
+	ride.PaymentStatus = payments.StatusPending
+
+	// The capture goes through but the ride isn't updated for it.
+	expectClaim(repository, ride, now, 1)
+	expectSettle(repository, ride, payments.StatusCaptured, now).Error(errDB)
+	err, _ := service.CapturePayment(ctx, &ride, now)
+	assert.Equal(t, errDB, err)
+
+	// Once the claim times out, a retry settles the ride without charging
+	// it again.
+	later := now.Add(2 * captureClaimTimeout)
+	ride.PaymentStatus = payments.StatusCapturing
+	expectClaim(repository, ride, later, 1)
+	expectSettle(repository, ride, payments.StatusCaptured, later).UpdatedCount(1)
+	err, _ = service.CapturePayment(ctx, &ride, later)
+	assert.Nil(t, err)
+	assert.Equal(t, payments.StatusCaptured, ride.PaymentStatus)
+
+	hold, _ := provider.Hold(ride.PaymentID)
+	assert.Equal(t, eur(1018), hold.Captured)
+
+	repository.AssertExpectations(t)
+}
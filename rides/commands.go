@@ -0,0 +1,29 @@
+package rides
+
+import (
+	"context"
+	"log"
+
+	"backend/iot"
+)
+
+// Commands to vehicles are detached from the request that sent them, so a
+// client that goes away can't leave a vehicle halfway. How long they may
+// keep the transaction of a ride waiting is up to the gateway, which
+// iot.Retrying bounds by the Budget of its policy.
+
+func unlock(ctx context.Context, gateway iot.Gateway, vehicleID string) error {
+	return gateway.Unlock(context.WithoutCancel(ctx), vehicleID)
+}
+
+func lock(ctx context.Context, gateway iot.Gateway, vehicleID string) error {
+	return gateway.Lock(context.WithoutCancel(ctx), vehicleID)
+}
+
+// relock locks a vehicle no ride holds anymore, logging when it can't as
+// the vehicle is then left open.
+func relock(ctx context.Context, gateway iot.Gateway, vehicleID string) {
+	if err := lock(ctx, gateway, vehicleID); err != nil {
+		log.Printf("rides: vehicle %s may be left unlocked: %v", vehicleID, err)
+	}
+}
@@ -2,34 +2,211 @@ package rides
 
 import (
 	"context"
-	"math"
+	"errors"
+	"log"
 	"time"
 
-	"backend/utils"
+	"backend/iot"
+	"backend/money"
+	"backend/payments"
+	"backend/pricing"
 
 	"github.com/go-rel/rel"
+	"github.com/go-rel/rel/where"
 )
 
 type finishRide struct {
 	repository rel.Repository
+	vehicles   Vehicles
+	gateway    iot.Gateway
+	passes     pricing.Passes
+	wallets    payments.Wallets
+	pricer     pricing.Pricer
+	capture    capturePayment
 }
 
-func (c startRide) FinishRide(ctx context.Context, ride *Ride, now time.Time) (error, *Ride) {
+// FinishRide locks the vehicle of the ride and finishes it. The ride stays
+// open when its vehicle can't be locked.
+func (c finishRide) FinishRide(ctx context.Context, ride *Ride, now time.Time) (error, *Ride) {
+	return c.finish(ctx, ride, StatusFinished, userActor(ride), true, now)
+}
+
+// ForceFinishRide closes the ride on behalf of the system, for the given
+// reason, e.g. because its vehicle ran out of battery. It is priced and
+// charged the way FinishRide does, and closed even if its vehicle can't be
+// locked.
+func (c finishRide) ForceFinishRide(ctx context.Context, ride *Ride, reason string, now time.Time) (error, *Ride) {
+	return c.finish(ctx, ride, StatusForceClosed, systemActor(reason), false, now)
+}
+
+func (c finishRide) finish(ctx context.Context, ride *Ride, to Status, actor string, mustLock bool, now time.Time) (error, *Ride) {
+	if ride.Status.Closed() {
+		return ErrRideAlreadyFinished, nil
+	}
+
 	var (
-		duration    = now.Sub(ride.CreatedAt)
-		seconds     = duration.Seconds()
-		minutes     = int(math.Ceil(seconds / 60))
-		minutePrice = utils.GetEnvAsInt("RIDE_MINUTE_PRICE", 100)
+		quote    pricing.Quote
+		net, tax money.Money
 	)
+	err := c.repository.Transaction(ctx, func(ctx context.Context) error {
+		var err error
+		if quote, err = c.quote(ctx, ride, now, true); err != nil {
+			return err
+		}
+		if net, tax, err = quote.Breakdown(); err != nil {
+			return err
+		}
+		price := quote.Price()
 
-	count, _ := c.repository.Count(ctx, "rides", rel.And(rel.Eq("id", ride.ID), rel.Eq("finished", true)))
-	if count != 0 {
-		return ErrRideAlreadyFinished, nil
+		// Only the finish that moves the ride out of its open status gets to
+		// charge it.
+		wasPaused := ride.Status == StatusPaused
+		mutates := []rel.Mutate{
+			rel.Set("price_amount", price.Amount),
+			rel.Set("price_currency", price.Currency),
+			rel.Set("net_amount", net.Amount),
+			rel.Set("tax_amount", tax.Amount),
+			rel.Set("items", pricing.LineItems(quote.Items)),
+		}
+		prepaid := ride.Tariff.Prepaid.Enabled
+		switch {
+		case prepaid:
+			mutates = append(mutates, rel.Set("payment_status", payments.StatusDebited))
+		case ride.PaymentID != "":
+			mutates = append(mutates, rel.Set("payment_status", payments.StatusPending))
+		}
+		err = transition(ctx, c.repository, ride, to, actor, now, mutates...)
+		if err != nil {
+			return err
+		}
+
+		if wasPaused {
+			if err := endPause(ctx, c.repository, ride, now); err != nil {
+				return err
+			}
+		}
+		if err := c.vehicles.Release(ctx, ride.VehicleID, now); err != nil {
+			return err
+		}
+		if prepaid {
+			if err := c.wallets.DebitRide(ctx, ride.UserID, ride.ID, price, now); err != nil {
+				return err
+			}
+		}
+
+		// The vehicle is locked last, so that the ride isn't finished
+		// without it.
+		if err := lock(ctx, c.gateway, ride.VehicleID); err != nil {
+			if mustLock {
+				return err
+			}
+			log.Printf("rides: vehicle %s of ride %d left unlocked: %v", ride.VehicleID, ride.ID, err)
+		}
+		return nil
+	})
+	if errors.Is(err, ErrRideStatusChanged) {
+		return c.changed(ctx, ride), nil
+	}
+	if err != nil {
+		return err, nil
+	}
+
+	ride.charge(quote, net, tax)
+	ride.Status = to
+	ride.UpdatedAt = now
+	if ride.Tariff.Prepaid.Enabled {
+		ride.PaymentStatus = payments.StatusDebited
+	}
+	if ride.PaymentID == "" {
+		return nil, ride
 	}
 
-	ride.Price = ride.Price + minutes*minutePrice
-	ride.Finished = true
+	// The ride is finished whether or not its price can be captured now.
+	// When it can't, its payment stays pending for CapturePayment to retry,
+	// so the error is left to that retry.
+	ride.PaymentStatus = payments.StatusPending
+	_, _ = c.capture.CapturePayment(ctx, ride, now)
+
+	return nil, ride
+}
+
+// changed tells why the ride couldn't be finished after another request
+// moved it out of the status it was read in: it is only already finished
+// if it was closed in the meantime, e.g. by a concurrent finish.
+func (c finishRide) changed(ctx context.Context, ride *Ride) error {
+	var current Ride
+	if err := c.repository.Find(ctx, &current, where.Eq("id", ride.ID)); err != nil || !current.Status.Closed() {
+		return ErrRideStatusChanged
+	}
+	return ErrRideAlreadyFinished
+}
+
+// quote prices the ride as if it finished at the given instant: passes
+// first, then the promo code discount, then the caps, and tax on what is
+// left. What the passes cover is only consumed when consume is set.
+func (c finishRide) quote(ctx context.Context, ride *Ride, now time.Time, consume bool) (pricing.Quote, error) {
+	var pauses []Pause
+	if err := c.repository.FindAll(ctx, &pauses, where.Eq("ride_id", ride.ID)); err != nil {
+		return pricing.Quote{}, err
+	}
+
+	consumed := usage(ride, pauses, now)
+	quote := c.pricer.Quote(ride.Tariff, consumed)
+	if err := c.coverQuote(ctx, ride, consumed, &quote, now, consume); err != nil {
+		return pricing.Quote{}, err
+	}
+	if ride.Discount != nil {
+		ride.Discount.Apply(c.pricer, ride.Tariff, consumed, &quote)
+	}
+	if err := c.capQuote(ctx, ride, &quote); err != nil {
+		return pricing.Quote{}, err
+	}
+	quote.Tax(ride.TaxRate)
+
+	return quote, quote.Err()
+}
+
+// coverQuote covers the ride with the passes of its user before anything
+// else takes off the price, recording what it used up of each of them
+// when consume is set.
+func (c finishRide) coverQuote(ctx context.Context, ride *Ride, consumed pricing.Usage, quote *pricing.Quote, now time.Time, consume bool) error {
+	allowances, err := c.passes.Allowances(ctx, ride.UserID, now)
+	if err != nil || len(allowances) == 0 {
+		return err
+	}
+
+	consumptions := quote.Cover(c.pricer, ride.Tariff, consumed, allowances)
+	if !consume || len(consumptions) == 0 {
+		return nil
+	}
+	return c.passes.Consume(ctx, ride.ID, consumptions, now)
+}
+
+// capQuote applies the caps of the ride tariff, given what the user was
+// already charged before tax for the other rides started in each capped
+// window.
+func (c finishRide) capQuote(ctx context.Context, ride *Ride, quote *pricing.Quote) error {
+	for _, window := range ride.Tariff.Windows(ride.CreatedAt) {
+		charged, err := c.repository.Aggregate(ctx,
+			rel.From("rides").Where(
+				where.Eq("user_id", ride.UserID).
+					AndIn("status", closedStatuses...).
+					AndEq("price_currency", quote.Currency()).
+					AndGte("created_at", window.Since).
+					AndNe("id", ride.ID),
+			),
+			"sum", "net_amount",
+		)
+		if err != nil {
+			return err
+		}
+
+		left, err := window.Limit.Sub(money.New(int64(charged), quote.Currency()))
+		if err != nil {
+			return err
+		}
+		quote.Cap(left, window.Description)
+	}
 
-	err := c.repository.Update(ctx, ride)
-	return err, ride
+	return nil
 }
@@ -5,7 +5,13 @@ import (
 	"testing"
 	"time"
 
+	"backend/iot"
+	"backend/money"
+	"backend/payments"
+	"backend/pricing"
+
 	"github.com/go-rel/rel"
+	"github.com/go-rel/rel/where"
 	"github.com/go-rel/reltest"
 	"github.com/stretchr/testify/assert"
 )
@@ -14,20 +20,34 @@ func TestFinish1Second(t *testing.T) {
 	var (
 		ctx        = context.TODO()
 		repository = reltest.New()
-		service    = New(repository)
+		released   []string
+		service    = newService(repository, func(d *Deps) { d.Vehicles = garage{released: &released} })
 		now        = time.Now()
 		createdAt  = now.Add(-time.Second * 1)
-		ride       = Ride{ID: 1, UserID: "1", VehicleID: "1", Price: 18, CreatedAt: createdAt, UpdatedAt: createdAt, Finished: false}
+		ride       = Ride{ID: 1, UserID: "1", VehicleID: "1", Price: eur(18), CreatedAt: createdAt, UpdatedAt: createdAt, Status: StatusActive, Tariff: tariff}
 	)
 
-	repository.ExpectCount("rides", rel.And(rel.Eq("id", ride.ID), rel.Eq("finished", true))).Result(0)
-	repository.ExpectUpdate().For(&ride)
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectFindAll(where.Eq("ride_id", ride.ID)).Result([]Pause{})
+		repository.ExpectUpdateAny(
+			rel.From("rides").Where(where.Eq("id", ride.ID).AndEq("status", StatusActive)),
+			rel.Set("status", StatusFinished),
+			rel.Set("updated_at", now),
+			rel.Set("price_amount", int64(118)),
+			rel.Set("price_currency", tariff.Currency),
+			rel.Set("net_amount", int64(118)),
+			rel.Set("tax_amount", int64(0)),
+			rel.Set("items", reltest.Any),
+		).UpdatedCount(1)
+		repository.ExpectInsert().For(&Transition{RideID: ride.ID, From: StatusActive, To: StatusFinished, Actor: "user:1", CreatedAt: now})
+	})
 
 	err, _ := service.FinishRide(ctx, &ride, now)
 	assert.Nil(t, err)
-	assert.Equal(t, ride.Price, 118)
+	assert.Equal(t, eur(118), ride.Price)
 	assert.NotEmpty(t, ride.UpdatedAt)
-	assert.True(t, ride.Finished)
+	assert.Equal(t, StatusFinished, ride.Status)
+	assert.Equal(t, []string{"1"}, released)
 
 	repository.AssertExpectations(t)
 }
@@ -36,19 +56,31 @@ func TestFinish10Seconds(t *testing.T) {
 	var (
 		ctx        = context.TODO()
 		repository = reltest.New()
-		service    = New(repository)
+		service    = newService(repository)
 		now        = time.Now()
 		createdAt  = now.Add(-time.Second * 10)
-		ride       = Ride{ID: 1, UserID: "1", VehicleID: "1", Price: 18, CreatedAt: createdAt, UpdatedAt: createdAt, Finished: false}
+		ride       = Ride{ID: 1, UserID: "1", VehicleID: "1", Price: eur(18), CreatedAt: createdAt, UpdatedAt: createdAt, Status: StatusActive, Tariff: tariff}
 	)
 
-	repository.ExpectCount("rides", rel.And(rel.Eq("id", ride.ID), rel.Eq("finished", true))).Result(0)
-	repository.ExpectUpdate().For(&ride)
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectFindAll(where.Eq("ride_id", ride.ID)).Result([]Pause{})
+		repository.ExpectUpdateAny(
+			rel.From("rides").Where(where.Eq("id", ride.ID).AndEq("status", StatusActive)),
+			rel.Set("status", StatusFinished),
+			rel.Set("updated_at", now),
+			rel.Set("price_amount", int64(118)),
+			rel.Set("price_currency", tariff.Currency),
+			rel.Set("net_amount", int64(118)),
+			rel.Set("tax_amount", int64(0)),
+			rel.Set("items", reltest.Any),
+		).UpdatedCount(1)
+		repository.ExpectInsert().For(&Transition{RideID: ride.ID, From: StatusActive, To: StatusFinished, Actor: "user:1", CreatedAt: now})
+	})
 
 	err, _ := service.FinishRide(ctx, &ride, now)
 	assert.Nil(t, err)
-	assert.Equal(t, ride.Price, 118)
-	assert.True(t, ride.Finished)
+	assert.Equal(t, eur(118), ride.Price)
+	assert.Equal(t, StatusFinished, ride.Status)
 
 	repository.AssertExpectations(t)
 }
@@ -57,19 +89,31 @@ func TestFinish59Seconds(t *testing.T) {
 	var (
 		ctx        = context.TODO()
 		repository = reltest.New()
-		service    = New(repository)
+		service    = newService(repository)
 		now        = time.Now()
 		createdAt  = now.Add(-time.Second * 59)
-		ride       = Ride{ID: 1, UserID: "1", VehicleID: "1", Price: 18, CreatedAt: createdAt, UpdatedAt: createdAt, Finished: false}
+		ride       = Ride{ID: 1, UserID: "1", VehicleID: "1", Price: eur(18), CreatedAt: createdAt, UpdatedAt: createdAt, Status: StatusActive, Tariff: tariff}
 	)
 
-	repository.ExpectCount("rides", rel.And(rel.Eq("id", ride.ID), rel.Eq("finished", true))).Result(0)
-	repository.ExpectUpdate().For(&ride)
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectFindAll(where.Eq("ride_id", ride.ID)).Result([]Pause{})
+		repository.ExpectUpdateAny(
+			rel.From("rides").Where(where.Eq("id", ride.ID).AndEq("status", StatusActive)),
+			rel.Set("status", StatusFinished),
+			rel.Set("updated_at", now),
+			rel.Set("price_amount", int64(118)),
+			rel.Set("price_currency", tariff.Currency),
+			rel.Set("net_amount", int64(118)),
+			rel.Set("tax_amount", int64(0)),
+			rel.Set("items", reltest.Any),
+		).UpdatedCount(1)
+		repository.ExpectInsert().For(&Transition{RideID: ride.ID, From: StatusActive, To: StatusFinished, Actor: "user:1", CreatedAt: now})
+	})
 
 	err, _ := service.FinishRide(ctx, &ride, now)
 	assert.Nil(t, err)
-	assert.Equal(t, ride.Price, 118)
-	assert.True(t, ride.Finished)
+	assert.Equal(t, eur(118), ride.Price)
+	assert.Equal(t, StatusFinished, ride.Status)
 
 	repository.AssertExpectations(t)
 }
@@ -78,19 +122,31 @@ func TestFinish60Seconds(t *testing.T) {
 	var (
 		ctx        = context.TODO()
 		repository = reltest.New()
-		service    = New(repository)
+		service    = newService(repository)
 		now        = time.Now()
 		createdAt  = now.Add(-time.Second * 60)
-		ride       = Ride{ID: 1, UserID: "1", VehicleID: "1", Price: 18, CreatedAt: createdAt, UpdatedAt: createdAt, Finished: false}
+		ride       = Ride{ID: 1, UserID: "1", VehicleID: "1", Price: eur(18), CreatedAt: createdAt, UpdatedAt: createdAt, Status: StatusActive, Tariff: tariff}
 	)
 
-	repository.ExpectCount("rides", rel.And(rel.Eq("id", ride.ID), rel.Eq("finished", true))).Result(0)
-	repository.ExpectUpdate().For(&ride)
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectFindAll(where.Eq("ride_id", ride.ID)).Result([]Pause{})
+		repository.ExpectUpdateAny(
+			rel.From("rides").Where(where.Eq("id", ride.ID).AndEq("status", StatusActive)),
+			rel.Set("status", StatusFinished),
+			rel.Set("updated_at", now),
+			rel.Set("price_amount", int64(118)),
+			rel.Set("price_currency", tariff.Currency),
+			rel.Set("net_amount", int64(118)),
+			rel.Set("tax_amount", int64(0)),
+			rel.Set("items", reltest.Any),
+		).UpdatedCount(1)
+		repository.ExpectInsert().For(&Transition{RideID: ride.ID, From: StatusActive, To: StatusFinished, Actor: "user:1", CreatedAt: now})
+	})
 
 	err, _ := service.FinishRide(ctx, &ride, now)
 	assert.Nil(t, err)
-	assert.Equal(t, ride.Price, 118)
-	assert.True(t, ride.Finished)
+	assert.Equal(t, eur(118), ride.Price)
+	assert.Equal(t, StatusFinished, ride.Status)
 
 	repository.AssertExpectations(t)
 }
@@ -99,19 +155,31 @@ func TestFinish61Seconds(t *testing.T) {
 	var (
 		ctx        = context.TODO()
 		repository = reltest.New()
-		service    = New(repository)
+		service    = newService(repository)
 		now        = time.Now()
 		createdAt  = now.Add(-time.Second * 61)
-		ride       = Ride{ID: 1, UserID: "1", VehicleID: "1", Price: 18, CreatedAt: createdAt, UpdatedAt: createdAt, Finished: false}
+		ride       = Ride{ID: 1, UserID: "1", VehicleID: "1", Price: eur(18), CreatedAt: createdAt, UpdatedAt: createdAt, Status: StatusActive, Tariff: tariff}
 	)
 
-	repository.ExpectCount("rides", rel.And(rel.Eq("id", ride.ID), rel.Eq("finished", true))).Result(0)
-	repository.ExpectUpdate().For(&ride)
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectFindAll(where.Eq("ride_id", ride.ID)).Result([]Pause{})
+		repository.ExpectUpdateAny(
+			rel.From("rides").Where(where.Eq("id", ride.ID).AndEq("status", StatusActive)),
+			rel.Set("status", StatusFinished),
+			rel.Set("updated_at", now),
+			rel.Set("price_amount", int64(218)),
+			rel.Set("price_currency", tariff.Currency),
+			rel.Set("net_amount", int64(218)),
+			rel.Set("tax_amount", int64(0)),
+			rel.Set("items", reltest.Any),
+		).UpdatedCount(1)
+		repository.ExpectInsert().For(&Transition{RideID: ride.ID, From: StatusActive, To: StatusFinished, Actor: "user:1", CreatedAt: now})
+	})
 
 	err, _ := service.FinishRide(ctx, &ride, now)
 	assert.Nil(t, err)
-	assert.Equal(t, ride.Price, 218)
-	assert.True(t, ride.Finished)
+	assert.Equal(t, eur(218), ride.Price)
+	assert.Equal(t, StatusFinished, ride.Status)
 
 	repository.AssertExpectations(t)
 }
@@ -120,19 +188,31 @@ func TestFinish1565Seconds(t *testing.T) {
 	var (
 		ctx        = context.TODO()
 		repository = reltest.New()
-		service    = New(repository)
+		service    = newService(repository)
 		now        = time.Now()
 		createdAt  = now.Add(-time.Second * 1565)
-		ride       = Ride{ID: 1, UserID: "1", VehicleID: "1", Price: 18, CreatedAt: createdAt, UpdatedAt: createdAt, Finished: false}
+		ride       = Ride{ID: 1, UserID: "1", VehicleID: "1", Price: eur(18), CreatedAt: createdAt, UpdatedAt: createdAt, Status: StatusActive, Tariff: tariff}
 	)
 
-	repository.ExpectCount("rides", rel.And(rel.Eq("id", ride.ID), rel.Eq("finished", true))).Result(0)
-	repository.ExpectUpdate().For(&ride)
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectFindAll(where.Eq("ride_id", ride.ID)).Result([]Pause{})
+		repository.ExpectUpdateAny(
+			rel.From("rides").Where(where.Eq("id", ride.ID).AndEq("status", StatusActive)),
+			rel.Set("status", StatusFinished),
+			rel.Set("updated_at", now),
+			rel.Set("price_amount", int64(2718)),
+			rel.Set("price_currency", tariff.Currency),
+			rel.Set("net_amount", int64(2718)),
+			rel.Set("tax_amount", int64(0)),
+			rel.Set("items", reltest.Any),
+		).UpdatedCount(1)
+		repository.ExpectInsert().For(&Transition{RideID: ride.ID, From: StatusActive, To: StatusFinished, Actor: "user:1", CreatedAt: now})
+	})
 
 	err, _ := service.FinishRide(ctx, &ride, now)
 	assert.Nil(t, err)
-	assert.Equal(t, ride.Price, 2718)
-	assert.True(t, ride.Finished)
+	assert.Equal(t, eur(2718), ride.Price)
+	assert.Equal(t, StatusFinished, ride.Status)
 
 	repository.AssertExpectations(t)
 }
@@ -141,16 +221,755 @@ func TestFinishRideAlreadyFinished(t *testing.T) {
 	var (
 		ctx        = context.TODO()
 		repository = reltest.New()
-		service    = New(repository)
+		service    = newService(repository)
+		now        = time.Now()
+		createdAt  = now.Add(-time.Second * 1565)
+		ride       = Ride{ID: 1, UserID: "1", VehicleID: "1", Price: eur(18), CreatedAt: createdAt, UpdatedAt: createdAt, Status: StatusActive, Tariff: tariff}
+	)
+
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectFindAll(where.Eq("ride_id", ride.ID)).Result([]Pause{})
+		repository.ExpectUpdateAny(
+			rel.From("rides").Where(where.Eq("id", ride.ID).AndEq("status", StatusActive)),
+			rel.Set("status", StatusFinished),
+			rel.Set("updated_at", now),
+			rel.Set("price_amount", int64(2718)),
+			rel.Set("price_currency", tariff.Currency),
+			rel.Set("net_amount", int64(2718)),
+			rel.Set("tax_amount", int64(0)),
+			rel.Set("items", reltest.Any),
+		).UpdatedCount(0)
+	})
+	repository.ExpectFind(where.Eq("id", ride.ID)).Result(Ride{ID: ride.ID, Status: StatusFinished})
+
+	err, savedRide := service.FinishRide(ctx, &ride, now)
+	assert.Equal(t, ErrRideAlreadyFinished, err)
+	assert.Nil(t, savedRide)
+	assert.Equal(t, eur(18), ride.Price)
+	assert.Equal(t, StatusActive, ride.Status)
+
+	repository.AssertExpectations(t)
+}
+
+func TestFinishRideStatusChanged(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = newService(repository)
 		now        = time.Now()
 		createdAt  = now.Add(-time.Second * 1565)
-		ride       = Ride{ID: 1, UserID: "1", VehicleID: "1", Price: 18, CreatedAt: createdAt, UpdatedAt: createdAt, Finished: false}
+		ride       = Ride{ID: 1, UserID: "1", VehicleID: "1", Price: eur(18), CreatedAt: createdAt, UpdatedAt: createdAt, Status: StatusActive, Tariff: tariff}
 	)
 
-	repository.ExpectCount("rides", rel.And(rel.Eq("id", ride.ID), rel.Eq("finished", true))).Result(1)
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectFindAll(where.Eq("ride_id", ride.ID)).Result([]Pause{})
+		repository.ExpectUpdateAny(
+			rel.From("rides").Where(where.Eq("id", ride.ID).AndEq("status", StatusActive)),
+			rel.Set("status", StatusFinished),
+			rel.Set("updated_at", now),
+			rel.Set("price_amount", int64(2718)),
+			rel.Set("price_currency", tariff.Currency),
+			rel.Set("net_amount", int64(2718)),
+			rel.Set("tax_amount", int64(0)),
+			rel.Set("items", reltest.Any),
+		).UpdatedCount(0)
+	})
+	repository.ExpectFind(where.Eq("id", ride.ID)).Result(Ride{ID: ride.ID, Status: StatusPaused})
+
+	err, savedRide := service.FinishRide(ctx, &ride, now)
+	assert.Equal(t, ErrRideStatusChanged, err)
+	assert.Nil(t, savedRide)
+	assert.Equal(t, eur(18), ride.Price)
+	assert.Equal(t, StatusActive, ride.Status)
+
+	repository.AssertExpectations(t)
+}
+
+func TestFinishRideFinishedFlag(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = newService(repository)
+		now        = time.Now()
+		createdAt  = now.Add(-time.Second * 1565)
+		ride       = Ride{ID: 1, UserID: "1", VehicleID: "1", Price: eur(2718), CreatedAt: createdAt, UpdatedAt: createdAt, Status: StatusFinished, Tariff: tariff}
+	)
 
 	err, _ := service.FinishRide(ctx, &ride, now)
 	assert.Equal(t, ErrRideAlreadyFinished, err)
+	assert.Equal(t, eur(2718), ride.Price)
+
+	ride.Status = StatusForceClosed
+	err, _ = service.ForceFinishRide(ctx, &ride, "low_battery", now)
+	assert.Equal(t, ErrRideAlreadyFinished, err)
+
+	repository.AssertExpectations(t)
+}
+
+func TestForceFinishRide(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = newService(repository)
+		now        = time.Now()
+		createdAt  = now.Add(-time.Second * 61)
+		ride       = Ride{ID: 1, UserID: "1", VehicleID: "1", Price: eur(18), CreatedAt: createdAt, UpdatedAt: createdAt, Status: StatusActive, Tariff: tariff}
+	)
+
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectFindAll(where.Eq("ride_id", ride.ID)).Result([]Pause{})
+		repository.ExpectUpdateAny(
+			rel.From("rides").Where(where.Eq("id", ride.ID).AndEq("status", StatusActive)),
+			rel.Set("status", StatusForceClosed),
+			rel.Set("updated_at", now),
+			rel.Set("price_amount", int64(218)),
+			rel.Set("price_currency", tariff.Currency),
+			rel.Set("net_amount", int64(218)),
+			rel.Set("tax_amount", int64(0)),
+			rel.Set("items", reltest.Any),
+		).UpdatedCount(1)
+		repository.ExpectInsert().For(&Transition{RideID: ride.ID, From: StatusActive, To: StatusForceClosed, Actor: "system:low_battery", CreatedAt: now})
+	})
+
+	err, _ := service.ForceFinishRide(ctx, &ride, "low_battery", now)
+	assert.Nil(t, err)
+	assert.Equal(t, eur(218), ride.Price)
+	assert.Equal(t, StatusForceClosed, ride.Status)
+
+	repository.AssertExpectations(t)
+}
+
+func TestFinishRideLockFails(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		simulator  = iot.NewSimulator()
+		service    = newService(repository, func(d *Deps) { d.Gateway = simulator })
+		now        = time.Now()
+		createdAt  = now.Add(-time.Second * 61)
+		ride       = Ride{ID: 1, UserID: "1", VehicleID: "1", Price: eur(18), CreatedAt: createdAt, UpdatedAt: createdAt, Status: StatusActive, Tariff: tariff}
+	)
+	assert.Nil(t, simulator.Unlock(ctx, "1"))
+	simulator.Fail("1", -1)
+
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectFindAll(where.Eq("ride_id", ride.ID)).Result([]Pause{})
+		repository.ExpectUpdateAny(
+			rel.From("rides").Where(where.Eq("id", ride.ID).AndEq("status", StatusActive)),
+			rel.Set("status", StatusFinished),
+			rel.Set("updated_at", now),
+			rel.Set("price_amount", int64(218)),
+			rel.Set("price_currency", tariff.Currency),
+			rel.Set("net_amount", int64(218)),
+			rel.Set("tax_amount", int64(0)),
+			rel.Set("items", reltest.Any),
+		).UpdatedCount(1)
+		repository.ExpectInsert().For(&Transition{RideID: ride.ID, From: StatusActive, To: StatusFinished, Actor: "user:1", CreatedAt: now})
+	})
+
+	err, finishedRide := service.FinishRide(ctx, &ride, now)
+	assert.Equal(t, iot.ErrUnreachable, err)
+	assert.Nil(t, finishedRide)
+	assert.Equal(t, StatusActive, ride.Status)
+	assert.True(t, simulator.Unlocked("1"))
+
+	repository.AssertExpectations(t)
+}
+
+func TestForceFinishRideLockFails(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		simulator  = iot.NewSimulator()
+		service    = newService(repository, func(d *Deps) { d.Gateway = simulator })
+		now        = time.Now()
+		createdAt  = now.Add(-time.Second * 61)
+		ride       = Ride{ID: 1, UserID: "1", VehicleID: "1", Price: eur(18), CreatedAt: createdAt, UpdatedAt: createdAt, Status: StatusActive, Tariff: tariff}
+	)
+	simulator.Fail("1", -1)
+
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectFindAll(where.Eq("ride_id", ride.ID)).Result([]Pause{})
+		repository.ExpectUpdateAny(
+			rel.From("rides").Where(where.Eq("id", ride.ID).AndEq("status", StatusActive)),
+			rel.Set("status", StatusForceClosed),
+			rel.Set("updated_at", now),
+			rel.Set("price_amount", int64(218)),
+			rel.Set("price_currency", tariff.Currency),
+			rel.Set("net_amount", int64(218)),
+			rel.Set("tax_amount", int64(0)),
+			rel.Set("items", reltest.Any),
+		).UpdatedCount(1)
+		repository.ExpectInsert().For(&Transition{RideID: ride.ID, From: StatusActive, To: StatusForceClosed, Actor: "system:low_battery", CreatedAt: now})
+	})
+
+	err, _ := service.ForceFinishRide(ctx, &ride, "low_battery", now)
+	assert.Nil(t, err)
+	assert.Equal(t, StatusForceClosed, ride.Status)
+
+	repository.AssertExpectations(t)
+}
+
+func TestFinishRideUpdateError(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = newService(repository)
+		now        = time.Now()
+		createdAt  = now.Add(-time.Second * 1565)
+		ride       = Ride{ID: 1, UserID: "1", VehicleID: "1", Price: eur(18), CreatedAt: createdAt, UpdatedAt: createdAt, Status: StatusActive, Tariff: tariff}
+	)
+
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectFindAll(where.Eq("ride_id", ride.ID)).Result([]Pause{})
+		repository.ExpectUpdateAny(
+			rel.From("rides").Where(where.Eq("id", ride.ID).AndEq("status", StatusActive)),
+			rel.Set("status", StatusFinished),
+			rel.Set("updated_at", now),
+			rel.Set("price_amount", int64(2718)),
+			rel.Set("price_currency", tariff.Currency),
+			rel.Set("net_amount", int64(2718)),
+			rel.Set("tax_amount", int64(0)),
+			rel.Set("items", reltest.Any),
+		).ConnectionClosed()
+	})
+
+	err, _ := service.FinishRide(ctx, &ride, now)
+	assert.Equal(t, reltest.ErrConnectionClosed, err)
+	assert.Equal(t, eur(18), ride.Price)
+	assert.Equal(t, StatusActive, ride.Status)
+
+	repository.AssertExpectations(t)
+}
+
+func TestFinishRideCancelled(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = newService(repository)
+		now        = time.Now()
+		createdAt  = now.Add(-time.Second * 1565)
+		ride       = Ride{ID: 1, UserID: "1", VehicleID: "1", Price: eur(18), CreatedAt: createdAt, UpdatedAt: createdAt, Status: StatusCancelled, Tariff: tariff}
+	)
+
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectFindAll(where.Eq("ride_id", ride.ID)).Result([]Pause{})
+	})
+
+	err, _ := service.FinishRide(ctx, &ride, now)
+	assert.Equal(t, TransitionError{From: StatusCancelled, To: StatusFinished}, err)
+	assert.Equal(t, StatusCancelled, ride.Status)
+
+	repository.AssertExpectations(t)
+}
+
+func TestFinishRideWithPauses(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = newService(repository)
+		now        = time.Now()
+		createdAt  = now.Add(-time.Minute * 30)
+		resumedAt  = now.Add(-time.Minute * 10)
+		ride       = Ride{ID: 1, UserID: "1", VehicleID: "1", Price: eur(18), CreatedAt: createdAt, UpdatedAt: resumedAt, Status: StatusActive, Tariff: tariff}
+		pauses     = []Pause{{ID: 1, RideID: 1, StartedAt: now.Add(-time.Minute*20 - time.Second*30), EndedAt: &resumedAt}}
+	)
+
+	// 10m30s paused and 19m30s active: 18 + 20*100 + 11*25
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectFindAll(where.Eq("ride_id", ride.ID)).Result(pauses)
+		repository.ExpectUpdateAny(
+			rel.From("rides").Where(where.Eq("id", ride.ID).AndEq("status", StatusActive)),
+			rel.Set("status", StatusFinished),
+			rel.Set("updated_at", now),
+			rel.Set("price_amount", int64(2293)),
+			rel.Set("price_currency", tariff.Currency),
+			rel.Set("net_amount", int64(2293)),
+			rel.Set("tax_amount", int64(0)),
+			rel.Set("items", reltest.Any),
+		).UpdatedCount(1)
+		repository.ExpectInsert().ForType("rides.Transition")
+	})
+
+	err, _ := service.FinishRide(ctx, &ride, now)
+	assert.Nil(t, err)
+	assert.Equal(t, eur(2293), ride.Price)
+	assert.Equal(t, StatusFinished, ride.Status)
+
+	repository.AssertExpectations(t)
+}
+
+func TestFinishPausedRide(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = newService(repository)
+		now        = time.Now()
+		createdAt  = now.Add(-time.Minute * 10)
+		pausedAt   = now.Add(-time.Minute * 4)
+		ride       = Ride{ID: 1, UserID: "1", VehicleID: "1", Price: eur(18), CreatedAt: createdAt, UpdatedAt: pausedAt, Status: StatusPaused, Tariff: tariff}
+		pauses     = []Pause{{ID: 1, RideID: 1, StartedAt: pausedAt}}
+	)
+
+	// 4m paused and 6m active: 18 + 6*100 + 4*25
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectFindAll(where.Eq("ride_id", ride.ID)).Result(pauses)
+		repository.ExpectUpdateAny(
+			rel.From("rides").Where(where.Eq("id", ride.ID).AndEq("status", StatusPaused)),
+			rel.Set("status", StatusFinished),
+			rel.Set("updated_at", now),
+			rel.Set("price_amount", int64(718)),
+			rel.Set("price_currency", tariff.Currency),
+			rel.Set("net_amount", int64(718)),
+			rel.Set("tax_amount", int64(0)),
+			rel.Set("items", reltest.Any),
+		).UpdatedCount(1)
+		repository.ExpectInsert().For(&Transition{RideID: ride.ID, From: StatusPaused, To: StatusFinished, Actor: "user:1", CreatedAt: now})
+		repository.ExpectUpdateAny(
+			rel.From("ride_pauses").Where(where.Eq("ride_id", ride.ID).AndNil("ended_at")),
+			rel.Set("ended_at", now),
+		).UpdatedCount(1)
+	})
+
+	err, _ := service.FinishRide(ctx, &ride, now)
+	assert.Nil(t, err)
+	assert.Equal(t, eur(718), ride.Price)
+	assert.Equal(t, StatusFinished, ride.Status)
+
+	repository.AssertExpectations(t)
+}
+
+func TestFinishRideUsesTariffSnapshot(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		newTariff  = pricing.Tariff{Version: "2", Currency: "EUR", UnlockPrice: 30, MinutePrice: 300, PausedMinutePrice: 50, Billing: pricing.Billing{Unit: pricing.UnitMinute, Rounding: pricing.RoundingCeil}}
+		service    = newService(repository, func(d *Deps) { d.Tariffs = pricing.Fixed{Tariff: newTariff} })
+		now        = time.Now()
+		createdAt  = now.Add(-time.Second * 61)
+		ride       = Ride{ID: 1, UserID: "1", VehicleID: "1", Price: eur(18), CreatedAt: createdAt, UpdatedAt: createdAt, Status: StatusActive, Tariff: tariff}
+	)
+
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectFindAll(where.Eq("ride_id", ride.ID)).Result([]Pause{})
+		repository.ExpectUpdateAny(
+			rel.From("rides").Where(where.Eq("id", ride.ID).AndEq("status", StatusActive)),
+			rel.Set("status", StatusFinished),
+			rel.Set("updated_at", now),
+			rel.Set("price_amount", int64(218)),
+			rel.Set("price_currency", tariff.Currency),
+			rel.Set("net_amount", int64(218)),
+			rel.Set("tax_amount", int64(0)),
+			rel.Set("items", reltest.Any),
+		).UpdatedCount(1)
+		repository.ExpectInsert().ForType("rides.Transition")
+	})
+
+	err, _ := service.FinishRide(ctx, &ride, now)
+	assert.Nil(t, err)
+	assert.Equal(t, eur(218), ride.Price)
+	assert.Equal(t, tariff, ride.Tariff)
+
+	repository.AssertExpectations(t)
+}
+
+func TestFinishRideAcrossRuleWindow(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = newService(repository)
+		nightPrice = 50
+		night      = pricing.Tariff{Version: "2", Currency: "EUR", UnlockPrice: 18, MinutePrice: 100, PausedMinutePrice: 25, Billing: pricing.Billing{Unit: pricing.UnitMinute, Rounding: pricing.RoundingCeil}, TimeZone: "Europe/Madrid",
+			Rules: pricing.Rules{{Name: "Night", From: "22:00", To: "06:00", MinutePrice: &nightPrice}}}
+		createdAt = time.Date(2026, 10, 16, 19, 50, 0, 0, time.UTC) // 21:50 in Madrid
+		now       = createdAt.Add(time.Minute*20 + time.Second)
+		ride      = Ride{ID: 1, UserID: "1", VehicleID: "1", Price: eur(18), CreatedAt: createdAt, UpdatedAt: createdAt, Status: StatusActive, Tariff: night}
+	)
+
+	// 10m before 22:00 and 10m1s after: 18 + 10*100 + 11*50
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectFindAll(where.Eq("ride_id", ride.ID)).Result([]Pause{})
+		repository.ExpectUpdateAny(
+			rel.From("rides").Where(where.Eq("id", ride.ID).AndEq("status", StatusActive)),
+			rel.Set("status", StatusFinished),
+			rel.Set("updated_at", now),
+			rel.Set("price_amount", int64(1568)),
+			rel.Set("price_currency", tariff.Currency),
+			rel.Set("net_amount", int64(1568)),
+			rel.Set("tax_amount", int64(0)),
+			rel.Set("items", reltest.Any),
+		).UpdatedCount(1)
+		repository.ExpectInsert().ForType("rides.Transition")
+	})
+
+	err, _ := service.FinishRide(ctx, &ride, now)
+	assert.Nil(t, err)
+	assert.Equal(t, eur(1568), ride.Price)
+
+	repository.AssertExpectations(t)
+}
+
+func TestFinishRideCaps(t *testing.T) {
+	var (
+		createdAt = time.Date(2026, 10, 18, 10, 0, 0, 0, time.UTC) // 12:00 in Madrid
+		now       = createdAt.Add(20 * time.Minute)
+		capped    = tariff
+	)
+	capped.TimeZone = "Europe/Madrid"
+	capped.Caps = pricing.Caps{Daily: 1500, Weekly: 4000}
+
+	tests := []struct {
+		name   string
+		daily  int
+		weekly int
+		price  int64
+		caps   []string
+	}{
+		{"first ride", 0, 0, 1500, []string{"Daily cap"}},
+		{"daily partly used", 1000, 1000, 500, []string{"Daily cap"}},
+		{"weekly almost used", 0, 3800, 200, []string{"Daily cap", "Weekly cap"}},
+		{"daily used", 1500, 1500, 0, []string{"Daily cap"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				ctx        = context.TODO()
+				repository = reltest.New()
+				service    = newService(repository)
+				ride       = Ride{ID: 7, UserID: "1", VehicleID: "1", Price: eur(18), CreatedAt: createdAt, UpdatedAt: createdAt, Status: StatusActive, Tariff: capped}
+				charged    = func(since time.Time) rel.Query {
+					return rel.From("rides").Where(
+						where.Eq("user_id", "1").
+							AndIn("status", closedStatuses...).
+							AndEq("price_currency", capped.Currency).
+							AndGte("created_at", since).
+							AndNe("id", ride.ID),
+					)
+				}
+			)
+
+			repository.ExpectTransaction(func(repository *reltest.Repository) {
+				repository.ExpectFindAll(where.Eq("ride_id", ride.ID)).Result([]Pause{})
+				repository.ExpectAggregate(charged(time.Date(2026, 10, 18, 0, 0, 0, 0, capped.Location())), "sum", "net_amount").Result(test.daily)
+				repository.ExpectAggregate(charged(createdAt.Add(-7*24*time.Hour)), "sum", "net_amount").Result(test.weekly)
+				repository.ExpectUpdateAny(
+					rel.From("rides").Where(where.Eq("id", ride.ID).AndEq("status", StatusActive)),
+					rel.Set("status", StatusFinished),
+					rel.Set("updated_at", now),
+					rel.Set("price_amount", test.price),
+					rel.Set("price_currency", capped.Currency),
+					rel.Set("net_amount", test.price),
+					rel.Set("tax_amount", int64(0)),
+					rel.Set("items", reltest.Any),
+				).UpdatedCount(1)
+				repository.ExpectInsert().ForType("rides.Transition")
+			})
+
+			err, _ := service.FinishRide(ctx, &ride, now)
+			assert.Nil(t, err)
+			assert.Equal(t, eur(test.price), ride.Price)
+
+			var caps []string
+			for _, item := range ride.Items {
+				if item.Kind == pricing.KindCap {
+					caps = append(caps, item.Description)
+				}
+			}
+			assert.Equal(t, test.caps, caps)
+
+			repository.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFinishRideWithoutCaps(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = newService(repository)
+		now        = time.Now()
+		createdAt  = now.Add(-20 * time.Minute)
+		ride       = Ride{ID: 1, UserID: "1", VehicleID: "1", Price: eur(18), CreatedAt: createdAt, UpdatedAt: createdAt, Status: StatusActive, Tariff: tariff}
+	)
+
+	// Without caps the charges of the other rides are not looked up.
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectFindAll(where.Eq("ride_id", ride.ID)).Result([]Pause{})
+		repository.ExpectUpdateAny(
+			rel.From("rides").Where(where.Eq("id", ride.ID).AndEq("status", StatusActive)),
+			rel.Set("status", StatusFinished),
+			rel.Set("updated_at", now),
+			rel.Set("price_amount", int64(2018)),
+			rel.Set("price_currency", tariff.Currency),
+			rel.Set("net_amount", int64(2018)),
+			rel.Set("tax_amount", int64(0)),
+			rel.Set("items", pricing.LineItems{
+				{Kind: pricing.KindUnlock, Description: "Unlock fee", Quantity: 1, UnitPrice: eur(18), Amount: eur(18)},
+				{Kind: pricing.KindTime, Description: "Riding time (minutes)", Quantity: 20, UnitPrice: eur(100), Amount: eur(2000)},
+			}),
+		).UpdatedCount(1)
+		repository.ExpectInsert().ForType("rides.Transition")
+	})
+
+	err, _ := service.FinishRide(ctx, &ride, now)
+	assert.Nil(t, err)
+	assert.Equal(t, eur(2018), ride.Price)
+	assert.Len(t, ride.Items, 2)
+
+	repository.AssertExpectations(t)
+}
+
+func TestFinishRideWithDiscount(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = newService(repository)
+		now        = time.Now()
+		createdAt  = now.Add(-20 * time.Minute)
+		discount   = pricing.Discount{Code: "WELCOME", Kind: pricing.DiscountFreeMinutes, Minutes: 10}
+		capped     = tariff
+	)
+	capped.Caps.Daily = 1500
+	ride := Ride{ID: 1, UserID: "1", VehicleID: "1", Price: eur(18), CreatedAt: createdAt, UpdatedAt: createdAt, Status: StatusActive, Tariff: capped, PromoCode: "WELCOME", Discount: &discount}
+
+	// The discount comes off before the caps are applied: 18 + 10*100.
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectFindAll(where.Eq("ride_id", ride.ID)).Result([]Pause{})
+		repository.ExpectAggregate(rel.From("rides").Where(
+			where.Eq("user_id", "1").
+				AndIn("status", closedStatuses...).
+				AndEq("price_currency", tariff.Currency).
+				AndGte("created_at", capped.Windows(createdAt)[0].Since).
+				AndNe("id", ride.ID),
+		), "sum", "net_amount").Result(0)
+		repository.ExpectUpdateAny(
+			rel.From("rides").Where(where.Eq("id", ride.ID).AndEq("status", StatusActive)),
+			rel.Set("status", StatusFinished),
+			rel.Set("updated_at", now),
+			rel.Set("price_amount", int64(1018)),
+			rel.Set("price_currency", tariff.Currency),
+			rel.Set("net_amount", int64(1018)),
+			rel.Set("tax_amount", int64(0)),
+			rel.Set("items", reltest.Any),
+		).UpdatedCount(1)
+		repository.ExpectInsert().ForType("rides.Transition")
+	})
+
+	err, _ := service.FinishRide(ctx, &ride, now)
+	assert.Nil(t, err)
+	assert.Equal(t, eur(1018), ride.Price)
+	assert.Len(t, ride.Items, 3)
+	assert.Equal(t, pricing.LineItem{Kind: pricing.KindDiscount, Description: "Promo code WELCOME", Quantity: 1, UnitPrice: eur(-1000), Amount: eur(-1000)}, ride.Items[2])
+
+	repository.AssertExpectations(t)
+}
+
+func TestFinishRideWithPasses(t *testing.T) {
+	var (
+		ctx          = context.TODO()
+		repository   = reltest.New()
+		consumptions []pricing.Consumption
+		allowances   = []pricing.Allowance{{PassID: 3, Name: "Unlimited unlocks", Unlock: true}, {PassID: 4, Name: "100 minutes", Minutes: 5}}
+		service      = newService(repository, func(d *Deps) { d.Passes = balances{allowances: allowances, consumed: &consumptions} })
+		now          = time.Now()
+		createdAt    = now.Add(-20 * time.Minute)
+		ride         = Ride{ID: 1, UserID: "1", VehicleID: "1", Price: eur(18), CreatedAt: createdAt, UpdatedAt: createdAt, Status: StatusActive, Tariff: tariff}
+	)
+
+	// The passes cover the unlock and 5 of the 20 minutes: 15*100.
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectFindAll(where.Eq("ride_id", ride.ID)).Result([]Pause{})
+		repository.ExpectUpdateAny(
+			rel.From("rides").Where(where.Eq("id", ride.ID).AndEq("status", StatusActive)),
+			rel.Set("status", StatusFinished),
+			rel.Set("updated_at", now),
+			rel.Set("price_amount", int64(1500)),
+			rel.Set("price_currency", tariff.Currency),
+			rel.Set("net_amount", int64(1500)),
+			rel.Set("tax_amount", int64(0)),
+			rel.Set("items", reltest.Any),
+		).UpdatedCount(1)
+		repository.ExpectInsert().ForType("rides.Transition")
+	})
+
+	err, _ := service.FinishRide(ctx, &ride, now)
+	assert.Nil(t, err)
+	assert.Equal(t, eur(1500), ride.Price)
+	assert.Equal(t, []pricing.Consumption{
+		{PassID: 3, Unlock: true, Amount: eur(18)},
+		{PassID: 4, Minutes: 5, Amount: eur(500)},
+	}, consumptions)
+
+	repository.AssertExpectations(t)
+}
+
+func TestFinishRideWithTax(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = newService(repository)
+		now        = time.Now()
+		createdAt  = now.Add(-10 * time.Minute)
+		discount   = pricing.Discount{Code: "SPRING", Kind: pricing.DiscountAmountOff, Amount: 105, Currency: "EUR"}
+	)
+
+	tests := []struct {
+		name     string
+		rounding pricing.TaxRounding
+		tax      int64
+	}{
+		// 2.5% of 18 + 1000 - 105 = 913 is 22.825.
+		{"per total", pricing.TaxRoundingPerTotal, 23},
+		// 0.45 + 25 - 2.625: 0 + 25 - 3.
+		{"per line", pricing.TaxRoundingPerLine, 22},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rate := pricing.TaxRate{Name: "VAT", Rate: 250, Rounding: test.rounding}
+			ride := Ride{ID: 1, UserID: "1", VehicleID: "1", Price: eur(20), CreatedAt: createdAt, UpdatedAt: createdAt, Status: StatusActive, Tariff: tariff, Discount: &discount, TaxRate: rate}
+
+			repository.ExpectTransaction(func(repository *reltest.Repository) {
+				repository.ExpectFindAll(where.Eq("ride_id", ride.ID)).Result([]Pause{})
+				repository.ExpectUpdateAny(
+					rel.From("rides").Where(where.Eq("id", ride.ID).AndEq("status", StatusActive)),
+					rel.Set("status", StatusFinished),
+					rel.Set("updated_at", now),
+					rel.Set("price_amount", 913+test.tax),
+					rel.Set("price_currency", tariff.Currency),
+					rel.Set("net_amount", int64(913)),
+					rel.Set("tax_amount", test.tax),
+					rel.Set("items", reltest.Any),
+				).UpdatedCount(1)
+				repository.ExpectInsert().ForType("rides.Transition")
+			})
+
+			err, _ := service.FinishRide(ctx, &ride, now)
+			assert.Nil(t, err)
+			assert.Equal(t, eur(913+test.tax), ride.Price)
+			assert.Equal(t, int64(913), ride.NetAmount)
+			assert.Equal(t, test.tax, ride.TaxAmount)
+			assert.Equal(t, pricing.KindTax, ride.Items[len(ride.Items)-1].Kind)
+
+			repository.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFinishPrepaidRide(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		debited    []money.Money
+		prepaid    = tariff
+		service    = newService(repository, func(d *Deps) { d.Wallets = purse{debited: &debited} })
+		now        = time.Now()
+		createdAt  = now.Add(-time.Second * 1)
+	)
+	prepaid.Prepaid = pricing.Prepaid{Enabled: true, MinimumBalance: 500}
+	ride := Ride{ID: 1, UserID: "1", VehicleID: "1", Price: eur(18), CreatedAt: createdAt, UpdatedAt: createdAt, Status: StatusActive, Tariff: prepaid}
+
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectFindAll(where.Eq("ride_id", ride.ID)).Result([]Pause{})
+		repository.ExpectUpdateAny(
+			rel.From("rides").Where(where.Eq("id", ride.ID).AndEq("status", StatusActive)),
+			rel.Set("status", StatusFinished),
+			rel.Set("updated_at", now),
+			rel.Set("price_amount", int64(118)),
+			rel.Set("price_currency", tariff.Currency),
+			rel.Set("net_amount", int64(118)),
+			rel.Set("tax_amount", int64(0)),
+			rel.Set("items", reltest.Any),
+			rel.Set("payment_status", payments.StatusDebited),
+		).UpdatedCount(1)
+		repository.ExpectInsert().For(&Transition{RideID: ride.ID, From: StatusActive, To: StatusFinished, Actor: "user:1", CreatedAt: now})
+	})
+
+	err, _ := service.FinishRide(ctx, &ride, now)
+	assert.Nil(t, err)
+	assert.Equal(t, []money.Money{eur(118)}, debited)
+	assert.Equal(t, payments.StatusDebited, ride.PaymentStatus)
 
 	repository.AssertExpectations(t)
 }
+
+func TestFinishBillingPolicies(t *testing.T) {
+	var (
+		perSecond = pricing.Billing{Unit: pricing.UnitSecond, Rounding: pricing.RoundingCeil}
+		floor     = pricing.Billing{Unit: pricing.UnitMinute, Rounding: pricing.RoundingFloor}
+		halfUp    = pricing.Billing{Unit: pricing.UnitMinute, Rounding: pricing.RoundingHalfUp}
+		blocks    = pricing.Billing{Unit: pricing.UnitBlock, BlockMinutes: 15, Rounding: pricing.RoundingCeil}
+		grace     = pricing.Billing{Unit: pricing.UnitMinute, Rounding: pricing.RoundingCeil, GraceSeconds: 60}
+		minimum   = pricing.Billing{Unit: pricing.UnitMinute, Rounding: pricing.RoundingCeil, MinimumCharge: 250}
+		maximum   = pricing.Billing{Unit: pricing.UnitMinute, Rounding: pricing.RoundingCeil, MaximumCharge: 1000}
+	)
+
+	tests := []struct {
+		name     string
+		billing  pricing.Billing
+		duration time.Duration
+		price    int64
+	}{
+		{"per second 1 second", perSecond, time.Second, 20},
+		{"per second 59 seconds", perSecond, 59 * time.Second, 116},
+		{"per second 60 seconds", perSecond, 60 * time.Second, 118},
+		{"per second 61 seconds", perSecond, 61 * time.Second, 120},
+		{"floor 59 seconds", floor, 59 * time.Second, 18},
+		{"floor 60 seconds", floor, 60 * time.Second, 118},
+		{"floor 119 seconds", floor, 119 * time.Second, 118},
+		{"floor 120 seconds", floor, 120 * time.Second, 218},
+		{"half up 29 seconds", halfUp, 29 * time.Second, 18},
+		{"half up 30 seconds", halfUp, 30 * time.Second, 118},
+		{"half up 89 seconds", halfUp, 89 * time.Second, 118},
+		{"half up 90 seconds", halfUp, 90 * time.Second, 218},
+		{"blocks 1 second", blocks, time.Second, 1518},
+		{"blocks 15 minutes", blocks, 15 * time.Minute, 1518},
+		{"blocks 15 minutes 1 second", blocks, 15*time.Minute + time.Second, 3018},
+		{"grace 60 seconds", grace, 60 * time.Second, 18},
+		{"grace 61 seconds", grace, 61 * time.Second, 118},
+		{"grace 120 seconds", grace, 120 * time.Second, 118},
+		{"grace 121 seconds", grace, 121 * time.Second, 218},
+		{"minimum 1 second", minimum, time.Second, 250},
+		{"minimum 121 seconds", minimum, 121 * time.Second, 318},
+		{"maximum 9 minutes", maximum, 9 * time.Minute, 918},
+		{"maximum 10 minutes", maximum, 10 * time.Minute, 1000},
+		{"maximum 1 hour", maximum, time.Hour, 1000},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				ctx        = context.TODO()
+				repository = reltest.New()
+				service    = newService(repository)
+				now        = time.Now()
+				createdAt  = now.Add(-test.duration)
+				billed     = tariff
+			)
+			billed.Billing = test.billing
+			ride := Ride{ID: 1, UserID: "1", VehicleID: "1", Price: eur(18), CreatedAt: createdAt, UpdatedAt: createdAt, Status: StatusActive, Tariff: billed}
+
+			repository.ExpectTransaction(func(repository *reltest.Repository) {
+				repository.ExpectFindAll(where.Eq("ride_id", ride.ID)).Result([]Pause{})
+				repository.ExpectUpdateAny(
+					rel.From("rides").Where(where.Eq("id", ride.ID).AndEq("status", StatusActive)),
+					rel.Set("status", StatusFinished),
+					rel.Set("updated_at", now),
+					rel.Set("price_amount", test.price),
+					rel.Set("price_currency", tariff.Currency),
+					rel.Set("net_amount", test.price),
+					rel.Set("tax_amount", int64(0)),
+					rel.Set("items", reltest.Any),
+				).UpdatedCount(1)
+				repository.ExpectInsert().For(&Transition{RideID: ride.ID, From: StatusActive, To: StatusFinished, Actor: "user:1", CreatedAt: now})
+			})
+
+			err, _ := service.FinishRide(ctx, &ride, now)
+			assert.Nil(t, err)
+			assert.Equal(t, eur(test.price), ride.Price)
+
+			repository.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package rides
+
+import (
+	"context"
+	"errors"
+
+	"github.com/go-rel/rel"
+	"github.com/go-rel/rel/where"
+)
+
+type getRide struct {
+	repository rel.Repository
+}
+
+func (c getRide) GetRide(ctx context.Context, id uint) (error, *Ride) {
+	var ride Ride
+	if err := c.repository.Find(ctx, &ride, where.Eq("id", id)); err != nil {
+		if errors.Is(err, rel.ErrNotFound) {
+			return ErrRideNotFound, nil
+		}
+		return err, nil
+	}
+
+	return nil, &ride
+}
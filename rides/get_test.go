@@ -0,0 +1,43 @@
+package rides
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-rel/rel/where"
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetRide(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = newService(repository)
+		ride       = Ride{ID: 1, UserID: "1", VehicleID: "1", Price: eur(18)}
+	)
+
+	repository.ExpectFind(where.Eq("id", uint(1))).Result(ride)
+
+	err, foundRide := service.GetRide(ctx, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, ride, *foundRide)
+
+	repository.AssertExpectations(t)
+}
+
+func TestGetRideNotFound(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = newService(repository)
+	)
+
+	repository.ExpectFind(where.Eq("id", uint(2))).NotFound()
+
+	err, foundRide := service.GetRide(ctx, 2)
+	assert.Equal(t, ErrRideNotFound, err)
+	assert.Nil(t, foundRide)
+
+	repository.AssertExpectations(t)
+}
@@ -0,0 +1,123 @@
+package rides
+
+import (
+	"context"
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-rel/rel"
+	"github.com/go-rel/rel/where"
+)
+
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+// Filter narrows down a ride listing. Zero values are ignored.
+type Filter struct {
+	UserID        string
+	VehicleID     string
+	Status        Status
+	CreatedAfter  time.Time // inclusive
+	CreatedBefore time.Time // exclusive
+	Cursor        string
+	Limit         int
+}
+
+// Page is a slice of rides sorted from newest to oldest.
+// NextCursor is empty on the last page.
+type Page struct {
+	Rides      []Ride `json:"rides"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+type listRides struct {
+	repository rel.Repository
+}
+
+func (c listRides) ListRides(ctx context.Context, filter Filter) (error, *Page) {
+	limit := filter.Limit
+	switch {
+	case limit <= 0:
+		limit = DefaultPageSize
+	case limit > MaxPageSize:
+		limit = MaxPageSize
+	}
+
+	var filters []rel.FilterQuery
+	if filter.UserID != "" {
+		filters = append(filters, where.Eq("user_id", filter.UserID))
+	}
+	if filter.VehicleID != "" {
+		filters = append(filters, where.Eq("vehicle_id", filter.VehicleID))
+	}
+	if filter.Status != "" {
+		filters = append(filters, where.Eq("status", filter.Status))
+	}
+	if !filter.CreatedAfter.IsZero() {
+		filters = append(filters, where.Gte("created_at", filter.CreatedAfter))
+	}
+	if !filter.CreatedBefore.IsZero() {
+		filters = append(filters, where.Lt("created_at", filter.CreatedBefore))
+	}
+	if filter.Cursor != "" {
+		createdAt, id, err := decodeCursor(filter.Cursor)
+		if err != nil {
+			return err, nil
+		}
+		// Keyset pagination on (created_at, id), which is unique and stable
+		// even when rides share the same creation time.
+		filters = append(filters, where.Or(
+			where.Lt("created_at", createdAt),
+			where.Eq("created_at", createdAt).AndLt("id", id),
+		))
+	}
+
+	var (
+		rides []Ride
+		query = rel.From("rides").Where(filters...).SortDesc("created_at", "id").Limit(limit + 1)
+	)
+	if err := c.repository.FindAll(ctx, &rides, query); err != nil {
+		return err, nil
+	}
+
+	page := &Page{Rides: rides}
+	if len(rides) > limit {
+		page.Rides = rides[:limit]
+		page.NextCursor = encodeCursor(page.Rides[limit-1])
+	}
+
+	return nil, page
+}
+
+func encodeCursor(ride Ride) string {
+	raw := ride.CreatedAt.UTC().Format(time.RFC3339Nano) + "|" + strconv.FormatUint(uint64(ride.ID), 10)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeCursor(cursor string) (time.Time, uint, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, 0, ErrInvalidCursor
+	}
+
+	parts := strings.SplitN(string(raw), "|", 2)
+	if len(parts) != 2 {
+		return time.Time{}, 0, ErrInvalidCursor
+	}
+
+	createdAt, err := time.Parse(time.RFC3339Nano, parts[0])
+	if err != nil {
+		return time.Time{}, 0, ErrInvalidCursor
+	}
+
+	id, err := strconv.ParseUint(parts[1], 10, 0)
+	if err != nil {
+		return time.Time{}, 0, ErrInvalidCursor
+	}
+
+	return createdAt, uint(id), nil
+}
@@ -0,0 +1,140 @@
+package rides
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-rel/rel"
+	"github.com/go-rel/rel/where"
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListRides(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = newService(repository)
+		now        = time.Date(2022, 6, 20, 10, 0, 0, 0, time.UTC)
+		rides      = []Ride{
+			{ID: 3, UserID: "1", VehicleID: "1", CreatedAt: now},
+			{ID: 2, UserID: "1", VehicleID: "2", CreatedAt: now.Add(-time.Minute)},
+		}
+	)
+
+	repository.ExpectFindAll(
+		rel.From("rides").Where(where.Eq("user_id", "1")).SortDesc("created_at", "id").Limit(DefaultPageSize + 1),
+	).Result(rides)
+
+	err, page := service.ListRides(ctx, Filter{UserID: "1"})
+	assert.Nil(t, err)
+	assert.Equal(t, rides, page.Rides)
+	assert.Empty(t, page.NextCursor)
+
+	repository.AssertExpectations(t)
+}
+
+func TestListRidesFilters(t *testing.T) {
+	var (
+		ctx           = context.TODO()
+		repository    = reltest.New()
+		service       = newService(repository)
+		createdAfter  = time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC)
+		createdBefore = time.Date(2022, 7, 1, 0, 0, 0, 0, time.UTC)
+	)
+
+	repository.ExpectFindAll(
+		rel.From("rides").Where(
+			where.Eq("user_id", "1"),
+			where.Eq("vehicle_id", "2"),
+			where.Eq("status", StatusFinished),
+			where.Gte("created_at", createdAfter),
+			where.Lt("created_at", createdBefore),
+		).SortDesc("created_at", "id").Limit(11),
+	).Result([]Ride{})
+
+	err, page := service.ListRides(ctx, Filter{
+		UserID:        "1",
+		VehicleID:     "2",
+		Status:        StatusFinished,
+		CreatedAfter:  createdAfter,
+		CreatedBefore: createdBefore,
+		Limit:         10,
+	})
+	assert.Nil(t, err)
+	assert.Empty(t, page.Rides)
+	assert.Empty(t, page.NextCursor)
+
+	repository.AssertExpectations(t)
+}
+
+func TestListRidesPagination(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = newService(repository)
+		now        = time.Date(2022, 6, 20, 10, 0, 0, 0, time.UTC)
+		rides      = []Ride{
+			{ID: 5, UserID: "1", VehicleID: "1", CreatedAt: now},
+			{ID: 4, UserID: "1", VehicleID: "1", CreatedAt: now.Add(-time.Minute)},
+			{ID: 3, UserID: "1", VehicleID: "1", CreatedAt: now.Add(-time.Minute)},
+		}
+	)
+
+	repository.ExpectFindAll(
+		rel.From("rides").Where().SortDesc("created_at", "id").Limit(3),
+	).Result(rides)
+
+	err, page := service.ListRides(ctx, Filter{Limit: 2})
+	assert.Nil(t, err)
+	assert.Equal(t, rides[:2], page.Rides)
+	assert.NotEmpty(t, page.NextCursor)
+
+	repository.ExpectFindAll(
+		rel.From("rides").Where(where.Or(
+			where.Lt("created_at", rides[1].CreatedAt),
+			where.Eq("created_at", rides[1].CreatedAt).AndLt("id", rides[1].ID),
+		)).SortDesc("created_at", "id").Limit(3),
+	).Result(rides[2:])
+
+	err, page = service.ListRides(ctx, Filter{Limit: 2, Cursor: page.NextCursor})
+	assert.Nil(t, err)
+	assert.Equal(t, rides[2:], page.Rides)
+	assert.Empty(t, page.NextCursor)
+
+	repository.AssertExpectations(t)
+}
+
+func TestListRidesMaxPageSize(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = newService(repository)
+	)
+
+	repository.ExpectFindAll(
+		rel.From("rides").Where().SortDesc("created_at", "id").Limit(MaxPageSize + 1),
+	).Result([]Ride{})
+
+	err, _ := service.ListRides(ctx, Filter{Limit: 1000})
+	assert.Nil(t, err)
+
+	repository.AssertExpectations(t)
+}
+
+func TestListRidesInvalidCursor(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = newService(repository)
+	)
+
+	for _, cursor := range []string{"not base64!", "bm8tc2VwYXJhdG9y", "YmFkfDE", "MjAyMi0wNi0yMFQxMDowMDowMFp8eA"} {
+		err, page := service.ListRides(ctx, Filter{Cursor: cursor})
+		assert.Equal(t, ErrInvalidCursor, err, cursor)
+		assert.Nil(t, page)
+	}
+
+	repository.AssertExpectations(t)
+}
@@ -0,0 +1,30 @@
+package rides
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-rel/rel"
+)
+
+type pauseRide struct {
+	repository rel.Repository
+}
+
+func (c pauseRide) PauseRide(ctx context.Context, ride *Ride, now time.Time) (error, *Ride) {
+	err := c.repository.Transaction(ctx, func(ctx context.Context) error {
+		if err := transition(ctx, c.repository, ride, StatusPaused, userActor(ride), now); err != nil {
+			return err
+		}
+
+		return c.repository.Insert(ctx, &Pause{RideID: ride.ID, StartedAt: now})
+	})
+	if err != nil {
+		return err, nil
+	}
+
+	ride.Status = StatusPaused
+	ride.UpdatedAt = now
+
+	return nil, ride
+}
@@ -0,0 +1,82 @@
+package rides
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-rel/rel"
+	"github.com/go-rel/rel/where"
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPauseRide(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = newService(repository)
+		now        = time.Now()
+		createdAt  = now.Add(-time.Minute * 5)
+		ride       = Ride{ID: 1, UserID: "1", VehicleID: "1", Price: eur(18), CreatedAt: createdAt, UpdatedAt: createdAt, Status: StatusActive, Tariff: tariff}
+	)
+
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectUpdateAny(
+			rel.From("rides").Where(where.Eq("id", ride.ID).AndEq("status", StatusActive)),
+			rel.Set("status", StatusPaused),
+			rel.Set("updated_at", now),
+		).UpdatedCount(1)
+		repository.ExpectInsert().For(&Transition{RideID: ride.ID, From: StatusActive, To: StatusPaused, Actor: "user:1", CreatedAt: now})
+		repository.ExpectInsert().For(&Pause{RideID: ride.ID, StartedAt: now})
+	})
+
+	err, savedRide := service.PauseRide(ctx, &ride, now)
+	assert.Nil(t, err)
+	assert.Equal(t, StatusPaused, savedRide.Status)
+	assert.Equal(t, now, savedRide.UpdatedAt)
+	assert.Equal(t, eur(18), savedRide.Price)
+
+	repository.AssertExpectations(t)
+}
+
+func TestPauseRideAlreadyPaused(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = newService(repository)
+		now        = time.Now()
+		ride       = Ride{ID: 1, UserID: "1", VehicleID: "1", Price: eur(18), Status: StatusPaused, Tariff: tariff}
+	)
+
+	repository.ExpectTransaction(func(repository *reltest.Repository) {})
+
+	err, _ := service.PauseRide(ctx, &ride, now)
+	assert.Equal(t, TransitionError{From: StatusPaused, To: StatusPaused}, err)
+
+	repository.AssertExpectations(t)
+}
+
+func TestPauseRideStatusChanged(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = newService(repository)
+		now        = time.Now()
+		ride       = Ride{ID: 1, UserID: "1", VehicleID: "1", Price: eur(18), Status: StatusActive, Tariff: tariff}
+	)
+
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectUpdateAny(
+			rel.From("rides").Where(where.Eq("id", ride.ID).AndEq("status", StatusActive)),
+			rel.Set("status", StatusPaused),
+			rel.Set("updated_at", now),
+		).UpdatedCount(0)
+	})
+
+	err, _ := service.PauseRide(ctx, &ride, now)
+	assert.Equal(t, ErrRideStatusChanged, err)
+	assert.Equal(t, StatusActive, ride.Status)
+
+	repository.AssertExpectations(t)
+}
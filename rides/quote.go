@@ -0,0 +1,66 @@
+package rides
+
+import (
+	"context"
+	"time"
+
+	"backend/money"
+	"backend/pricing"
+)
+
+// Quote is what a ride in progress would be charged if it finished at At,
+// with the time units billed so far in the billing unit of its tariff.
+type Quote struct {
+	RideID      uint              `json:"ride_id"`
+	At          time.Time         `json:"at"`
+	Unit        pricing.Unit      `json:"unit"`
+	RidingUnits int               `json:"riding_units"`
+	PausedUnits int               `json:"paused_units"`
+	Price       money.Money       `json:"price"`
+	Net         money.Money       `json:"net"`
+	Tax         money.Money       `json:"tax"`
+	Items       pricing.LineItems `json:"items"`
+}
+
+type quoteRide struct {
+	finishRide finishRide
+}
+
+// QuoteRide prices the ride the way FinishRide would at the given instant,
+// without consuming passes nor storing anything.
+func (c quoteRide) QuoteRide(ctx context.Context, ride *Ride, at time.Time) (error, *Quote) {
+	if ride.Status != StatusActive && ride.Status != StatusPaused {
+		return ErrRideNotInProgress, nil
+	}
+	if at.Before(ride.CreatedAt) {
+		return ErrQuoteAtInvalid, nil
+	}
+
+	quote, err := c.finishRide.quote(ctx, ride, at, false)
+	if err != nil {
+		return err, nil
+	}
+
+	net, tax, err := quote.Breakdown()
+	if err != nil {
+		return err, nil
+	}
+
+	// The zero billing policy of older tariffs bills minutes.
+	unit := ride.Tariff.Billing.Unit
+	if unit == "" {
+		unit = pricing.UnitMinute
+	}
+
+	return nil, &Quote{
+		RideID:      ride.ID,
+		At:          at,
+		Unit:        unit,
+		RidingUnits: quote.Units(pricing.KindTime),
+		PausedUnits: quote.Units(pricing.KindPausedTime),
+		Price:       quote.Price(),
+		Net:         net,
+		Tax:         tax,
+		Items:       quote.Items,
+	}
+}
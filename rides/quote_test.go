@@ -0,0 +1,130 @@
+package rides
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"backend/pricing"
+
+	"github.com/go-rel/rel/where"
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuoteRide(t *testing.T) {
+	var (
+		ctx          = context.TODO()
+		repository   = reltest.New()
+		consumptions []pricing.Consumption
+		allowances   = []pricing.Allowance{{PassID: 3, Name: "100 minutes", Minutes: 5}}
+		service      = newService(repository, func(d *Deps) { d.Passes = balances{allowances: allowances, consumed: &consumptions} })
+		createdAt    = time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+		at           = createdAt.Add(20 * time.Minute)
+		ride         = Ride{ID: 1, UserID: "1", VehicleID: "1", Price: eur(18), CreatedAt: createdAt, UpdatedAt: createdAt, Status: StatusActive, Tariff: tariff}
+	)
+
+	repository.ExpectFindAll(where.Eq("ride_id", ride.ID)).Result([]Pause{})
+
+	// 18 + 20*100, less the 5 minutes the pass covers.
+	err, quote := service.QuoteRide(ctx, &ride, at)
+	assert.Nil(t, err)
+	assert.Equal(t, at, quote.At)
+	assert.Equal(t, pricing.UnitMinute, quote.Unit)
+	assert.Equal(t, 20, quote.RidingUnits)
+	assert.Equal(t, 0, quote.PausedUnits)
+	assert.Equal(t, eur(1518), quote.Price)
+	assert.Empty(t, consumptions)
+	assert.Equal(t, StatusActive, ride.Status)
+	assert.Equal(t, eur(18), ride.Price)
+
+	repository.AssertExpectations(t)
+}
+
+func TestQuotePausedRide(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = newService(repository)
+		createdAt  = time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+		ride       = Ride{ID: 1, UserID: "1", VehicleID: "1", Price: eur(18), CreatedAt: createdAt, UpdatedAt: createdAt, Status: StatusPaused, Tariff: tariff}
+		pause      = Pause{ID: 1, RideID: 1, StartedAt: createdAt.Add(10 * time.Minute)}
+	)
+
+	// The open pause is projected up to the quoted instant.
+	repository.ExpectFindAll(where.Eq("ride_id", ride.ID)).Result([]Pause{pause})
+
+	err, quote := service.QuoteRide(ctx, &ride, createdAt.Add(30*time.Minute))
+	assert.Nil(t, err)
+	assert.Equal(t, 10, quote.RidingUnits)
+	assert.Equal(t, 20, quote.PausedUnits)
+	assert.Equal(t, eur(18+10*100+20*25), quote.Price)
+
+	repository.AssertExpectations(t)
+}
+
+func TestQuoteRideBeforePauseEnds(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = newService(repository)
+		createdAt  = time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+		ride       = Ride{ID: 1, UserID: "1", VehicleID: "1", Price: eur(18), CreatedAt: createdAt, UpdatedAt: createdAt, Status: StatusActive, Tariff: tariff}
+		endedAt    = createdAt.Add(20 * time.Minute)
+		pause      = Pause{ID: 1, RideID: 1, StartedAt: createdAt.Add(10 * time.Minute), EndedAt: &endedAt}
+	)
+
+	// A pause that starts after the quoted instant is left out.
+	repository.ExpectFindAll(where.Eq("ride_id", ride.ID)).Result([]Pause{pause})
+
+	err, quote := service.QuoteRide(ctx, &ride, createdAt.Add(5*time.Minute))
+	assert.Nil(t, err)
+	assert.Equal(t, 5, quote.RidingUnits)
+	assert.Equal(t, 0, quote.PausedUnits)
+	assert.Equal(t, eur(518), quote.Price)
+
+	// A pause that ends after it is cut short.
+	repository.ExpectFindAll(where.Eq("ride_id", ride.ID)).Result([]Pause{pause})
+
+	err, quote = service.QuoteRide(ctx, &ride, createdAt.Add(15*time.Minute))
+	assert.Nil(t, err)
+	assert.Equal(t, 10, quote.RidingUnits)
+	assert.Equal(t, 5, quote.PausedUnits)
+	assert.Equal(t, eur(18+10*100+5*25), quote.Price)
+
+	repository.AssertExpectations(t)
+}
+
+func TestQuoteRideNotInProgress(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = newService(repository)
+		now        = time.Now()
+	)
+
+	for _, status := range []Status{StatusReserved, StatusFinished, StatusCancelled, StatusForceClosed} {
+		ride := Ride{ID: 1, UserID: "1", VehicleID: "1", CreatedAt: now, Status: status, Tariff: tariff}
+		err, quote := service.QuoteRide(ctx, &ride, now)
+		assert.Equal(t, ErrRideNotInProgress, err)
+		assert.Nil(t, quote)
+	}
+
+	repository.AssertExpectations(t)
+}
+
+func TestQuoteRideBeforeStart(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = newService(repository)
+		now        = time.Now()
+		ride       = Ride{ID: 1, UserID: "1", VehicleID: "1", CreatedAt: now, Status: StatusActive, Tariff: tariff}
+	)
+
+	err, quote := service.QuoteRide(ctx, &ride, now.Add(-time.Minute))
+	assert.Equal(t, ErrQuoteAtInvalid, err)
+	assert.Nil(t, quote)
+
+	repository.AssertExpectations(t)
+}
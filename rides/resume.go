@@ -0,0 +1,72 @@
+package rides
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"backend/pricing"
+
+	"github.com/go-rel/rel"
+	"github.com/go-rel/rel/where"
+)
+
+type resumeRide struct {
+	repository rel.Repository
+}
+
+func (c resumeRide) ResumeRide(ctx context.Context, ride *Ride, now time.Time) (error, *Ride) {
+	err := c.repository.Transaction(ctx, func(ctx context.Context) error {
+		if err := transition(ctx, c.repository, ride, StatusActive, userActor(ride), now); err != nil {
+			return err
+		}
+
+		return endPause(ctx, c.repository, ride, now)
+	})
+	if err != nil {
+		return err, nil
+	}
+
+	ride.Status = StatusActive
+	ride.UpdatedAt = now
+
+	return nil, ride
+}
+
+// endPause closes the open pause interval of the ride.
+func endPause(ctx context.Context, repository rel.Repository, ride *Ride, now time.Time) error {
+	_, err := repository.UpdateAny(ctx,
+		rel.From("ride_pauses").Where(where.Eq("ride_id", ride.ID).AndNil("ended_at")),
+		rel.Set("ended_at", now),
+	)
+	return err
+}
+
+// usage splits the ride into the intervals it spent riding and paused up
+// to now, counting open pauses up to now. Pauses are clipped at now, so a
+// quote for an earlier instant leaves out what happened after it.
+func usage(ride *Ride, pauses []Pause, now time.Time) pricing.Usage {
+	sort.Slice(pauses, func(i, j int) bool {
+		return pauses[i].StartedAt.Before(pauses[j].StartedAt)
+	})
+
+	var (
+		usage pricing.Usage
+		start = ride.CreatedAt
+	)
+	for _, pause := range pauses {
+		if !pause.StartedAt.Before(now) {
+			break
+		}
+		end := now
+		if pause.EndedAt != nil && pause.EndedAt.Before(now) {
+			end = *pause.EndedAt
+		}
+		usage.Active = append(usage.Active, pricing.Interval{Start: start, End: pause.StartedAt})
+		usage.Paused = append(usage.Paused, pricing.Interval{Start: pause.StartedAt, End: end})
+		start = end
+	}
+	usage.Active = append(usage.Active, pricing.Interval{Start: start, End: now})
+
+	return usage
+}
@@ -0,0 +1,91 @@
+package rides
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"backend/pricing"
+
+	"github.com/go-rel/rel"
+	"github.com/go-rel/rel/where"
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResumeRide(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = newService(repository)
+		now        = time.Now()
+		pausedAt   = now.Add(-time.Minute * 2)
+		ride       = Ride{ID: 1, UserID: "1", VehicleID: "1", Price: eur(18), UpdatedAt: pausedAt, Status: StatusPaused, Tariff: tariff}
+	)
+
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectUpdateAny(
+			rel.From("rides").Where(where.Eq("id", ride.ID).AndEq("status", StatusPaused)),
+			rel.Set("status", StatusActive),
+			rel.Set("updated_at", now),
+		).UpdatedCount(1)
+		repository.ExpectInsert().For(&Transition{RideID: ride.ID, From: StatusPaused, To: StatusActive, Actor: "user:1", CreatedAt: now})
+		repository.ExpectUpdateAny(
+			rel.From("ride_pauses").Where(where.Eq("ride_id", ride.ID).AndNil("ended_at")),
+			rel.Set("ended_at", now),
+		).UpdatedCount(1)
+	})
+
+	err, savedRide := service.ResumeRide(ctx, &ride, now)
+	assert.Nil(t, err)
+	assert.Equal(t, StatusActive, savedRide.Status)
+	assert.Equal(t, now, savedRide.UpdatedAt)
+
+	repository.AssertExpectations(t)
+}
+
+func TestResumeRideNotPaused(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = newService(repository)
+		now        = time.Now()
+		ride       = Ride{ID: 1, UserID: "1", VehicleID: "1", Price: eur(18), Status: StatusActive, Tariff: tariff}
+	)
+
+	repository.ExpectTransaction(func(repository *reltest.Repository) {})
+
+	err, _ := service.ResumeRide(ctx, &ride, now)
+	assert.Equal(t, TransitionError{From: StatusActive, To: StatusActive}, err)
+
+	repository.AssertExpectations(t)
+}
+
+func TestUsage(t *testing.T) {
+	var (
+		now       = time.Now()
+		createdAt = now.Add(-time.Minute * 20)
+		ended     = now.Add(-time.Minute * 10)
+		ride      = Ride{CreatedAt: createdAt}
+		pauses    = []Pause{
+			{StartedAt: now.Add(-time.Minute * 3)},
+			{StartedAt: now.Add(-time.Minute * 15), EndedAt: &ended},
+		}
+	)
+
+	assert.Equal(t, pricing.Usage{
+		Active: []pricing.Interval{{Start: createdAt, End: now}},
+	}, usage(&ride, nil, now))
+
+	assert.Equal(t, pricing.Usage{
+		Active: []pricing.Interval{
+			{Start: createdAt, End: now.Add(-time.Minute * 15)},
+			{Start: ended, End: now.Add(-time.Minute * 3)},
+			{Start: now, End: now},
+		},
+		Paused: []pricing.Interval{
+			{Start: now.Add(-time.Minute * 15), End: ended},
+			{Start: now.Add(-time.Minute * 3), End: now},
+		},
+	}, usage(&ride, pauses, now))
+}
@@ -1,18 +1,122 @@
 package rides
 
 import (
+	"encoding/json"
 	"errors"
 	"time"
+
+	"backend/money"
+	"backend/payments"
+	"backend/pricing"
 )
 
 type Ride struct {
 	ID        uint      `json:"id"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
-	Price     int       `json:"price"`
-	UserID    string    `json:"user_id"`
-	VehicleID string    `json:"vehicle_id"`
-	Finished  bool      `json:"finished"`
+	Price     `json:"price" db:"price_" swaggerignore:"true"`
+	UserID    string            `json:"user_id"`
+	VehicleID string            `json:"vehicle_id"`
+	Status    Status            `json:"status"`
+	Tariff    pricing.Tariff    `json:"tariff"` // in effect when the ride started
+	Items     pricing.LineItems `json:"items"`  // what the price is made of
+	PromoCode string            `json:"promo_code,omitempty"`
+	Discount  *pricing.Discount `json:"discount,omitempty"` // of PromoCode when the ride started
+	City      string            `json:"city,omitempty"`
+	Operator  string            `json:"operator,omitempty"`
+	TaxRate   pricing.TaxRate   `json:"tax_rate"` // of City or Operator when the ride started
+	// NetAmount and TaxAmount are Price before tax and the tax included in
+	// it, in the currency of Price like every amount of the ride. They go
+	// out as money.
+	NetAmount int64  `json:"-"`
+	TaxAmount int64  `json:"-"`
+	PaymentID string `json:"payment_id,omitempty"`
+	// HoldAmount is what was authorized on PaymentID when the ride
+	// started.
+	HoldAmount int64 `json:"-"`
+	// PaymentStatus is pending from the moment a ride finishes until its
+	// price is captured.
+	PaymentStatus payments.Status `json:"payment_status,omitempty"`
+	// AdjustmentsAmount is the sum of the adjustments of the ride, which
+	// leave Price as it was charged.
+	AdjustmentsAmount int64 `json:"-"`
+}
+
+// Net is the price of the ride before tax.
+func (r Ride) Net() money.Money {
+	return money.New(r.NetAmount, r.Price.Currency)
+}
+
+// Tax is the tax included in the price of the ride.
+func (r Ride) Tax() money.Money {
+	return money.New(r.TaxAmount, r.Price.Currency)
+}
+
+// Hold is what was authorized on the payment method of the user when the
+// ride started.
+func (r Ride) Hold() money.Money {
+	return money.New(r.HoldAmount, r.Price.Currency)
+}
+
+// Adjustments is the sum of the adjustments of the price of the ride.
+func (r Ride) Adjustments() money.Money {
+	return money.New(r.AdjustmentsAmount, r.Price.Currency)
+}
+
+// EffectivePrice is what the ride costs after its adjustments.
+func (r Ride) EffectivePrice() (money.Money, error) {
+	return r.Price.Add(r.Adjustments())
+}
+
+// MarshalJSON adds the amounts of the ride, as money, to the stored fields.
+func (r Ride) MarshalJSON() ([]byte, error) {
+	effective, err := r.EffectivePrice()
+	if err != nil {
+		return nil, err
+	}
+
+	var hold *money.Money
+	if r.HoldAmount != 0 {
+		amount := r.Hold()
+		hold = &amount
+	}
+
+	type ride Ride
+	return json.Marshal(struct {
+		ride
+		Net            money.Money  `json:"net"`
+		Tax            money.Money  `json:"tax"`
+		Hold           *money.Money `json:"hold,omitempty"`
+		Adjustments    money.Money  `json:"adjustments"`
+		EffectivePrice money.Money  `json:"effective_price"`
+	}{ride(r), r.Net(), r.Tax(), hold, r.Adjustments(), effective})
+}
+
+// Price is what a ride is charged. It is embedded in Ride so that it is
+// stored in the price_amount and price_currency columns. Swag can't tell
+// it apart from an inlined struct, so the API docs describe it per endpoint.
+type Price = money.Money
+
+// charge sets the price of the ride to the quote, broken down into net and
+// tax.
+func (r *Ride) charge(quote pricing.Quote, net, tax money.Money) {
+	r.Price = quote.Price()
+	r.NetAmount = net.Amount
+	r.TaxAmount = tax.Amount
+	r.Items = quote.Items
+}
+
+// Pause is an interval during which a ride was paused.
+// EndedAt is nil while the ride is still paused.
+type Pause struct {
+	ID        uint       `json:"id"`
+	RideID    uint       `json:"ride_id"`
+	StartedAt time.Time  `json:"started_at"`
+	EndedAt   *time.Time `json:"ended_at"`
+}
+
+func (Pause) Table() string {
+	return "ride_pauses"
 }
 
 var (
@@ -20,6 +124,13 @@ var (
 	ErrRideVehicleIDBlank  = errors.New("VehicleID can't be blank")
 	ErrRideAlreadyStarted  = errors.New("A ride is already started for this vehicle or user")
 	ErrRideAlreadyFinished = errors.New("This ride is already finished")
+	ErrRideNotFound        = errors.New("No ride matches the given ID")
+	ErrInvalidCursor       = errors.New("The pagination cursor is not valid")
+	ErrRideStatusChanged   = errors.New("The ride status was changed by another request")
+	ErrRideNotInProgress   = errors.New("Only active or paused rides can be quoted")
+	ErrQuoteAtInvalid      = errors.New("A ride can't be quoted before it started")
+	ErrPaymentNotPending   = errors.New("Only rides whose payment is pending can be captured")
+	ErrCancelTooLate       = errors.New("An active ride can only be cancelled within 2 minutes of its start, it can be finished instead")
 )
 
 func (r Ride) Validate() error {
@@ -1,11 +1,180 @@
 package rides
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
 	"testing"
+	"time"
 
+	"backend/iot"
+	"backend/money"
+	"backend/payments"
+	"backend/pricing"
+
+	"github.com/go-rel/rel"
 	"github.com/stretchr/testify/assert"
 )
 
+var (
+	riders   = members{}
+	vehicles = garage{}
+	gateway  = iot.NewSimulator()
+	tariff   = pricing.Tariff{Version: "1", Currency: "EUR", UnlockPrice: 18, MinutePrice: 100, PausedMinutePrice: 25, Billing: pricing.Billing{Unit: pricing.UnitMinute, Rounding: pricing.RoundingCeil}}
+	tariffs  = pricing.Fixed{Tariff: tariff}
+	promos   = promotions{}
+	passes   = balances{}
+	taxes    = pricing.NoTaxes{}
+	provider = payments.NewFake()
+	wallet   = purse{}
+	pricer   = pricing.Flat{}
+)
+
+// newService builds the service with the fakes above, after letting the
+// overrides swap the ones a test exercises.
+func newService(repository rel.Repository, overrides ...func(*Deps)) Service {
+	deps := Deps{
+		Riders:   riders,
+		Vehicles: vehicles,
+		Gateway:  gateway,
+		Tariffs:  tariffs,
+		Promos:   promos,
+		Passes:   passes,
+		Taxes:    taxes,
+		Payments: provider,
+		Wallets:  wallet,
+		Pricer:   pricer,
+		Hold:     DefaultHold,
+	}
+	for _, override := range overrides {
+		override(&deps)
+	}
+	return New(repository, deps)
+}
+
+var errUnknownCode = errors.New("unknown promo code")
+
+// promotions knows the code of its discount, if any, and counts the rides
+// that redeem it.
+type promotions struct {
+	discount *pricing.Discount
+	redeemed *[]uint
+}
+
+func (p promotions) Discount(ctx context.Context, code string, userID string, at time.Time) (pricing.Discount, error) {
+	if p.discount == nil || code != p.discount.Code {
+		return pricing.Discount{}, errUnknownCode
+	}
+	return *p.discount, nil
+}
+
+func (p promotions) Redeem(ctx context.Context, code string, userID string, rideID uint, at time.Time) error {
+	*p.redeemed = append(*p.redeemed, rideID)
+	return nil
+}
+
+func (p promotions) Release(ctx context.Context, rideID uint) error {
+	if p.redeemed == nil {
+		return nil
+	}
+	redeemed := (*p.redeemed)[:0]
+	for _, id := range *p.redeemed {
+		if id != rideID {
+			redeemed = append(redeemed, id)
+		}
+	}
+	*p.redeemed = redeemed
+	return nil
+}
+
+// members lets every user ride but the ones it refuses.
+type members struct {
+	refused map[string]error
+}
+
+func (m members) Allow(ctx context.Context, userID string) error {
+	return m.refused[userID]
+}
+
+// garage lends out every vehicle but the ones it refuses, and records the
+// vehicles rides give back. Its vehicles are scooters run by acme in
+// Valencia unless told otherwise.
+type garage struct {
+	vehicles map[string]Vehicle
+	refused  map[string]error
+	released *[]string
+}
+
+func (g garage) Lookup(ctx context.Context, vehicleID string) (Vehicle, error) {
+	if vehicle, ok := g.vehicles[vehicleID]; ok {
+		return vehicle, nil
+	}
+	return Vehicle{Type: "scooter", City: "Valencia", Operator: "acme"}, nil
+}
+
+func (g garage) Take(ctx context.Context, vehicleID string, at time.Time) error {
+	return g.refused[vehicleID]
+}
+
+func (g garage) Release(ctx context.Context, vehicleID string, at time.Time) error {
+	if g.released != nil {
+		*g.released = append(*g.released, vehicleID)
+	}
+	return nil
+}
+
+// balances offers its allowances to every ride and records what they
+// consume.
+type balances struct {
+	allowances []pricing.Allowance
+	consumed   *[]pricing.Consumption
+}
+
+func (b balances) Allowances(ctx context.Context, userID string, at time.Time) ([]pricing.Allowance, error) {
+	return b.allowances, nil
+}
+
+func (b balances) Consume(ctx context.Context, rideID uint, consumptions []pricing.Consumption, at time.Time) error {
+	*b.consumed = append(*b.consumed, consumptions...)
+	return nil
+}
+
+// taxed taxes every ride at its rate, whatever its city and operator.
+type taxed struct {
+	rate pricing.TaxRate
+}
+
+func (t taxed) Rate(ctx context.Context, city, operator string) (pricing.TaxRate, error) {
+	return t.rate, nil
+}
+
+// purse holds the same balance in every currency and records what rides
+// debit from it and how their adjustments change that.
+type purse struct {
+	balance  int64
+	debited  *[]money.Money
+	adjusted *[]money.Money
+}
+
+func (p purse) Balance(ctx context.Context, userID string, currency money.Currency) (money.Money, error) {
+	return money.New(p.balance, currency), nil
+}
+
+func (p purse) DebitRide(ctx context.Context, userID string, rideID uint, amount money.Money, at time.Time) error {
+	*p.debited = append(*p.debited, amount)
+	return nil
+}
+
+func (p purse) AdjustRide(ctx context.Context, userID string, rideID uint, amount money.Money, description string, at time.Time) error {
+	*p.adjusted = append(*p.adjusted, amount)
+	return nil
+}
+
+func eur(amount int64) money.Money {
+	return money.New(amount, "EUR")
+}
+
 func TestRideValidation(t *testing.T) {
 	var ride Ride
 
@@ -23,3 +192,28 @@ func TestRideValidation(t *testing.T) {
 		assert.Nil(t, ride.Validate())
 	})
 }
+
+func TestRideJSONAmounts(t *testing.T) {
+	ride := Ride{ID: 1, Price: eur(418), NetAmount: 345, TaxAmount: 73, AdjustmentsAmount: -200}
+
+	body, err := json.Marshal(ride)
+	assert.Nil(t, err)
+
+	var fields map[string]interface{}
+	assert.Nil(t, json.Unmarshal(body, &fields))
+	assert.Equal(t, map[string]interface{}{"amount": float64(418), "currency": "EUR"}, fields["price"])
+	assert.Equal(t, map[string]interface{}{"amount": float64(218), "currency": "EUR"}, fields["effective_price"])
+	assert.Equal(t, map[string]interface{}{"amount": float64(345), "currency": "EUR"}, fields["net"])
+	assert.Equal(t, map[string]interface{}{"amount": float64(73), "currency": "EUR"}, fields["tax"])
+	assert.Equal(t, map[string]interface{}{"amount": float64(-200), "currency": "EUR"}, fields["adjustments"])
+	assert.NotContains(t, fields, "hold")
+	assert.NotContains(t, fields, "net_amount")
+	assert.NotContains(t, fields, "adjustments_amount")
+}
+
+func TestRideJSONOverflow(t *testing.T) {
+	ride := Ride{ID: 1, Price: eur(math.MaxInt64), AdjustmentsAmount: 1}
+
+	_, err := json.Marshal(ride)
+	assert.ErrorIs(t, err, money.ErrOverflow)
+}
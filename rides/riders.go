@@ -0,0 +1,9 @@
+package rides
+
+import "context"
+
+// Riders tells which users may start rides.
+type Riders interface {
+	// Allow fails when the user doesn't exist or isn't allowed to ride.
+	Allow(ctx context.Context, userID string) error
+}
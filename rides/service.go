@@ -4,22 +4,79 @@ import (
 	"context"
 	"time"
 
+	"backend/iot"
+	"backend/payments"
+	"backend/pricing"
+
 	"github.com/go-rel/rel"
 )
 
 type Service interface {
 	StartRide(ctx context.Context, ride *Ride) (error, *Ride)
 	FinishRide(ctx context.Context, ride *Ride, now time.Time) (error, *Ride)
+	ForceFinishRide(ctx context.Context, ride *Ride, reason string, now time.Time) (error, *Ride)
+	PauseRide(ctx context.Context, ride *Ride, now time.Time) (error, *Ride)
+	ResumeRide(ctx context.Context, ride *Ride, now time.Time) (error, *Ride)
+	CancelRide(ctx context.Context, ride *Ride, now time.Time) (error, *Ride)
+	CapturePayment(ctx context.Context, ride *Ride, now time.Time) (error, *Ride)
+	AdjustRide(ctx context.Context, ride *Ride, adjustment *Adjustment, now time.Time) (error, *Adjustment)
+	SettleAdjustment(ctx context.Context, ride *Ride, adjustmentID uint, now time.Time) (error, *Adjustment)
+	ListAdjustments(ctx context.Context, rideID uint) (error, []Adjustment)
+	QuoteRide(ctx context.Context, ride *Ride, at time.Time) (error, *Quote)
+	GetRide(ctx context.Context, id uint) (error, *Ride)
+	ListRides(ctx context.Context, filter Filter) (error, *Page)
 }
 
 type service struct {
 	startRide
 	finishRide
+	pauseRide
+	resumeRide
+	cancelRide
+	capturePayment
+	adjustRide
+	listAdjustments
+	quoteRide
+	getRide
+	listRides
+}
+
+// Deps are what the rides service relies on besides its repository.
+type Deps struct {
+	Riders   Riders
+	Vehicles Vehicles
+	Gateway  iot.Gateway
+	Tariffs  pricing.TariffSource
+	Promos   pricing.Promotions
+	Passes   pricing.Passes
+	Taxes    pricing.Taxes
+	Payments payments.Provider
+	Wallets  payments.Wallets
+	Pricer   pricing.Pricer
+	// Hold is what rides are authorized for when their tariff has no
+	// maximum charge, in the minor unit of its currency.
+	Hold int
 }
 
-func New(repository rel.Repository) Service {
+// DefaultHold is the hold of rides whose tariff has no maximum charge.
+const DefaultHold = 3000
+
+func New(repository rel.Repository, deps Deps) Service {
+	var (
+		capture = capturePayment{repository: repository, payments: deps.Payments}
+		finish  = finishRide{repository: repository, vehicles: deps.Vehicles, gateway: deps.Gateway, passes: deps.Passes, wallets: deps.Wallets, pricer: deps.Pricer, capture: capture}
+	)
 	return service{
-		startRide:  startRide{repository: repository},
-		finishRide: finishRide{repository: repository},
+		startRide:       startRide{repository: repository, riders: deps.Riders, vehicles: deps.Vehicles, gateway: deps.Gateway, tariffs: deps.Tariffs, promos: deps.Promos, taxes: deps.Taxes, payments: deps.Payments, wallets: deps.Wallets, pricer: deps.Pricer, hold: deps.Hold},
+		finishRide:      finish,
+		pauseRide:       pauseRide{repository: repository},
+		resumeRide:      resumeRide{repository: repository},
+		cancelRide:      cancelRide{repository: repository, vehicles: deps.Vehicles, gateway: deps.Gateway, promos: deps.Promos, payments: deps.Payments},
+		capturePayment:  capture,
+		adjustRide:      adjustRide{repository: repository, payments: deps.Payments, wallets: deps.Wallets},
+		listAdjustments: listAdjustments{repository: repository},
+		quoteRide:       quoteRide{finishRide: finish},
+		getRide:         getRide{repository: repository},
+		listRides:       listRides{repository: repository},
 	}
 }
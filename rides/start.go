@@ -2,32 +2,160 @@ package rides
 
 import (
 	"context"
+	"errors"
+	"time"
 
-	"backend/utils"
+	"backend/iot"
+	"backend/payments"
+	"backend/pricing"
 
 	"github.com/go-rel/rel"
 )
 
 type startRide struct {
 	repository rel.Repository
+	riders     Riders
+	vehicles   Vehicles
+	gateway    iot.Gateway
+	tariffs    pricing.TariffSource
+	promos     pricing.Promotions
+	taxes      pricing.Taxes
+	payments   payments.Provider
+	wallets    payments.Wallets
+	pricer     pricing.Pricer
+	hold       int
 }
 
 func (c startRide) StartRide(ctx context.Context, ride *Ride) (error, *Ride) {
 	if err := ride.Validate(); err != nil {
 		return err, nil
 	}
+	if err := c.riders.Allow(ctx, ride.UserID); err != nil {
+		return err, nil
+	}
+
+	// The city and the operator of the ride are those of its vehicle, never
+	// what the client claims.
+	vehicle, err := c.vehicles.Lookup(ctx, ride.VehicleID)
+	if err != nil {
+		return err, nil
+	}
+	ride.City = vehicle.City
+	ride.Operator = vehicle.Operator
 
-	count, _ := c.repository.Count(ctx, "rides",
-		rel.Eq("finished", false).And(
-			rel.Eq("user_id", ride.UserID).OrEq("vehicle_id", ride.VehicleID),
-		),
-	)
-	if count != 0 {
-		return ErrRideAlreadyStarted, nil
+	now := time.Now()
+	tariff, err := c.tariffs.Active(ctx, vehicle.Scope(), now)
+	if err != nil {
+		return err, nil
 	}
 
-	ride.Price = utils.GetEnvAsInt("RIDE_INITIAL_PRICE", 18)
+	ride.Tariff = tariff
+	if ride.PromoCode != "" {
+		discount, err := c.promos.Discount(ctx, ride.PromoCode, ride.UserID, now)
+		if err != nil {
+			return err, nil
+		}
+		if !discount.Applies(tariff) {
+			return pricing.ErrDiscountNotApplicable, nil
+		}
+		ride.PromoCode = discount.Code
+		ride.Discount = &discount
+	}
+	if ride.TaxRate, err = c.taxes.Rate(ctx, ride.City, ride.Operator); err != nil {
+		return err, nil
+	}
+
+	quote := c.pricer.Quote(ride.Tariff, pricing.Usage{})
+	quote.Tax(ride.TaxRate)
+	net, tax, err := quote.Breakdown()
+	if err != nil {
+		return err, nil
+	}
+	ride.charge(quote, net, tax)
+	ride.Status = StatusActive
+
+	// Prepaid rides are paid from the wallet when they finish. The others
+	// get a hold for the most they can be charged placed before the ride is
+	// stored, and released if it can't be.
+	if ride.Tariff.Prepaid.Enabled {
+		balance, err := c.wallets.Balance(ctx, ride.UserID, ride.Tariff.Currency)
+		if err != nil {
+			return err, nil
+		}
+		if balance.Amount < int64(ride.Tariff.Prepaid.MinimumBalance) {
+			return payments.ErrInsufficientBalance, nil
+		}
+	} else {
+		hold, err := ride.Tariff.Hold(ride.TaxRate, c.hold)
+		if err != nil {
+			return err, nil
+		}
+		if ride.PaymentID, err = c.payments.Authorize(ctx, ride.UserID, hold); err != nil {
+			return err, nil
+		}
+		ride.HoldAmount = hold.Amount
+		ride.PaymentStatus = payments.StatusAuthorized
+	}
+
+	var unlocking bool
+	err = c.repository.Transaction(ctx, func(ctx context.Context) error {
+		count, err := c.repository.Count(ctx, "rides",
+			rel.In("status", openStatuses...).And(
+				rel.Eq("user_id", ride.UserID).OrEq("vehicle_id", ride.VehicleID),
+			),
+		)
+		if err != nil {
+			return err
+		}
+		if count != 0 {
+			return ErrRideAlreadyStarted
+		}
+		if err := c.vehicles.Take(ctx, ride.VehicleID, now); err != nil {
+			return err
+		}
+
+		// The partial unique indexes on open rides catch the starts that
+		// raced past the count above.
+		if err := c.repository.Insert(ctx, ride); err != nil {
+			if errors.Is(err, rel.ErrUniqueConstraint) {
+				return ErrRideAlreadyStarted
+			}
+			return err
+		}
+
+		if ride.Discount != nil {
+			if err := c.promos.Redeem(ctx, ride.PromoCode, ride.UserID, ride.ID, now); err != nil {
+				return err
+			}
+		}
+
+		if err := c.repository.Insert(ctx, &Transition{
+			RideID:    ride.ID,
+			To:        ride.Status,
+			Actor:     userActor(ride),
+			CreatedAt: ride.CreatedAt,
+		}); err != nil {
+			return err
+		}
+
+		// The vehicle is unlocked last, so that a ride it can't be unlocked
+		// for is rolled back.
+		unlocking = true
+		return unlock(ctx, c.gateway, ride.VehicleID)
+	})
+	if err != nil {
+		// An unlock that timed out may still have reached the vehicle, and
+		// one that went through leaves it open if the ride couldn't be
+		// committed, so it is locked again.
+		if unlocking {
+			relock(ctx, c.gateway, ride.VehicleID)
+		}
+		// A hold that fails to void expires with the provider.
+		if ride.PaymentID != "" {
+			_ = c.payments.Void(ctx, ride.PaymentID)
+		}
+		return err, nil
+	}
 
-	err := c.repository.Insert(ctx, ride)
-	return err, ride
+	return nil, ride
 }
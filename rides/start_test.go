@@ -2,7 +2,13 @@ package rides
 
 import (
 	"context"
+	"errors"
 	"testing"
+	"time"
+
+	"backend/iot"
+	"backend/payments"
+	"backend/pricing"
 
 	"github.com/go-rel/rel"
 	"github.com/go-rel/reltest"
@@ -13,25 +19,331 @@ func TestStart(t *testing.T) {
 	var (
 		ctx        = context.TODO()
 		repository = reltest.New()
-		service    = New(repository)
+		service    = newService(repository)
 		ride       = Ride{UserID: "1", VehicleID: "1"}
 	)
 
-	repository.ExpectCount("rides",
-		rel.Eq("finished", false).And(
-			rel.Eq("user_id", ride.UserID).OrEq("vehicle_id", ride.VehicleID),
-		),
-	).Result(0)
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectCount("rides",
+			rel.In("status", openStatuses...).And(
+				rel.Eq("user_id", ride.UserID).OrEq("vehicle_id", ride.VehicleID),
+			),
+		).Result(0)
 
-	repository.ExpectInsert().For(&ride)
+		repository.ExpectInsert().For(&ride)
+		repository.ExpectInsert().ForType("rides.Transition")
+	})
 
 	err, _ := service.StartRide(ctx, &ride)
 	assert.Nil(t, err)
 	assert.NotEmpty(t, ride.ID)
-	assert.Equal(t, ride.Price, 18)
+	assert.Equal(t, eur(18), ride.Price)
+	assert.Equal(t, tariff, ride.Tariff)
 	assert.NotEmpty(t, ride.CreatedAt)
 	assert.NotEmpty(t, ride.UpdatedAt)
-	assert.False(t, ride.Finished)
+	assert.Equal(t, StatusActive, ride.Status)
+
+	repository.AssertExpectations(t)
+}
+
+func TestStartWithTax(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		vat        = pricing.TaxRate{Name: "VAT", Rate: 2100, Rounding: pricing.TaxRoundingPerTotal}
+		service    = newService(repository, func(d *Deps) { d.Taxes = taxed{rate: vat} })
+		ride       = Ride{UserID: "1", VehicleID: "1", City: "Valencia"}
+	)
+
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectCount("rides",
+			rel.In("status", openStatuses...).And(
+				rel.Eq("user_id", ride.UserID).OrEq("vehicle_id", ride.VehicleID),
+			),
+		).Result(0)
+
+		repository.ExpectInsert().For(&ride)
+		repository.ExpectInsert().ForType("rides.Transition")
+	})
+
+	// 18 + 3.78 rounded up.
+	err, _ := service.StartRide(ctx, &ride)
+	assert.Nil(t, err)
+	assert.Equal(t, vat, ride.TaxRate)
+	assert.Equal(t, eur(22), ride.Price)
+	assert.Equal(t, int64(18), ride.NetAmount)
+	assert.Equal(t, int64(4), ride.TaxAmount)
+
+	repository.AssertExpectations(t)
+}
+
+func TestStartPrepaidRide(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		prepaid    = tariff
+	)
+	prepaid.Prepaid = pricing.Prepaid{Enabled: true, MinimumBalance: 500}
+	var (
+		service = newService(repository, func(d *Deps) {
+			d.Tariffs = pricing.Fixed{Tariff: prepaid}
+			d.Wallets = purse{balance: 500}
+		})
+		ride = Ride{UserID: "1", VehicleID: "1"}
+	)
+
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectCount("rides",
+			rel.In("status", openStatuses...).And(
+				rel.Eq("user_id", ride.UserID).OrEq("vehicle_id", ride.VehicleID),
+			),
+		).Result(0)
+
+		repository.ExpectInsert().For(&ride)
+		repository.ExpectInsert().ForType("rides.Transition")
+	})
+
+	err, _ := service.StartRide(ctx, &ride)
+	assert.Nil(t, err)
+	assert.Empty(t, ride.PaymentID)
+	assert.Empty(t, ride.PaymentStatus)
+
+	repository.AssertExpectations(t)
+}
+
+func TestStartPrepaidRideBalanceTooLow(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		prepaid    = tariff
+	)
+	prepaid.Prepaid = pricing.Prepaid{Enabled: true, MinimumBalance: 500}
+	var (
+		service = newService(repository, func(d *Deps) {
+			d.Tariffs = pricing.Fixed{Tariff: prepaid}
+			d.Wallets = purse{balance: 499}
+		})
+		ride = Ride{UserID: "1", VehicleID: "1"}
+	)
+
+	err, savedRide := service.StartRide(ctx, &ride)
+	assert.Equal(t, payments.ErrInsufficientBalance, err)
+	assert.Nil(t, savedRide)
+
+	repository.AssertExpectations(t)
+}
+
+type freeUnlock struct{}
+
+func (freeUnlock) Active(ctx context.Context, scope pricing.Scope, at time.Time) (pricing.Tariff, error) {
+	return pricing.Tariff{Version: "free-unlock", Currency: "EUR"}, nil
+}
+
+func (freeUnlock) Quote(tariff pricing.Tariff, usage pricing.Usage) pricing.Quote {
+	return pricing.NewQuote(tariff.Currency)
+}
+
+func TestStartWithPricer(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = newService(repository, func(d *Deps) {
+			d.Tariffs = freeUnlock{}
+			d.Pricer = freeUnlock{}
+		})
+		ride = Ride{UserID: "1", VehicleID: "1"}
+	)
+
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectCount("rides",
+			rel.In("status", openStatuses...).And(
+				rel.Eq("user_id", ride.UserID).OrEq("vehicle_id", ride.VehicleID),
+			),
+		).Result(0)
+
+		repository.ExpectInsert().For(&ride)
+		repository.ExpectInsert().ForType("rides.Transition")
+	})
+
+	err, _ := service.StartRide(ctx, &ride)
+	assert.Nil(t, err)
+	assert.Equal(t, eur(0), ride.Price)
+	assert.Equal(t, "free-unlock", ride.Tariff.Version)
+
+	repository.AssertExpectations(t)
+}
+
+type noTariff struct{}
+
+func (noTariff) Active(ctx context.Context, scope pricing.Scope, at time.Time) (pricing.Tariff, error) {
+	return pricing.Tariff{}, errNoTariff
+}
+
+var errNoTariff = errors.New("no tariff")
+
+func TestStartRideWithoutTariff(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = newService(repository, func(d *Deps) { d.Tariffs = noTariff{} })
+		ride       = Ride{UserID: "1", VehicleID: "1"}
+	)
+
+	err, savedRide := service.StartRide(ctx, &ride)
+	assert.Equal(t, errNoTariff, err)
+	assert.Nil(t, savedRide)
+
+	repository.AssertExpectations(t)
+}
+
+// perType offers a tariff per vehicle type, and records the scopes it was
+// asked for.
+type perType struct {
+	tariffs map[string]pricing.Tariff
+	scopes  *[]pricing.Scope
+}
+
+func (p perType) Active(ctx context.Context, scope pricing.Scope, at time.Time) (pricing.Tariff, error) {
+	*p.scopes = append(*p.scopes, scope)
+	return p.tariffs[scope.VehicleType], nil
+}
+
+func TestStartRideTariffOfVehicleType(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		moped      = pricing.Tariff{Version: "2", Currency: "EUR", UnlockPrice: 50, MinutePrice: 200, Billing: tariff.Billing}
+		scopes     []pricing.Scope
+		source     = perType{tariffs: map[string]pricing.Tariff{"scooter": tariff, "moped": moped}, scopes: &scopes}
+		service    = newService(repository, func(d *Deps) {
+			d.Vehicles = garage{vehicles: map[string]Vehicle{"2": {Type: "moped", City: "Madrid"}}}
+			d.Tariffs = source
+		})
+		ride = Ride{UserID: "1", VehicleID: "2", City: "Valencia"}
+	)
+
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectCount("rides",
+			rel.In("status", openStatuses...).And(
+				rel.Eq("user_id", ride.UserID).OrEq("vehicle_id", ride.VehicleID),
+			),
+		).Result(0)
+
+		repository.ExpectInsert().For(&ride)
+		repository.ExpectInsert().ForType("rides.Transition")
+	})
+
+	err, _ := service.StartRide(ctx, &ride)
+	assert.Nil(t, err)
+	assert.Equal(t, moped, ride.Tariff)
+	assert.Equal(t, eur(50), ride.Price)
+	assert.Equal(t, []pricing.Scope{{VehicleType: "moped", City: "Madrid"}}, scopes)
+	assert.Equal(t, "Madrid", ride.City)
+
+	repository.AssertExpectations(t)
+}
+
+var errSuspended = errors.New("suspended")
+
+func TestStartRideRiderRefused(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = newService(repository, func(d *Deps) { d.Riders = members{refused: map[string]error{"1": errSuspended}} })
+		ride       = Ride{UserID: "1", VehicleID: "1"}
+	)
+
+	err, savedRide := service.StartRide(ctx, &ride)
+	assert.Equal(t, errSuspended, err)
+	assert.Nil(t, savedRide)
+
+	repository.AssertExpectations(t)
+}
+
+var errNotAvailable = errors.New("not available")
+
+func TestStartRideVehicleNotAvailable(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = newService(repository, func(d *Deps) { d.Vehicles = garage{refused: map[string]error{"1": errNotAvailable}} })
+		ride       = Ride{UserID: "1", VehicleID: "1"}
+	)
+
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectCount("rides",
+			rel.In("status", StatusReserved, StatusActive, StatusPaused).And(
+				rel.Eq("user_id", ride.UserID).OrEq("vehicle_id", ride.VehicleID),
+			),
+		).Result(0)
+	})
+
+	err, savedRide := service.StartRide(ctx, &ride)
+	assert.Equal(t, errNotAvailable, err)
+	assert.Nil(t, savedRide)
+
+	hold, _ := provider.Hold(ride.PaymentID)
+	assert.Equal(t, payments.StatusVoided, hold.Status)
+
+	repository.AssertExpectations(t)
+}
+
+func TestStartRideWithPromoCode(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		discount   = pricing.Discount{Code: "SPRING", Kind: pricing.DiscountFreeUnlock}
+		redeemed   []uint
+		service    = newService(repository, func(d *Deps) { d.Promos = promotions{discount: &discount, redeemed: &redeemed} })
+		ride       = Ride{UserID: "1", VehicleID: "1", PromoCode: "SPRING"}
+	)
+
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectCount("rides",
+			rel.In("status", openStatuses...).And(
+				rel.Eq("user_id", ride.UserID).OrEq("vehicle_id", ride.VehicleID),
+			),
+		).Result(0)
+
+		repository.ExpectInsert().For(&ride)
+		repository.ExpectInsert().ForType("rides.Transition")
+	})
+
+	err, _ := service.StartRide(ctx, &ride)
+	assert.Nil(t, err)
+	assert.Equal(t, &discount, ride.Discount)
+	assert.Equal(t, []uint{ride.ID}, redeemed)
+
+	repository.AssertExpectations(t)
+}
+
+func TestStartRideWithUnknownPromoCode(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = newService(repository)
+		ride       = Ride{UserID: "1", VehicleID: "1", PromoCode: "WINTER"}
+	)
+
+	err, savedRide := service.StartRide(ctx, &ride)
+	assert.Equal(t, errUnknownCode, err)
+	assert.Nil(t, savedRide)
+
+	repository.AssertExpectations(t)
+}
+
+func TestStartRideWithPromoCodeInAnotherCurrency(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		discount   = pricing.Discount{Code: "DOLLAR", Kind: pricing.DiscountAmountOff, Amount: 100, Currency: "USD"}
+		service    = newService(repository, func(d *Deps) { d.Promos = promotions{discount: &discount} })
+		ride       = Ride{UserID: "1", VehicleID: "1", PromoCode: "DOLLAR"}
+	)
+
+	err, savedRide := service.StartRide(ctx, &ride)
+	assert.Equal(t, pricing.ErrDiscountNotApplicable, err)
+	assert.Nil(t, savedRide)
 
 	repository.AssertExpectations(t)
 }
@@ -40,7 +352,7 @@ func TestStartRideValidationErrorUserID(t *testing.T) {
 	var (
 		ctx        = context.TODO()
 		repository = reltest.New()
-		service    = New(repository)
+		service    = newService(repository)
 		ride       = Ride{UserID: "", VehicleID: "1"}
 	)
 
@@ -54,7 +366,7 @@ func TestStartRideValidationErrorVehicleID(t *testing.T) {
 	var (
 		ctx        = context.TODO()
 		repository = reltest.New()
-		service    = New(repository)
+		service    = newService(repository)
 		ride       = Ride{UserID: "1", VehicleID: ""}
 	)
 
@@ -68,14 +380,16 @@ func TestStartAnotherRideAlreadyStarted(t *testing.T) {
 	var (
 		ctx        = context.TODO()
 		repository = reltest.New()
-		service    = New(repository)
+		service    = newService(repository)
 		ride       = Ride{UserID: "2", VehicleID: "2"}
 	)
-	repository.ExpectCount("rides",
-		rel.Eq("finished", false).And(
-			rel.Eq("user_id", ride.UserID).OrEq("vehicle_id", ride.VehicleID),
-		),
-	).Result(1)
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectCount("rides",
+			rel.In("status", openStatuses...).And(
+				rel.Eq("user_id", ride.UserID).OrEq("vehicle_id", ride.VehicleID),
+			),
+		).Result(1)
+	})
 
 	err, _ := service.StartRide(ctx, &ride)
 	assert.Equal(t, ErrRideAlreadyStarted, err)
@@ -83,3 +397,111 @@ func TestStartAnotherRideAlreadyStarted(t *testing.T) {
 
 	repository.AssertExpectations(t)
 }
+
+func TestStartRideUniqueConstraint(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = newService(repository)
+		ride       = Ride{UserID: "3", VehicleID: "3"}
+	)
+
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectCount("rides",
+			rel.In("status", openStatuses...).And(
+				rel.Eq("user_id", ride.UserID).OrEq("vehicle_id", ride.VehicleID),
+			),
+		).Result(0)
+
+		repository.ExpectInsert().For(&ride).NotUnique("rides_open_vehicle_id_idx")
+	})
+
+	err, savedRide := service.StartRide(ctx, &ride)
+	assert.Equal(t, ErrRideAlreadyStarted, err)
+	assert.Nil(t, savedRide)
+
+	repository.AssertExpectations(t)
+}
+
+func TestStartRideCountError(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = newService(repository)
+		ride       = Ride{UserID: "4", VehicleID: "4"}
+	)
+
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectCount("rides",
+			rel.In("status", openStatuses...).And(
+				rel.Eq("user_id", ride.UserID).OrEq("vehicle_id", ride.VehicleID),
+			),
+		).ConnectionClosed()
+	})
+
+	err, _ := service.StartRide(ctx, &ride)
+	assert.Equal(t, reltest.ErrConnectionClosed, err)
+
+	repository.AssertExpectations(t)
+}
+
+func TestStartRideUnlockFails(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		simulator  = iot.NewSimulator()
+		service    = newService(repository, func(d *Deps) { d.Gateway = simulator })
+		ride       = Ride{UserID: "1", VehicleID: "1"}
+	)
+	simulator.Fail("1", 1)
+
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectCount("rides",
+			rel.In("status", openStatuses...).And(
+				rel.Eq("user_id", ride.UserID).OrEq("vehicle_id", ride.VehicleID),
+			),
+		).Result(0)
+
+		repository.ExpectInsert().For(&ride)
+		repository.ExpectInsert().ForType("rides.Transition")
+	})
+
+	err, savedRide := service.StartRide(ctx, &ride)
+	assert.Equal(t, iot.ErrUnreachable, err)
+	assert.Nil(t, savedRide)
+	assert.False(t, simulator.Unlocked("1"))
+
+	hold, _ := provider.Hold(ride.PaymentID)
+	assert.Equal(t, payments.StatusVoided, hold.Status)
+
+	repository.AssertExpectations(t)
+}
+
+func TestStartRideUnlockOutlivesRequest(t *testing.T) {
+	var (
+		ctx, cancel = context.WithCancel(context.TODO())
+		repository  = reltest.New()
+		simulator   = iot.NewSimulator()
+		service     = newService(repository, func(d *Deps) { d.Gateway = simulator })
+		ride        = Ride{UserID: "1", VehicleID: "1"}
+	)
+	simulator.Delay(10 * time.Millisecond)
+	cancel()
+
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectCount("rides",
+			rel.In("status", openStatuses...).And(
+				rel.Eq("user_id", ride.UserID).OrEq("vehicle_id", ride.VehicleID),
+			),
+		).Result(0)
+
+		repository.ExpectInsert().For(&ride)
+		repository.ExpectInsert().ForType("rides.Transition")
+	})
+
+	err, _ := service.StartRide(ctx, &ride)
+	assert.Nil(t, err)
+	assert.True(t, simulator.Unlocked("1"))
+
+	repository.AssertExpectations(t)
+}
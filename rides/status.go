@@ -0,0 +1,79 @@
+package rides
+
+import "fmt"
+
+type Status string
+
+const (
+	StatusReserved    Status = "reserved"
+	StatusActive      Status = "active"
+	StatusPaused      Status = "paused"
+	StatusFinished    Status = "finished"
+	StatusCancelled   Status = "cancelled"
+	StatusForceClosed Status = "force_closed"
+)
+
+// transitions lists the statuses a ride can move to from each status.
+// The empty status is the ride before it is inserted.
+var transitions = map[Status][]Status{
+	"":             {StatusReserved, StatusActive},
+	StatusReserved: {StatusActive, StatusCancelled},
+	StatusActive:   {StatusPaused, StatusFinished, StatusCancelled, StatusForceClosed},
+	StatusPaused:   {StatusActive, StatusFinished, StatusForceClosed},
+}
+
+// openStatuses are the statuses in which a ride holds its user and vehicle.
+// They must match the partial unique indexes on the rides table.
+var openStatuses = []interface{}{StatusReserved, StatusActive, StatusPaused}
+
+// closedStatuses are the statuses of the rides that were priced and charged,
+// whether their user finished them or the system had to.
+var closedStatuses = []interface{}{StatusFinished, StatusForceClosed}
+
+// TransitionError is returned when a ride is asked to move to a status that
+// is not reachable from its current one.
+type TransitionError struct {
+	From Status
+	To   Status
+}
+
+func (e TransitionError) Error() string {
+	return fmt.Sprintf("A ride can't go from %q to %q", e.From, e.To)
+}
+
+func (s Status) Valid() bool {
+	switch s {
+	case StatusReserved, StatusActive, StatusPaused, StatusFinished, StatusCancelled, StatusForceClosed:
+		return true
+	}
+	return false
+}
+
+func (s Status) CanTransitionTo(to Status) bool {
+	for _, next := range transitions[s] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Open reports whether the ride still holds its user and vehicle.
+func (s Status) Open() bool {
+	for _, open := range openStatuses {
+		if open == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Closed reports whether the ride was finished, by its user or the system.
+func (s Status) Closed() bool {
+	for _, closed := range closedStatuses {
+		if closed == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,62 @@
+package rides
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatusTransitions(t *testing.T) {
+	tests := []struct {
+		from    Status
+		to      Status
+		allowed bool
+	}{
+		{"", StatusReserved, true},
+		{"", StatusActive, true},
+		{"", StatusFinished, false},
+		{StatusReserved, StatusActive, true},
+		{StatusReserved, StatusCancelled, true},
+		{StatusReserved, StatusFinished, false},
+		{StatusActive, StatusPaused, true},
+		{StatusActive, StatusFinished, true},
+		{StatusActive, StatusCancelled, true},
+		{StatusActive, StatusForceClosed, true},
+		{StatusActive, StatusReserved, false},
+		{StatusPaused, StatusActive, true},
+		{StatusPaused, StatusFinished, true},
+		{StatusPaused, StatusForceClosed, true},
+		{StatusPaused, StatusCancelled, false},
+		{StatusFinished, StatusActive, false},
+		{StatusFinished, StatusFinished, false},
+		{StatusCancelled, StatusActive, false},
+		{StatusForceClosed, StatusFinished, false},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.allowed, test.from.CanTransitionTo(test.to), "%q -> %q", test.from, test.to)
+	}
+}
+
+func TestStatusOpen(t *testing.T) {
+	assert.True(t, StatusReserved.Open())
+	assert.True(t, StatusActive.Open())
+	assert.True(t, StatusPaused.Open())
+	assert.False(t, StatusFinished.Open())
+	assert.False(t, StatusCancelled.Open())
+	assert.False(t, StatusForceClosed.Open())
+}
+
+func TestStatusClosed(t *testing.T) {
+	assert.True(t, StatusFinished.Closed())
+	assert.True(t, StatusForceClosed.Closed())
+	assert.False(t, StatusActive.Closed())
+	assert.False(t, StatusPaused.Closed())
+	assert.False(t, StatusCancelled.Closed())
+}
+
+func TestStatusValid(t *testing.T) {
+	assert.True(t, StatusForceClosed.Valid())
+	assert.False(t, Status("").Valid())
+	assert.False(t, Status("stolen").Valid())
+}
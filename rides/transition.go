@@ -0,0 +1,61 @@
+package rides
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-rel/rel"
+	"github.com/go-rel/rel/where"
+)
+
+// Transition is an audit record of a ride changing status.
+type Transition struct {
+	ID        uint      `json:"id"`
+	RideID    uint      `json:"ride_id"`
+	From      Status    `json:"from" db:"from_status"`
+	To        Status    `json:"to" db:"to_status"`
+	Actor     string    `json:"actor"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (Transition) Table() string {
+	return "ride_transitions"
+}
+
+// userActor identifies the rider as the author of a transition.
+func userActor(ride *Ride) string {
+	return "user:" + ride.UserID
+}
+
+// systemActor identifies the application as the author of a transition,
+// for the given reason.
+func systemActor(reason string) string {
+	return "system:" + reason
+}
+
+// transition moves an inserted ride to the given status and records it.
+// The update only applies if the ride still has the status it was read with,
+// so concurrent transitions of the same ride can't both succeed; the loser
+// gets ErrRideStatusChanged. Extra mutations are applied in the same update.
+// The ride itself is left untouched, callers update it once their
+// transaction has committed.
+func transition(ctx context.Context, repository rel.Repository, ride *Ride, to Status, actor string, now time.Time, mutates ...rel.Mutate) error {
+	from := ride.Status
+	if !from.CanTransitionTo(to) {
+		return TransitionError{From: from, To: to}
+	}
+
+	mutates = append([]rel.Mutate{rel.Set("status", to), rel.Set("updated_at", now)}, mutates...)
+	updated, err := repository.UpdateAny(ctx,
+		rel.From("rides").Where(where.Eq("id", ride.ID).AndEq("status", from)),
+		mutates...,
+	)
+	if err != nil {
+		return err
+	}
+	if updated == 0 {
+		return ErrRideStatusChanged
+	}
+
+	return repository.Insert(ctx, &Transition{RideID: ride.ID, From: from, To: to, Actor: actor, CreatedAt: now})
+}
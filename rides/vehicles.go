@@ -0,0 +1,35 @@
+package rides
+
+import (
+	"context"
+	"time"
+
+	"backend/pricing"
+)
+
+// Vehicle is what prices the rides on a vehicle: its type and the city it
+// operates in pick their tariff, and the city and its operator pick their
+// tax rate.
+type Vehicle struct {
+	Type     string
+	City     string
+	Operator string
+}
+
+// Scope is the scope of the tariffs of the rides on the vehicle.
+func (v Vehicle) Scope() pricing.Scope {
+	return pricing.Scope{VehicleType: v.Type, City: v.City}
+}
+
+// Vehicles hands out the vehicles of the fleet to rides. Take and Release run
+// in the transaction of their caller.
+type Vehicles interface {
+	// Lookup returns what rides on the vehicle are priced by, failing when
+	// the vehicle doesn't exist.
+	Lookup(ctx context.Context, vehicleID string) (Vehicle, error)
+	// Take puts the vehicle in a ride, failing when it doesn't exist or
+	// isn't available.
+	Take(ctx context.Context, vehicleID string, at time.Time) error
+	// Release makes the vehicle of a ride that ended available again.
+	Release(ctx context.Context, vehicleID string, at time.Time) error
+}
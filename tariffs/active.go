@@ -0,0 +1,65 @@
+package tariffs
+
+import (
+	"context"
+	"time"
+
+	"backend/pricing"
+
+	"github.com/go-rel/rel"
+	"github.com/go-rel/rel/where"
+)
+
+type activeTariff struct {
+	repository rel.Repository
+}
+
+// ActiveTariff returns the tariff rides in the scope starting at the given
+// instant are billed with. Among the versions effective then, one for the
+// vehicle type and city wins over one for the vehicle type, then one for the
+// city, then one for any ride. Between versions as specific, the one that
+// became effective last wins.
+func (c activeTariff) ActiveTariff(ctx context.Context, scope pricing.Scope, at time.Time) (error, *Tariff) {
+	var (
+		candidates = []Tariff{}
+		query      = rel.Where(
+			where.Lte("effective_from", at),
+			where.Nil("effective_to").OrGt("effective_to", at),
+			where.In("vehicle_type", "", scope.VehicleType),
+			where.In("city", "", scope.City),
+		).SortDesc("effective_from", "id")
+	)
+	if err := c.repository.FindAll(ctx, &candidates, query); err != nil {
+		return err, nil
+	}
+
+	var tariff *Tariff
+	for i := range candidates {
+		if tariff == nil || candidates[i].specificity() > tariff.specificity() {
+			tariff = &candidates[i]
+		}
+	}
+	if tariff == nil {
+		return ErrNoActiveTariff, nil
+	}
+
+	return nil, tariff
+}
+
+// Source is the pricing.TariffSource backed by the stored tariff versions.
+type Source struct {
+	activeTariff
+}
+
+func NewSource(repository rel.Repository) Source {
+	return Source{activeTariff{repository: repository}}
+}
+
+func (s Source) Active(ctx context.Context, scope pricing.Scope, at time.Time) (pricing.Tariff, error) {
+	err, tariff := s.ActiveTariff(ctx, scope, at)
+	if err != nil {
+		return pricing.Tariff{}, err
+	}
+
+	return tariff.Snapshot(), nil
+}
@@ -0,0 +1,107 @@
+package tariffs
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"backend/pricing"
+
+	"github.com/go-rel/rel"
+	"github.com/go-rel/rel/where"
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+)
+
+var scope = pricing.Scope{VehicleType: "moped", City: "Valencia"}
+
+func activeQuery() rel.Query {
+	return rel.Where(
+		where.Lte("effective_from", now),
+		where.Nil("effective_to").OrGt("effective_to", now),
+		where.In("vehicle_type", "", scope.VehicleType),
+		where.In("city", "", scope.City),
+	).SortDesc("effective_from", "id")
+}
+
+func TestActiveTariff(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository)
+	)
+
+	repository.ExpectFindAll(activeQuery()).Result([]Tariff{tariff})
+
+	err, activeTariff := service.ActiveTariff(ctx, scope, now)
+	assert.Nil(t, err)
+	assert.Equal(t, tariff, *activeTariff)
+
+	repository.AssertExpectations(t)
+}
+
+func TestActiveTariffMostSpecific(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository)
+		city       = Tariff{ID: 2, Name: "Valencia", City: "Valencia", EffectiveFrom: now}
+		mopeds     = Tariff{ID: 3, Name: "Mopeds", VehicleType: "moped", EffectiveFrom: now.Add(-time.Hour)}
+		newer      = Tariff{ID: 4, Name: "Mopeds", VehicleType: "moped", EffectiveFrom: now.Add(-time.Minute)}
+	)
+
+	repository.ExpectFindAll(activeQuery()).Result([]Tariff{city, newer, mopeds, tariff})
+
+	err, activeTariff := service.ActiveTariff(ctx, scope, now)
+	assert.Nil(t, err)
+	assert.Equal(t, newer, *activeTariff)
+
+	repository.AssertExpectations(t)
+}
+
+func TestActiveTariffNone(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository)
+	)
+
+	repository.ExpectFindAll(activeQuery()).Result([]Tariff{})
+
+	err, activeTariff := service.ActiveTariff(ctx, scope, now)
+	assert.Equal(t, ErrNoActiveTariff, err)
+	assert.Nil(t, activeTariff)
+
+	repository.AssertExpectations(t)
+}
+
+func TestSource(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		source     = NewSource(repository)
+	)
+
+	repository.ExpectFindAll(activeQuery()).Result([]Tariff{tariff})
+
+	snapshot, err := source.Active(ctx, scope, now)
+	assert.Nil(t, err)
+	assert.Equal(t, tariff.Snapshot(), snapshot)
+
+	repository.AssertExpectations(t)
+}
+
+func TestSourceNone(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		source     = NewSource(repository)
+	)
+
+	repository.ExpectFindAll(activeQuery()).Result([]Tariff{})
+
+	_, err := source.Active(ctx, scope, now)
+	assert.Equal(t, ErrNoActiveTariff, err)
+
+	repository.AssertExpectations(t)
+}
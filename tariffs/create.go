@@ -0,0 +1,48 @@
+package tariffs
+
+import (
+	"context"
+	"time"
+
+	"backend/pricing"
+
+	"github.com/go-rel/rel"
+)
+
+type createTariff struct {
+	repository rel.Repository
+}
+
+// CreateTariff schedules a new tariff version. A tariff without
+// EffectiveFrom becomes effective right away.
+func (c createTariff) CreateTariff(ctx context.Context, tariff *Tariff, now time.Time) (error, *Tariff) {
+	setDefaults(tariff, now)
+
+	if err := tariff.Validate(); err != nil {
+		return err, nil
+	}
+	if tariff.EffectiveFrom.Before(now) {
+		return ErrTariffEffectiveFromPast, nil
+	}
+
+	if err := c.repository.Insert(ctx, tariff); err != nil {
+		return err, nil
+	}
+
+	return nil, tariff
+}
+
+func setDefaults(tariff *Tariff, now time.Time) {
+	if tariff.Billing.Unit == "" {
+		tariff.Billing.Unit = pricing.UnitMinute
+	}
+	if tariff.Billing.Rounding == "" {
+		tariff.Billing.Rounding = pricing.RoundingCeil
+	}
+	if tariff.TimeZone == "" {
+		tariff.TimeZone = "UTC"
+	}
+	if tariff.EffectiveFrom.IsZero() {
+		tariff.EffectiveFrom = now
+	}
+}
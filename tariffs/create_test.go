@@ -0,0 +1,88 @@
+package tariffs
+
+import (
+	"context"
+	"testing"
+
+	"backend/pricing"
+
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateTariff(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository)
+		tariff     = Tariff{
+			Name:              "Winter",
+			Currency:          "EUR",
+			UnlockPrice:       20,
+			MinutePrice:       110,
+			PausedMinutePrice: 30,
+			TimeZone:          "Europe/Madrid",
+			Rules:             pricing.Rules{{Name: "Night", From: "22:00", To: "06:00", MultiplierPercent: 80}},
+			EffectiveFrom:     tomorrow,
+		}
+	)
+
+	repository.ExpectInsert().For(&tariff)
+
+	err, savedTariff := service.CreateTariff(ctx, &tariff, now)
+	assert.Nil(t, err)
+	assert.NotEmpty(t, savedTariff.ID)
+	assert.Equal(t, tomorrow, savedTariff.EffectiveFrom)
+	assert.Equal(t, pricing.Billing{Unit: pricing.UnitMinute, Rounding: pricing.RoundingCeil}, savedTariff.Billing)
+	assert.Equal(t, "Europe/Madrid", savedTariff.TimeZone)
+
+	repository.AssertExpectations(t)
+}
+
+func TestCreateTariffEffectiveNow(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository)
+		tariff     = Tariff{Name: "Winter", Currency: "EUR"}
+	)
+
+	repository.ExpectInsert().For(&tariff)
+
+	err, savedTariff := service.CreateTariff(ctx, &tariff, now)
+	assert.Nil(t, err)
+	assert.Equal(t, now, savedTariff.EffectiveFrom)
+	assert.Equal(t, "UTC", savedTariff.TimeZone)
+
+	repository.AssertExpectations(t)
+}
+
+func TestCreateTariffEffectiveFromPast(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository)
+		tariff     = Tariff{Name: "Winter", Currency: "EUR", EffectiveFrom: now.Add(-1)}
+	)
+
+	err, savedTariff := service.CreateTariff(ctx, &tariff, now)
+	assert.Equal(t, ErrTariffEffectiveFromPast, err)
+	assert.Nil(t, savedTariff)
+
+	repository.AssertExpectations(t)
+}
+
+func TestCreateTariffValidationError(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository)
+		tariff     = Tariff{Name: "Winter", Currency: "EUR", MinutePrice: -1}
+	)
+
+	err, savedTariff := service.CreateTariff(ctx, &tariff, now)
+	assert.Equal(t, ErrTariffPriceNegative, err)
+	assert.Nil(t, savedTariff)
+
+	repository.AssertExpectations(t)
+}
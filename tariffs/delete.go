@@ -0,0 +1,28 @@
+package tariffs
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-rel/rel"
+)
+
+type deleteTariff struct {
+	repository rel.Repository
+}
+
+// DeleteTariff removes a tariff version that is not effective yet. Versions
+// that rides may have started with are kept for the record; set their
+// EffectiveTo instead.
+func (c deleteTariff) DeleteTariff(ctx context.Context, id uint, now time.Time) error {
+	err, stored := getTariff{repository: c.repository}.GetTariff(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if !stored.EffectiveFrom.After(now) {
+		return ErrTariffAlreadyEffective
+	}
+
+	return c.repository.Delete(ctx, stored)
+}
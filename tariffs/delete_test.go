@@ -0,0 +1,56 @@
+package tariffs
+
+import (
+	"context"
+	"testing"
+
+	"backend/pricing"
+
+	"github.com/go-rel/rel/where"
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeleteScheduledTariff(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository)
+		stored     = Tariff{ID: 2, Name: "Winter", Currency: "EUR", Billing: pricing.Billing{Unit: pricing.UnitMinute, Rounding: pricing.RoundingCeil}, EffectiveFrom: tomorrow}
+	)
+
+	repository.ExpectFind(where.Eq("id", uint(2))).Result(stored)
+	repository.ExpectDelete().For(&stored)
+
+	assert.Nil(t, service.DeleteTariff(ctx, 2, now))
+
+	repository.AssertExpectations(t)
+}
+
+func TestDeleteEffectiveTariff(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository)
+	)
+
+	repository.ExpectFind(where.Eq("id", uint(1))).Result(tariff)
+
+	assert.Equal(t, ErrTariffAlreadyEffective, service.DeleteTariff(ctx, 1, now))
+
+	repository.AssertExpectations(t)
+}
+
+func TestDeleteTariffNotFound(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository)
+	)
+
+	repository.ExpectFind(where.Eq("id", uint(3))).NotFound()
+
+	assert.Equal(t, ErrTariffNotFound, service.DeleteTariff(ctx, 3, now))
+
+	repository.AssertExpectations(t)
+}
@@ -0,0 +1,61 @@
+package tariffs
+
+import (
+	"context"
+	"errors"
+
+	"github.com/go-rel/rel"
+	"github.com/go-rel/rel/where"
+)
+
+type getTariff struct {
+	repository rel.Repository
+}
+
+func (c getTariff) GetTariff(ctx context.Context, id uint) (error, *Tariff) {
+	var tariff Tariff
+	if err := c.repository.Find(ctx, &tariff, where.Eq("id", id)); err != nil {
+		if errors.Is(err, rel.ErrNotFound) {
+			return ErrTariffNotFound, nil
+		}
+		return err, nil
+	}
+
+	return nil, &tariff
+}
+
+// Filter narrows a list of tariffs down to the versions that can apply to
+// rides on a type of vehicle or in a city, generic versions included. Blank
+// fields match every version.
+type Filter struct {
+	VehicleType string
+	City        string
+}
+
+type listTariffs struct {
+	repository rel.Repository
+}
+
+// ListTariffs returns the tariff versions that match the filter, the latest
+// to become effective first.
+func (c listTariffs) ListTariffs(ctx context.Context, filter Filter) (error, []Tariff) {
+	if filter.VehicleType != "" && !validVehicleType(filter.VehicleType) {
+		return ErrTariffVehicleTypeInvalid, nil
+	}
+
+	var conditions []rel.FilterQuery
+	if filter.VehicleType != "" {
+		conditions = append(conditions, where.In("vehicle_type", "", filter.VehicleType))
+	}
+	if filter.City != "" {
+		conditions = append(conditions, where.In("city", "", filter.City))
+	}
+
+	tariffs := []Tariff{}
+	query := rel.From("tariffs").Where(conditions...).SortDesc("effective_from", "id")
+	if err := c.repository.FindAll(ctx, &tariffs, query); err != nil {
+		return err, nil
+	}
+
+	return nil, tariffs
+}
@@ -0,0 +1,96 @@
+package tariffs
+
+import (
+	"context"
+	"testing"
+
+	"backend/pricing"
+
+	"github.com/go-rel/rel"
+	"github.com/go-rel/rel/where"
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetTariff(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository)
+	)
+
+	repository.ExpectFind(where.Eq("id", uint(1))).Result(tariff)
+
+	err, foundTariff := service.GetTariff(ctx, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, tariff, *foundTariff)
+
+	repository.AssertExpectations(t)
+}
+
+func TestGetTariffNotFound(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository)
+	)
+
+	repository.ExpectFind(where.Eq("id", uint(2))).NotFound()
+
+	err, foundTariff := service.GetTariff(ctx, 2)
+	assert.Equal(t, ErrTariffNotFound, err)
+	assert.Nil(t, foundTariff)
+
+	repository.AssertExpectations(t)
+}
+
+func TestListTariffs(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository)
+		scheduled  = Tariff{ID: 2, Name: "Winter", Currency: "EUR", Billing: pricing.Billing{Unit: pricing.UnitMinute, Rounding: pricing.RoundingCeil}, EffectiveFrom: tomorrow}
+	)
+
+	repository.ExpectFindAll(rel.From("tariffs").SortDesc("effective_from", "id")).Result([]Tariff{scheduled, tariff})
+
+	err, list := service.ListTariffs(ctx, Filter{})
+	assert.Nil(t, err)
+	assert.Equal(t, []Tariff{scheduled, tariff}, list)
+
+	repository.AssertExpectations(t)
+}
+
+func TestListTariffsForVehicleType(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository)
+		mopeds     = Tariff{ID: 2, Name: "Mopeds", VehicleType: "moped", City: "Valencia", Currency: "EUR", Billing: pricing.Billing{Unit: pricing.UnitMinute, Rounding: pricing.RoundingCeil}, EffectiveFrom: now}
+	)
+
+	repository.ExpectFindAll(rel.From("tariffs").Where(
+		where.In("vehicle_type", "", "moped"),
+		where.In("city", "", "Valencia"),
+	).SortDesc("effective_from", "id")).Result([]Tariff{mopeds, tariff})
+
+	err, list := service.ListTariffs(ctx, Filter{VehicleType: "moped", City: "Valencia"})
+	assert.Nil(t, err)
+	assert.Equal(t, []Tariff{mopeds, tariff}, list)
+
+	repository.AssertExpectations(t)
+}
+
+func TestListTariffsVehicleTypeInvalid(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository)
+	)
+
+	err, list := service.ListTariffs(ctx, Filter{VehicleType: "car"})
+	assert.Equal(t, ErrTariffVehicleTypeInvalid, err)
+	assert.Nil(t, list)
+
+	repository.AssertExpectations(t)
+}
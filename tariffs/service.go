@@ -0,0 +1,39 @@
+package tariffs
+
+import (
+	"context"
+	"time"
+
+	"backend/pricing"
+
+	"github.com/go-rel/rel"
+)
+
+type Service interface {
+	CreateTariff(ctx context.Context, tariff *Tariff, now time.Time) (error, *Tariff)
+	UpdateTariff(ctx context.Context, tariff *Tariff, now time.Time) (error, *Tariff)
+	DeleteTariff(ctx context.Context, id uint, now time.Time) error
+	GetTariff(ctx context.Context, id uint) (error, *Tariff)
+	ListTariffs(ctx context.Context, filter Filter) (error, []Tariff)
+	ActiveTariff(ctx context.Context, scope pricing.Scope, at time.Time) (error, *Tariff)
+}
+
+type service struct {
+	createTariff
+	updateTariff
+	deleteTariff
+	getTariff
+	listTariffs
+	activeTariff
+}
+
+func New(repository rel.Repository) Service {
+	return service{
+		createTariff: createTariff{repository: repository},
+		updateTariff: updateTariff{repository: repository},
+		deleteTariff: deleteTariff{repository: repository},
+		getTariff:    getTariff{repository: repository},
+		listTariffs:  listTariffs{repository: repository},
+		activeTariff: activeTariff{repository: repository},
+	}
+}
@@ -0,0 +1,137 @@
+package tariffs
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"backend/money"
+	"backend/pricing"
+)
+
+// Tariff is a version of the ride prices. It applies to the rides started
+// from EffectiveFrom until EffectiveTo, or indefinitely when EffectiveTo is
+// nil. A version can be specific to a type of vehicle, a city or both; blank
+// VehicleType and City match every ride. When versions overlap, the most
+// specific one wins, then the one that became effective last.
+type Tariff struct {
+	ID                uint            `json:"id"`
+	CreatedAt         time.Time       `json:"created_at"`
+	UpdatedAt         time.Time       `json:"updated_at"`
+	Name              string          `json:"name"`
+	VehicleType       string          `json:"vehicle_type"`
+	City              string          `json:"city"`
+	Currency          money.Currency  `json:"currency"`
+	UnlockPrice       int             `json:"unlock_price"`
+	MinutePrice       int             `json:"minute_price"`
+	PausedMinutePrice int             `json:"paused_minute_price"`
+	Billing           pricing.Billing `json:"billing"`
+	Caps              pricing.Caps    `json:"caps"`
+	Prepaid           pricing.Prepaid `json:"prepaid"`
+	TimeZone          string          `json:"time_zone"`
+	Rules             pricing.Rules   `json:"rules"`
+	EffectiveFrom     time.Time       `json:"effective_from"`
+	EffectiveTo       *time.Time      `json:"effective_to"`
+}
+
+var (
+	ErrTariffNameBlank          = errors.New("Name can't be blank")
+	ErrTariffVehicleTypeInvalid = errors.New("VehicleType must be one of: scooter, bike, moped")
+	ErrTariffCurrencyInvalid    = errors.New("Currency must be a three-letter ISO 4217 code")
+	ErrTariffPriceNegative      = errors.New("Prices can't be negative")
+	ErrTariffTimeZoneInvalid    = errors.New("TimeZone must be an IANA time zone name such as Europe/Madrid")
+	ErrTariffEffectiveFromPast  = errors.New("A tariff can't become effective in the past")
+	ErrTariffEffectiveToInvalid = errors.New("EffectiveTo must be after EffectiveFrom")
+	ErrTariffEffectiveToPast    = errors.New("A tariff can't stop being effective in the past")
+	ErrTariffAlreadyEffective   = errors.New("Only the end of a tariff that is already effective can be changed")
+	ErrTariffExpired            = errors.New("A tariff that is no longer effective can't be changed")
+	ErrTariffNotFound           = errors.New("No tariff matches the given ID")
+	ErrNoActiveTariff           = errors.New("No tariff is active at the given instant")
+)
+
+func (t Tariff) Validate() error {
+	switch {
+	case t.Name == "":
+		return ErrTariffNameBlank
+	case t.VehicleType != "" && !validVehicleType(t.VehicleType):
+		return ErrTariffVehicleTypeInvalid
+	case !t.Currency.Valid():
+		return ErrTariffCurrencyInvalid
+	case t.UnlockPrice < 0 || t.MinutePrice < 0 || t.PausedMinutePrice < 0:
+		return ErrTariffPriceNegative
+	case !validTimeZone(t.TimeZone):
+		return ErrTariffTimeZoneInvalid
+	case t.EffectiveTo != nil && !t.EffectiveTo.After(t.EffectiveFrom):
+		return ErrTariffEffectiveToInvalid
+	}
+
+	if err := t.Billing.Validate(); err != nil {
+		return err
+	}
+
+	if err := t.Caps.Validate(); err != nil {
+		return err
+	}
+
+	if err := t.Prepaid.Validate(); err != nil {
+		return err
+	}
+
+	for _, rule := range t.Rules {
+		if err := rule.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Snapshot is the copy of the prices stored on the rides started with this
+// version.
+func (t Tariff) Snapshot() pricing.Tariff {
+	return pricing.Tariff{
+		Version:           strconv.FormatUint(uint64(t.ID), 10),
+		Currency:          t.Currency,
+		UnlockPrice:       t.UnlockPrice,
+		MinutePrice:       t.MinutePrice,
+		PausedMinutePrice: t.PausedMinutePrice,
+		Billing:           t.Billing,
+		Caps:              t.Caps,
+		Prepaid:           t.Prepaid,
+		TimeZone:          t.TimeZone,
+		Rules:             t.Rules,
+	}
+}
+
+// specificity ranks how narrow the scope of the version is, a vehicle type
+// weighing more than a city.
+func (t Tariff) specificity() int {
+	rank := 0
+	if t.VehicleType != "" {
+		rank += 2
+	}
+	if t.City != "" {
+		rank++
+	}
+	return rank
+}
+
+// vehicleTypes are the types of vehicle the fleet has.
+var vehicleTypes = []string{"scooter", "bike", "moped"}
+
+func validVehicleType(vehicleType string) bool {
+	for _, t := range vehicleTypes {
+		if t == vehicleType {
+			return true
+		}
+	}
+	return false
+}
+
+func validTimeZone(name string) bool {
+	if name == "" {
+		return false
+	}
+	_, err := time.LoadLocation(name)
+	return err == nil
+}
@@ -0,0 +1,92 @@
+package tariffs
+
+import (
+	"testing"
+	"time"
+
+	"backend/pricing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	now      = time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	tomorrow = now.Add(24 * time.Hour)
+	tariff   = Tariff{
+		ID:                1,
+		Name:              "Default",
+		Currency:          "EUR",
+		UnlockPrice:       18,
+		MinutePrice:       100,
+		PausedMinutePrice: 25,
+		Billing:           pricing.Billing{Unit: pricing.UnitMinute, Rounding: pricing.RoundingCeil},
+		TimeZone:          "UTC",
+		EffectiveFrom:     now.Add(-24 * time.Hour),
+	}
+)
+
+func TestTariffValidation(t *testing.T) {
+	yesterday := now.Add(-24 * time.Hour)
+
+	tests := []struct {
+		name   string
+		change func(*Tariff)
+		err    error
+	}{
+		{"valid", func(t *Tariff) {}, nil},
+		{"name blank", func(t *Tariff) { t.Name = "" }, ErrTariffNameBlank},
+		{"vehicle type", func(t *Tariff) { t.VehicleType = "moped" }, nil},
+		{"vehicle type unknown", func(t *Tariff) { t.VehicleType = "car" }, ErrTariffVehicleTypeInvalid},
+		{"city", func(t *Tariff) { t.City = "Valencia" }, nil},
+		{"currency blank", func(t *Tariff) { t.Currency = "" }, ErrTariffCurrencyInvalid},
+		{"currency lowercase", func(t *Tariff) { t.Currency = "eur" }, ErrTariffCurrencyInvalid},
+		{"currency too long", func(t *Tariff) { t.Currency = "EURO" }, ErrTariffCurrencyInvalid},
+		{"unlock price negative", func(t *Tariff) { t.UnlockPrice = -1 }, ErrTariffPriceNegative},
+		{"minute price negative", func(t *Tariff) { t.MinutePrice = -1 }, ErrTariffPriceNegative},
+		{"paused minute price negative", func(t *Tariff) { t.PausedMinutePrice = -1 }, ErrTariffPriceNegative},
+		{"free", func(t *Tariff) { t.UnlockPrice, t.MinutePrice, t.PausedMinutePrice = 0, 0, 0 }, nil},
+		{"billing invalid", func(t *Tariff) { t.Billing.Rounding = "up" }, pricing.ErrBillingRoundingInvalid},
+		{"billing per second", func(t *Tariff) { t.Billing.Unit = pricing.UnitSecond }, nil},
+		{"caps", func(t *Tariff) { t.Caps = pricing.Caps{Daily: 1500, Weekly: 6000} }, nil},
+		{"caps negative", func(t *Tariff) { t.Caps.Daily = -1 }, pricing.ErrCapNegative},
+		{"prepaid", func(t *Tariff) { t.Prepaid = pricing.Prepaid{Enabled: true, MinimumBalance: 500} }, nil},
+		{"prepaid minimum negative", func(t *Tariff) { t.Prepaid.MinimumBalance = -1 }, pricing.ErrPrepaidMinimumNegative},
+		{"time zone blank", func(t *Tariff) { t.TimeZone = "" }, ErrTariffTimeZoneInvalid},
+		{"time zone unknown", func(t *Tariff) { t.TimeZone = "Europe/Atlantis" }, ErrTariffTimeZoneInvalid},
+		{"time zone", func(t *Tariff) { t.TimeZone = "Europe/Madrid" }, nil},
+		{"rules", func(t *Tariff) {
+			t.Rules = pricing.Rules{{Name: "Night", From: "22:00", To: "06:00", MultiplierPercent: 50}}
+		}, nil},
+		{"rule invalid", func(t *Tariff) { t.Rules = pricing.Rules{{Name: "Night", From: "22:00", To: "6"}} }, pricing.ErrRuleClockInvalid},
+		{"effective to before from", func(t *Tariff) { t.EffectiveTo = &yesterday; t.EffectiveFrom = now }, ErrTariffEffectiveToInvalid},
+		{"effective to equals from", func(t *Tariff) { t.EffectiveTo = &now; t.EffectiveFrom = now }, ErrTariffEffectiveToInvalid},
+		{"effective to after from", func(t *Tariff) { t.EffectiveTo = &tomorrow }, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			changed := tariff
+			test.change(&changed)
+			assert.Equal(t, test.err, changed.Validate())
+		})
+	}
+}
+
+func TestTariffSnapshot(t *testing.T) {
+	assert.Equal(t, pricing.Tariff{
+		Version:           "1",
+		Currency:          "EUR",
+		UnlockPrice:       18,
+		MinutePrice:       100,
+		PausedMinutePrice: 25,
+		Billing:           pricing.Billing{Unit: pricing.UnitMinute, Rounding: pricing.RoundingCeil},
+		TimeZone:          "UTC",
+	}, tariff.Snapshot())
+}
+
+func TestTariffSnapshotCaps(t *testing.T) {
+	capped := tariff
+	capped.Caps = pricing.Caps{Daily: 1500, Weekly: 6000}
+
+	assert.Equal(t, capped.Caps, capped.Snapshot().Caps)
+}
@@ -0,0 +1,68 @@
+package tariffs
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-rel/rel"
+)
+
+type updateTariff struct {
+	repository rel.Repository
+}
+
+// UpdateTariff replaces a tariff version. Rides may already have been
+// started with a version that is effective, so only its end can be moved,
+// and never into the past.
+func (c updateTariff) UpdateTariff(ctx context.Context, tariff *Tariff, now time.Time) (error, *Tariff) {
+	err, stored := getTariff{repository: c.repository}.GetTariff(ctx, tariff.ID)
+	if err != nil {
+		return err, nil
+	}
+
+	setDefaults(tariff, now)
+	tariff.CreatedAt = stored.CreatedAt
+
+	if err := tariff.Validate(); err != nil {
+		return err, nil
+	}
+
+	if stored.EffectiveFrom.After(now) {
+		if tariff.EffectiveFrom.Before(now) {
+			return ErrTariffEffectiveFromPast, nil
+		}
+	} else {
+		if stored.EffectiveTo != nil && !stored.EffectiveTo.After(now) {
+			return ErrTariffExpired, nil
+		}
+		if !samePrices(*stored, *tariff) {
+			return ErrTariffAlreadyEffective, nil
+		}
+		if tariff.EffectiveTo != nil && tariff.EffectiveTo.Before(now) {
+			return ErrTariffEffectiveToPast, nil
+		}
+	}
+
+	if err := c.repository.Update(ctx, tariff); err != nil {
+		return err, nil
+	}
+
+	return nil, tariff
+}
+
+// samePrices reports whether two versions only differ in their end.
+func samePrices(a, b Tariff) bool {
+	return a.Name == b.Name &&
+		a.VehicleType == b.VehicleType &&
+		a.City == b.City &&
+		a.Currency == b.Currency &&
+		a.UnlockPrice == b.UnlockPrice &&
+		a.MinutePrice == b.MinutePrice &&
+		a.PausedMinutePrice == b.PausedMinutePrice &&
+		a.Billing == b.Billing &&
+		a.Caps == b.Caps &&
+		a.Prepaid == b.Prepaid &&
+		a.TimeZone == b.TimeZone &&
+		a.Rules.Equal(b.Rules) &&
+		a.EffectiveFrom.Equal(b.EffectiveFrom)
+}
@@ -0,0 +1,203 @@
+package tariffs
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"backend/pricing"
+
+	"github.com/go-rel/rel/where"
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateScheduledTariff(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository)
+		createdAt  = now.Add(-time.Hour)
+		stored     = Tariff{ID: 2, CreatedAt: createdAt, Name: "Winter", Currency: "EUR", UnlockPrice: 20, Billing: pricing.Billing{Unit: pricing.UnitMinute, Rounding: pricing.RoundingCeil}, EffectiveFrom: tomorrow}
+		tariff     = Tariff{ID: 2, Name: "Winter", Currency: "EUR", UnlockPrice: 25, EffectiveFrom: tomorrow.Add(time.Hour)}
+	)
+
+	repository.ExpectFind(where.Eq("id", uint(2))).Result(stored)
+	repository.ExpectUpdate().For(&tariff)
+
+	err, savedTariff := service.UpdateTariff(ctx, &tariff, now)
+	assert.Nil(t, err)
+	assert.Equal(t, 25, savedTariff.UnlockPrice)
+	assert.Equal(t, createdAt, savedTariff.CreatedAt)
+
+	repository.AssertExpectations(t)
+}
+
+func TestUpdateScheduledTariffEffectiveFromPast(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository)
+		stored     = Tariff{ID: 2, Name: "Winter", Currency: "EUR", Billing: pricing.Billing{Unit: pricing.UnitMinute, Rounding: pricing.RoundingCeil}, EffectiveFrom: tomorrow}
+		tariff     = Tariff{ID: 2, Name: "Winter", Currency: "EUR", EffectiveFrom: now.Add(-time.Hour)}
+	)
+
+	repository.ExpectFind(where.Eq("id", uint(2))).Result(stored)
+
+	err, savedTariff := service.UpdateTariff(ctx, &tariff, now)
+	assert.Equal(t, ErrTariffEffectiveFromPast, err)
+	assert.Nil(t, savedTariff)
+
+	repository.AssertExpectations(t)
+}
+
+func TestUpdateEffectiveTariffEnd(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository)
+		changed    = tariff
+	)
+	changed.EffectiveTo = &tomorrow
+
+	repository.ExpectFind(where.Eq("id", uint(1))).Result(tariff)
+	repository.ExpectUpdate().For(&changed)
+
+	err, savedTariff := service.UpdateTariff(ctx, &changed, now)
+	assert.Nil(t, err)
+	assert.Equal(t, &tomorrow, savedTariff.EffectiveTo)
+
+	repository.AssertExpectations(t)
+}
+
+func TestUpdateEffectiveTariffEndWithoutRules(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository)
+		stored     = tariff
+		changed    = tariff
+	)
+	stored.Rules = pricing.Rules{}
+	changed.Rules = nil
+	changed.EffectiveTo = &tomorrow
+
+	repository.ExpectFind(where.Eq("id", uint(1))).Result(stored)
+	repository.ExpectUpdate().For(&changed)
+
+	err, savedTariff := service.UpdateTariff(ctx, &changed, now)
+	assert.Nil(t, err)
+	assert.Equal(t, &tomorrow, savedTariff.EffectiveTo)
+
+	repository.AssertExpectations(t)
+}
+
+func TestUpdateEffectiveTariffEndInThePast(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository)
+		changed    = tariff
+		past       = now.Add(-time.Hour)
+	)
+	changed.EffectiveTo = &past
+
+	repository.ExpectFind(where.Eq("id", uint(1))).Result(tariff)
+
+	err, savedTariff := service.UpdateTariff(ctx, &changed, now)
+	assert.Equal(t, ErrTariffEffectiveToPast, err)
+	assert.Nil(t, savedTariff)
+
+	repository.AssertExpectations(t)
+}
+
+func TestUpdateEffectiveTariffPrices(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository)
+		changed    = tariff
+	)
+	changed.MinutePrice = 120
+
+	repository.ExpectFind(where.Eq("id", uint(1))).Result(tariff)
+
+	err, savedTariff := service.UpdateTariff(ctx, &changed, now)
+	assert.Equal(t, ErrTariffAlreadyEffective, err)
+	assert.Nil(t, savedTariff)
+
+	repository.AssertExpectations(t)
+}
+
+func TestUpdateEffectiveTariffCaps(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository)
+		changed    = tariff
+	)
+	changed.Caps.Daily = 1500
+
+	repository.ExpectFind(where.Eq("id", uint(1))).Result(tariff)
+
+	err, savedTariff := service.UpdateTariff(ctx, &changed, now)
+	assert.Equal(t, ErrTariffAlreadyEffective, err)
+	assert.Nil(t, savedTariff)
+
+	repository.AssertExpectations(t)
+}
+
+func TestUpdateEffectiveTariffVehicleType(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository)
+		changed    = tariff
+	)
+	changed.VehicleType = "moped"
+
+	repository.ExpectFind(where.Eq("id", uint(1))).Result(tariff)
+
+	err, savedTariff := service.UpdateTariff(ctx, &changed, now)
+	assert.Equal(t, ErrTariffAlreadyEffective, err)
+	assert.Nil(t, savedTariff)
+
+	repository.AssertExpectations(t)
+}
+
+func TestUpdateExpiredTariff(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository)
+		stored     = tariff
+		changed    = tariff
+		past       = now.Add(-time.Hour)
+	)
+	stored.EffectiveTo = &past
+
+	repository.ExpectFind(where.Eq("id", uint(1))).Result(stored)
+
+	err, savedTariff := service.UpdateTariff(ctx, &changed, now)
+	assert.Equal(t, ErrTariffExpired, err)
+	assert.Nil(t, savedTariff)
+
+	repository.AssertExpectations(t)
+}
+
+func TestUpdateTariffNotFound(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository)
+		changed    = Tariff{ID: 3, Name: "Winter", Currency: "EUR"}
+	)
+
+	repository.ExpectFind(where.Eq("id", uint(3))).NotFound()
+
+	err, savedTariff := service.UpdateTariff(ctx, &changed, now)
+	assert.Equal(t, ErrTariffNotFound, err)
+	assert.Nil(t, savedTariff)
+
+	repository.AssertExpectations(t)
+}
@@ -0,0 +1,29 @@
+package taxes
+
+import (
+	"context"
+	"errors"
+
+	"github.com/go-rel/rel"
+)
+
+type createRate struct {
+	repository rel.Repository
+}
+
+// CreateRate sets the tax rate of a city or an operator. Rides already
+// started keep the rate they started with.
+func (c createRate) CreateRate(ctx context.Context, rate *Rate) (error, *Rate) {
+	if err := rate.Validate(); err != nil {
+		return err, nil
+	}
+
+	if err := c.repository.Insert(ctx, rate); err != nil {
+		if errors.Is(err, rel.ErrUniqueConstraint) {
+			return ErrTaxRateTaken, nil
+		}
+		return err, nil
+	}
+
+	return nil, rate
+}
@@ -0,0 +1,80 @@
+package taxes
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-rel/rel"
+	"github.com/go-rel/rel/where"
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateRate(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository)
+		rate       = vat
+	)
+	rate.ID = 0
+
+	repository.ExpectInsert().For(&rate)
+
+	err, savedRate := service.CreateRate(ctx, &rate)
+	assert.Nil(t, err)
+	assert.NotEmpty(t, savedRate.ID)
+
+	repository.AssertExpectations(t)
+}
+
+func TestCreateRateTaken(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository)
+		rate       = vat
+	)
+	rate.ID = 0
+
+	repository.ExpectInsert().For(&rate).Error(rel.ErrUniqueConstraint)
+
+	err, savedRate := service.CreateRate(ctx, &rate)
+	assert.Equal(t, ErrTaxRateTaken, err)
+	assert.Nil(t, savedRate)
+
+	repository.AssertExpectations(t)
+}
+
+func TestUpdateRate(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository)
+		rate       = vat
+	)
+	rate.Rate = 1000
+
+	repository.ExpectFind(where.Eq("id", vat.ID)).Result(vat)
+	repository.ExpectUpdate().For(&rate)
+
+	err, savedRate := service.UpdateRate(ctx, &rate)
+	assert.Nil(t, err)
+	assert.Equal(t, 1000, savedRate.Rate)
+
+	repository.AssertExpectations(t)
+}
+
+func TestDeleteRateNotFound(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository)
+	)
+
+	repository.ExpectFind(where.Eq("id", uint(2))).NotFound()
+
+	assert.Equal(t, ErrTaxRateNotFound, service.DeleteRate(ctx, 2))
+
+	repository.AssertExpectations(t)
+}
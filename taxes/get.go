@@ -0,0 +1,39 @@
+package taxes
+
+import (
+	"context"
+	"errors"
+
+	"github.com/go-rel/rel"
+	"github.com/go-rel/rel/where"
+)
+
+type getRate struct {
+	repository rel.Repository
+}
+
+func (c getRate) GetRate(ctx context.Context, id uint) (error, *Rate) {
+	var rate Rate
+	if err := c.repository.Find(ctx, &rate, where.Eq("id", id)); err != nil {
+		if errors.Is(err, rel.ErrNotFound) {
+			return ErrTaxRateNotFound, nil
+		}
+		return err, nil
+	}
+
+	return nil, &rate
+}
+
+type listRates struct {
+	repository rel.Repository
+}
+
+// ListRates returns the rates of every city, then of every operator.
+func (c listRates) ListRates(ctx context.Context) (error, []Rate) {
+	rates := []Rate{}
+	if err := c.repository.FindAll(ctx, &rates, rel.From("tax_rates").SortDesc("city").SortAsc("operator", "id")); err != nil {
+		return err, nil
+	}
+
+	return nil, rates
+}
@@ -0,0 +1,47 @@
+package taxes
+
+import (
+	"errors"
+	"time"
+
+	"backend/pricing"
+)
+
+// Rate is the tax rate of the rides in a city or run by an operator. The
+// rate of a city wins over the rate of the operator.
+type Rate struct {
+	ID        uint                `json:"id"`
+	CreatedAt time.Time           `json:"created_at"`
+	UpdatedAt time.Time           `json:"updated_at"`
+	City      string              `json:"city,omitempty"`
+	Operator  string              `json:"operator,omitempty"`
+	Name      string              `json:"name"`
+	Rate      int                 `json:"rate"` // in basis points, 2100 for 21%
+	Rounding  pricing.TaxRounding `json:"rounding"`
+}
+
+func (Rate) Table() string {
+	return "tax_rates"
+}
+
+var (
+	ErrTaxScopeInvalid = errors.New("A tax rate applies to either a city or an operator")
+	ErrTaxNameBlank    = errors.New("Name can't be blank")
+	ErrTaxRateTaken    = errors.New("The city or operator already has a tax rate")
+	ErrTaxRateNotFound = errors.New("No tax rate matches the given ID")
+)
+
+func (r Rate) Validate() error {
+	switch {
+	case (r.City == "") == (r.Operator == ""):
+		return ErrTaxScopeInvalid
+	case r.Name == "":
+		return ErrTaxNameBlank
+	}
+	return r.TaxRate().Validate()
+}
+
+// TaxRate is the rate as applied to ride prices.
+func (r Rate) TaxRate() pricing.TaxRate {
+	return pricing.TaxRate{Name: r.Name, Rate: r.Rate, Rounding: r.Rounding}
+}
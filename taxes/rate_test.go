@@ -0,0 +1,33 @@
+package taxes
+
+import (
+	"testing"
+
+	"backend/pricing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var vat = Rate{ID: 1, City: "Valencia", Name: "VAT", Rate: 2100, Rounding: pricing.TaxRoundingPerTotal}
+
+func TestRateValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		rate Rate
+		err  error
+	}{
+		{"city", vat, nil},
+		{"operator", Rate{Operator: "acme", Name: "VAT", Rate: 2100, Rounding: pricing.TaxRoundingPerLine}, nil},
+		{"no scope", Rate{Name: "VAT", Rate: 2100, Rounding: pricing.TaxRoundingPerLine}, ErrTaxScopeInvalid},
+		{"both scopes", Rate{City: "Valencia", Operator: "acme", Name: "VAT", Rate: 2100, Rounding: pricing.TaxRoundingPerLine}, ErrTaxScopeInvalid},
+		{"name blank", Rate{City: "Valencia", Rate: 2100, Rounding: pricing.TaxRoundingPerLine}, ErrTaxNameBlank},
+		{"rate", Rate{City: "Valencia", Name: "VAT", Rate: 12000, Rounding: pricing.TaxRoundingPerLine}, pricing.ErrTaxRateInvalid},
+		{"rounding", Rate{City: "Valencia", Name: "VAT", Rate: 2100}, pricing.ErrTaxRoundingInvalid},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.err, test.rate.Validate())
+		})
+	}
+}
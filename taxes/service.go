@@ -0,0 +1,33 @@
+package taxes
+
+import (
+	"context"
+
+	"github.com/go-rel/rel"
+)
+
+type Service interface {
+	CreateRate(ctx context.Context, rate *Rate) (error, *Rate)
+	UpdateRate(ctx context.Context, rate *Rate) (error, *Rate)
+	DeleteRate(ctx context.Context, id uint) error
+	GetRate(ctx context.Context, id uint) (error, *Rate)
+	ListRates(ctx context.Context) (error, []Rate)
+}
+
+type service struct {
+	createRate
+	updateRate
+	deleteRate
+	getRate
+	listRates
+}
+
+func New(repository rel.Repository) Service {
+	return service{
+		createRate: createRate{repository: repository},
+		updateRate: updateRate{repository: repository},
+		deleteRate: deleteRate{repository: repository},
+		getRate:    getRate{repository: repository},
+		listRates:  listRates{repository: repository},
+	}
+}
@@ -0,0 +1,45 @@
+package taxes
+
+import (
+	"context"
+	"errors"
+
+	"backend/pricing"
+
+	"github.com/go-rel/rel"
+	"github.com/go-rel/rel/where"
+)
+
+// Source is the pricing.Taxes backed by the stored tax rates.
+type Source struct {
+	repository rel.Repository
+}
+
+func NewSource(repository rel.Repository) Source {
+	return Source{repository: repository}
+}
+
+func (s Source) Rate(ctx context.Context, city, operator string) (pricing.TaxRate, error) {
+	scopes := []struct {
+		field, value string
+	}{
+		{"city", city},
+		{"operator", operator},
+	}
+	for _, scope := range scopes {
+		if scope.value == "" {
+			continue
+		}
+
+		var rate Rate
+		err := s.repository.Find(ctx, &rate, where.Eq(scope.field, scope.value))
+		if err == nil {
+			return rate.TaxRate(), nil
+		}
+		if !errors.Is(err, rel.ErrNotFound) {
+			return pricing.TaxRate{}, err
+		}
+	}
+
+	return pricing.TaxRate{}, nil
+}
@@ -0,0 +1,53 @@
+package taxes
+
+import (
+	"context"
+	"testing"
+
+	"backend/pricing"
+
+	"github.com/go-rel/rel/where"
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSourceRate(t *testing.T) {
+	operator := Rate{ID: 2, Operator: "acme", Name: "Sales tax", Rate: 800, Rounding: pricing.TaxRoundingPerLine}
+
+	tests := []struct {
+		name     string
+		city     string
+		operator string
+		expect   func(repository *reltest.Repository)
+		rate     pricing.TaxRate
+	}{
+		{"city", "Valencia", "acme", func(repository *reltest.Repository) {
+			repository.ExpectFind(where.Eq("city", "Valencia")).Result(vat)
+		}, vat.TaxRate()},
+		{"operator", "Madrid", "acme", func(repository *reltest.Repository) {
+			repository.ExpectFind(where.Eq("city", "Madrid")).NotFound()
+			repository.ExpectFind(where.Eq("operator", "acme")).Result(operator)
+		}, operator.TaxRate()},
+		{"none", "", "other", func(repository *reltest.Repository) {
+			repository.ExpectFind(where.Eq("operator", "other")).NotFound()
+		}, pricing.TaxRate{}},
+		{"unknown", "", "", func(repository *reltest.Repository) {}, pricing.TaxRate{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				ctx        = context.TODO()
+				repository = reltest.New()
+				source     = NewSource(repository)
+			)
+			test.expect(repository)
+
+			rate, err := source.Rate(ctx, test.city, test.operator)
+			assert.Nil(t, err)
+			assert.Equal(t, test.rate, rate)
+
+			repository.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package taxes
+
+import (
+	"context"
+	"errors"
+
+	"github.com/go-rel/rel"
+	"github.com/go-rel/rel/where"
+)
+
+type updateRate struct {
+	repository rel.Repository
+}
+
+// UpdateRate replaces the tax rate that matches rate.ID. Rides already
+// started keep the rate they started with.
+func (c updateRate) UpdateRate(ctx context.Context, rate *Rate) (error, *Rate) {
+	if err := rate.Validate(); err != nil {
+		return err, nil
+	}
+
+	var current Rate
+	if err := c.repository.Find(ctx, &current, where.Eq("id", rate.ID)); err != nil {
+		if errors.Is(err, rel.ErrNotFound) {
+			return ErrTaxRateNotFound, nil
+		}
+		return err, nil
+	}
+
+	rate.CreatedAt = current.CreatedAt
+	if err := c.repository.Update(ctx, rate); err != nil {
+		if errors.Is(err, rel.ErrUniqueConstraint) {
+			return ErrTaxRateTaken, nil
+		}
+		return err, nil
+	}
+
+	return nil, rate
+}
+
+type deleteRate struct {
+	repository rel.Repository
+}
+
+func (c deleteRate) DeleteRate(ctx context.Context, id uint) error {
+	var rate Rate
+	if err := c.repository.Find(ctx, &rate, where.Eq("id", id)); err != nil {
+		if errors.Is(err, rel.ErrNotFound) {
+			return ErrTaxRateNotFound
+		}
+		return err
+	}
+
+	return c.repository.Delete(ctx, &rate)
+}
@@ -0,0 +1,57 @@
+package users
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/go-rel/rel"
+	"github.com/go-rel/rel/where"
+)
+
+// Directory is the rides.Riders backed by the stored users.
+type Directory struct {
+	repository rel.Repository
+}
+
+func NewDirectory(repository rel.Repository) Directory {
+	return Directory{repository: repository}
+}
+
+func (d Directory) Allow(ctx context.Context, userID string) error {
+	user, err := d.find(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	return user.CanRide()
+}
+
+// Address is the email address riders are reached at, as the
+// batteries.Mailer needs it.
+func (d Directory) Address(ctx context.Context, userID string) (string, error) {
+	user, err := d.find(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+
+	return user.Email, nil
+}
+
+func (d Directory) find(ctx context.Context, userID string) (User, error) {
+	var user User
+
+	id, err := strconv.ParseUint(userID, 10, 0)
+	if err != nil {
+		return user, ErrUserNotFound
+	}
+
+	if err := d.repository.Find(ctx, &user, where.Eq("id", uint(id))); err != nil {
+		if errors.Is(err, rel.ErrNotFound) {
+			return user, ErrUserNotFound
+		}
+		return user, err
+	}
+
+	return user, nil
+}
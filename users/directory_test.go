@@ -0,0 +1,69 @@
+package users
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-rel/rel/where"
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDirectoryAllow(t *testing.T) {
+	suspended := alice
+	suspended.Status = StatusSuspended
+
+	tests := []struct {
+		name string
+		user *User
+		err  error
+	}{
+		{"active", &alice, nil},
+		{"suspended", &suspended, ErrUserSuspended},
+		{"not found", nil, ErrUserNotFound},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repository := reltest.New()
+			if test.user != nil {
+				repository.ExpectFind(where.Eq("id", uint(1))).Result(*test.user)
+			} else {
+				repository.ExpectFind(where.Eq("id", uint(1))).NotFound()
+			}
+
+			assert.Equal(t, test.err, NewDirectory(repository).Allow(context.TODO(), "1"))
+
+			repository.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDirectoryAllowMalformedID(t *testing.T) {
+	repository := reltest.New()
+
+	assert.Equal(t, ErrUserNotFound, NewDirectory(repository).Allow(context.TODO(), "alice"))
+
+	repository.AssertExpectations(t)
+}
+
+func TestDirectoryAddress(t *testing.T) {
+	repository := reltest.New()
+	repository.ExpectFind(where.Eq("id", uint(1))).Result(alice)
+
+	address, err := NewDirectory(repository).Address(context.TODO(), "1")
+	assert.Nil(t, err)
+	assert.Equal(t, alice.Email, address)
+
+	repository.AssertExpectations(t)
+}
+
+func TestDirectoryAddressNotFound(t *testing.T) {
+	repository := reltest.New()
+	repository.ExpectFind(where.Eq("id", uint(1))).NotFound()
+
+	_, err := NewDirectory(repository).Address(context.TODO(), "1")
+	assert.Equal(t, ErrUserNotFound, err)
+
+	repository.AssertExpectations(t)
+}
@@ -0,0 +1,53 @@
+package users
+
+import (
+	"context"
+	"errors"
+
+	"github.com/go-rel/rel"
+	"github.com/go-rel/rel/where"
+)
+
+type getUser struct {
+	repository rel.Repository
+}
+
+func (c getUser) GetUser(ctx context.Context, id uint) (error, *User) {
+	return find(ctx, c.repository, id)
+}
+
+type listUsers struct {
+	repository rel.Repository
+}
+
+// ListUsers returns the users with the given status, or every user when it
+// is blank, the oldest first.
+func (c listUsers) ListUsers(ctx context.Context, status Status) (error, []User) {
+	if status != "" && !status.Valid() {
+		return ErrUserStatusInvalid, nil
+	}
+
+	query := rel.From("users").SortAsc("id")
+	if status != "" {
+		query = query.Where(where.Eq("status", status))
+	}
+
+	users := []User{}
+	if err := c.repository.FindAll(ctx, &users, query); err != nil {
+		return err, nil
+	}
+
+	return nil, users
+}
+
+func find(ctx context.Context, repository rel.Repository, id uint) (error, *User) {
+	var user User
+	if err := repository.Find(ctx, &user, where.Eq("id", id)); err != nil {
+		if errors.Is(err, rel.ErrNotFound) {
+			return ErrUserNotFound, nil
+		}
+		return err, nil
+	}
+
+	return nil, &user
+}
@@ -0,0 +1,32 @@
+package users
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/go-rel/rel"
+)
+
+type registerUser struct {
+	repository rel.Repository
+}
+
+// RegisterUser stores a new user, active right away. Emails are unique
+// regardless of their case.
+func (c registerUser) RegisterUser(ctx context.Context, user *User) (error, *User) {
+	user.Email = strings.ToLower(user.Email)
+	if err := user.Validate(); err != nil {
+		return err, nil
+	}
+
+	user.Status = StatusActive
+	if err := c.repository.Insert(ctx, user); err != nil {
+		if errors.Is(err, rel.ErrUniqueConstraint) {
+			return ErrUserEmailTaken, nil
+		}
+		return err, nil
+	}
+
+	return nil, user
+}
@@ -0,0 +1,45 @@
+package users
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-rel/rel"
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterUser(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository)
+		user       = User{Name: "Alice", Email: "Alice@Example.com"}
+	)
+
+	repository.ExpectInsert().For(&User{Name: "Alice", Email: "alice@example.com", Status: StatusActive})
+
+	err, savedUser := service.RegisterUser(ctx, &user)
+	assert.Nil(t, err)
+	assert.NotEmpty(t, savedUser.ID)
+	assert.Equal(t, StatusActive, savedUser.Status)
+
+	repository.AssertExpectations(t)
+}
+
+func TestRegisterUserEmailTaken(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository)
+		user       = User{Name: "Alice", Email: "alice@example.com"}
+	)
+
+	repository.ExpectInsert().ForType("*users.User").Error(rel.ErrUniqueConstraint)
+
+	err, savedUser := service.RegisterUser(ctx, &user)
+	assert.Equal(t, ErrUserEmailTaken, err)
+	assert.Nil(t, savedUser)
+
+	repository.AssertExpectations(t)
+}
@@ -0,0 +1,38 @@
+package users
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-rel/rel"
+)
+
+type Service interface {
+	RegisterUser(ctx context.Context, user *User) (error, *User)
+	UpdateUser(ctx context.Context, user *User) (error, *User)
+	SetStatus(ctx context.Context, id uint, status Status, now time.Time) (error, *User)
+	DeleteUser(ctx context.Context, id uint, now time.Time) error
+	GetUser(ctx context.Context, id uint) (error, *User)
+	ListUsers(ctx context.Context, status Status) (error, []User)
+}
+
+type service struct {
+	registerUser
+	updateUser
+	setStatus
+	deleteUser
+	getUser
+	listUsers
+}
+
+func New(repository rel.Repository) Service {
+	status := setStatus{repository: repository}
+	return service{
+		registerUser: registerUser{repository: repository},
+		updateUser:   updateUser{repository: repository},
+		setStatus:    status,
+		deleteUser:   deleteUser{status: status},
+		getUser:      getUser{repository: repository},
+		listUsers:    listUsers{repository: repository},
+	}
+}
@@ -0,0 +1,91 @@
+package users
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/go-rel/rel"
+	"github.com/go-rel/rel/where"
+)
+
+type updateUser struct {
+	repository rel.Repository
+}
+
+// UpdateUser replaces the name and email of the user that matches user.ID.
+// Its status only changes through SetStatus.
+func (c updateUser) UpdateUser(ctx context.Context, user *User) (error, *User) {
+	user.Email = strings.ToLower(user.Email)
+	if err := user.Validate(); err != nil {
+		return err, nil
+	}
+
+	err, current := find(ctx, c.repository, user.ID)
+	if err != nil {
+		return err, nil
+	}
+	if current.Status == StatusDeleted {
+		return ErrUserDeleted, nil
+	}
+
+	user.CreatedAt = current.CreatedAt
+	user.Status = current.Status
+	if err := c.repository.Update(ctx, user); err != nil {
+		if errors.Is(err, rel.ErrUniqueConstraint) {
+			return ErrUserEmailTaken, nil
+		}
+		return err, nil
+	}
+
+	return nil, user
+}
+
+type setStatus struct {
+	repository rel.Repository
+}
+
+// SetStatus suspends, reactivates or deletes the user. Deleting is final,
+// so the status of a deleted user never changes again.
+func (c setStatus) SetStatus(ctx context.Context, id uint, status Status, now time.Time) (error, *User) {
+	if !status.Valid() {
+		return ErrUserStatusInvalid, nil
+	}
+
+	err, user := find(ctx, c.repository, id)
+	if err != nil {
+		return err, nil
+	}
+	if user.Status == StatusDeleted {
+		return ErrUserDeleted, nil
+	}
+
+	// The user may have been deleted since it was read.
+	updated, err := c.repository.UpdateAny(ctx,
+		rel.From("users").Where(where.Eq("id", id).AndNe("status", StatusDeleted)),
+		rel.Set("status", status),
+		rel.Set("updated_at", now),
+	)
+	if err != nil {
+		return err, nil
+	}
+	if updated == 0 {
+		return ErrUserDeleted, nil
+	}
+
+	user.Status = status
+	user.UpdatedAt = now
+	return nil, user
+}
+
+type deleteUser struct {
+	status setStatus
+}
+
+// DeleteUser marks the user as deleted rather than removing it, as its rides
+// and invoices still refer to it.
+func (c deleteUser) DeleteUser(ctx context.Context, id uint, now time.Time) error {
+	err, _ := c.status.SetStatus(ctx, id, StatusDeleted, now)
+	return err
+}
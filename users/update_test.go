@@ -0,0 +1,105 @@
+package users
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-rel/rel"
+	"github.com/go-rel/rel/where"
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateUser(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository)
+		user       = User{ID: alice.ID, Name: "Alice Smith", Email: "alice@example.com"}
+	)
+
+	repository.ExpectFind(where.Eq("id", alice.ID)).Result(alice)
+	repository.ExpectUpdate().For(&User{ID: alice.ID, Name: "Alice Smith", Email: "alice@example.com", Status: StatusActive})
+
+	err, savedUser := service.UpdateUser(ctx, &user)
+	assert.Nil(t, err)
+	assert.Equal(t, "Alice Smith", savedUser.Name)
+	assert.Equal(t, StatusActive, savedUser.Status)
+
+	repository.AssertExpectations(t)
+}
+
+func TestUpdateUserDeleted(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository)
+		deleted    = alice
+		user       = alice
+	)
+	deleted.Status = StatusDeleted
+
+	repository.ExpectFind(where.Eq("id", alice.ID)).Result(deleted)
+
+	err, savedUser := service.UpdateUser(ctx, &user)
+	assert.Equal(t, ErrUserDeleted, err)
+	assert.Nil(t, savedUser)
+
+	repository.AssertExpectations(t)
+}
+
+func TestSuspendUser(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository)
+	)
+
+	repository.ExpectFind(where.Eq("id", alice.ID)).Result(alice)
+	repository.ExpectUpdateAny(
+		rel.From("users").Where(where.Eq("id", alice.ID).AndNe("status", StatusDeleted)),
+		rel.Set("status", StatusSuspended),
+		rel.Set("updated_at", now),
+	).UpdatedCount(1)
+
+	err, user := service.SetStatus(ctx, alice.ID, StatusSuspended, now)
+	assert.Nil(t, err)
+	assert.Equal(t, StatusSuspended, user.Status)
+	assert.Equal(t, now, user.UpdatedAt)
+
+	repository.AssertExpectations(t)
+}
+
+func TestDeleteUserRaced(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository)
+	)
+
+	repository.ExpectFind(where.Eq("id", alice.ID)).Result(alice)
+	repository.ExpectUpdateAny(
+		rel.From("users").Where(where.Eq("id", alice.ID).AndNe("status", StatusDeleted)),
+		rel.Set("status", StatusDeleted),
+		rel.Set("updated_at", now),
+	).UpdatedCount(0)
+
+	err := service.DeleteUser(ctx, alice.ID, now)
+	assert.Equal(t, ErrUserDeleted, err)
+
+	repository.AssertExpectations(t)
+}
+
+func TestSetStatusInvalid(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository)
+	)
+
+	err, user := service.SetStatus(ctx, alice.ID, "banned", now)
+	assert.Equal(t, ErrUserStatusInvalid, err)
+	assert.Nil(t, user)
+
+	repository.AssertExpectations(t)
+}
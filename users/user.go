@@ -0,0 +1,74 @@
+package users
+
+import (
+	"errors"
+	"net/mail"
+	"time"
+)
+
+// Status is where the account of a user stands.
+type Status string
+
+const (
+	StatusActive Status = "active"
+	// StatusSuspended users keep their account but can't start rides until
+	// they are reactivated.
+	StatusSuspended Status = "suspended"
+	// StatusDeleted users are kept for the rides and invoices that refer to
+	// them, and can't be changed anymore.
+	StatusDeleted Status = "deleted"
+)
+
+func (s Status) Valid() bool {
+	switch s {
+	case StatusActive, StatusSuspended, StatusDeleted:
+		return true
+	}
+	return false
+}
+
+// User is a registered rider. Rides refer to it by its ID written in
+// decimal.
+type User struct {
+	ID        uint      `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Name      string    `json:"name"`
+	Email     string    `json:"email"`
+	Status    Status    `json:"status"`
+}
+
+func (User) Table() string {
+	return "users"
+}
+
+var (
+	ErrUserNameBlank     = errors.New("Name can't be blank")
+	ErrUserEmailInvalid  = errors.New("Email must be a valid address")
+	ErrUserEmailTaken    = errors.New("Another user already registered this email")
+	ErrUserNotFound      = errors.New("No user matches the given ID")
+	ErrUserStatusInvalid = errors.New("Status must be one of: active, suspended, deleted")
+	ErrUserSuspended     = errors.New("The user is suspended and can't ride")
+	ErrUserDeleted       = errors.New("The user was deleted")
+)
+
+func (u User) Validate() error {
+	if u.Name == "" {
+		return ErrUserNameBlank
+	}
+	if address, err := mail.ParseAddress(u.Email); err != nil || address.Address != u.Email {
+		return ErrUserEmailInvalid
+	}
+	return nil
+}
+
+// CanRide tells whether the user is allowed to start a ride.
+func (u User) CanRide() error {
+	switch u.Status {
+	case StatusActive:
+		return nil
+	case StatusSuspended:
+		return ErrUserSuspended
+	}
+	return ErrUserDeleted
+}
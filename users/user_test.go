@@ -0,0 +1,50 @@
+package users
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	now   = time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)
+	alice = User{ID: 1, Name: "Alice", Email: "alice@example.com", Status: StatusActive}
+)
+
+func TestUserValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		user User
+		err  error
+	}{
+		{"valid", alice, nil},
+		{"name blank", User{Email: "alice@example.com"}, ErrUserNameBlank},
+		{"email blank", User{Name: "Alice"}, ErrUserEmailInvalid},
+		{"email invalid", User{Name: "Alice", Email: "alice"}, ErrUserEmailInvalid},
+		{"email with name", User{Name: "Alice", Email: "Alice <alice@example.com>"}, ErrUserEmailInvalid},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.err, test.user.Validate())
+		})
+	}
+}
+
+func TestUserCanRide(t *testing.T) {
+	tests := []struct {
+		status Status
+		err    error
+	}{
+		{StatusActive, nil},
+		{StatusSuspended, ErrUserSuspended},
+		{StatusDeleted, ErrUserDeleted},
+	}
+
+	for _, test := range tests {
+		t.Run(string(test.status), func(t *testing.T) {
+			assert.Equal(t, test.err, User{Status: test.status}.CanRide())
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package vehicles
+
+import (
+	"context"
+
+	"github.com/go-rel/rel"
+)
+
+type createVehicle struct {
+	repository rel.Repository
+}
+
+// CreateVehicle adds a vehicle to the fleet, available unless told
+// otherwise.
+func (c createVehicle) CreateVehicle(ctx context.Context, vehicle *Vehicle) (error, *Vehicle) {
+	if vehicle.Status == "" {
+		vehicle.Status = StatusAvailable
+	}
+	if err := vehicle.Validate(); err != nil {
+		return err, nil
+	}
+	if vehicle.Status == StatusInRide {
+		return ErrVehicleInRide, nil
+	}
+
+	if err := c.repository.Insert(ctx, vehicle); err != nil {
+		return err, nil
+	}
+
+	return nil, vehicle
+}
@@ -0,0 +1,42 @@
+package vehicles
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateVehicle(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository)
+		vehicle    = Vehicle{Type: TypeScooter, Model: "Ninebot Max", Battery: 100}
+	)
+
+	repository.ExpectInsert().For(&Vehicle{Type: TypeScooter, Model: "Ninebot Max", Status: StatusAvailable, Battery: 100})
+
+	err, savedVehicle := service.CreateVehicle(ctx, &vehicle)
+	assert.Nil(t, err)
+	assert.NotEmpty(t, savedVehicle.ID)
+	assert.Equal(t, StatusAvailable, savedVehicle.Status)
+
+	repository.AssertExpectations(t)
+}
+
+func TestCreateVehicleInRide(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository)
+		vehicle    = Vehicle{Type: TypeScooter, Model: "Ninebot Max", Status: StatusInRide}
+	)
+
+	err, savedVehicle := service.CreateVehicle(ctx, &vehicle)
+	assert.Equal(t, ErrVehicleInRide, err)
+	assert.Nil(t, savedVehicle)
+
+	repository.AssertExpectations(t)
+}
@@ -0,0 +1,86 @@
+package vehicles
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"backend/rides"
+
+	"github.com/go-rel/rel"
+	"github.com/go-rel/rel/where"
+)
+
+// Fleet is the rides.Vehicles backed by the stored vehicles. Rides only
+// start on vehicles with at least minimumBattery percent of battery.
+type Fleet struct {
+	repository     rel.Repository
+	minimumBattery int
+}
+
+func NewFleet(repository rel.Repository, minimumBattery int) Fleet {
+	return Fleet{repository: repository, minimumBattery: minimumBattery}
+}
+
+// Lookup returns the type of the vehicle and the city and operator it
+// runs in.
+func (f Fleet) Lookup(ctx context.Context, vehicleID string) (rides.Vehicle, error) {
+	id, err := strconv.ParseUint(vehicleID, 10, 0)
+	if err != nil {
+		return rides.Vehicle{}, ErrVehicleNotFound
+	}
+
+	err, vehicle := find(ctx, f.repository, uint(id))
+	if err != nil {
+		return rides.Vehicle{}, err
+	}
+	return rides.Vehicle{Type: string(vehicle.Type), City: vehicle.City, Operator: vehicle.Operator}, nil
+}
+
+// Take moves the vehicle from available to in_ride in a single statement,
+// so two rides can't take it at once.
+func (f Fleet) Take(ctx context.Context, vehicleID string, at time.Time) error {
+	id, err := strconv.ParseUint(vehicleID, 10, 0)
+	if err != nil {
+		return ErrVehicleNotFound
+	}
+
+	updated, err := f.repository.UpdateAny(ctx,
+		rel.From("vehicles").Where(
+			where.Eq("id", uint(id)).
+				AndEq("status", StatusAvailable).
+				AndGte("battery", f.minimumBattery),
+		),
+		rel.Set("status", StatusInRide),
+		rel.Set("updated_at", at),
+	)
+	if err != nil || updated != 0 {
+		return err
+	}
+
+	err, vehicle := find(ctx, f.repository, uint(id))
+	switch {
+	case err != nil:
+		return err
+	case vehicle.Status != StatusAvailable:
+		return ErrVehicleNotAvailable
+	}
+	return ErrBatteryTooLow
+}
+
+// Release makes the vehicle available again if it is still in a ride. Rides
+// started before the fleet was registered may refer to vehicles that don't
+// exist, which are left alone.
+func (f Fleet) Release(ctx context.Context, vehicleID string, at time.Time) error {
+	id, err := strconv.ParseUint(vehicleID, 10, 0)
+	if err != nil {
+		return nil
+	}
+
+	_, err = f.repository.UpdateAny(ctx,
+		rel.From("vehicles").Where(where.Eq("id", uint(id)).AndEq("status", StatusInRide)),
+		rel.Set("status", StatusAvailable),
+		rel.Set("updated_at", at),
+	)
+	return err
+}
@@ -0,0 +1,106 @@
+package vehicles
+
+import (
+	"context"
+	"testing"
+
+	"backend/rides"
+
+	"github.com/go-rel/rel"
+	"github.com/go-rel/rel/where"
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFleetLookup(t *testing.T) {
+	repository := reltest.New()
+
+	repository.ExpectFind(where.Eq("id", uint(1))).Result(scooter)
+
+	vehicle, err := NewFleet(repository, 20).Lookup(context.TODO(), "1")
+	assert.Nil(t, err)
+	assert.Equal(t, rides.Vehicle{Type: "scooter", City: "Valencia", Operator: "acme"}, vehicle)
+
+	repository.AssertExpectations(t)
+}
+
+func TestFleetLookupNotFound(t *testing.T) {
+	repository := reltest.New()
+
+	repository.ExpectFind(where.Eq("id", uint(2))).NotFound()
+
+	_, err := NewFleet(repository, 20).Lookup(context.TODO(), "2")
+	assert.Equal(t, ErrVehicleNotFound, err)
+
+	repository.AssertExpectations(t)
+}
+
+func TestFleetTake(t *testing.T) {
+	repository := reltest.New()
+
+	repository.ExpectUpdateAny(
+		rel.From("vehicles").Where(where.Eq("id", uint(1)).AndEq("status", StatusAvailable).AndGte("battery", 20)),
+		rel.Set("status", StatusInRide),
+		rel.Set("updated_at", now),
+	).UpdatedCount(1)
+
+	assert.Nil(t, NewFleet(repository, 20).Take(context.TODO(), "1", now))
+
+	repository.AssertExpectations(t)
+}
+
+func TestFleetTakeNotAvailable(t *testing.T) {
+	repository := reltest.New()
+	inRide := scooter
+	inRide.Status = StatusInRide
+
+	repository.ExpectUpdateAny(
+		rel.From("vehicles").Where(where.Eq("id", uint(1)).AndEq("status", StatusAvailable).AndGte("battery", 20)),
+		rel.Set("status", StatusInRide),
+		rel.Set("updated_at", now),
+	).UpdatedCount(0)
+	repository.ExpectFind(where.Eq("id", uint(1))).Result(inRide)
+
+	assert.Equal(t, ErrVehicleNotAvailable, NewFleet(repository, 20).Take(context.TODO(), "1", now))
+
+	repository.AssertExpectations(t)
+}
+
+func TestFleetTakeBatteryTooLow(t *testing.T) {
+	repository := reltest.New()
+	lowBattery := scooter
+	lowBattery.Battery = 19
+
+	repository.ExpectUpdateAny(
+		rel.From("vehicles").Where(where.Eq("id", uint(1)).AndEq("status", StatusAvailable).AndGte("battery", 20)),
+		rel.Set("status", StatusInRide),
+		rel.Set("updated_at", now),
+	).UpdatedCount(0)
+	repository.ExpectFind(where.Eq("id", uint(1))).Result(lowBattery)
+
+	assert.Equal(t, ErrBatteryTooLow, NewFleet(repository, 20).Take(context.TODO(), "1", now))
+
+	repository.AssertExpectations(t)
+}
+
+func TestFleetTakeMalformedID(t *testing.T) {
+	repository := reltest.New()
+
+	assert.Equal(t, ErrVehicleNotFound, NewFleet(repository, 20).Take(context.TODO(), "scooter-1", now))
+
+	repository.AssertExpectations(t)
+}
+
+func TestFleetRelease(t *testing.T) {
+	repository := reltest.New()
+
+	repository.ExpectUpdateAny(
+		rel.From("vehicles").Where(where.Eq("id", uint(1)).AndEq("status", StatusInRide)),
+		rel.Set("status", StatusAvailable),
+		rel.Set("updated_at", now),
+	).UpdatedCount(1)
+
+	assert.Nil(t, NewFleet(repository, 20).Release(context.TODO(), "1", now))
+
+	repository.AssertExpectations(t)
+}
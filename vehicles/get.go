@@ -0,0 +1,72 @@
+package vehicles
+
+import (
+	"context"
+	"errors"
+
+	"github.com/go-rel/rel"
+	"github.com/go-rel/rel/where"
+)
+
+type getVehicle struct {
+	repository rel.Repository
+}
+
+func (c getVehicle) GetVehicle(ctx context.Context, id uint) (error, *Vehicle) {
+	return find(ctx, c.repository, id)
+}
+
+// Filter narrows down the listed vehicles. Zero values match every
+// vehicle.
+type Filter struct {
+	Type   Type
+	Status Status
+	// BatteryBelow only matches the vehicles with less battery, in percent.
+	BatteryBelow int
+}
+
+type listVehicles struct {
+	repository rel.Repository
+}
+
+// ListVehicles returns the vehicles that match the filter, the oldest
+// first.
+func (c listVehicles) ListVehicles(ctx context.Context, filter Filter) (error, []Vehicle) {
+	if filter.Type != "" && !filter.Type.Valid() {
+		return ErrVehicleTypeInvalid, nil
+	}
+	if filter.Status != "" && !filter.Status.Valid() {
+		return ErrVehicleStatusInvalid, nil
+	}
+
+	var conditions []rel.FilterQuery
+	if filter.Type != "" {
+		conditions = append(conditions, where.Eq("type", filter.Type))
+	}
+	if filter.Status != "" {
+		conditions = append(conditions, where.Eq("status", filter.Status))
+	}
+	if filter.BatteryBelow != 0 {
+		conditions = append(conditions, where.Lt("battery", filter.BatteryBelow))
+	}
+
+	vehicles := []Vehicle{}
+	query := rel.From("vehicles").Where(conditions...).SortAsc("id")
+	if err := c.repository.FindAll(ctx, &vehicles, query); err != nil {
+		return err, nil
+	}
+
+	return nil, vehicles
+}
+
+func find(ctx context.Context, repository rel.Repository, id uint) (error, *Vehicle) {
+	var vehicle Vehicle
+	if err := repository.Find(ctx, &vehicle, where.Eq("id", id)); err != nil {
+		if errors.Is(err, rel.ErrNotFound) {
+			return ErrVehicleNotFound, nil
+		}
+		return err, nil
+	}
+
+	return nil, &vehicle
+}
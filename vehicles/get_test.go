@@ -0,0 +1,43 @@
+package vehicles
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-rel/rel"
+	"github.com/go-rel/rel/where"
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListVehicles(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository)
+	)
+
+	repository.ExpectFindAll(
+		rel.From("vehicles").Where(where.Eq("type", TypeScooter), where.Eq("status", StatusAvailable)).SortAsc("id"),
+	).Result([]Vehicle{scooter})
+
+	err, list := service.ListVehicles(ctx, Filter{Type: TypeScooter, Status: StatusAvailable})
+	assert.Nil(t, err)
+	assert.Len(t, list, 1)
+
+	repository.AssertExpectations(t)
+}
+
+func TestListVehiclesStatusInvalid(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository)
+	)
+
+	err, list := service.ListVehicles(ctx, Filter{Status: "lost"})
+	assert.Equal(t, ErrVehicleStatusInvalid, err)
+	assert.Nil(t, list)
+
+	repository.AssertExpectations(t)
+}
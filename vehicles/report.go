@@ -0,0 +1,37 @@
+package vehicles
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-rel/rel"
+	"github.com/go-rel/rel/where"
+)
+
+type reportTelemetry struct {
+	repository rel.Repository
+}
+
+// ReportTelemetry records the battery level and position a vehicle reports,
+// whatever its status.
+func (c reportTelemetry) ReportTelemetry(ctx context.Context, id uint, telemetry Telemetry, now time.Time) (error, *Vehicle) {
+	if err := telemetry.Validate(); err != nil {
+		return err, nil
+	}
+
+	updated, err := c.repository.UpdateAny(ctx,
+		rel.From("vehicles").Where(where.Eq("id", id)),
+		rel.Set("battery", telemetry.Battery),
+		rel.Set("latitude", telemetry.Latitude),
+		rel.Set("longitude", telemetry.Longitude),
+		rel.Set("updated_at", now),
+	)
+	if err != nil {
+		return err, nil
+	}
+	if updated == 0 {
+		return ErrVehicleNotFound, nil
+	}
+
+	return find(ctx, c.repository, id)
+}
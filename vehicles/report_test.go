@@ -0,0 +1,58 @@
+package vehicles
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-rel/rel"
+	"github.com/go-rel/rel/where"
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReportTelemetry(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository)
+		reported   = scooter
+	)
+	reported.Battery = 42
+
+	repository.ExpectUpdateAny(
+		rel.From("vehicles").Where(where.Eq("id", scooter.ID)),
+		rel.Set("battery", 42),
+		rel.Set("latitude", 39.47),
+		rel.Set("longitude", -0.38),
+		rel.Set("updated_at", now),
+	).UpdatedCount(1)
+	repository.ExpectFind(where.Eq("id", scooter.ID)).Result(reported)
+
+	err, vehicle := service.ReportTelemetry(ctx, scooter.ID, Telemetry{Battery: 42, Latitude: 39.47, Longitude: -0.38}, now)
+	assert.Nil(t, err)
+	assert.Equal(t, 42, vehicle.Battery)
+
+	repository.AssertExpectations(t)
+}
+
+func TestReportTelemetryNotFound(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository)
+	)
+
+	repository.ExpectUpdateAny(
+		rel.From("vehicles").Where(where.Eq("id", uint(2))),
+		rel.Set("battery", 42),
+		rel.Set("latitude", 0.0),
+		rel.Set("longitude", 0.0),
+		rel.Set("updated_at", now),
+	).UpdatedCount(0)
+
+	err, vehicle := service.ReportTelemetry(ctx, 2, Telemetry{Battery: 42}, now)
+	assert.Equal(t, ErrVehicleNotFound, err)
+	assert.Nil(t, vehicle)
+
+	repository.AssertExpectations(t)
+}
@@ -0,0 +1,37 @@
+package vehicles
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-rel/rel"
+)
+
+type Service interface {
+	CreateVehicle(ctx context.Context, vehicle *Vehicle) (error, *Vehicle)
+	UpdateVehicle(ctx context.Context, vehicle *Vehicle, now time.Time) (error, *Vehicle)
+	RetireVehicle(ctx context.Context, id uint, now time.Time) error
+	ReportTelemetry(ctx context.Context, id uint, telemetry Telemetry, now time.Time) (error, *Vehicle)
+	GetVehicle(ctx context.Context, id uint) (error, *Vehicle)
+	ListVehicles(ctx context.Context, filter Filter) (error, []Vehicle)
+}
+
+type service struct {
+	createVehicle
+	updateVehicle
+	retireVehicle
+	reportTelemetry
+	getVehicle
+	listVehicles
+}
+
+func New(repository rel.Repository) Service {
+	return service{
+		createVehicle:   createVehicle{repository: repository},
+		updateVehicle:   updateVehicle{repository: repository},
+		retireVehicle:   retireVehicle{repository: repository},
+		reportTelemetry: reportTelemetry{repository: repository},
+		getVehicle:      getVehicle{repository: repository},
+		listVehicles:    listVehicles{repository: repository},
+	}
+}
@@ -0,0 +1,73 @@
+package vehicles
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-rel/rel"
+	"github.com/go-rel/rel/where"
+)
+
+type updateVehicle struct {
+	repository rel.Repository
+}
+
+// UpdateVehicle replaces the vehicle that matches vehicle.ID. The status of
+// a vehicle in a ride can't change, and no other vehicle can be put in one.
+func (c updateVehicle) UpdateVehicle(ctx context.Context, vehicle *Vehicle, now time.Time) (error, *Vehicle) {
+	if err := vehicle.Validate(); err != nil {
+		return err, nil
+	}
+
+	err, current := find(ctx, c.repository, vehicle.ID)
+	if err != nil {
+		return err, nil
+	}
+	if (current.Status == StatusInRide) != (vehicle.Status == StatusInRide) {
+		return ErrVehicleInRide, nil
+	}
+
+	// A ride may have taken or released the vehicle since it was read.
+	updated, err := c.repository.UpdateAny(ctx,
+		rel.From("vehicles").Where(where.Eq("id", vehicle.ID).AndEq("status", current.Status)),
+		rel.Set("type", vehicle.Type),
+		rel.Set("model", vehicle.Model),
+		rel.Set("status", vehicle.Status),
+		rel.Set("battery", vehicle.Battery),
+		rel.Set("latitude", vehicle.Latitude),
+		rel.Set("longitude", vehicle.Longitude),
+		rel.Set("updated_at", now),
+	)
+	if err != nil {
+		return err, nil
+	}
+	if updated == 0 {
+		return ErrVehicleInRide, nil
+	}
+
+	vehicle.CreatedAt = current.CreatedAt
+	vehicle.UpdatedAt = now
+	return nil, vehicle
+}
+
+type retireVehicle struct {
+	repository rel.Repository
+}
+
+// RetireVehicle takes the vehicle out of the fleet. It is kept with the
+// retired status rather than removed, as rides still refer to it.
+func (c retireVehicle) RetireVehicle(ctx context.Context, id uint, now time.Time) error {
+	updated, err := c.repository.UpdateAny(ctx,
+		rel.From("vehicles").Where(where.Eq("id", id).AndNe("status", StatusInRide)),
+		rel.Set("status", StatusRetired),
+		rel.Set("updated_at", now),
+	)
+	if err != nil || updated != 0 {
+		return err
+	}
+
+	if err, _ := find(ctx, c.repository, id); err != nil {
+		return err
+	}
+	return ErrVehicleInRide
+}
@@ -0,0 +1,98 @@
+package vehicles
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-rel/rel"
+	"github.com/go-rel/rel/where"
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateVehicle(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository)
+		vehicle    = scooter
+	)
+	vehicle.Status = StatusMaintenance
+	vehicle.Battery = 15
+
+	repository.ExpectFind(where.Eq("id", scooter.ID)).Result(scooter)
+	repository.ExpectUpdateAny(
+		rel.From("vehicles").Where(where.Eq("id", scooter.ID).AndEq("status", StatusAvailable)),
+		rel.Set("type", TypeScooter),
+		rel.Set("model", "Ninebot Max"),
+		rel.Set("status", StatusMaintenance),
+		rel.Set("battery", 15),
+		rel.Set("latitude", 39.47),
+		rel.Set("longitude", -0.38),
+		rel.Set("updated_at", now),
+	).UpdatedCount(1)
+
+	err, savedVehicle := service.UpdateVehicle(ctx, &vehicle, now)
+	assert.Nil(t, err)
+	assert.Equal(t, StatusMaintenance, savedVehicle.Status)
+	assert.Equal(t, now, savedVehicle.UpdatedAt)
+
+	repository.AssertExpectations(t)
+}
+
+func TestUpdateVehicleInRide(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository)
+		inRide     = scooter
+		vehicle    = scooter
+	)
+	inRide.Status = StatusInRide
+	vehicle.Status = StatusMaintenance
+
+	repository.ExpectFind(where.Eq("id", scooter.ID)).Result(inRide)
+
+	err, savedVehicle := service.UpdateVehicle(ctx, &vehicle, now)
+	assert.Equal(t, ErrVehicleInRide, err)
+	assert.Nil(t, savedVehicle)
+
+	repository.AssertExpectations(t)
+}
+
+func TestRetireVehicle(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository)
+	)
+
+	repository.ExpectUpdateAny(
+		rel.From("vehicles").Where(where.Eq("id", scooter.ID).AndNe("status", StatusInRide)),
+		rel.Set("status", StatusRetired),
+		rel.Set("updated_at", now),
+	).UpdatedCount(1)
+
+	assert.Nil(t, service.RetireVehicle(ctx, scooter.ID, now))
+
+	repository.AssertExpectations(t)
+}
+
+func TestRetireVehicleNotFound(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository)
+	)
+
+	repository.ExpectUpdateAny(
+		rel.From("vehicles").Where(where.Eq("id", uint(2)).AndNe("status", StatusInRide)),
+		rel.Set("status", StatusRetired),
+		rel.Set("updated_at", now),
+	).UpdatedCount(0)
+	repository.ExpectFind(where.Eq("id", uint(2))).NotFound()
+
+	assert.Equal(t, ErrVehicleNotFound, service.RetireVehicle(ctx, 2, now))
+
+	repository.AssertExpectations(t)
+}
@@ -0,0 +1,111 @@
+package vehicles
+
+import (
+	"errors"
+	"time"
+)
+
+type Type string
+
+const (
+	TypeScooter Type = "scooter"
+	TypeBike    Type = "bike"
+	TypeMoped   Type = "moped"
+)
+
+func (t Type) Valid() bool {
+	switch t {
+	case TypeScooter, TypeBike, TypeMoped:
+		return true
+	}
+	return false
+}
+
+// Status is where a vehicle of the fleet stands.
+type Status string
+
+const (
+	StatusAvailable Status = "available"
+	// StatusInRide vehicles are taken by an open ride. Only rides move
+	// vehicles in and out of it.
+	StatusInRide      Status = "in_ride"
+	StatusMaintenance Status = "maintenance"
+	// StatusRetired vehicles left the fleet, and are kept for the rides
+	// that refer to them.
+	StatusRetired Status = "retired"
+)
+
+func (s Status) Valid() bool {
+	switch s {
+	case StatusAvailable, StatusInRide, StatusMaintenance, StatusRetired:
+		return true
+	}
+	return false
+}
+
+// Vehicle is a vehicle of the fleet. Rides refer to it by its ID written in
+// decimal.
+type Vehicle struct {
+	ID        uint      `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Type      Type      `json:"type"`
+	Model     string    `json:"model"`
+	// City is where the vehicle operates, which picks the tariff and the
+	// tax rate of the rides on it along with its type.
+	City string `json:"city,omitempty"`
+	// Operator runs the vehicle, and picks the tax rate of its rides when
+	// City has none.
+	Operator string `json:"operator,omitempty"`
+	Status   Status `json:"status"`
+	Battery  int    `json:"battery"` // in percent
+	// Latitude and Longitude are its last known position.
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+func (Vehicle) Table() string {
+	return "vehicles"
+}
+
+var (
+	ErrVehicleTypeInvalid   = errors.New("Type must be one of: scooter, bike, moped")
+	ErrVehicleModelBlank    = errors.New("Model can't be blank")
+	ErrVehicleStatusInvalid = errors.New("Status must be one of: available, in_ride, maintenance, retired")
+	ErrBatteryInvalid       = errors.New("Battery must be between 0 and 100")
+	ErrPositionInvalid      = errors.New("Latitude must be between -90 and 90, and longitude between -180 and 180")
+	ErrVehicleNotFound      = errors.New("No vehicle matches the given ID")
+	ErrVehicleNotAvailable  = errors.New("The vehicle is not available")
+	ErrVehicleInRide        = errors.New("Only rides move vehicles in and out of in_ride")
+	ErrBatteryTooLow        = errors.New("The battery of the vehicle is too low to start a ride")
+)
+
+func (v Vehicle) Validate() error {
+	switch {
+	case !v.Type.Valid():
+		return ErrVehicleTypeInvalid
+	case v.Model == "":
+		return ErrVehicleModelBlank
+	case !v.Status.Valid():
+		return ErrVehicleStatusInvalid
+	}
+	return Telemetry{Battery: v.Battery, Latitude: v.Latitude, Longitude: v.Longitude}.Validate()
+}
+
+// Telemetry is what a vehicle reports about itself: its battery level and
+// where it is.
+type Telemetry struct {
+	Battery   int     `json:"battery"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+func (t Telemetry) Validate() error {
+	switch {
+	case t.Battery < 0 || t.Battery > 100:
+		return ErrBatteryInvalid
+	case t.Latitude < -90 || t.Latitude > 90 || t.Longitude < -180 || t.Longitude > 180:
+		return ErrPositionInvalid
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package vehicles
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	now     = time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)
+	scooter = Vehicle{ID: 1, Type: TypeScooter, Model: "Ninebot Max", City: "Valencia", Operator: "acme", Status: StatusAvailable, Battery: 80, Latitude: 39.47, Longitude: -0.38}
+)
+
+func TestVehicleValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		vehicle Vehicle
+		err     error
+	}{
+		{"valid", scooter, nil},
+		{"type", Vehicle{Type: "car", Model: "Ninebot Max", Status: StatusAvailable}, ErrVehicleTypeInvalid},
+		{"model blank", Vehicle{Type: TypeBike, Status: StatusAvailable}, ErrVehicleModelBlank},
+		{"status", Vehicle{Type: TypeBike, Model: "Urban", Status: "lost"}, ErrVehicleStatusInvalid},
+		{"battery", Vehicle{Type: TypeMoped, Model: "Cityscoot", Status: StatusAvailable, Battery: 101}, ErrBatteryInvalid},
+		{"latitude", Vehicle{Type: TypeBike, Model: "Urban", Status: StatusAvailable, Latitude: 91}, ErrPositionInvalid},
+		{"longitude", Vehicle{Type: TypeBike, Model: "Urban", Status: StatusAvailable, Longitude: -181}, ErrPositionInvalid},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.err, test.vehicle.Validate())
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package wallets
+
+import (
+	"context"
+
+	"backend/money"
+
+	"github.com/go-rel/rel"
+	"github.com/go-rel/rel/where"
+)
+
+type getBalance struct {
+	repository rel.Repository
+}
+
+// GetBalance is what the wallet of the user holds in the given currency.
+func (c getBalance) GetBalance(ctx context.Context, userID string, currency money.Currency) (error, money.Money) {
+	switch {
+	case userID == "":
+		return ErrWalletUserIDBlank, money.Money{}
+	case !currency.Valid():
+		return ErrWalletCurrencyInvalid, money.Money{}
+	}
+
+	balance, err := balanceOf(ctx, c.repository, userID, currency)
+	if err != nil {
+		return err, money.Money{}
+	}
+
+	return nil, balance
+}
+
+func balanceOf(ctx context.Context, repository rel.Repository, userID string, currency money.Currency) (money.Money, error) {
+	sum, err := repository.Aggregate(ctx,
+		rel.From("wallet_entries").Where(where.Eq("account", Account(userID)).AndEq("currency", currency)),
+		"sum", "amount",
+	)
+	if err != nil {
+		return money.Money{}, err
+	}
+
+	return money.New(int64(sum), currency), nil
+}
+
+type listTransactions struct {
+	repository rel.Repository
+}
+
+// ListTransactions returns the movements of the wallet of the user, the
+// latest first.
+func (c listTransactions) ListTransactions(ctx context.Context, userID string) (error, []Transaction) {
+	transactions := []Transaction{}
+	if err := c.repository.FindAll(ctx, &transactions, rel.Where(where.Eq("user_id", userID)).SortDesc("id")); err != nil {
+		return err, nil
+	}
+
+	return nil, transactions
+}
@@ -0,0 +1,66 @@
+package wallets
+
+import (
+	"context"
+	"testing"
+
+	"backend/money"
+	"backend/payments"
+
+	"github.com/go-rel/rel"
+	"github.com/go-rel/rel/where"
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetBalance(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository, payments.NewFake())
+	)
+
+	repository.ExpectAggregate(
+		rel.From("wallet_entries").Where(where.Eq("account", "user:1").AndEq("currency", money.Currency("EUR"))),
+		"sum", "amount",
+	).Result(1250)
+
+	err, balance := service.GetBalance(ctx, "1", "EUR")
+	assert.Nil(t, err)
+	assert.Equal(t, money.New(1250, "EUR"), balance)
+
+	repository.AssertExpectations(t)
+}
+
+func TestGetBalanceCurrencyInvalid(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		service    = New(repository, payments.NewFake())
+	)
+
+	err, _ := service.GetBalance(ctx, "1", "")
+	assert.Equal(t, ErrWalletCurrencyInvalid, err)
+
+	repository.AssertExpectations(t)
+}
+
+func TestListTransactions(t *testing.T) {
+	var (
+		ctx          = context.TODO()
+		repository   = reltest.New()
+		service      = New(repository, payments.NewFake())
+		transactions = []Transaction{
+			{ID: 2, UserID: "1", Kind: KindRideDebit, Amount: -418, Currency: "EUR"},
+			{ID: 1, UserID: "1", Kind: KindTopUp, Amount: 2000, Currency: "EUR"},
+		}
+	)
+
+	repository.ExpectFindAll(rel.Where(where.Eq("user_id", "1")).SortDesc("id")).Result(transactions)
+
+	err, list := service.ListTransactions(ctx, "1")
+	assert.Nil(t, err)
+	assert.Equal(t, transactions, list)
+
+	repository.AssertExpectations(t)
+}
@@ -0,0 +1,59 @@
+package wallets
+
+import (
+	"context"
+	"time"
+
+	"backend/money"
+
+	"github.com/go-rel/rel"
+)
+
+// Ledger is the payments.Wallets backed by the stored entries.
+type Ledger struct {
+	repository rel.Repository
+}
+
+func NewLedger(repository rel.Repository) Ledger {
+	return Ledger{repository: repository}
+}
+
+func (l Ledger) Balance(ctx context.Context, userID string, currency money.Currency) (money.Money, error) {
+	return balanceOf(ctx, l.repository, userID, currency)
+}
+
+// DebitRide pays the ride from the wallet to the revenue account. Free
+// rides leave no transaction.
+func (l Ledger) DebitRide(ctx context.Context, userID string, rideID uint, amount money.Money, at time.Time) error {
+	if amount.IsZero() {
+		return nil
+	}
+
+	return post(ctx, l.repository, &Transaction{
+		CreatedAt: at,
+		UserID:    userID,
+		Kind:      KindRideDebit,
+		Amount:    -amount.Amount,
+		Currency:  amount.Currency,
+		RideID:    &rideID,
+	}, AccountRevenue)
+}
+
+// AdjustRide records a refund when the price of the ride went down, and an
+// adjustment when it went up.
+func (l Ledger) AdjustRide(ctx context.Context, userID string, rideID uint, amount money.Money, description string, at time.Time) error {
+	kind := KindAdjustment
+	if amount.Amount < 0 {
+		kind = KindRefund
+	}
+
+	return post(ctx, l.repository, &Transaction{
+		CreatedAt:   at,
+		UserID:      userID,
+		Kind:        kind,
+		Amount:      -amount.Amount,
+		Currency:    amount.Currency,
+		RideID:      &rideID,
+		Description: description,
+	}, AccountRevenue)
+}
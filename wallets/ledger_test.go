@@ -0,0 +1,79 @@
+package wallets
+
+import (
+	"context"
+	"testing"
+
+	"backend/money"
+
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDebitRide(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		ledger     = NewLedger(repository)
+		entries    = []Entry{
+			{ID: 1, CreatedAt: now, TransactionID: 1, Account: "user:1", Amount: -418, Currency: "EUR"},
+			{ID: 2, CreatedAt: now, TransactionID: 1, Account: AccountRevenue, Amount: 418, Currency: "EUR"},
+		}
+	)
+
+	repository.ExpectInsert().ForType("*wallets.Transaction")
+	repository.ExpectInsertAll().For(&entries)
+
+	assert.Nil(t, ledger.DebitRide(ctx, "1", 7, money.New(418, "EUR"), now))
+
+	repository.AssertExpectations(t)
+}
+
+func TestDebitFreeRide(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		ledger     = NewLedger(repository)
+	)
+
+	assert.Nil(t, ledger.DebitRide(ctx, "1", 7, money.New(0, "EUR"), now))
+
+	repository.AssertExpectations(t)
+}
+
+func TestAdjustRide(t *testing.T) {
+	tests := []struct {
+		name   string
+		amount int64
+		kind   Kind
+	}{
+		{"refund", -200, KindRefund},
+		{"surcharge", 150, KindAdjustment},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				ctx        = context.TODO()
+				repository = reltest.New()
+				ledger     = NewLedger(repository)
+				rideID     = uint(7)
+			)
+
+			repository.ExpectInsert().For(&Transaction{
+				CreatedAt:   now,
+				UserID:      "1",
+				Kind:        test.kind,
+				Amount:      -test.amount,
+				Currency:    "EUR",
+				RideID:      &rideID,
+				Description: "goodwill",
+			})
+			repository.ExpectInsertAll().ForType("[]wallets.Entry")
+
+			assert.Nil(t, ledger.AdjustRide(ctx, "1", rideID, money.New(test.amount, "EUR"), "goodwill", now))
+
+			repository.AssertExpectations(t)
+		})
+	}
+}
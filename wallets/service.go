@@ -0,0 +1,32 @@
+package wallets
+
+import (
+	"context"
+	"time"
+
+	"backend/money"
+	"backend/payments"
+
+	"github.com/go-rel/rel"
+)
+
+type Service interface {
+	TopUp(ctx context.Context, userID string, amount money.Money, now time.Time) (error, *Transaction)
+	GetBalance(ctx context.Context, userID string, currency money.Currency) (error, money.Money)
+	ListTransactions(ctx context.Context, userID string) (error, []Transaction)
+}
+
+type service struct {
+	topUp
+	getBalance
+	listTransactions
+}
+
+// New builds the wallets service, which charges top-ups through payments.
+func New(repository rel.Repository, payments payments.Provider) Service {
+	return service{
+		topUp:            topUp{repository: repository, payments: payments},
+		getBalance:       getBalance{repository: repository},
+		listTransactions: listTransactions{repository: repository},
+	}
+}
@@ -0,0 +1,64 @@
+package wallets
+
+import (
+	"context"
+	"time"
+
+	"backend/money"
+	"backend/payments"
+
+	"github.com/go-rel/rel"
+)
+
+type topUp struct {
+	repository rel.Repository
+	payments   payments.Provider
+}
+
+// TopUp charges amount to the payment method of the user and credits it to
+// their wallet. The payment is authorized and captured before anything is
+// credited, and the transaction keeps its ID as the reference, so every
+// top-up stands for a payment that went through. A top-up that can't be
+// stored once paid is refunded.
+func (c topUp) TopUp(ctx context.Context, userID string, amount money.Money, now time.Time) (error, *Transaction) {
+	switch {
+	case userID == "":
+		return ErrWalletUserIDBlank, nil
+	case amount.Amount <= 0 || !amount.Currency.Valid():
+		return ErrTopUpAmountInvalid, nil
+	}
+
+	paymentID, err := c.payments.Authorize(ctx, userID, amount)
+	if err != nil {
+		return err, nil
+	}
+	if err := c.payments.Capture(ctx, paymentID, amount, topUpKey(paymentID)); err != nil {
+		// A hold that fails to void expires with the provider.
+		_ = c.payments.Void(ctx, paymentID)
+		return err, nil
+	}
+
+	transaction := Transaction{
+		CreatedAt: now,
+		UserID:    userID,
+		Kind:      KindTopUp,
+		Amount:    amount.Amount,
+		Currency:  amount.Currency,
+		Reference: paymentID,
+	}
+	err = c.repository.Transaction(ctx, func(ctx context.Context) error {
+		return post(ctx, c.repository, &transaction, AccountFunding)
+	})
+	if err != nil {
+		// A refund that fails is left to be reconciled with the provider.
+		_ = c.payments.Refund(ctx, paymentID, amount, topUpKey(paymentID)+"-refund")
+		return err, nil
+	}
+
+	return nil, &transaction
+}
+
+// topUpKey is the idempotency key of the capture of a top-up.
+func topUpKey(paymentID string) string {
+	return "top-up-" + paymentID
+}
@@ -0,0 +1,129 @@
+package wallets
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"backend/money"
+	"backend/payments"
+
+	"github.com/go-rel/reltest"
+	"github.com/stretchr/testify/assert"
+)
+
+var now = time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+func TestTopUp(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		provider   = payments.NewFake()
+		service    = New(repository, provider)
+	)
+
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectInsert().ForType("*wallets.Transaction")
+		repository.ExpectInsertAll().ForType("[]wallets.Entry")
+	})
+
+	err, transaction := service.TopUp(ctx, "1", money.New(2000, "EUR"), now)
+	assert.Nil(t, err)
+	assert.NotEmpty(t, transaction.ID)
+	assert.Equal(t, KindTopUp, transaction.Kind)
+	assert.Equal(t, int64(2000), transaction.Amount)
+	assert.Equal(t, "fake_1", transaction.Reference)
+
+	hold, _ := provider.Hold(transaction.Reference)
+	assert.Equal(t, payments.StatusCaptured, hold.Status)
+	assert.Equal(t, money.New(2000, "EUR"), hold.Captured)
+
+	repository.AssertExpectations(t)
+}
+
+func TestTopUpDeclined(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		provider   = payments.NewFake()
+		service    = New(repository, provider)
+	)
+	provider.Decline("1")
+
+	err, transaction := service.TopUp(ctx, "1", money.New(2000, "EUR"), now)
+	assert.Equal(t, payments.ErrDeclined, err)
+	assert.Nil(t, transaction)
+
+	repository.AssertExpectations(t)
+}
+
+func TestTopUpCaptureFails(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		provider   = payments.NewFake()
+		service    = New(repository, provider)
+		errTimeout = errors.New("timeout")
+	)
+	provider.FailCaptures(errTimeout)
+
+	err, transaction := service.TopUp(ctx, "1", money.New(2000, "EUR"), now)
+	assert.Equal(t, errTimeout, err)
+	assert.Nil(t, transaction)
+
+	hold, _ := provider.Hold("fake_1")
+	assert.Equal(t, payments.StatusVoided, hold.Status)
+
+	repository.AssertExpectations(t)
+}
+
+func TestTopUpInvalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		userID string
+		amount money.Money
+		err    error
+	}{
+		{"user blank", "", money.New(2000, "EUR"), ErrWalletUserIDBlank},
+		{"zero", "1", money.New(0, "EUR"), ErrTopUpAmountInvalid},
+		{"negative", "1", money.New(-1, "EUR"), ErrTopUpAmountInvalid},
+		{"currency", "1", money.New(2000, "eur"), ErrTopUpAmountInvalid},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repository := reltest.New()
+
+			err, transaction := New(repository, payments.NewFake()).TopUp(context.TODO(), test.userID, test.amount, now)
+			assert.Equal(t, test.err, err)
+			assert.Nil(t, transaction)
+
+			repository.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTopUpError(t *testing.T) {
+	var (
+		ctx        = context.TODO()
+		repository = reltest.New()
+		provider   = payments.NewFake()
+		service    = New(repository, provider)
+	)
+
+	repository.ExpectTransaction(func(repository *reltest.Repository) {
+		repository.ExpectInsert().ForType("*wallets.Transaction")
+		repository.ExpectInsertAll().ForType("[]wallets.Entry").ConnectionClosed()
+	})
+
+	err, transaction := service.TopUp(ctx, "1", money.New(2000, "EUR"), now)
+	assert.Equal(t, reltest.ErrConnectionClosed, err)
+	assert.Nil(t, transaction)
+
+	// The payment that couldn't be credited is given back.
+	hold, _ := provider.Hold("fake_1")
+	assert.Equal(t, money.New(2000, "EUR"), hold.Refunded)
+
+	repository.AssertExpectations(t)
+}
@@ -0,0 +1,94 @@
+package wallets
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"backend/money"
+
+	"github.com/go-rel/rel"
+)
+
+// Kind is what moved money in or out of a wallet.
+type Kind string
+
+const (
+	KindTopUp      Kind = "top_up"
+	KindRideDebit  Kind = "ride_debit"
+	KindRefund     Kind = "refund"
+	KindAdjustment Kind = "adjustment"
+)
+
+// The accounts on the other side of the wallets: money comes in from the
+// payment methods of users through AccountFunding, and rides are paid to
+// AccountRevenue.
+const (
+	AccountFunding = "funding"
+	AccountRevenue = "revenue"
+)
+
+// Account is the ledger account of the wallet of the user.
+func Account(userID string) string {
+	return "user:" + userID
+}
+
+// Transaction is a movement of a wallet. Amount is what the wallet gains,
+// negative when it pays, and its entries balance it out against another
+// account.
+type Transaction struct {
+	ID          uint           `json:"id"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UserID      string         `json:"user_id"`
+	Kind        Kind           `json:"kind"`
+	Amount      int64          `json:"amount"`
+	Currency    money.Currency `json:"currency"`
+	RideID      *uint          `json:"ride_id,omitempty"`
+	Reference   string         `json:"reference,omitempty"`
+	Description string         `json:"description,omitempty"`
+}
+
+func (Transaction) Table() string {
+	return "wallet_transactions"
+}
+
+// Entry is one side of a transaction. The entries of a transaction sum to
+// zero, and the balance of an account is the sum of its entries.
+type Entry struct {
+	ID            uint           `json:"id"`
+	CreatedAt     time.Time      `json:"created_at"`
+	TransactionID uint           `json:"transaction_id"`
+	Account       string         `json:"account"`
+	Amount        int64          `json:"amount"`
+	Currency      money.Currency `json:"currency"`
+}
+
+func (Entry) Table() string {
+	return "wallet_entries"
+}
+
+var (
+	ErrWalletUserIDBlank     = errors.New("UserID can't be blank")
+	ErrTopUpAmountInvalid    = errors.New("Amount must be positive and needs a three-letter ISO 4217 currency")
+	ErrWalletCurrencyInvalid = errors.New("Currency must be a three-letter ISO 4217 code")
+)
+
+// post stores the transaction with the entries moving its amount between
+// the wallet of its user and the counter account. It runs in the
+// transaction of its caller.
+func post(ctx context.Context, repository rel.Repository, transaction *Transaction, counter string) error {
+	if err := repository.Insert(ctx, transaction); err != nil {
+		return err
+	}
+
+	entries := []Entry{
+		{Account: Account(transaction.UserID), Amount: transaction.Amount},
+		{Account: counter, Amount: -transaction.Amount},
+	}
+	for i := range entries {
+		entries[i].CreatedAt = transaction.CreatedAt
+		entries[i].TransactionID = transaction.ID
+		entries[i].Currency = transaction.Currency
+	}
+	return repository.InsertAll(ctx, &entries)
+}